@@ -4,27 +4,59 @@
 package main
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	httppprof "net/http/pprof"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	runtimedebug "runtime/debug"
+	runtimepprof "runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	"github.com/specterops/sharehound/internal/archive"
+	"github.com/specterops/sharehound/internal/audit"
+	"github.com/specterops/sharehound/internal/campaign"
 	"github.com/specterops/sharehound/internal/checkpoint"
 	"github.com/specterops/sharehound/internal/collector"
 	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/control"
 	"github.com/specterops/sharehound/internal/credentials"
+	"github.com/specterops/sharehound/internal/daemon"
+	"github.com/specterops/sharehound/internal/distrib"
+	"github.com/specterops/sharehound/internal/drift"
+	"github.com/specterops/sharehound/internal/findings"
 	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/ldap"
 	"github.com/specterops/sharehound/internal/logger"
+	"github.com/specterops/sharehound/internal/loot"
+	"github.com/specterops/sharehound/internal/policy"
+	"github.com/specterops/sharehound/internal/report"
 	"github.com/specterops/sharehound/internal/rules"
+	"github.com/specterops/sharehound/internal/secrets"
+	"github.com/specterops/sharehound/internal/shell"
+	"github.com/specterops/sharehound/internal/smb"
 	"github.com/specterops/sharehound/internal/status"
+	"github.com/specterops/sharehound/internal/stream"
 	"github.com/specterops/sharehound/internal/targets"
 	"github.com/specterops/sharehound/internal/utils"
 	"github.com/specterops/sharehound/internal/worker"
+	"github.com/specterops/sharehound/internal/yara"
 	"github.com/specterops/sharehound/pkg/kinds"
 )
 
@@ -41,45 +73,128 @@ var (
 	output   string
 
 	// Advanced configuration
-	advertisedName    string
-	threads           int
-	maxWorkersPerHost int
-	globalMaxWorkers  int
-	nameserver        string
-	timeout           float64
-	hostTimeout       float64
+	advertisedName      string
+	threads             int
+	maxWorkersPerHost   int
+	globalMaxWorkers    int
+	memoryLimitMB       int
+	nameserver          string
+	timeout             float64
+	hostTimeout         float64
+	hostTimeoutPerShare float64
+	hostTimeoutMax      float64
+	scanDeadline        float64
+	scanDeadlineGrace   float64
+	shutdownGrace       float64
 
 	// Rules
 	rulesFiles  []string
 	ruleStrings []string
 
+	// Content scanning
+	yaraFiles        []string
+	yaraMaxFileSize  int64
+	secretTriage     bool
+	secretSampleSize int64
+	archivePeek      bool
+	hashFiles        bool
+	hashMaxFileSize  int64
+
+	// Evidence collection
+	lootDir         string
+	lootMaxFileSize int64
+	lootBudget      int64
+
 	// Share exploration
 	shareName           string
+	smbPort             int
 	depth               int
 	includeCommonShares bool
 
 	// Targets and authentication
 	targetsFile  string
+	maxTargets   int
 	targetsList  []string
 	authDomain   string
 	authDCIP     string
 	authUser     string
 	authPassword string
+	askPassword  bool
+	passwordFd   int
 	authHashes   string
 	authKey      string
+	localAuth    bool
 	useKerberos  bool
 	windowsAuth  bool
 	kdcHost      string
 	useLDAPS     bool
 	subnets      bool
+	forest       bool
 
 	// Checkpoint/resume options
 	checkpointFile     string
 	checkpointInterval float64
+	exportInterval     float64
 	resume             bool
 
 	// Output filtering
 	effectiveAccessOnly bool
+	accessCheckOnly     bool
+	impersonate         string
+	ignoreSIDs          string
+	localPrincipals     string
+	ignorePrincipals    string
+	rollupEdges         bool
+	edgeKinds           string
+	excludeEdgeKinds    string
+	minimalProperties   bool
+	pruneEmpty          bool
+	maxNodes            int
+	maxEdges            int
+	fastExport          bool
+	omitProperties      string
+	alertWebhook        string
+	maxAuthFailures     int
+	localGroups         bool
+	canonicalIDs        bool
+	rawSDDL             bool
+	campaignName        string
+	campaignDir         string
+
+	// Distributed collection
+	aggregatorAddr string
+	aggregatorCert string
+	aggregatorKey  string
+	aggregatorCA   string
+	agentName      string
+
+	// Live results stream
+	streamListen string
+	streamCert   string
+	streamKey    string
+	streamCA     string
+
+	// Audit options
+	auditLogPath      string
+	skipReportPath    string
+	findingsSTIXPath  string
+	findingsOCSFPath  string
+	findingsSARIFPath string
+	policyPath        string
+	perHostResultsDir string
+
+	// Runtime control
+	controlSocket string
+	pprofListen   string
+	profileOutput string
+
+	// OPSEC options
+	opsecProfile       string
+	shuffleTargets     bool
+	dedupeTargets      bool
+	orderBy            string
+	maxHostsPerSubnet  int
+	registrySDFallback bool
 )
 
 func main() {
@@ -97,48 +212,142 @@ creating a BloodHound-compatible OpenGraph for security analysis.`,
 	rootCmd.Flags().BoolVar(&debug, "debug", false, "Debug mode")
 	rootCmd.Flags().BoolVar(&noColors, "no-colors", false, "Disable ANSI escape codes")
 	rootCmd.Flags().StringVar(&logfile, "logfile", "", "Log file to write to")
-	rootCmd.Flags().StringVarP(&output, "output", "o", "opengraph.zip", "Output file (use .zip extension for compression)")
+	rootCmd.Flags().StringVarP(&output, "output", "o", "opengraph.zip", "Output file (use .zip for deflate or .zst for multicore zstd compression)")
 
 	// Advanced configuration
 	rootCmd.Flags().StringVar(&advertisedName, "advertised-name", "", "Advertised name of the client")
 	rootCmd.Flags().IntVar(&threads, "threads", runtime.NumCPU()*8, "Number of threads to use")
 	rootCmd.Flags().IntVar(&maxWorkersPerHost, "max-workers-per-host", 8, "Maximum concurrent shares per host")
 	rootCmd.Flags().IntVar(&globalMaxWorkers, "global-max-workers", 200, "Global maximum workers")
+	rootCmd.Flags().IntVar(&memoryLimitMB, "memory-limit", 0, "Soft memory budget in MiB: sets GOMEMLIMIT, shrinks write buffers and throttles concurrency near the limit (0 = no budget)")
 	rootCmd.Flags().StringVarP(&nameserver, "nameserver", "n", "", "Nameserver for DNS queries")
 	rootCmd.Flags().Float64VarP(&timeout, "timeout", "t", 2.5, "Timeout in seconds for network operations")
 	rootCmd.Flags().Float64Var(&hostTimeout, "host-timeout", 0, "Maximum time in minutes per host (0 = no limit)")
+	rootCmd.Flags().Float64Var(&hostTimeoutPerShare, "host-timeout-per-share", 0, "Additional seconds of host time budget per discovered share (0 = flat --host-timeout)")
+	rootCmd.Flags().Float64Var(&hostTimeoutMax, "host-timeout-max", 0, "Upper bound in minutes on the adaptive host time budget (0 = no bound)")
+	rootCmd.Flags().Float64Var(&scanDeadline, "scan-deadline", 0, "Maximum total scan runtime in minutes; when reached, dispatch stops and the scan wraps up with what was collected (0 = no deadline)")
+	rootCmd.Flags().Float64Var(&scanDeadlineGrace, "scan-deadline-grace", 2, "Minutes granted to in-flight hosts after the scan deadline before they are aborted")
+	rootCmd.Flags().Float64Var(&shutdownGrace, "shutdown-grace", 0, "Seconds granted to in-flight hosts after SIGINT/SIGTERM before they are aborted, for orchestrators that follow up with SIGKILL (0 = wait for them to finish)")
 
 	// Rules
 	rootCmd.Flags().StringArrayVarP(&rulesFiles, "rules-file", "r", nil, "Path to file containing rules")
 	rootCmd.Flags().StringArrayVar(&ruleStrings, "rule-string", nil, "Rule string (can be specified multiple times)")
+	rootCmd.Flags().StringArrayVar(&yaraFiles, "yara-rules", nil, "YARA rule file to run against files selected for processing (can be specified multiple times)")
+	rootCmd.Flags().Int64Var(&yaraMaxFileSize, "yara-max-filesize", 10*1024*1024, "Maximum file size in bytes to scan with YARA rules")
+	rootCmd.Flags().BoolVar(&secretTriage, "secret-triage", false, "Score files selected for processing with entropy and credential-format heuristics (sets a likelySecret property)")
+	rootCmd.Flags().Int64Var(&secretSampleSize, "secret-sample-size", 64*1024, "Bytes to sample from each end of a file during secret triage")
+	rootCmd.Flags().BoolVar(&archivePeek, "archive-peek", false, "List the contents of ZIP archives selected for processing via ranged reads (no download)")
+	rootCmd.Flags().BoolVar(&hashFiles, "hash-files", false, "Record a sha256 property on files selected for processing, without storing content (for DLP hash-list correlation)")
+	rootCmd.Flags().Int64Var(&hashMaxFileSize, "hash-max-filesize", 100*1024*1024, "Maximum file size in bytes to hash with --hash-files")
+	rootCmd.Flags().StringVar(&lootDir, "loot-dir", "", "Directory for files downloaded by COLLECT rules, indexed in a hashed manifest")
+	rootCmd.Flags().Int64Var(&lootMaxFileSize, "loot-max-filesize", 50*1024*1024, "Maximum size in bytes of a single collected file")
+	rootCmd.Flags().Int64Var(&lootBudget, "loot-budget", 1024*1024*1024, "Total byte budget for collected files in one run")
 
 	// Share exploration
-	rootCmd.Flags().StringVar(&shareName, "share", "", "Share to enumerate (default: all shares)")
+	rootCmd.Flags().StringVar(&shareName, "share", "", "Only enumerate these shares, comma-separated and case-insensitive (default: all shares)")
+	rootCmd.Flags().IntVar(&smbPort, "smb-port", 445, "TCP port to reach SMB on; when 445 is filtered, 139 is tried automatically with NetBIOS session setup")
 	rootCmd.Flags().IntVar(&depth, "depth", 0, "Maximum depth to traverse directories (0 = unlimited)")
 	rootCmd.Flags().BoolVar(&includeCommonShares, "include-common-shares", false, "Include C$, ADMIN$, IPC$, PRINT$")
 
 	// Targets and authentication
-	rootCmd.Flags().StringVarP(&targetsFile, "targets-file", "f", "", "Path to file containing targets")
+	rootCmd.Flags().StringVarP(&targetsFile, "targets-file", "f", "", "Path to file containing targets ('-' reads from stdin)")
+	rootCmd.Flags().IntVar(&maxTargets, "max-targets", 65536, "Abort if CIDR expansion yields more than this many targets (0 = no cap); explicit and AD-enumerated targets are never capped")
 	rootCmd.Flags().StringArrayVar(&targetsList, "target", nil, "Target IP, FQDN or CIDR")
 	rootCmd.Flags().StringVar(&authDomain, "auth-domain", "", "Windows domain to authenticate to")
 	rootCmd.Flags().StringVar(&authDCIP, "auth-dc-ip", "", "IP of the domain controller")
 	rootCmd.Flags().StringVar(&authUser, "auth-user", "", "Username of the domain account")
-	rootCmd.Flags().StringVar(&authPassword, "auth-password", "", "Password of the domain account")
+	rootCmd.Flags().StringVar(&authPassword, "auth-password", "", "Password of the domain account (also read from SHAREHOUND_PASSWORD)")
+	rootCmd.Flags().BoolVar(&askPassword, "ask-password", false, "Prompt interactively for the password (no echo)")
+	rootCmd.Flags().IntVar(&passwordFd, "password-fd", -1, "Read the password from the given file descriptor")
 	rootCmd.Flags().StringVar(&authHashes, "auth-hashes", "", "LM:NT hashes for pass-the-hash")
-	rootCmd.Flags().StringVar(&authKey, "auth-key", "", "Kerberos keytab path for authentication")
+	rootCmd.Flags().StringVar(&authKey, "auth-key", "", "Kerberos AES128/AES256 hex key or keytab path for authentication")
+	rootCmd.Flags().BoolVar(&localAuth, "local-auth", false, "Authenticate against local accounts (uses the target hostname as the NTLM domain)")
 	rootCmd.Flags().BoolVarP(&useKerberos, "use-kerberos", "k", false, "Use Kerberos authentication")
 	rootCmd.Flags().BoolVar(&windowsAuth, "windows-auth", false, "Use current Windows credentials with Kerberos SSPI authentication")
 	rootCmd.Flags().StringVar(&kdcHost, "kdc-host", "", "KDC host for Kerberos authentication")
 	rootCmd.Flags().BoolVar(&useLDAPS, "ldaps", false, "Use LDAPS instead of LDAP")
 	rootCmd.Flags().BoolVar(&subnets, "subnets", false, "Auto-enumerate all domain subnets")
+	rootCmd.Flags().BoolVar(&forest, "forest", false, "Enumerate computers from every domain in the forest (via the Global Catalog); the single supplied credential is used against every domain, so it must be valid forest-wide")
 
 	// Checkpoint/resume options
 	rootCmd.Flags().StringVar(&checkpointFile, "checkpoint", "", "Checkpoint file for resumable scans")
 	rootCmd.Flags().Float64Var(&checkpointInterval, "checkpoint-interval", 60, "Checkpoint save interval in seconds")
+	rootCmd.Flags().Float64Var(&exportInterval, "export-interval", 0, "Write an ingestable snapshot of the graph every N seconds while the scan runs (0 = disabled)")
 	rootCmd.Flags().BoolVar(&resume, "resume", false, "Resume from existing checkpoint file")
 
 	// Output filtering
 	rootCmd.Flags().BoolVar(&effectiveAccessOnly, "effective-access-only", false, "Only emit CanEffectiveRead/Write/Execute edges for files and directories (reduces edge count)")
+	rootCmd.Flags().BoolVar(&accessCheckOnly, "access-check-only", false, "Only check share connectivity and root listability, without traversing contents")
+	rootCmd.Flags().StringVar(&impersonate, "impersonate", "", "Comma-separated sAMAccountNames of test accounts; collected DACLs are evaluated against each account's token groups and per-account effective access edges are emitted")
+	rootCmd.Flags().StringVar(&ignoreSIDs, "ignore-sid", "", "Comma-separated SIDs or prefix patterns (e.g. S-1-5-80-*) whose rights edges are suppressed, in addition to the default service SID filter")
+	rootCmd.Flags().StringVar(&localPrincipals, "local-principals", "", "How to treat rights granted to the scanned host's own local SAM accounts: \"tag\" adds a localPrincipal edge property, \"skip\" drops the edges; requires --auth-domain and --auth-dc-ip")
+	rootCmd.Flags().BoolVar(&rollupEdges, "rollup-edges", false, "Emit a single HasNTFSAccess edge per principal with a rights list property instead of one edge per NTFS flag")
+	rootCmd.Flags().StringVar(&edgeKinds, "edge-kinds", "", "Comma-separated permission edge kinds to collect (e.g. CanGenericWrite,CanNTFSWriteDacl); all others are skipped")
+	rootCmd.Flags().StringVar(&excludeEdgeKinds, "exclude-edge-kinds", "", "Comma-separated permission edge kinds to skip during collection")
+	rootCmd.Flags().BoolVar(&minimalProperties, "minimal-properties", false, "Omit timestamps, sizes and descriptions from file/directory nodes (structure and rights only)")
+	rootCmd.Flags().BoolVar(&pruneEmpty, "prune-empty", false, "Remove file/directory nodes without rights edges anywhere in their subtree before export")
+	rootCmd.Flags().IntVar(&maxNodes, "max-nodes", 0, "Degrade to directory-level collection once the graph holds this many nodes (0 = unlimited)")
+	rootCmd.Flags().IntVar(&maxEdges, "max-edges", 0, "Degrade to directory-level collection once the graph holds this many edges (0 = unlimited)")
+	rootCmd.Flags().BoolVar(&fastExport, "fast-export", false, "Skip per-line JSON revalidation during export (faster for very large graphs)")
+	rootCmd.Flags().StringVar(&omitProperties, "omit-properties", "", "Comma-separated node properties to omit, overriding the --minimal-properties default set")
+	rootCmd.Flags().StringVar(&alertWebhook, "alert-webhook", "", "URL receiving a JSON POST whenever a world-writable share is discovered")
+	rootCmd.Flags().IntVar(&maxAuthFailures, "max-auth-failures", 3, "Suspend the credential after this many consecutive authentication failures to prevent account lockout (0 = never)")
+	rootCmd.Flags().BoolVar(&localGroups, "local-groups", false, "Sample local Administrators and Remote Desktop Users membership via SAMR, emitting AdminTo/CanRDP edges (disabled under strict OPSEC)")
+	rootCmd.Flags().BoolVar(&canonicalIDs, "canonical-ids", false, "Build node IDs from the reverse-resolved, case-folded FQDN so repeated scans update nodes instead of duplicating them")
+	rootCmd.Flags().BoolVar(&rawSDDL, "raw-sddl", false, "Store each collected security descriptor as an SDDL string on its node so the exact ACL text can be re-verified")
+	rootCmd.Flags().StringVar(&ignorePrincipals, "ignore-principal", "", "Comma-separated principal names (e.g. \"NT SERVICE\\\\MSSQLSERVER\", \"TrustedInstaller\") whose rights edges are suppressed")
+
+	// Campaign options
+	rootCmd.Flags().StringVar(&campaignName, "campaign", "", "Campaign name; when set, the scan is recorded in the campaign store with a persistent scan ID")
+	rootCmd.Flags().StringVar(&campaignDir, "campaign-dir", "", "Campaign store directory (default ~/.sharehound/scans)")
+
+	// Distributed collection
+	rootCmd.Flags().StringVar(&aggregatorAddr, "aggregator", "", "Stream results to the central aggregator at this host:port after the scan (see \"sharehound aggregator\")")
+	rootCmd.Flags().StringVar(&aggregatorCert, "aggregator-cert", "", "Agent certificate (PEM) for mutual TLS with the aggregator")
+	rootCmd.Flags().StringVar(&aggregatorKey, "aggregator-key", "", "Agent private key (PEM)")
+	rootCmd.Flags().StringVar(&aggregatorCA, "aggregator-ca", "", "CA certificate (PEM) the aggregator's certificate must chain to")
+	rootCmd.Flags().StringVar(&agentName, "agent-name", "", "Agent name reported to the aggregator (default: hostname)")
+
+	// Live results stream
+	rootCmd.Flags().StringVar(&streamListen, "stream-listen", "", "Stream nodes, edges and progress events live to subscribers on this address (newline-delimited JSON)")
+	rootCmd.Flags().StringVar(&streamCert, "stream-cert", "", "Stream server certificate (PEM); with --stream-key and --stream-ca, subscribers must present a certificate signed by the CA")
+	rootCmd.Flags().StringVar(&streamKey, "stream-key", "", "Stream server private key (PEM)")
+	rootCmd.Flags().StringVar(&streamCA, "stream-ca", "", "CA certificate (PEM) subscriber certificates must chain to")
+
+	// Audit options
+	rootCmd.Flags().StringVar(&auditLogPath, "audit-log", "", "Append-only hash-chained log of every host connected, share mounted and file opened")
+	rootCmd.Flags().StringVar(&skipReportPath, "skip-report", "", "Write a JSON report of shares and subtrees skipped by rules, depth limits or host timeouts")
+	rootCmd.Flags().StringVar(&perHostResultsDir, "per-host-results", "", "Directory for per-host result JSON files, written as each host finishes")
+	rootCmd.Flags().StringVar(&findingsSTIXPath, "findings-stix", "", "Write notable findings (world-writable shares, likely secrets, admin-share access) as a STIX 2.1 bundle")
+	rootCmd.Flags().StringVar(&findingsOCSFPath, "findings-ocsf", "", "Write notable findings as OCSF Detection Finding events")
+	rootCmd.Flags().StringVar(&findingsSARIFPath, "findings-sarif", "", "Write notable findings as a SARIF 2.1.0 log for CI pipeline gating; high severity maps to \"error\"")
+	rootCmd.Flags().StringVar(&policyPath, "policy", "", "Evaluate the YAML policy file's share-permission assertions post-scan and exit non-zero on violations")
+
+	// Runtime control
+	rootCmd.Flags().StringVar(&controlSocket, "control-socket", "", "Listen on this local socket for runtime commands (see \"sharehound ctl\")")
+	rootCmd.Flags().StringVar(&pprofListen, "pprof-listen", "", "Expose net/http/pprof (profiles and on-demand execution traces) on this address, e.g. 127.0.0.1:6060")
+	rootCmd.Flags().StringVar(&profileOutput, "profile-output", "", "Write a CPU profile covering the whole scan to this file")
+
+	// OPSEC options
+	rootCmd.Flags().StringVar(&opsecProfile, "opsec-profile", "", "OPSEC profile; \"strict\" randomizes target order, jitters and rate-limits connections, and avoids SRVSVC RPC calls")
+	rootCmd.Flags().BoolVar(&registrySDFallback, "registry-sd-fallback", false, "Read share security descriptors from the LanmanServer registry key (Remote Registry) when SRVSVC denies them")
+	rootCmd.Flags().BoolVar(&shuffleTargets, "shuffle-targets", false, "Randomize target order before scanning")
+	rootCmd.Flags().IntVar(&maxHostsPerSubnet, "max-hosts-per-subnet", 0, "Maximum hosts scanned concurrently per /24 subnet, to protect slow site links (0 = no cap)")
+	rootCmd.Flags().BoolVar(&dedupeTargets, "dedupe-targets", false, "Resolve targets before scanning and drop aliases (IP, short name, FQDN) of already-listed servers")
+	rootCmd.Flags().StringVar(&orderBy, "order-by", "", "Target ordering: random, subnet-interleaved or ad-site")
+
+	rootCmd.AddCommand(newHistoryCommand())
+	rootCmd.AddCommand(newVerifyAuditCommand())
+	rootCmd.AddCommand(newCtlCommand())
+	rootCmd.AddCommand(newRulesCommand())
+	rootCmd.AddCommand(newCheckCommand())
+	rootCmd.AddCommand(newSelftestCommand())
+	rootCmd.AddCommand(newShellCommand())
+	rootCmd.AddCommand(newDaemonCommand())
+	rootCmd.AddCommand(newAggregatorCommand())
+	rootCmd.AddCommand(newReportCommand())
+	rootCmd.AddCommand(newDiffCommand())
+	rootCmd.AddCommand(newSchemaCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -146,6 +355,925 @@ creating a BloodHound-compatible OpenGraph for security analysis.`,
 	}
 }
 
+// resolvePassword fills authPassword from interactive prompting, a file
+// descriptor, or the SHAREHOUND_PASSWORD environment variable so the
+// password never has to appear in shell history or process listings.
+// An explicit --auth-password always wins.
+func resolvePassword() error {
+	if authPassword != "" {
+		return nil
+	}
+
+	if askPassword {
+		fmt.Fprint(os.Stderr, "Password: ")
+		pw, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return fmt.Errorf("failed to read password from terminal: %w", err)
+		}
+		authPassword = string(pw)
+		return nil
+	}
+
+	if passwordFd >= 0 {
+		f := os.NewFile(uintptr(passwordFd), "password-fd")
+		if f == nil {
+			return fmt.Errorf("invalid password file descriptor: %d", passwordFd)
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return fmt.Errorf("failed to read password from fd %d: %w", passwordFd, err)
+		}
+		authPassword = strings.TrimRight(string(data), "\r\n")
+		return nil
+	}
+
+	if pw := os.Getenv("SHAREHOUND_PASSWORD"); pw != "" {
+		authPassword = pw
+	}
+
+	return nil
+}
+
+// resolveIgnoredPrincipal converts a principal name into a SID or SID pattern
+// for the ignore list. "NT SERVICE\name" is derived via the service SID
+// algorithm ("NT SERVICE\*" matches all service SIDs); other names are looked
+// up among the well-known SIDs. Returns "" when the name cannot be resolved.
+func resolveIgnoredPrincipal(name string) string {
+	if svc, ok := strings.CutPrefix(strings.ToUpper(name), "NT SERVICE\\"); ok {
+		if svc == "*" {
+			return "S-1-5-80-*"
+		}
+		return smb.ServiceSID(svc)
+	}
+
+	if strings.EqualFold(name, "TrustedInstaller") {
+		return smb.TrustedInstallerSID
+	}
+
+	for sid, known := range smb.WellKnownSIDs {
+		if strings.EqualFold(known, name) {
+			return sid
+		}
+	}
+
+	return ""
+}
+
+// newHistoryCommand builds the "history" subcommand, which lists past scans
+// recorded in the campaign store.
+func newHistoryCommand() *cobra.Command {
+	var historyDir string
+	var historyCampaign string
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List past scans recorded in the campaign store",
+		Run: func(cmd *cobra.Command, args []string) {
+			store := campaign.NewStore(historyDir)
+			records, err := store.List()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			records = campaign.FilterByCampaign(records, historyCampaign)
+
+			if len(records) == 0 {
+				fmt.Println("No recorded scans")
+				return
+			}
+
+			fmt.Printf("%-22s %-16s %-20s %-9s %6s %7s %8s  %s\n",
+				"ID", "CAMPAIGN", "STARTED", "DURATION", "HOSTS", "SHARES", "FILES", "OUTPUT")
+			for _, rec := range records {
+				fmt.Printf("%-22s %-16s %-20s %-9s %6d %7d %8d  %s\n",
+					rec.ID,
+					rec.Campaign,
+					rec.StartedAt.Format("2006-01-02 15:04:05"),
+					rec.FinishedAt.Sub(rec.StartedAt).Round(time.Second),
+					rec.Hosts,
+					rec.Shares,
+					rec.Files,
+					rec.OutputFile,
+				)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&historyDir, "campaign-dir", "", "Campaign store directory (default ~/.sharehound/scans)")
+	cmd.Flags().StringVar(&historyCampaign, "campaign", "", "Only list scans from this campaign")
+	return cmd
+}
+
+// newReportCommand builds the "report" subcommand, which renders a
+// standalone HTML report from a recorded scan or an exported graph file.
+func newReportCommand() *cobra.Command {
+	var (
+		reportOutput string
+		reportDir    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report <scan-id|graph-file>",
+		Short: "Render a standalone HTML report from a scan's graph data",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ref := args[0]
+
+			// A path to an exported graph file is used directly; anything
+			// else is resolved through the campaign store
+			source := ref
+			graphFile := ref
+			if _, err := os.Stat(ref); err != nil {
+				store := campaign.NewStore(reportDir)
+				rec, err := store.Get(ref)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				source = rec.ID
+				graphFile = rec.OutputFile
+			}
+
+			nodes, edges, err := graph.LoadExport(graphFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			data := report.Build(nodes, edges, source, time.Now().Format("2006-01-02 15:04"))
+
+			file, err := os.Create(reportOutput)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			if err := report.Render(file, data); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			fmt.Printf("[+] Report written to \"%s\" (%d hosts, %d shares)\n", reportOutput, data.Hosts, data.Shares)
+		},
+	}
+
+	cmd.Flags().StringVarP(&reportOutput, "output", "o", "sharehound-report.html", "Report output file")
+	cmd.Flags().StringVar(&reportDir, "campaign-dir", "", "Campaign store directory (default ~/.sharehound/scans)")
+	return cmd
+}
+
+// newDiffCommand builds the "diff" subcommand, which compares the share
+// permissions of two scans and alerts on widened access. The change feed is
+// stored next to the newer scan's metadata when it came from the campaign
+// store, so drift stays reviewable per scan.
+func newDiffCommand() *cobra.Command {
+	var (
+		diffDir     string
+		diffOutput  string
+		diffWebhook string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <old scan-id|graph-file> <new scan-id|graph-file>",
+		Short: "Compare two scans and alert on widened share permissions",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			oldRef, newRef := args[0], args[1]
+			store := campaign.NewStore(diffDir)
+
+			oldFile, err := store.Resolve(oldRef)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			newFile, err := store.Resolve(newRef)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			oldNodes, oldEdges, err := graph.LoadExport(oldFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			newNodes, newEdges, err := graph.LoadExport(newFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			events := drift.Compare(oldNodes, oldEdges, newNodes, newEdges)
+			for _, ev := range events {
+				switch {
+				case ev.Severity == drift.SeverityHigh:
+					fmt.Printf("[!] ACCESS WIDENED %s: %s gained %s\n", ev.UNCPath, ev.Principal, ev.Right)
+				case ev.Change == "granted":
+					fmt.Printf("[*] granted %s: %s gained %s\n", ev.UNCPath, ev.Principal, ev.Right)
+				default:
+					fmt.Printf("[*] revoked %s: %s lost %s\n", ev.UNCPath, ev.Principal, ev.Right)
+				}
+			}
+			if len(events) == 0 {
+				fmt.Println("[+] No share permission changes between the two scans")
+			}
+
+			feed := &drift.Feed{
+				GeneratedAt: time.Now().UTC(),
+				OldScan:     oldRef,
+				NewScan:     newRef,
+				Events:      events,
+			}
+			feedPath := diffOutput
+			if feedPath == "" {
+				if _, err := store.Get(newRef); err == nil {
+					feedPath = store.SidecarPath(newRef, "permchanges.json")
+				} else {
+					feedPath = "sharehound-permchanges.json"
+				}
+			}
+			if err := drift.WriteFeed(feedPath, feed); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			widened := drift.Widened(events)
+			fmt.Printf("[+] Change feed written to \"%s\" (%d events, %d widened)\n", feedPath, len(events), widened)
+
+			if diffWebhook != "" {
+				cfg := config.NewConfig(false, &noColors)
+				log := logger.NewLogger(cfg, "")
+				drift.Notify(diffWebhook, events, log)
+			}
+
+			// Exit 2 distinguishes "access widened" from runtime errors, so
+			// monitoring pipelines can page on it
+			if widened > 0 {
+				os.Exit(2)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&diffDir, "campaign-dir", "", "Campaign store directory (default ~/.sharehound/scans)")
+	cmd.Flags().StringVarP(&diffOutput, "output", "o", "", "Change feed output file (default stored next to the newer scan's metadata)")
+	cmd.Flags().StringVar(&diffWebhook, "webhook", "", "Webhook URL notified when access widened")
+	return cmd
+}
+
+// newSchemaCommand builds the "schema" subcommand, which emits the OpenGraph
+// custom node kind configuration (icons, colors, display names) for the
+// kinds ShareHound produces, in the shape BloodHound's custom-kind API
+// expects (POST /api/v2/custom-nodes), so imported data renders nicely out
+// of the box.
+func newSchemaCommand() *cobra.Command {
+	var schemaOutput string
+
+	type schemaIcon struct {
+		Type  string `json:"type"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+	type schemaKind struct {
+		DisplayName string     `json:"displayName"`
+		Icon        schemaIcon `json:"icon"`
+	}
+
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Emit the BloodHound custom node kind configuration for ShareHound data",
+		Run: func(cmd *cobra.Command, args []string) {
+			bundle := map[string]map[string]schemaKind{
+				"custom_types": {
+					kinds.NodeKindNetworkShareHost: {
+						DisplayName: "Network Share Host",
+						Icon:        schemaIcon{Type: "font-awesome", Name: "server", Color: "#8B4FD8"},
+					},
+					kinds.NodeKindNetworkShareSMB: {
+						DisplayName: "SMB Share",
+						Icon:        schemaIcon{Type: "font-awesome", Name: "share-nodes", Color: "#4F8BD8"},
+					},
+					kinds.NodeKindNetworkShareDFS: {
+						DisplayName: "DFS Share",
+						Icon:        schemaIcon{Type: "font-awesome", Name: "network-wired", Color: "#4F8BD8"},
+					},
+					kinds.NodeKindDirectory: {
+						DisplayName: "Directory",
+						Icon:        schemaIcon{Type: "font-awesome", Name: "folder", Color: "#D8A44F"},
+					},
+					kinds.NodeKindFile: {
+						DisplayName: "File",
+						Icon:        schemaIcon{Type: "font-awesome", Name: "file", Color: "#6FAF6F"},
+					},
+					kinds.NodeKindShareHoundScan: {
+						DisplayName: "ShareHound Scan",
+						Icon:        schemaIcon{Type: "font-awesome", Name: "magnifying-glass", Color: "#808080"},
+					},
+				},
+			}
+
+			data, err := json.MarshalIndent(bundle, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			data = append(data, '\n')
+
+			if schemaOutput == "" {
+				os.Stdout.Write(data)
+				return
+			}
+			if err := os.WriteFile(schemaOutput, data, 0o644); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			fmt.Printf("[+] Schema bundle written to \"%s\"\n", schemaOutput)
+		},
+	}
+
+	cmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "Write the bundle to a file instead of stdout")
+	return cmd
+}
+
+// newVerifyAuditCommand builds the "verify-audit" subcommand, which checks the
+// hash chain of an audit log written with --audit-log.
+func newVerifyAuditCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify-audit <file>",
+		Short: "Verify the hash chain of an audit log",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := audit.VerifyFile(args[0]); err != nil {
+				fmt.Fprintf(os.Stderr, "[-] Audit log verification failed: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("[+] Audit log %s is intact\n", args[0])
+		},
+	}
+}
+
+// newCtlCommand builds the "ctl" subcommand, which sends a runtime command
+// to a scan started with --control-socket.
+func newCtlCommand() *cobra.Command {
+	var socketPath string
+	cmd := &cobra.Command{
+		Use:   "ctl <pause|resume|status|skip-host HOST|set KEY VALUE>",
+		Short: "Control a running scan over its control socket",
+		Args:  cobra.RangeArgs(1, 3),
+		Run: func(cmd *cobra.Command, args []string) {
+			reply, err := control.Send(socketPath, strings.Join(args, " "))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[-] %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(reply)
+		},
+	}
+	cmd.Flags().StringVar(&socketPath, "control-socket", control.DefaultSocketPath(), "Path to the control socket of the running scan")
+	return cmd
+}
+
+// newRulesCommand builds the "rules" subcommand group for working with
+// ShareQL rule sets without running a scan.
+func newRulesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Inspect and validate ShareQL rule sets",
+	}
+	cmd.AddCommand(newRulesLintCommand())
+	return cmd
+}
+
+// newRulesLintCommand builds "rules lint", which parses rules files (following
+// INCLUDE directives) and reports unreachable rules, unknown fields, and
+// conditions that can never match.
+func newRulesLintCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint <rules-file>...",
+		Short: "Statically analyze rules files for unreachable or always-false rules",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			parser := rules.NewParser()
+			failed := false
+
+			for _, file := range args {
+				parsed, _, errs := parser.ParseFile(file)
+				if len(errs) > 0 {
+					for _, e := range errs {
+						fmt.Fprintf(os.Stderr, "[-] %v\n", e)
+					}
+					failed = true
+					continue
+				}
+
+				warnings := rules.Lint(parsed)
+				if len(warnings) == 0 {
+					fmt.Printf("[+] %s: %d rules, no warnings\n", file, len(parsed))
+					continue
+				}
+				for _, w := range warnings {
+					fmt.Printf("[!] %s\n", w)
+				}
+				failed = true
+			}
+
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+// newCheckCommand builds the "check" subcommand, which walks through DNS
+// resolution, port reachability, authentication, share enumeration and one
+// ACL query against a single target, printing a pass/fail breakdown. Useful
+// for answering "why is my scan producing empty graphs" before committing to
+// a multi-hour run.
+func newCheckCommand() *cobra.Command {
+	var (
+		checkTarget     string
+		checkShare      string
+		checkDomain     string
+		checkDCIP       string
+		checkUser       string
+		checkPassword   string
+		checkHashes     string
+		checkKerberos   bool
+		checkLocalAuth  bool
+		checkNameserver string
+		checkPort       int
+		checkTimeout    float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Validate DNS, connectivity, credentials and ACL access against a single target",
+		Run: func(cmd *cobra.Command, args []string) {
+			timeout := time.Duration(checkTimeout * float64(time.Second))
+			failed := false
+			fail := func(step string, err error) {
+				fmt.Printf("[-] %-16s %v\n", step+":", err)
+				failed = true
+			}
+			pass := func(step, detail string) {
+				fmt.Printf("[+] %-16s %s\n", step+":", detail)
+			}
+
+			// DNS resolution
+			host := checkTarget
+			if utils.IsIPv4Addr(checkTarget) || utils.IsIPv6Addr(checkTarget) {
+				pass("DNS", "skipped (target is an IP address)")
+			} else {
+				resolved, err := utils.DNSResolve(checkTarget, checkNameserver, checkDCIP, timeout)
+				if err != nil || resolved == "" {
+					if err == nil {
+						err = fmt.Errorf("no address returned")
+					}
+					fail("DNS", err)
+					os.Exit(1)
+				}
+				pass("DNS", checkTarget+" -> "+resolved)
+				host = resolved
+			}
+
+			// Port reachability. An unreachable port is reported but not
+			// fatal: when 445 is filtered the session below still falls
+			// back to NetBIOS on 139, so Connect gets the final word.
+			portLabel := fmt.Sprintf("Port %d", checkPort)
+			portOpen, portErr := utils.IsPortOpen(host, checkPort, timeout)
+			fallback139 := false
+			if !portOpen && checkPort == smb.PortSMBDirect {
+				fallback139, _ = utils.IsPortOpen(host, smb.PortNetBIOS, timeout)
+			}
+			switch {
+			case portOpen:
+				pass(portLabel, "open")
+			case fallback139:
+				pass(portLabel, "filtered, but 139 is open (NetBIOS session fallback)")
+			default:
+				if portErr == nil {
+					portErr = fmt.Errorf("connection failed")
+				}
+				fail(portLabel, portErr)
+			}
+
+			// Authentication
+			creds := credentials.NewCredentials(checkDomain, checkUser, checkPassword, &checkHashes, checkKerberos, false, new(string), new(string))
+			creds.LocalAuth = checkLocalAuth
+
+			cfg := config.NewConfig(false, &noColors)
+			log := logger.NewLogger(cfg, "")
+			session := smb.NewSMBSession(host, checkPort, timeout, creds, checkTarget, "", cfg, log)
+			if err := session.Connect(); err != nil {
+				fail("Authentication", err)
+				os.Exit(1)
+			}
+			defer session.Close()
+			identity := checkUser
+			if checkDomain != "" {
+				identity = checkDomain + "\\" + checkUser
+			}
+			pass("Authentication", "session established as "+identity)
+
+			// Share enumeration
+			shares, err := session.ListShares()
+			if err != nil {
+				fail("Share listing", err)
+				os.Exit(1)
+			}
+			names := make([]string, 0, len(shares))
+			for _, info := range shares {
+				names = append(names, info.Name)
+			}
+			sort.Strings(names)
+			pass("Share listing", fmt.Sprintf("%d share(s): %s", len(names), strings.Join(names, ", ")))
+
+			// One ACL query, against --share or the first non-hidden share
+			aclShare := checkShare
+			if aclShare == "" {
+				for _, name := range names {
+					if !strings.HasSuffix(name, "$") {
+						aclShare = name
+						break
+					}
+				}
+			}
+			if aclShare == "" {
+				fail("ACL query", fmt.Errorf("no candidate share; pass one with --share"))
+			} else if sdBytes, err := session.GetShareSecurityDescriptor(aclShare); err != nil {
+				fail("ACL query", fmt.Errorf("share %s: %w", aclShare, err))
+			} else if sd, err := smb.ParseSecurityDescriptor(sdBytes); err != nil {
+				fail("ACL query", fmt.Errorf("share %s: %w", aclShare, err))
+			} else {
+				aces := 0
+				if sd.Dacl != nil {
+					aces = len(sd.Dacl.Aces)
+				}
+				pass("ACL query", fmt.Sprintf("share %s: security descriptor with %d ACE(s)", aclShare, aces))
+			}
+
+			if failed {
+				fmt.Println("[-] Some checks failed")
+				os.Exit(1)
+			}
+			fmt.Println("[+] All checks passed")
+		},
+	}
+
+	cmd.Flags().StringVar(&checkTarget, "target", "", "Target host (IP or FQDN) to validate against")
+	cmd.Flags().StringVar(&checkShare, "share", "", "Share to query the ACL of (default: first non-hidden share)")
+	cmd.Flags().StringVar(&checkDomain, "auth-domain", "", "Windows domain to authenticate to")
+	cmd.Flags().StringVar(&checkDCIP, "auth-dc-ip", "", "IP of the domain controller (used as DNS fallback)")
+	cmd.Flags().StringVar(&checkUser, "auth-user", "", "Username of the domain account")
+	cmd.Flags().StringVar(&checkPassword, "auth-password", "", "Password of the domain account")
+	cmd.Flags().StringVar(&checkHashes, "auth-hashes", "", "LM:NT hashes for pass-the-hash")
+	cmd.Flags().BoolVarP(&checkKerberos, "use-kerberos", "k", false, "Use Kerberos authentication")
+	cmd.Flags().BoolVar(&checkLocalAuth, "local-auth", false, "Authenticate against local accounts (uses the target hostname as the NTLM domain)")
+	cmd.Flags().StringVar(&checkNameserver, "nameserver", "", "Nameserver for DNS queries")
+	cmd.Flags().IntVar(&checkPort, "port", 445, "TCP port to reach SMB on; when 445 is filtered, 139 is tried automatically with NetBIOS session setup")
+	cmd.Flags().Float64Var(&checkTimeout, "timeout", 10, "Timeout in seconds for each step")
+	cmd.MarkFlagRequired("target")
+	return cmd
+}
+
+// newSelftestCommand builds the "selftest" subcommand, which runs a bundled
+// expectation suite against the Samba fixture in test/samba (docker compose
+// up). It drives the full collection pipeline — connection, share
+// enumeration, ACL collection, traversal, graph emission — and asserts that
+// the produced graph contains the nodes and edges the fixture guarantees.
+func newSelftestCommand() *cobra.Command {
+	var (
+		selftestTarget   string
+		selftestUser     string
+		selftestPassword string
+		selftestTimeout  float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Run the bundled expectation suite against the Samba fixture in test/samba",
+		Run: func(cmd *cobra.Command, args []string) {
+			hashes, aesKey, kdcHost := "", "", ""
+			creds := credentials.NewCredentials("", selftestUser, selftestPassword, &hashes, false, false, &aesKey, &kdcHost)
+
+			cfg := config.NewConfig(false, &noColors)
+			og, err := graph.NewOpenGraph(kinds.NodeKindNetworkShareBase)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[-] Failed to create graph: %v\n", err)
+				os.Exit(1)
+			}
+			defer og.Close()
+
+			fmt.Printf("[*] Collecting from fixture at %s (user %s)\n", selftestTarget, selftestUser)
+			opts := &worker.Options{
+				Creds:             creds,
+				Timeout:           time.Duration(selftestTimeout * float64(time.Second)),
+				MaxWorkersPerHost: 2,
+				GlobalMaxWorkers:  2,
+				Depth:             5,
+			}
+			results := &collector.WorkerResults{}
+			worker.ProcessTarget(targets.Target{Type: "ipv4", Value: selftestTarget}, opts, cfg, og, nil, results)
+
+			data, err := og.ToJSON()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[-] Failed to serialize graph: %v\n", err)
+				os.Exit(1)
+			}
+			nodeIDs, edgeKindCounts, err := summarizeGraphJSON(data)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[-] Failed to parse graph output: %v\n", err)
+				os.Exit(1)
+			}
+
+			failed := 0
+			expectNode := func(desc, id string) {
+				if nodeIDs[id] {
+					fmt.Printf("[+] %s: %s\n", desc, id)
+				} else {
+					fmt.Printf("[-] %s: missing node %s\n", desc, id)
+					failed++
+				}
+			}
+			expectEdges := func(kind string, minCount int) {
+				if edgeKindCounts[kind] >= minCount {
+					fmt.Printf("[+] %s edges: %d\n", kind, edgeKindCounts[kind])
+				} else {
+					fmt.Printf("[-] %s edges: %d, expected at least %d\n", kind, edgeKindCounts[kind], minCount)
+					failed++
+				}
+			}
+
+			unc := func(parts ...string) string {
+				return "\\\\" + selftestTarget + "\\" + strings.Join(parts, "\\")
+			}
+			expectNode("Host node", "NSHOST:"+selftestTarget)
+			expectNode("Public share", unc("public")+"\\")
+			expectNode("Read-only share", unc("readonly")+"\\")
+			expectNode("Restricted share", unc("secret")+"\\")
+			expectNode("Seeded file", "FILE:"+unc("public", "readme.txt"))
+			expectNode("Nested directory", "DIR:"+unc("public", "reports"))
+			expectNode("Nested file", "FILE:"+unc("public", "reports", "q1.txt"))
+			expectEdges(kinds.EdgeKindHasNetworkShare, 3)
+			expectEdges(kinds.EdgeKindContains, 3)
+
+			if failed > 0 {
+				fmt.Printf("[-] Selftest failed: %d expectation(s) not met (is the fixture running? cd test/samba && docker compose up -d)\n", failed)
+				os.Exit(1)
+			}
+			fmt.Println("[+] Selftest passed: all expectations met")
+		},
+	}
+
+	cmd.Flags().StringVar(&selftestTarget, "target", "127.0.0.1", "Address the Samba fixture is listening on")
+	cmd.Flags().StringVar(&selftestUser, "auth-user", "shareuser", "Fixture account to authenticate as")
+	cmd.Flags().StringVar(&selftestPassword, "auth-password", "sharehound", "Password of the fixture account")
+	cmd.Flags().Float64Var(&selftestTimeout, "timeout", 10, "Timeout in seconds for SMB operations")
+	return cmd
+}
+
+// summarizeGraphJSON extracts the set of node IDs and per-kind edge counts
+// from serialized OpenGraph output.
+func summarizeGraphJSON(data []byte) (map[string]bool, map[string]int, error) {
+	var output struct {
+		Graph struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+			Edges []struct {
+				Kind string `json:"kind"`
+			} `json:"edges"`
+		} `json:"graph"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, nil, err
+	}
+
+	nodeIDs := make(map[string]bool, len(output.Graph.Nodes))
+	for _, n := range output.Graph.Nodes {
+		nodeIDs[n.ID] = true
+	}
+	edgeKindCounts := make(map[string]int)
+	for _, e := range output.Graph.Edges {
+		edgeKindCounts[e.Kind]++
+	}
+	return nodeIDs, edgeKindCounts, nil
+}
+
+// newAggregatorCommand builds the "aggregator" subcommand: the central end
+// of distributed collection. It accepts mutually authenticated TLS
+// connections from collector agents, merges their streamed results into one
+// OpenGraph and exports it on shutdown.
+func newAggregatorCommand() *cobra.Command {
+	var (
+		aggListen string
+		aggCert   string
+		aggKey    string
+		aggCA     string
+		aggOutput string
+		aggDebug  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "aggregator",
+		Short: "Receive and merge results streamed by collector agents",
+		Run: func(cmd *cobra.Command, args []string) {
+			noColors := false
+			cfg := config.NewConfig(aggDebug, &noColors)
+			log := logger.NewLogger(cfg, "")
+
+			tlsConfig, err := distrib.ServerTLSConfig(aggCert, aggKey, aggCA)
+			if err != nil {
+				log.Error(fmt.Sprintf("Invalid TLS configuration: %v", err))
+				os.Exit(1)
+			}
+
+			og, err := graph.NewOpenGraph(kinds.NodeKindNetworkShareBase)
+			if err != nil {
+				log.Error(fmt.Sprintf("Failed to create graph: %v", err))
+				os.Exit(1)
+			}
+			defer og.Close()
+
+			agg := distrib.NewAggregator(og, log)
+			if err := agg.Start(aggListen, tlsConfig); err != nil {
+				log.Error(fmt.Sprintf("Failed to start aggregator: %v", err))
+				os.Exit(1)
+			}
+			log.Info(fmt.Sprintf("Aggregator listening on %s", aggListen))
+
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+			sig := <-sigChan
+			log.Warning(fmt.Sprintf("Received signal %v, waiting for active agents and exporting...", sig))
+			agg.Close()
+
+			log.Info(fmt.Sprintf("Exporting merged graph to \"%s\" (%d nodes, %d edges)", aggOutput, og.GetNodeCount(), og.GetEdgeCount()))
+			if err := og.ExportToFile(aggOutput, true); err != nil {
+				log.Error(fmt.Sprintf("Failed to export graph: %v", err))
+				os.Exit(1)
+			}
+			log.Info("Merged graph exported")
+		},
+	}
+
+	cmd.Flags().StringVar(&aggListen, "listen", ":8445", "Address to listen on for agent connections")
+	cmd.Flags().StringVar(&aggCert, "tls-cert", "", "Aggregator certificate (PEM)")
+	cmd.Flags().StringVar(&aggKey, "tls-key", "", "Aggregator private key (PEM)")
+	cmd.Flags().StringVar(&aggCA, "tls-ca", "", "CA certificate (PEM) agent certificates must chain to")
+	cmd.Flags().StringVarP(&aggOutput, "output", "o", "opengraph.zip", "Merged output file (use .zip extension for compression)")
+	cmd.Flags().BoolVar(&aggDebug, "debug", false, "Debug mode")
+	cmd.MarkFlagRequired("tls-cert")
+	cmd.MarkFlagRequired("tls-key")
+	cmd.MarkFlagRequired("tls-ca")
+
+	return cmd
+}
+
+// newDaemonCommand builds the "daemon" subcommand: recurring scans driven by
+// a persistent configuration file, for running ShareHound as a systemd unit
+// or Windows service without an external cron wrapper.
+func newDaemonCommand() *cobra.Command {
+	var (
+		daemonConfig string
+		daemonDebug  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Run recurring scans on a schedule from a persistent configuration file",
+		Run: func(cmd *cobra.Command, args []string) {
+			dcfg, err := daemon.LoadConfig(daemonConfig)
+			if err != nil {
+				fmt.Printf("[!] Invalid daemon configuration: %v\n", err)
+				os.Exit(1)
+			}
+
+			noColors := false
+			cfg := config.NewConfig(daemonDebug, &noColors)
+			log := logger.NewLogger(cfg, "")
+
+			d, err := daemon.New(dcfg, log)
+			if err != nil {
+				log.Error(fmt.Sprintf("Failed to initialize daemon: %v", err))
+				os.Exit(1)
+			}
+
+			stop := make(chan struct{})
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				sig := <-sigChan
+				log.Warning(fmt.Sprintf("Received signal %v, stopping daemon...", sig))
+				close(stop)
+			}()
+
+			log.Info(fmt.Sprintf("Daemon started, scan interval %s", dcfg.Interval))
+			if err := d.Run(stop); err != nil {
+				log.Error(fmt.Sprintf("Daemon failed: %v", err))
+				os.Exit(1)
+			}
+			log.Info("Daemon stopped")
+		},
+	}
+
+	cmd.Flags().StringVar(&daemonConfig, "config", "", "Path to the daemon configuration file (JSON)")
+	cmd.MarkFlagRequired("config")
+	cmd.Flags().BoolVar(&daemonDebug, "debug", false, "Debug mode")
+
+	return cmd
+}
+
+// newShellCommand builds the "shell" subcommand: an interactive prompt over
+// a single authenticated SMB session (ls, cd, acl, shares, get) for manually
+// verifying findings without re-authenticating through another client.
+func newShellCommand() *cobra.Command {
+	var (
+		shellTarget     string
+		shellDomain     string
+		shellDCIP       string
+		shellUser       string
+		shellPassword   string
+		shellHashes     string
+		shellKerberos   bool
+		shellLocalAuth  bool
+		shellNameserver string
+		shellPort       int
+		shellTimeout    float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Open an interactive exploration prompt on a single host",
+		Run: func(cmd *cobra.Command, args []string) {
+			timeout := time.Duration(shellTimeout * float64(time.Second))
+
+			host := shellTarget
+			if !utils.IsIPv4Addr(shellTarget) && !utils.IsIPv6Addr(shellTarget) {
+				resolved, err := utils.DNSResolve(shellTarget, shellNameserver, shellDCIP, timeout)
+				if err != nil || resolved == "" {
+					fmt.Fprintf(os.Stderr, "[-] Failed to resolve %s: %v\n", shellTarget, err)
+					os.Exit(1)
+				}
+				host = resolved
+			}
+
+			creds := credentials.NewCredentials(shellDomain, shellUser, shellPassword, &shellHashes, shellKerberos, false, new(string), new(string))
+			creds.LocalAuth = shellLocalAuth
+
+			cfg := config.NewConfig(false, &noColors)
+			log := logger.NewLogger(cfg, "")
+			session := smb.NewSMBSession(host, shellPort, timeout, creds, shellTarget, "", cfg, log)
+			if err := session.Connect(); err != nil {
+				fmt.Fprintf(os.Stderr, "[-] Failed to connect to %s: %v\n", shellTarget, err)
+				os.Exit(1)
+			}
+			defer session.Close()
+
+			fmt.Printf("[+] Connected to %s\n", shellTarget)
+			if err := shell.New(session, shellTarget, os.Stdout).Run(os.Stdin); err != nil {
+				fmt.Fprintf(os.Stderr, "[-] %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&shellTarget, "target", "", "Target host (IP or FQDN) to connect to")
+	cmd.Flags().StringVar(&shellDomain, "auth-domain", "", "Windows domain to authenticate to")
+	cmd.Flags().StringVar(&shellDCIP, "auth-dc-ip", "", "IP of the domain controller (used as DNS fallback)")
+	cmd.Flags().StringVar(&shellUser, "auth-user", "", "Username of the domain account")
+	cmd.Flags().StringVar(&shellPassword, "auth-password", "", "Password of the domain account")
+	cmd.Flags().StringVar(&shellHashes, "auth-hashes", "", "LM:NT hashes for pass-the-hash")
+	cmd.Flags().BoolVarP(&shellKerberos, "use-kerberos", "k", false, "Use Kerberos authentication")
+	cmd.Flags().BoolVar(&shellLocalAuth, "local-auth", false, "Authenticate against local accounts (uses the target hostname as the NTLM domain)")
+	cmd.Flags().StringVar(&shellNameserver, "nameserver", "", "Nameserver for DNS queries")
+	cmd.Flags().IntVar(&shellPort, "port", 445, "TCP port to reach SMB on; when 445 is filtered, 139 is tried automatically with NetBIOS session setup")
+	cmd.Flags().Float64Var(&shellTimeout, "timeout", 10, "Timeout in seconds for SMB operations")
+	cmd.MarkFlagRequired("target")
+	return cmd
+}
+
+// parseEdgeKindList splits a comma-separated list of edge kinds and validates
+// each entry against the known kinds.
+func parseEdgeKindList(list string) ([]string, error) {
+	known := make(map[string]struct{})
+	for _, k := range kinds.AllEdgeKinds() {
+		known[k] = struct{}{}
+	}
+
+	var result []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		if _, ok := known[entry]; !ok {
+			return nil, fmt.Errorf("unknown edge kind %q (see documentation for valid kinds)", entry)
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
 func run(cmd *cobra.Command, args []string) {
 	fmt.Printf("ShareHound v%s - Original by Remi Gascou (@podalirius_) @ SpecterOps, Go port by Javier Azofra @ Siemens Healthineers\n\n", Version)
 
@@ -156,16 +1284,31 @@ func run(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if err := resolvePassword(); err != nil {
+		fmt.Printf("[!] %v\n", err)
+		os.Exit(1)
+	}
+
 	if authPassword != "" && authHashes != "" {
 		fmt.Println("[!] Options --auth-password and --auth-hashes are mutually exclusive.")
 		os.Exit(1)
 	}
 
+	if localAuth && (useKerberos || windowsAuth) {
+		fmt.Println("[!] Option --local-auth performs NTLM against local accounts and cannot be combined with --use-kerberos or --windows-auth.")
+		os.Exit(1)
+	}
+
 	if windowsAuth && (authUser != "" || authPassword != "" || authHashes != "" || authKey != "") {
 		fmt.Println("[!] Option --windows-auth uses the current Windows logon session and cannot be combined with explicit credentials.")
 		os.Exit(1)
 	}
 
+	// AES keys and keytabs are Kerberos-only credential material
+	if authKey != "" {
+		useKerberos = true
+	}
+
 	if windowsAuth {
 		useKerberos = true
 		if authDomain == "" {
@@ -188,8 +1331,25 @@ func run(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if opsecProfile != "" && opsecProfile != "strict" {
+		fmt.Printf("[!] Unknown OPSEC profile %q (supported: strict).\n", opsecProfile)
+		os.Exit(1)
+	}
+
+	switch orderBy {
+	case "", targets.OrderRandom, targets.OrderSubnetInterleaved, targets.OrderADSite:
+	default:
+		fmt.Printf("[!] Unknown ordering mode %q (supported: %s, %s, %s).\n",
+			orderBy, targets.OrderRandom, targets.OrderSubnetInterleaved, targets.OrderADSite)
+		os.Exit(1)
+	}
+
 	// Create configuration
 	cfg := config.NewConfig(debug, &noColors)
+	if opsecProfile == "strict" {
+		cfg.SetOpsecStrict(true)
+	}
+	cfg.SetRegistrySDFallback(registrySDFallback)
 
 	// Create logger
 	log := logger.NewLogger(cfg, logfile)
@@ -197,6 +1357,7 @@ func run(cmd *cobra.Command, args []string) {
 
 	// Parse rules
 	var parsedRules []rules.Rule
+	var ruleSource []string
 	parser := rules.NewParser()
 
 	if len(rulesFiles) == 0 && len(ruleStrings) == 0 {
@@ -206,12 +1367,7 @@ func run(cmd *cobra.Command, args []string) {
 
 	if len(rulesFiles) > 0 {
 		for _, file := range rulesFiles {
-			content, err := os.ReadFile(file)
-			if err != nil {
-				log.Error(fmt.Sprintf("Error reading rules file %s: %v", file, err))
-				os.Exit(1)
-			}
-			fileRules, errors := parser.Parse(string(content))
+			fileRules, sources, errors := parser.ParseFile(file)
 			if len(errors) > 0 {
 				log.Error(fmt.Sprintf("Errors parsing rules file %s:", file))
 				for _, e := range errors {
@@ -220,6 +1376,9 @@ func run(cmd *cobra.Command, args []string) {
 				os.Exit(1)
 			}
 			parsedRules = append(parsedRules, fileRules...)
+			// sources includes every INCLUDEd file, so the rule set hash
+			// changes when a shared baseline changes
+			ruleSource = append(ruleSource, sources...)
 		}
 	} else if len(ruleStrings) > 0 {
 		rules, errors := parser.ParseStrings(ruleStrings)
@@ -231,20 +1390,100 @@ func run(cmd *cobra.Command, args []string) {
 			os.Exit(1)
 		}
 		parsedRules = rules
+		ruleSource = ruleStrings
 	}
 
 	log.Debug(fmt.Sprintf("%d rules parsed successfully", len(parsedRules)))
 
+	// Compile YARA rules and register the content scanner
+	if len(yaraFiles) > 0 {
+		var yaraRules []yara.Rule
+		yaraParser := yara.NewParser()
+		for _, file := range yaraFiles {
+			fileRules, err := yaraParser.ParseFile(file)
+			if err != nil {
+				log.Error(fmt.Sprintf("Error parsing YARA rules file %s: %v", file, err))
+				os.Exit(1)
+			}
+			yaraRules = append(yaraRules, fileRules...)
+		}
+		collector.RegisterFileHandler(yara.NewHandler(yara.NewScanner(yaraRules), yaraMaxFileSize))
+		log.Info(fmt.Sprintf("Loaded %d YARA rules", len(yaraRules)))
+	}
+
+	if secretTriage {
+		collector.RegisterFileHandler(secrets.NewHandler(secretSampleSize))
+	}
+
+	if archivePeek {
+		collector.RegisterFileHandler(archive.NewHandler())
+	}
+
+	if hashFiles {
+		collector.RegisterFileHandler(collector.NewHashHandler(hashMaxFileSize))
+	}
+
+	// Set up evidence collection for COLLECT rules
+	if lootDir != "" {
+		lootCollector, err := loot.NewCollector(lootDir, lootMaxFileSize, lootBudget)
+		if err != nil {
+			log.Error(fmt.Sprintf("Failed to set up loot directory: %v", err))
+			os.Exit(1)
+		}
+		defer func() {
+			files, bytes := lootCollector.Collected()
+			if files > 0 {
+				log.Info(fmt.Sprintf("Collected %d files (%s) into %s", files, utils.FormatFileSize(bytes), lootDir))
+			}
+			lootCollector.Close()
+		}()
+		collector.SetLootCollector(lootCollector)
+		if !rules.HasCollectRules(parsedRules) {
+			log.Warning("--loot-dir is set but the rule set has no COLLECT rules; nothing will be downloaded")
+		}
+	} else if rules.HasCollectRules(parsedRules) {
+		log.Warning("Rule set contains COLLECT rules but --loot-dir is not set; matching files will not be downloaded")
+	}
+
+	// Hash the rule set so scans driven by different rules are distinguishable
+	// in the scan metadata node
+	ruleDigest := sha256.Sum256([]byte(strings.Join(ruleSource, "\n")))
+	ruleSetHash := hex.EncodeToString(ruleDigest[:])
+
 	log.Info("Starting ShareHound")
 	startTime := time.Now()
+	scanID := campaign.NewScanID(startTime)
+
+	// Open the operator audit log before any network activity so every host
+	// connection, share mount and file open lands in the hash chain
+	if auditLogPath != "" {
+		auditLog, err := audit.Open(auditLogPath)
+		if err != nil {
+			log.Error(fmt.Sprintf("Failed to open audit log: %v", err))
+			os.Exit(1)
+		}
+		defer auditLog.Close()
+		audit.Enable(auditLog)
+	}
+
+	// Memory budget: hand the limit to the runtime so GC pacing respects it,
+	// and trade graph write-buffer throughput for a smaller footprint
+	graphBufferSize := graph.DefaultBufferSize
+	if memoryLimitMB > 0 {
+		runtimedebug.SetMemoryLimit(int64(memoryLimitMB) * 1024 * 1024)
+		graphBufferSize = 32 * 1024
+		log.Info(fmt.Sprintf("Memory budget set: GOMEMLIMIT=%d MiB", memoryLimitMB))
+	}
 
 	// Create OpenGraph
-	og, err := graph.NewOpenGraph(kinds.NodeKindNetworkShareBase)
+	og, err := graph.NewOpenGraphBuffered(kinds.NodeKindNetworkShareBase, graphBufferSize)
 	if err != nil {
 		log.Error(fmt.Sprintf("Failed to create graph: %v", err))
 		os.Exit(1)
 	}
 	defer og.Close()
+	og.SetLimits(maxNodes, maxEdges)
+	og.SetFastExport(fastExport)
 
 	// Create checkpoint manager
 	cpInterval := time.Duration(checkpointInterval * float64(time.Second))
@@ -263,6 +1502,9 @@ func run(cmd *cobra.Command, args []string) {
 			og.RestoreNodesAndEdges(cp.GraphNodes, cp.GraphEdges)
 			log.Info(fmt.Sprintf("Restored %d processed targets, %d nodes, %d edges",
 				len(cp.ProcessedTargets), len(cp.GraphNodes), len(cp.GraphEdges)))
+			if len(cp.PartialTargets) > 0 {
+				log.Info(fmt.Sprintf("%d target(s) were in flight when the previous run stopped and will be rescanned", len(cp.PartialTargets)))
+			}
 		} else {
 			log.Warning("Checkpoint file not found, starting fresh scan")
 		}
@@ -283,6 +1525,8 @@ func run(cmd *cobra.Command, args []string) {
 		KDCHost:      kdcHost,
 		UseLDAPS:     useLDAPS,
 		Subnets:      subnets,
+		Forest:       forest,
+		MaxTargets:   maxTargets,
 		Timeout:      time.Duration(timeout * float64(time.Second)),
 	}
 
@@ -310,19 +1554,219 @@ func run(cmd *cobra.Command, args []string) {
 		&authKey,
 		&kdcHost,
 	)
+	creds.LocalAuth = localAuth
+
+	// Parse and validate the edge kind filters
+	includeEdgeKindList, err := parseEdgeKindList(edgeKinds)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	excludeEdgeKindList, err := parseEdgeKindList(excludeEdgeKinds)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	// Build the node property omission set
+	var omittedPropertyList []string
+	if minimalProperties {
+		omittedPropertyList = []string{"createdAt", "modifiedAt", "fileSize", "description"}
+	}
+	if omitProperties != "" {
+		omittedPropertyList = nil
+		for _, entry := range strings.Split(omitProperties, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				omittedPropertyList = append(omittedPropertyList, entry)
+			}
+		}
+	}
+
+	// Build the SID ignore list.  Service SIDs (NT SERVICE\*, which includes
+	// TrustedInstaller) are suppressed by default — they bloat the graph with
+	// edges nobody queries.
+	ignoredSIDList := []string{"S-1-5-80-*"}
+	for _, entry := range strings.Split(ignoreSIDs, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			ignoredSIDList = append(ignoredSIDList, entry)
+		}
+	}
+	for _, entry := range strings.Split(ignorePrincipals, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		sid := resolveIgnoredPrincipal(entry)
+		if sid == "" {
+			log.Error(fmt.Sprintf("Cannot resolve principal %q to a SID; use --ignore-sid with the SID directly", entry))
+			os.Exit(1)
+		}
+		ignoredSIDList = append(ignoredSIDList, sid)
+	}
+
+	// Resolve impersonation test accounts to their token SIDs via LDAP
+	var impersonated map[string][]string
+	if impersonate != "" {
+		if authDomain == "" || authDCIP == "" {
+			log.Error("Option --impersonate requires --auth-domain and --auth-dc-ip to resolve token groups via LDAP")
+			os.Exit(1)
+		}
+
+		ldapClient, err := ldap.NewClient(&ldap.ClientOptions{
+			Domain:      authDomain,
+			DCIP:        authDCIP,
+			Username:    authUser,
+			Password:    authPassword,
+			Hashes:      authHashes,
+			AuthKey:     authKey,
+			UseLDAPS:    useLDAPS,
+			UseKerberos: useKerberos,
+			WindowsAuth: windowsAuth,
+			KDCHost:     kdcHost,
+		})
+		if err != nil {
+			log.Error(fmt.Sprintf("Failed to create LDAP client: %v", err))
+			os.Exit(1)
+		}
+		if err := ldapClient.Connect(); err != nil {
+			log.Error(fmt.Sprintf("Failed to connect to LDAP: %v", err))
+			os.Exit(1)
+		}
+
+		impersonated = make(map[string][]string)
+		for _, account := range strings.Split(impersonate, ",") {
+			account = strings.TrimSpace(account)
+			if account == "" {
+				continue
+			}
+			accountSID, tokenSIDs, err := ldapClient.GetAccountTokenSIDs(account)
+			if err != nil {
+				log.Error(fmt.Sprintf("Failed to resolve token groups for %s: %v", account, err))
+				ldapClient.Close()
+				os.Exit(1)
+			}
+			impersonated[accountSID] = tokenSIDs
+			log.Info(fmt.Sprintf("Impersonating %s (%s): %d SIDs in token", account, accountSID, len(tokenSIDs)))
+		}
+		ldapClient.Close()
+	}
+
+	// The policy file is parsed before scanning so a typo in an assertion
+	// fails fast, not after hours of collection
+	var scanPolicy *policy.Policy
+	if policyPath != "" {
+		var err error
+		scanPolicy, err = policy.Load(policyPath)
+		if err != nil {
+			log.Error(fmt.Sprintf("Invalid policy file: %v", err))
+			os.Exit(1)
+		}
+	}
+
+	// In distributed mode the TLS material is validated before scanning so a
+	// bad certificate path fails fast, not after hours of collection
+	var aggregatorTLS *tls.Config
+	if aggregatorAddr != "" {
+		if aggregatorCert == "" || aggregatorKey == "" || aggregatorCA == "" {
+			log.Error("Option --aggregator requires --aggregator-cert, --aggregator-key and --aggregator-ca")
+			os.Exit(1)
+		}
+		var err error
+		aggregatorTLS, err = distrib.ClientTLSConfig(aggregatorCert, aggregatorKey, aggregatorCA, "")
+		if err != nil {
+			log.Error(fmt.Sprintf("Invalid aggregator TLS configuration: %v", err))
+			os.Exit(1)
+		}
+		if agentName == "" {
+			agentName, _ = os.Hostname()
+		}
+	}
+
+	// Resolve the domain SID so S-1-5-21-* principals found in ACLs can be
+	// classified as domain accounts or the scanned host's own local SAM
+	// accounts (LSARPC is not available, so the reference SID comes from the
+	// domain head object via LDAP)
+	var domainSID string
+	if localPrincipals != "" {
+		if localPrincipals != "tag" && localPrincipals != "skip" {
+			log.Error(fmt.Sprintf("Invalid --local-principals mode %q: must be \"tag\" or \"skip\"", localPrincipals))
+			os.Exit(1)
+		}
+		if authDomain == "" || authDCIP == "" {
+			log.Error("Option --local-principals requires --auth-domain and --auth-dc-ip to resolve the domain SID via LDAP")
+			os.Exit(1)
+		}
+
+		ldapClient, err := ldap.NewClient(&ldap.ClientOptions{
+			Domain:      authDomain,
+			DCIP:        authDCIP,
+			Username:    authUser,
+			Password:    authPassword,
+			Hashes:      authHashes,
+			AuthKey:     authKey,
+			UseLDAPS:    useLDAPS,
+			UseKerberos: useKerberos,
+			WindowsAuth: windowsAuth,
+			KDCHost:     kdcHost,
+		})
+		if err != nil {
+			log.Error(fmt.Sprintf("Failed to create LDAP client: %v", err))
+			os.Exit(1)
+		}
+		if err := ldapClient.Connect(); err != nil {
+			log.Error(fmt.Sprintf("Failed to connect to LDAP: %v", err))
+			os.Exit(1)
+		}
+		domainSID, err = ldapClient.GetDomainSID()
+		ldapClient.Close()
+		if err != nil {
+			log.Error(fmt.Sprintf("Failed to resolve the domain SID: %v", err))
+			os.Exit(1)
+		}
+		verb := "tagged"
+		if localPrincipals == "skip" {
+			verb = "skipped"
+		}
+		log.Info(fmt.Sprintf("Domain SID of %s: %s (local-account edges will be %s)", authDomain, domainSID, verb))
+	}
 
 	// Create worker options
 	workerOpts := &worker.Options{
 		Creds:               creds,
 		Timeout:             time.Duration(timeout * float64(time.Second)),
 		HostTimeout:         time.Duration(hostTimeout * float64(time.Minute)),
+		HostTimeoutPerShare: time.Duration(hostTimeoutPerShare * float64(time.Second)),
+		HostTimeoutMax:      time.Duration(hostTimeoutMax * float64(time.Minute)),
 		AdvertisedName:      advertisedName,
 		MaxWorkersPerHost:   maxWorkersPerHost,
 		GlobalMaxWorkers:    globalMaxWorkers,
 		Depth:               depth,
 		Nameserver:          nameserver,
+		Port:                smbPort,
 		Logfile:             logfile,
 		EffectiveAccessOnly: effectiveAccessOnly,
+		AccessCheckOnly:     accessCheckOnly,
+		Impersonated:        impersonated,
+		IgnoredSIDs:         ignoredSIDList,
+		DomainSID:           domainSID,
+		SkipLocalPrincipals: localPrincipals == "skip",
+		RollupEdges:         rollupEdges,
+		IncludeEdgeKinds:    includeEdgeKindList,
+		ExcludeEdgeKinds:    excludeEdgeKindList,
+		OmittedProperties:   omittedPropertyList,
+		AlertWebhook:        alertWebhook,
+		OpsecStrict:         opsecProfile == "strict",
+		PerHostResultsDir:   perHostResultsDir,
+		LocalGroups:         localGroups,
+		CanonicalIDs:        canonicalIDs,
+		RawSDDL:             rawSDDL,
+	}
+	if maxAuthFailures > 0 {
+		workerOpts.AuthGuard = worker.NewAuthGuard(maxAuthFailures)
+	}
+	for _, entry := range strings.Split(shareName, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			workerOpts.Shares = append(workerOpts.Shares, entry)
+		}
 	}
 
 	// Debug: show host timeout value
@@ -332,7 +1776,112 @@ func run(cmd *cobra.Command, args []string) {
 
 	// Create results tracker
 	results := &collector.WorkerResults{}
-	var resultsLock sync.Mutex
+
+	// Skip reporting (nil disables it; SkipReport.Add is nil-safe)
+	var skipReport *collector.SkipReport
+	if skipReportPath != "" {
+		skipReport = collector.NewSkipReport()
+	}
+	workerOpts.SkipReport = skipReport
+
+	// Diagnostics for slow or hung scans: a pprof endpoint for on-demand
+	// profiles and traces, and an optional whole-scan CPU profile
+	if pprofListen != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", httppprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", httppprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", httppprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", httppprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", httppprof.Trace)
+		go func() {
+			if err := http.ListenAndServe(pprofListen, mux); err != nil {
+				log.Warning(fmt.Sprintf("pprof listener failed: %v", err))
+			}
+		}()
+		fmt.Printf("[*] pprof available at http://%s/debug/pprof/\n", pprofListen)
+		log.Info(fmt.Sprintf("pprof listening at %s", pprofListen))
+	}
+	if profileOutput != "" {
+		profileFile, err := os.Create(profileOutput)
+		if err != nil {
+			log.Error(fmt.Sprintf("Failed to create profile output file: %v", err))
+			os.Exit(1)
+		}
+		if err := runtimepprof.StartCPUProfile(profileFile); err != nil {
+			log.Error(fmt.Sprintf("Failed to start CPU profile: %v", err))
+			os.Exit(1)
+		}
+	}
+
+	// Runtime control socket for pause/resume/status/skip-host
+	var ctlServer *control.Server
+	if controlSocket != "" {
+		ctlServer = control.NewServer(func() string {
+			return fmt.Sprintf("hosts: %d/%d done (active: %d) | shares: %d | files: %d | errors: %d | elapsed: %s",
+				results.Success.Load()+results.Errors.Load(), len(loadedTargets), results.ActiveHosts.Load(),
+				results.SharesProcessed.Load(), results.FilesProcessed.Load(), results.Errors.Load(),
+				utils.DeltaTime(time.Since(startTime)))
+		})
+		if err := ctlServer.Start(controlSocket); err != nil {
+			log.Error(fmt.Sprintf("Failed to start control socket: %v", err))
+			os.Exit(1)
+		}
+		defer ctlServer.Close()
+		log.Info(fmt.Sprintf("Control socket listening at %s", controlSocket))
+		workerOpts.Control = ctlServer
+
+		// Concurrency tuning that newly dispatched hosts pick up
+		ctlServer.RegisterSetting("workers-per-host", func(value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("workers-per-host must be a positive integer")
+			}
+			workerOpts.SetWorkersPerHost(n)
+			return nil
+		})
+		ctlServer.RegisterSetting("connect-interval", func(value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				return fmt.Errorf("connect-interval must be a non-negative duration (e.g. 500ms)")
+			}
+			workerOpts.SetConnectInterval(d)
+			return nil
+		})
+	}
+
+	// Live results feed for integrators: every node and edge is pushed to
+	// subscribers as it is accepted into the graph
+	var streamServer *stream.Server
+	if streamListen != "" {
+		var streamTLS *tls.Config
+		if streamCert != "" || streamKey != "" || streamCA != "" {
+			if streamCert == "" || streamKey == "" || streamCA == "" {
+				log.Error("Options --stream-cert, --stream-key and --stream-ca must be set together")
+				os.Exit(1)
+			}
+			var err error
+			streamTLS, err = distrib.ServerTLSConfig(streamCert, streamKey, streamCA)
+			if err != nil {
+				log.Error(fmt.Sprintf("Invalid stream TLS configuration: %v", err))
+				os.Exit(1)
+			}
+		}
+		streamServer = stream.NewServer(log)
+		if err := streamServer.Start(streamListen, streamTLS); err != nil {
+			log.Error(fmt.Sprintf("Failed to start results stream: %v", err))
+			os.Exit(1)
+		}
+		log.Info(fmt.Sprintf("Results stream listening on %s", streamServer.Addr()))
+		og.SetObserver(streamServer.PublishNode, streamServer.PublishEdge)
+	}
+
+	// The scan deadline and shutdown grace abort in-flight hosts through the
+	// control server's host registry, so one is needed even when no control
+	// socket is configured. An unstarted server is just the registry.
+	if (scanDeadline > 0 || shutdownGrace > 0) && ctlServer == nil {
+		ctlServer = control.NewServer(nil)
+		workerOpts.Control = ctlServer
+	}
 
 	// Filter out already-processed targets if resuming
 	var targetsToProcess []targets.Target
@@ -350,23 +1899,77 @@ func run(cmd *cobra.Command, args []string) {
 			skippedCount, len(targetsToProcess)))
 	}
 
+	// Collapse aliases of the same server before ordering so shares aren't
+	// collected once per IP, short name and FQDN
+	if dedupeTargets {
+		targetsToProcess = targets.DedupeByAddress(targetsToProcess, nameserver, authDCIP, time.Duration(timeout*float64(time.Second)), log)
+	}
+
+	// Target ordering: --order-by takes precedence; --shuffle-targets and the
+	// strict OPSEC profile default to random order
+	orderMode := orderBy
+	if orderMode == "" && (shuffleTargets || opsecProfile == "strict") {
+		orderMode = targets.OrderRandom
+	}
+	if orderMode != "" {
+		var siteBySubnet map[string]string
+		if orderMode == targets.OrderADSite {
+			if authDomain == "" || authDCIP == "" {
+				log.Error("Option --order-by ad-site requires --auth-domain and --auth-dc-ip to read AD Sites and Services")
+				os.Exit(1)
+			}
+			ldapClient, err := ldap.NewClient(&ldap.ClientOptions{
+				Domain:      authDomain,
+				DCIP:        authDCIP,
+				Username:    authUser,
+				Password:    authPassword,
+				Hashes:      authHashes,
+				AuthKey:     authKey,
+				UseLDAPS:    useLDAPS,
+				UseKerberos: useKerberos,
+				WindowsAuth: windowsAuth,
+				KDCHost:     kdcHost,
+			})
+			if err != nil {
+				log.Error(fmt.Sprintf("Failed to create LDAP client: %v", err))
+				os.Exit(1)
+			}
+			if err := ldapClient.Connect(); err != nil {
+				log.Error(fmt.Sprintf("Failed to connect to LDAP: %v", err))
+				os.Exit(1)
+			}
+			siteBySubnet, err = ldapClient.GetSubnetSites()
+			ldapClient.Close()
+			if err != nil {
+				log.Error(fmt.Sprintf("Failed to read subnet-to-site mapping: %v", err))
+				os.Exit(1)
+			}
+			log.Info(fmt.Sprintf("Loaded %d subnet-to-site mappings from AD Sites and Services", len(siteBySubnet)))
+		}
+
+		ordered, err := targets.OrderTargets(targetsToProcess, orderMode, siteBySubnet)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		targetsToProcess = ordered
+	}
+
 	// Start progress tracker
-	tracker := status.NewProgressTracker(results, &resultsLock, len(loadedTargets))
+	tracker := status.NewProgressTracker(results, len(loadedTargets))
 	tracker.Start()
 
 	// Start checkpoint manager
 	getStats := func() checkpoint.Statistics {
-		resultsLock.Lock()
-		defer resultsLock.Unlock()
 		return checkpoint.Statistics{
-			Success:              results.Success,
-			Errors:               results.Errors,
-			SharesTotal:          results.SharesTotal,
-			SharesProcessed:      results.SharesProcessed,
-			FilesTotal:           results.FilesTotal,
-			FilesProcessed:       results.FilesProcessed,
-			DirectoriesTotal:     results.DirectoriesTotal,
-			DirectoriesProcessed: results.DirectoriesProcessed,
+			Success:              results.Success.Load(),
+			Errors:               results.Errors.Load(),
+			SharesTotal:          results.SharesTotal.Load(),
+			SharesProcessed:      results.SharesProcessed.Load(),
+			FilesTotal:           results.FilesTotal.Load(),
+			FilesProcessed:       results.FilesProcessed.Load(),
+			DirectoriesTotal:     results.DirectoriesTotal.Load(),
+			DirectoriesProcessed: results.DirectoriesProcessed.Load(),
 		}
 	}
 	cpManager.Start(og, len(loadedTargets), getStats)
@@ -376,34 +1979,205 @@ func run(cmd *cobra.Command, args []string) {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	stopChan := make(chan struct{})
 
+	// Hosts cut off by an abort stay marked partial in the checkpoint so a
+	// resumed run rescans them instead of trusting their half-collected data.
+	var graceExpired atomic.Bool
+
 	go func() {
 		sig := <-sigChan
 		log.Warning(fmt.Sprintf("Received signal %v, saving checkpoint and shutting down...", sig))
 		cpManager.TriggerSave()
 		close(stopChan)
+		// Under orchestration (Kubernetes, systemd) SIGTERM is followed by
+		// SIGKILL, so in-flight hosts get a bounded grace period and the run
+		// then exits 0 through the normal checkpoint-and-export path.
+		if shutdownGrace > 0 {
+			time.AfterFunc(time.Duration(shutdownGrace*float64(time.Second)), func() {
+				log.Warning("Shutdown grace period expired, aborting in-flight hosts")
+				graceExpired.Store(true)
+				ctlServer.AbortAllHosts()
+			})
+		}
 	}()
 
-	// Process targets concurrently
+	// Absolute runtime deadline: stop dispatching new hosts when it passes,
+	// give in-flight hosts a grace period, then abort whatever is left so the
+	// checkpoint and export run with everything collected so far.
+	deadlineChan := make(chan struct{})
+	if scanDeadline > 0 {
+		deadline := time.Duration(scanDeadline * float64(time.Minute))
+		grace := time.Duration(scanDeadlineGrace * float64(time.Minute))
+		time.AfterFunc(deadline, func() {
+			log.Warning(fmt.Sprintf("Scan deadline of %s reached, stopping dispatch (%s grace for in-flight hosts)", utils.DeltaTime(deadline), utils.DeltaTime(grace)))
+			cpManager.TriggerSave()
+			close(deadlineChan)
+			time.AfterFunc(grace, func() {
+				log.Warning("Scan deadline grace period expired, aborting in-flight hosts")
+				graceExpired.Store(true)
+				ctlServer.AbortAllHosts()
+			})
+		})
+		log.Info(fmt.Sprintf("Scan deadline set: dispatch stops after %s", utils.DeltaTime(deadline)))
+	}
+
+	// Process targets concurrently. The limiter replaces a fixed-size
+	// channel so the global thread count can be adjusted mid-scan.
 	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, threads)
+	limiter := worker.NewLimiter(threads)
+
+	// Optional per-subnet cap on top of the global limit, so interleaved
+	// target lists can't saturate one branch office link.
+	var subnetLimiter *worker.GroupLimiter
+	if maxHostsPerSubnet > 0 {
+		subnetLimiter = worker.NewGroupLimiter(maxHostsPerSubnet)
+	}
+	if ctlServer != nil {
+		ctlServer.RegisterSetting("threads", func(value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("threads must be a positive integer")
+			}
+			limiter.SetLimit(n)
+			return nil
+		})
+	}
+
+	// Memory budget guard: when the heap nears the limit, cut dispatch
+	// concurrency so fewer in-flight hosts hold buffers and ACL copies at
+	// once; restore the configured thread count once pressure subsides
+	memGuardQuit := make(chan struct{})
+	if memoryLimitMB > 0 {
+		limitBytes := uint64(memoryLimitMB) * 1024 * 1024
+		throttledLimit := threads / 4
+		if throttledLimit < 1 {
+			throttledLimit = 1
+		}
+		go func() {
+			throttled := false
+			ticker := time.NewTicker(5 * time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-memGuardQuit:
+					return
+				case <-ticker.C:
+					var stats runtime.MemStats
+					runtime.ReadMemStats(&stats)
+					if !throttled && stats.HeapAlloc > limitBytes*85/100 {
+						throttled = true
+						limiter.SetLimit(throttledLimit)
+						log.Warning(fmt.Sprintf("Heap at %d MiB, approaching the %d MiB budget: throttling to %d threads",
+							stats.HeapAlloc/(1024*1024), memoryLimitMB, throttledLimit))
+					} else if throttled && stats.HeapAlloc < limitBytes*70/100 {
+						throttled = false
+						limiter.SetLimit(threads)
+						log.Info("Memory pressure subsided, restoring the configured thread count")
+					}
+				}
+			}
+		}()
+	}
+
+	// Periodic progress events on the live stream
+	streamQuit := make(chan struct{})
+	if streamServer != nil {
+		go func() {
+			ticker := time.NewTicker(2 * time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-streamQuit:
+					return
+				case <-ticker.C:
+					streamServer.PublishProgress(stream.Progress{
+						HostsProcessed:  results.Success.Load() + results.Errors.Load(),
+						HostsTotal:      len(loadedTargets),
+						SharesProcessed: results.SharesProcessed.Load(),
+						FilesProcessed:  results.FilesProcessed.Load(),
+						Errors:          results.Errors.Load(),
+					})
+				}
+			}
+		}()
+	}
 
+	// Periodic snapshot exports: a valid OpenGraph file of everything
+	// collected so far, replaced atomically so partial results can be
+	// ingested mid-engagement without waiting for the scan to finish.
+	snapshotQuit := make(chan struct{})
+	if exportInterval > 0 {
+		ext := filepath.Ext(output)
+		snapshotFile := strings.TrimSuffix(output, ext) + ".partial" + ext
+		go func() {
+			ticker := time.NewTicker(time.Duration(exportInterval * float64(time.Second)))
+			defer ticker.Stop()
+			for {
+				select {
+				case <-snapshotQuit:
+					return
+				case <-ticker.C:
+					tmpFile := snapshotFile + ".tmp" + ext
+					if err := og.ExportToFile(tmpFile, true); err != nil {
+						log.Warning(fmt.Sprintf("Snapshot export failed: %v", err))
+						os.Remove(tmpFile)
+						continue
+					}
+					if err := os.Rename(tmpFile, snapshotFile); err != nil {
+						log.Warning(fmt.Sprintf("Snapshot export failed: %v", err))
+						os.Remove(tmpFile)
+						continue
+					}
+					log.Info(fmt.Sprintf("Snapshot exported to \"%s\" (%d nodes, %d edges)",
+						snapshotFile, og.GetNodeCount(), og.GetEdgeCount()))
+				}
+			}
+		}()
+	}
+
+dispatch:
 	for _, target := range targetsToProcess {
-		// Check for stop signal
+		// Check for stop signal or deadline
 		select {
 		case <-stopChan:
 			log.Info("Stop signal received, waiting for current tasks to complete...")
-			break
+			break dispatch
+		case <-deadlineChan:
+			log.Info("Scan deadline passed, waiting for in-flight hosts...")
+			break dispatch
 		default:
 		}
 
+		// Honour operator commands from the control socket
+		if ctlServer != nil {
+			ctlServer.WaitIfPaused()
+			if ctlServer.ShouldSkip(target.Value) {
+				log.Info(fmt.Sprintf("Skipping %s by operator request", target.Value))
+				continue
+			}
+		}
+
 		wg.Add(1)
-		semaphore <- struct{}{}
+		// Take the subnet slot before the global one so a saturated subnet
+		// does not hold a global slot idle while it waits
+		if subnetLimiter != nil {
+			subnetLimiter.Acquire(targets.SubnetKey(target))
+		}
+		limiter.Acquire()
 
 		go func(t targets.Target) {
 			defer wg.Done()
-			defer func() { <-semaphore }()
+			defer limiter.Release()
+			if subnetLimiter != nil {
+				defer subnetLimiter.Release(targets.SubnetKey(t))
+			}
 
-			worker.ProcessTarget(t, workerOpts, cfg, og, parsedRules, results, &resultsLock)
+			cpManager.MarkTargetStarted(t)
+			worker.ProcessTarget(t, workerOpts, cfg, og, parsedRules, results)
+			if graceExpired.Load() {
+				// This host may have been cut off mid-share; leave it
+				// partial so the resumed scan redoes it
+				return
+			}
 			cpManager.MarkTargetProcessed(t)
 		}(target)
 	}
@@ -415,11 +2189,57 @@ func run(cmd *cobra.Command, args []string) {
 	scanElapsed := time.Since(startTime)
 	fmt.Printf("[*] Scan phase completed in %s\n", utils.DeltaTime(scanElapsed))
 
+	// Emit the scan metadata node and link it to every host node this run
+	// produced, so a run's data can be filtered or deleted in BloodHound by
+	// its scan ID
+	principal := creds.Username
+	if creds.Domain != "" {
+		principal = creds.Domain + "\\" + creds.Username
+	}
+	scanNode := graph.NewNode("SHSCAN:"+scanID, kinds.NodeKindShareHoundScan).
+		SetProperty("name", "ShareHound Scan "+scanID).
+		SetProperty("scanId", scanID).
+		SetProperty("startedAt", startTime.Format(time.RFC3339)).
+		SetProperty("finishedAt", time.Now().Format(time.RFC3339)).
+		SetProperty("ruleSetHash", ruleSetHash).
+		SetProperty("principal", principal).
+		SetProperty("targetCount", len(loadedTargets))
+	og.AddNode(scanNode)
+	for _, hostID := range og.GetNodeIDsWithPrefix("NSHOST:") {
+		scanEdge := graph.NewEdge(scanNode.ID, hostID, kinds.EdgeKindScannedHost)
+		scanEdge.SetStartMatchBy("id")
+		scanEdge.SetEndMatchBy("id")
+		scanEdge.SetStartKind(kinds.NodeKindShareHoundScan)
+		scanEdge.SetEndKind(kinds.NodeKindNetworkShareHost)
+		og.AddEdge(scanEdge)
+	}
+
+	// End the live stream after the scan metadata node so subscribers also
+	// receive it before the final done event
+	close(streamQuit)
+	close(memGuardQuit)
+	close(snapshotQuit)
+	if streamServer != nil {
+		streamServer.Close()
+	}
+
 	// --- Post-scan phase with step-by-step visibility ---
 	fmt.Printf("[*] Stopping checkpoint manager...\n")
 	cpManager.Stop()
 	fmt.Printf("[+] Checkpoint manager stopped\n")
 
+	if (maxNodes > 0 || maxEdges > 0) && og.LimitReached() {
+		fmt.Printf("[!] Graph size cap reached (--max-nodes/--max-edges); affected hosts fell back to directory-level collection and carry collectionDegraded=true\n")
+		log.Warning("Graph size cap reached; file-level collection was degraded on the hosts still running at that point")
+	}
+
+	if pruneEmpty {
+		fmt.Printf("[*] Pruning subtrees without rights edges...\n")
+		prunedNodes, prunedEdges := og.PruneEmptySubtrees()
+		fmt.Printf("[+] Pruned %d node(s) and %d edge(s)\n", prunedNodes, prunedEdges)
+		log.Info(fmt.Sprintf("Pruned %d empty-subtree node(s) and %d edge(s)", prunedNodes, prunedEdges))
+	}
+
 	// Export graph with progress
 	nodeCount := og.GetNodeCount()
 	edgeCount := og.GetEdgeCount()
@@ -463,7 +2283,104 @@ func run(cmd *cobra.Command, args []string) {
 	log.DecrementIndent()
 
 	// Print final summary
-	status.PrintFinalSummary(results, &resultsLock)
+	status.PrintFinalSummary(results)
+
+	// Write the errors sidecar when any host operation failed, so failed
+	// hosts can be triaged without grepping debug logs
+	if results.HostErrorCount() > 0 {
+		errorsPath := strings.TrimSuffix(output, filepath.Ext(output)) + ".errors.json"
+		if err := collector.WriteErrorReport(results, errorsPath); err != nil {
+			log.Warning(fmt.Sprintf("Failed to write error report: %v", err))
+		} else {
+			fmt.Printf("[+] Error report written to \"%s\" (%d failed operations)\n", errorsPath, results.HostErrorCount())
+		}
+	}
+
+	// Write the machine-readable skip report
+	if skipReportPath != "" {
+		if err := skipReport.WriteFile(skipReportPath); err != nil {
+			log.Warning(fmt.Sprintf("Failed to write skip report: %v", err))
+		} else {
+			fmt.Printf("[+] Skip report written to \"%s\" (%d entries)\n", skipReportPath, skipReport.Count())
+		}
+	}
+
+	// Export notable findings for SIEM/SOAR ingestion
+	if findingsSTIXPath != "" || findingsOCSFPath != "" || findingsSARIFPath != "" {
+		nodes, edges := og.GetNodesAndEdges()
+		found := findings.FromGraph(nodes, edges)
+		if findingsSTIXPath != "" {
+			if err := findings.WriteSTIX(findingsSTIXPath, found, scanID, time.Now()); err != nil {
+				log.Warning(fmt.Sprintf("Failed to write STIX findings: %v", err))
+			} else {
+				fmt.Printf("[+] STIX findings written to \"%s\" (%d findings)\n", findingsSTIXPath, len(found))
+			}
+		}
+		if findingsOCSFPath != "" {
+			if err := findings.WriteOCSF(findingsOCSFPath, found, scanID, time.Now()); err != nil {
+				log.Warning(fmt.Sprintf("Failed to write OCSF findings: %v", err))
+			} else {
+				fmt.Printf("[+] OCSF findings written to \"%s\" (%d findings)\n", findingsOCSFPath, len(found))
+			}
+		}
+		if findingsSARIFPath != "" {
+			if err := findings.WriteSARIF(findingsSARIFPath, found); err != nil {
+				log.Warning(fmt.Sprintf("Failed to write SARIF findings: %v", err))
+			} else {
+				fmt.Printf("[+] SARIF findings written to \"%s\" (%d results)\n", findingsSARIFPath, len(found))
+			}
+		}
+	}
+
+	// Evaluate the compliance policy against the collected graph
+	policyViolations := 0
+	if scanPolicy != nil {
+		nodes, edges := og.GetNodesAndEdges()
+		violations := scanPolicy.Evaluate(nodes, edges)
+		policyViolations = len(violations)
+		if policyViolations == 0 {
+			fmt.Printf("[+] Policy check passed (%d assertions)\n", len(scanPolicy.Assertions))
+		} else {
+			for _, v := range violations {
+				fmt.Printf("[!] Policy violation [%s] %s: %s — %s\n", v.Assertion, v.UNCPath, v.Principal, v.Detail)
+				log.Warning(fmt.Sprintf("Policy violation [%s] %s: %s — %s", v.Assertion, v.UNCPath, v.Principal, v.Detail))
+			}
+			fmt.Printf("[!] Policy check failed: %d violation(s)\n", policyViolations)
+		}
+	}
+
+	// Stream results to the central aggregator in distributed mode
+	if aggregatorAddr != "" {
+		if err := distrib.SendGraph(aggregatorAddr, aggregatorTLS, agentName, scanID, og, log); err != nil {
+			log.Error(fmt.Sprintf("Failed to stream results to aggregator: %v", err))
+		} else {
+			fmt.Printf("[+] Results streamed to aggregator %s\n", aggregatorAddr)
+		}
+	}
+
+	// Record the scan in the campaign store
+	if campaignName != "" {
+		store := campaign.NewStore(campaignDir)
+		rec := &campaign.ScanRecord{
+			ID:          scanID,
+			Campaign:    campaignName,
+			StartedAt:   startTime,
+			FinishedAt:  time.Now(),
+			Arguments:   os.Args[1:],
+			OutputFile:  output,
+			Targets:     len(loadedTargets),
+			Hosts:       results.Success.Load(),
+			Errors:      results.Errors.Load(),
+			Shares:      results.SharesProcessed.Load(),
+			Files:       results.FilesProcessed.Load(),
+			Directories: results.DirectoriesProcessed.Load(),
+		}
+		if err := store.Save(rec); err != nil {
+			log.Warning(fmt.Sprintf("Failed to record scan in campaign store: %v", err))
+		} else {
+			fmt.Printf("[+] Scan recorded as %s (campaign %q)\n", rec.ID, campaignName)
+		}
+	}
 
 	// Clean up checkpoint file on successful completion
 	if cpManager.IsEnabled() && len(targetsToProcess) == 0 || cpManager.GetProcessedCount() == len(loadedTargets) {
@@ -477,7 +2394,19 @@ func run(cmd *cobra.Command, args []string) {
 		fmt.Printf("[*] Checkpoint saved to %s (use --resume to continue)\n", cpManager.GetFilepath())
 	}
 
+	// Flush the CPU profile now that collection and export are done
+	if profileOutput != "" {
+		runtimepprof.StopCPUProfile()
+		fmt.Printf("[+] CPU profile written to \"%s\"\n", profileOutput)
+	}
+
 	elapsed := time.Since(startTime)
 	log.Info(fmt.Sprintf("ShareHound completed, time elapsed: %s", utils.DeltaTime(elapsed)))
 	fmt.Printf("[+] ShareHound completed, total time: %s\n", utils.DeltaTime(elapsed))
+
+	// A failed policy check fails the whole run so pipelines can gate on it;
+	// exit 2 distinguishes non-compliance from runtime errors
+	if policyViolations > 0 {
+		os.Exit(2)
+	}
 }
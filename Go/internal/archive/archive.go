@@ -0,0 +1,128 @@
+// Package archive lists the contents of archive files found on shares
+// without downloading them. ZIP archives keep a central directory at the end
+// of the file, so a few ranged reads are enough to enumerate every contained
+// filename — backups.zip holding ntds.dit is exactly the find analysts want,
+// and pulling gigabytes to learn that is not.
+//
+// 7z archives are not supported: their header is usually LZMA-compressed, so
+// there is no cheap listing without real decompression.
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/specterops/sharehound/internal/collector"
+)
+
+// zipExtensions are the ZIP-container extensions worth peeking into.
+// Document containers (docx, xlsx, ...) are ZIP too but their entry lists
+// are boilerplate, so they are left out.
+var zipExtensions = map[string]bool{
+	".zip": true,
+	".jar": true,
+	".war": true,
+}
+
+// maxListedNames caps how many entry names land on the node; archives with
+// more entries still record the full count.
+const maxListedNames = 500
+
+// Handler lists archive contents during traversal. It is registered through
+// collector.RegisterFileHandler when --archive-peek is set.
+type Handler struct{}
+
+// NewHandler creates an archive listing handler.
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// Name implements collector.FileHandler.
+func (h *Handler) Name() string {
+	return "archive-peek"
+}
+
+// Matches implements collector.FileHandler.
+func (h *Handler) Matches(name string, size int64) bool {
+	return size > 0 && zipExtensions[strings.ToLower(path.Ext(name))]
+}
+
+// Handle implements collector.FileHandler. It reads the central directory
+// through ranged reads and records the contained filenames on the node.
+func (h *Handler) Handle(hctx *collector.FileHandlerContext) error {
+	reader, err := hctx.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	readerAt, ok := readerAtFor(reader)
+	if !ok {
+		// Without ranged reads, listing would mean downloading the
+		// whole archive — the exact cost this handler exists to avoid.
+		return fmt.Errorf("file handle does not support ranged reads")
+	}
+
+	names, total, err := listZip(readerAt, hctx.Size)
+	if err != nil {
+		return err
+	}
+
+	hctx.Node.SetProperty("archiveFiles", names)
+	hctx.Node.SetProperty("archiveFileCount", total)
+	if total > len(names) {
+		hctx.Node.SetProperty("archiveFilesTruncated", true)
+	}
+	hctx.Log.Debug(fmt.Sprintf("Archive %s lists %d entries", hctx.UNCPath, total))
+	return nil
+}
+
+// listZip enumerates the central directory, returning up to maxListedNames
+// entry names and the total entry count. Directory entries are skipped.
+func listZip(r io.ReaderAt, size int64) ([]string, int, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, 0, fmt.Errorf("not a readable ZIP archive: %v", err)
+	}
+	var names []string
+	total := 0
+	for _, f := range zr.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		total++
+		if len(names) < maxListedNames {
+			names = append(names, f.Name)
+		}
+	}
+	return names, total, nil
+}
+
+// readerAtFor extracts ranged-read capability from the opened file handle.
+// SMB file handles and os.File support ReadAt directly; a seekable reader
+// gets an adapter.
+func readerAtFor(r io.Reader) (io.ReaderAt, bool) {
+	if ra, ok := r.(io.ReaderAt); ok {
+		return ra, true
+	}
+	if rs, ok := r.(io.ReadSeeker); ok {
+		return &seekingReaderAt{rs: rs}, true
+	}
+	return nil, false
+}
+
+// seekingReaderAt adapts an io.ReadSeeker to io.ReaderAt. The zip reader
+// calls it sequentially, so the unsynchronized seek is fine.
+type seekingReaderAt struct {
+	rs io.ReadSeeker
+}
+
+func (s *seekingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := s.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(s.rs, p)
+}
@@ -0,0 +1,146 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/specterops/sharehound/internal/collector"
+	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// buildZip creates an in-memory ZIP with the given entry names.
+func buildZip(t *testing.T, names []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Create %s: %v", name, err)
+		}
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+		if _, err := w.Write([]byte("data")); err != nil {
+			t.Fatalf("Write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+type seekCloser struct {
+	*bytes.Reader
+}
+
+func (seekCloser) Close() error { return nil }
+
+func newArchiveContext(name string, content []byte) *collector.FileHandlerContext {
+	return &collector.FileHandlerContext{
+		Host:    "srv",
+		Share:   "data",
+		Path:    name,
+		UNCPath: "\\\\srv\\data\\" + name,
+		Name:    name,
+		Size:    int64(len(content)),
+		Node:    graph.NewNode("FILE:\\\\srv\\data\\" + name),
+		Open: func() (io.ReadCloser, error) {
+			return seekCloser{bytes.NewReader(content)}, nil
+		},
+		Log: logger.NewLogger(config.NewConfig(false, nil), ""),
+	}
+}
+
+func TestMatchesArchiveExtensions(t *testing.T) {
+	handler := NewHandler()
+	for name, want := range map[string]bool{
+		"backups.zip":  true,
+		"BACKUPS.ZIP":  true,
+		"app.jar":      true,
+		"app.war":      true,
+		"report.docx":  false,
+		"archive.7z":   false,
+		"notes.txt":    false,
+		"zip":          false,
+		"archive.zip2": false,
+	} {
+		if got := handler.Matches(name, 100); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if handler.Matches("empty.zip", 0) {
+		t.Error("Empty file should not match")
+	}
+}
+
+func TestHandleListsZipContents(t *testing.T) {
+	content := buildZip(t, []string{"readme.txt", "backup/", "backup/ntds.dit", "backup/SYSTEM"})
+	hctx := newArchiveContext("backups.zip", content)
+	if err := NewHandler().Handle(hctx); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	names, ok := hctx.Node.GetProperty("archiveFiles")
+	want := []string{"readme.txt", "backup/ntds.dit", "backup/SYSTEM"}
+	if !ok || !reflect.DeepEqual(names, want) {
+		t.Errorf("archiveFiles = %v, want %v", names, want)
+	}
+	if count, ok := hctx.Node.GetProperty("archiveFileCount"); !ok || count != 3 {
+		t.Errorf("archiveFileCount = %v, want 3", count)
+	}
+	if _, ok := hctx.Node.GetProperty("archiveFilesTruncated"); ok {
+		t.Error("Small archive should not be marked truncated")
+	}
+}
+
+func TestHandleTruncatesLongListings(t *testing.T) {
+	names := make([]string, maxListedNames+10)
+	for i := range names {
+		names[i] = fmt.Sprintf("file%04d.txt", i)
+	}
+	hctx := newArchiveContext("big.zip", buildZip(t, names))
+	if err := NewHandler().Handle(hctx); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	listed, _ := hctx.Node.GetProperty("archiveFiles")
+	if got := len(listed.([]string)); got != maxListedNames {
+		t.Errorf("Listed %d names, want %d", got, maxListedNames)
+	}
+	if count, _ := hctx.Node.GetProperty("archiveFileCount"); count != len(names) {
+		t.Errorf("archiveFileCount = %v, want %d", count, len(names))
+	}
+	if truncated, ok := hctx.Node.GetProperty("archiveFilesTruncated"); !ok || truncated != true {
+		t.Error("Expected archiveFilesTruncated = true")
+	}
+}
+
+func TestHandleRequiresRangedReads(t *testing.T) {
+	content := buildZip(t, []string{"a.txt"})
+	hctx := newArchiveContext("backups.zip", content)
+	hctx.Open = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewBufferString(string(content))), nil
+	}
+	if err := NewHandler().Handle(hctx); err == nil {
+		t.Error("Expected an error for a non-seekable reader")
+	}
+}
+
+func TestHandleRejectsNonZipContent(t *testing.T) {
+	hctx := newArchiveContext("fake.zip", []byte("this is not a zip file at all"))
+	if err := NewHandler().Handle(hctx); err == nil {
+		t.Error("Expected an error for non-ZIP content")
+	}
+	if _, ok := hctx.Node.GetProperty("archiveFiles"); ok {
+		t.Error("Broken archive should set no properties")
+	}
+}
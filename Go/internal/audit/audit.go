@@ -0,0 +1,165 @@
+// Package audit provides an append-only, hash-chained action log recording
+// what the tool touched (hosts connected, shares mounted, files opened).
+// Each entry embeds the hash of its predecessor, so truncation or edits
+// anywhere in the file break the chain and are detectable with Verify.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is one recorded action.
+type Entry struct {
+	Seq       uint64 `json:"seq"`
+	Timestamp string `json:"timestamp"`
+	Action    string `json:"action"`
+	Target    string `json:"target"`
+	Prev      string `json:"prev"`
+	Hash      string `json:"hash"`
+}
+
+// Log is an append-only audit log backed by an NDJSON file.
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  uint64
+	prev string
+}
+
+// Default is the process-wide audit log; nil means auditing is disabled.
+// It is set once at startup via Enable, before workers run.
+var defaultLog *Log
+
+// Enable installs the process-wide audit log used by Record.
+func Enable(l *Log) {
+	defaultLog = l
+}
+
+// Record appends an action to the process-wide audit log, if one is enabled.
+func Record(action, target string) {
+	if defaultLog != nil {
+		defaultLog.Record(action, target)
+	}
+}
+
+// Open opens (or creates) an audit log at path. When the file already holds
+// entries, the hash chain continues from the last one.
+func Open(path string) (*Log, error) {
+	l := &Log{}
+
+	// Continue an existing chain from the last entry
+	if data, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(data)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		var last Entry
+		for scanner.Scan() {
+			var e Entry
+			if json.Unmarshal(scanner.Bytes(), &e) == nil {
+				last = e
+			}
+		}
+		data.Close()
+		l.seq = last.Seq
+		l.prev = last.Hash
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = file
+
+	return l, nil
+}
+
+// Record appends one hash-chained entry. Write errors are swallowed —
+// auditing must never break a scan — but the chain state stays consistent.
+func (l *Log) Record(action, target string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := Entry{
+		Seq:       l.seq + 1,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Action:    action,
+		Target:    target,
+		Prev:      l.prev,
+	}
+	e.Hash = entryHash(&e)
+
+	data, err := json.Marshal(&e)
+	if err != nil {
+		return
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return
+	}
+
+	l.seq = e.Seq
+	l.prev = e.Hash
+}
+
+// Close syncs and closes the underlying file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	l.file.Sync()
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// VerifyFile walks an audit log and checks the hash chain. It returns an
+// error naming the first entry whose hash or back-link does not match.
+func VerifyFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var prev string
+	var prevSeq uint64
+	line := 0
+	for scanner.Scan() {
+		line++
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("line %d: invalid entry: %w", line, err)
+		}
+		if e.Seq != prevSeq+1 {
+			return fmt.Errorf("line %d: sequence gap (expected %d, got %d)", line, prevSeq+1, e.Seq)
+		}
+		if e.Prev != prev {
+			return fmt.Errorf("line %d: broken chain (prev hash mismatch)", line)
+		}
+		if entryHash(&e) != e.Hash {
+			return fmt.Errorf("line %d: entry hash mismatch (tampered or corrupt)", line)
+		}
+		prev = e.Hash
+		prevSeq = e.Seq
+	}
+
+	return scanner.Err()
+}
+
+// entryHash computes the chained hash of an entry (Hash field excluded).
+func entryHash(e *Entry) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s|%s|%s",
+		e.Prev, e.Seq, e.Timestamp, e.Action, e.Target)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLogChainVerifies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	log, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	log.Record("host_connect", "192.168.1.10")
+	log.Record("share_mount", "\\\\192.168.1.10\\C$")
+	log.Record("file_open", "\\\\192.168.1.10\\C$\\Windows\\win.ini")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if err := VerifyFile(path); err != nil {
+		t.Errorf("expected intact chain to verify, got: %v", err)
+	}
+
+	// Reopen and append; the chain must continue from the last entry
+	log, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	log.Record("host_connect", "192.168.1.11")
+	log.Close()
+
+	if err := VerifyFile(path); err != nil {
+		t.Errorf("expected chain to verify after reopen, got: %v", err)
+	}
+}
+
+func TestAuditLogDetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	log, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	log.Record("host_connect", "192.168.1.10")
+	log.Record("share_mount", "\\\\192.168.1.10\\C$")
+	log.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	// Edit a recorded target in place
+	tampered := strings.Replace(string(data), "C$", "D$", 1)
+	if tampered == string(data) {
+		t.Fatal("tampering substitution did not apply")
+	}
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	if err := VerifyFile(path); err == nil {
+		t.Error("expected VerifyFile to detect tampered entry")
+	}
+
+	// Deleting an entry must also break the chain
+	lines := strings.SplitAfter(string(data), "\n")
+	if err := os.WriteFile(path, []byte(lines[1]), 0o600); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := VerifyFile(path); err == nil {
+		t.Error("expected VerifyFile to detect removed entry")
+	}
+}
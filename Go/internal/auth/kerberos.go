@@ -2,11 +2,15 @@
 package auth
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	krbclient "github.com/jcmturner/gokrb5/v8/client"
 	krbconfig "github.com/jcmturner/gokrb5/v8/config"
@@ -32,12 +36,21 @@ func NewKerberosClient(opts KerberosOptions) (*krbclient.Client, error) {
 	}
 
 	if opts.KeytabPath != "" {
-		kt, err := keytab.Load(opts.KeytabPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load Kerberos keytab %q: %w", opts.KeytabPath, err)
-		}
 		if username == "" {
-			return nil, fmt.Errorf("--auth-user is required with --auth-key when using Kerberos keytab authentication")
+			return nil, fmt.Errorf("--auth-user is required with --auth-key when using Kerberos key or keytab authentication")
+		}
+		var kt *keytab.Keytab
+		var err error
+		if IsHexAESKey(opts.KeytabPath) {
+			kt, err = KeytabFromAESKey(username, realm, opts.KeytabPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build keytab from AES key: %w", err)
+			}
+		} else {
+			kt, err = keytab.Load(opts.KeytabPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load Kerberos keytab %q: %w", opts.KeytabPath, err)
+			}
 		}
 		client := krbclient.NewWithKeytab(username, realm, kt, cfg)
 		if err := client.Login(); err != nil {
@@ -69,6 +82,71 @@ func NewKerberosClient(opts KerberosOptions) (*krbclient.Client, error) {
 	return client, nil
 }
 
+// AES encryption types from RFC 3962 (aes128-cts-hmac-sha1-96, aes256-cts-hmac-sha1-96).
+const (
+	encTypeAES128 = 17
+	encTypeAES256 = 18
+)
+
+// IsHexAESKey reports whether s looks like a raw AES128 (32 hex chars) or
+// AES256 (64 hex chars) Kerberos key rather than a keytab file path.
+func IsHexAESKey(s string) bool {
+	if len(s) != 32 && len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// KeytabFromAESKey builds an in-memory keytab containing a single entry with
+// the given raw AES key, allowing pass-the-key Kerberos authentication for
+// accounts with NTLM disabled (gMSAs, hardened service accounts).
+//
+// gokrb5 does not expose keytab entry construction from raw key material, so
+// the entry is serialised in keytab v2 format and unmarshalled back.
+func KeytabFromAESKey(username, realm, hexKey string) (*keytab.Keytab, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex AES key: %w", err)
+	}
+
+	var encType uint16
+	switch len(key) {
+	case 16:
+		encType = encTypeAES128
+	case 32:
+		encType = encTypeAES256
+	default:
+		return nil, fmt.Errorf("AES key must be 16 or 32 bytes, got %d", len(key))
+	}
+
+	// Entry body (keytab v2, big endian):
+	// numComponents, realm, components, nameType, timestamp, vno8, keyblock.
+	var entry bytes.Buffer
+	binary.Write(&entry, binary.BigEndian, uint16(1)) // num components
+	binary.Write(&entry, binary.BigEndian, uint16(len(realm)))
+	entry.WriteString(realm)
+	binary.Write(&entry, binary.BigEndian, uint16(len(username)))
+	entry.WriteString(username)
+	binary.Write(&entry, binary.BigEndian, uint32(1)) // name type: KRB_NT_PRINCIPAL
+	binary.Write(&entry, binary.BigEndian, uint32(time.Now().Unix()))
+	entry.WriteByte(1) // key version number
+	binary.Write(&entry, binary.BigEndian, encType)
+	binary.Write(&entry, binary.BigEndian, uint16(len(key)))
+	entry.Write(key)
+
+	var raw bytes.Buffer
+	raw.Write([]byte{0x05, 0x02}) // keytab format version 2
+	binary.Write(&raw, binary.BigEndian, int32(entry.Len()))
+	raw.Write(entry.Bytes())
+
+	kt := keytab.New()
+	if err := kt.Unmarshal(raw.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to assemble in-memory keytab: %w", err)
+	}
+	return kt, nil
+}
+
 // LoadKerberosConfig loads KRB5_CONFIG when present, otherwise builds a minimal AD realm config.
 func LoadKerberosConfig(realm, domain, kdcHost string) (*krbconfig.Config, error) {
 	if path := kerberosConfigPath(); path != "" {
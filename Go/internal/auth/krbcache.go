@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"strings"
+	"sync"
+
+	krbclient "github.com/jcmturner/gokrb5/v8/client"
+)
+
+// Process-wide Kerberos client cache. gokrb5 clients carry the TGT obtained
+// at login and an internal per-SPN service ticket cache, and renew tickets
+// as they approach expiry, so one client can serve every host of a scan.
+var (
+	krbClientMu sync.Mutex
+	krbClients  = make(map[string]*krbclient.Client)
+	krbLogins   int
+	krbReuses   int
+)
+
+// SharedKerberosClient returns a cached Kerberos client for the given
+// credential material, creating and logging it in on first use. Reusing the
+// client across hosts means a scan of thousands of targets performs one AS
+// exchange instead of one per connection. The lock is held across the login
+// deliberately: concurrent workers racing on a cold cache would otherwise
+// each hit the KDC with their own AS exchange.
+func SharedKerberosClient(opts KerberosOptions) (*krbclient.Client, error) {
+	key := opts.cacheKey()
+
+	krbClientMu.Lock()
+	defer krbClientMu.Unlock()
+
+	if client, ok := krbClients[key]; ok {
+		krbReuses++
+		return client, nil
+	}
+
+	client, err := NewKerberosClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	krbLogins++
+	krbClients[key] = client
+	return client, nil
+}
+
+// KerberosClientStats returns how many Kerberos logins (AS exchanges) and
+// cached-client reuses have occurred, for debug logging.
+func KerberosClientStats() (logins, reuses int) {
+	krbClientMu.Lock()
+	defer krbClientMu.Unlock()
+	return krbLogins, krbReuses
+}
+
+// cacheKey identifies the credential material a client was built from; any
+// difference must produce a separate client.
+func (opts KerberosOptions) cacheKey() string {
+	return strings.Join([]string{
+		opts.Domain,
+		opts.Username,
+		opts.Password,
+		opts.KeytabPath,
+		opts.KDCHost,
+	}, "\x00")
+}
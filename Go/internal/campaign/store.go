@@ -0,0 +1,166 @@
+// Package campaign provides a small on-disk store of past scans, so runs can
+// be listed and referenced by a persistent scan ID instead of output file
+// paths.
+package campaign
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScanRecord holds the metadata of one recorded scan.
+type ScanRecord struct {
+	ID         string    `json:"id"`
+	Campaign   string    `json:"campaign,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Arguments  []string  `json:"arguments,omitempty"`
+	OutputFile string    `json:"outputFile"`
+
+	Targets     int   `json:"targets"`
+	Hosts       int64 `json:"hosts"`
+	Errors      int64 `json:"errors"`
+	Shares      int64 `json:"shares"`
+	Files       int64 `json:"files"`
+	Directories int64 `json:"directories"`
+}
+
+// Store is a directory of scan records, one metadata.json per scan ID.
+type Store struct {
+	baseDir string
+}
+
+// DefaultDir returns the default campaign store location
+// (~/.sharehound/scans, falling back to ./sharehound-scans).
+func DefaultDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".sharehound", "scans")
+	}
+	return "sharehound-scans"
+}
+
+// NewStore creates a store rooted at baseDir ("" uses the default location).
+func NewStore(baseDir string) *Store {
+	if baseDir == "" {
+		baseDir = DefaultDir()
+	}
+	return &Store{baseDir: baseDir}
+}
+
+// NewScanID generates a sortable scan ID (timestamp plus a random suffix).
+func NewScanID(start time.Time) string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%s", start.Format("20060102-150405"), hex.EncodeToString(suffix))
+}
+
+// Save writes a scan record to the store. The output file is recorded with
+// an absolute path so it stays resolvable from other working directories.
+func (s *Store) Save(rec *ScanRecord) error {
+	if rec.ID == "" {
+		return fmt.Errorf("scan record has no ID")
+	}
+	if abs, err := filepath.Abs(rec.OutputFile); err == nil {
+		rec.OutputFile = abs
+	}
+
+	dir := filepath.Join(s.baseDir, rec.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create scan directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan record: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(dir, "metadata.json"), data, 0o644)
+}
+
+// List returns all recorded scans, newest first.
+func (s *Store) List() ([]*ScanRecord, error) {
+	entries, err := os.ReadDir(s.baseDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read campaign store: %w", err)
+	}
+
+	var records []*ScanRecord
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		rec, err := s.Get(entry.Name())
+		if err != nil {
+			continue // skip unreadable or partial records
+		}
+		records = append(records, rec)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartedAt.After(records[j].StartedAt)
+	})
+	return records, nil
+}
+
+// Get loads a single scan record by ID.
+func (s *Store) Get(id string) (*ScanRecord, error) {
+	data, err := os.ReadFile(filepath.Join(s.baseDir, id, "metadata.json"))
+	if err != nil {
+		return nil, fmt.Errorf("scan %s not found: %w", id, err)
+	}
+
+	var rec ScanRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("scan %s has invalid metadata: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// Resolve maps a scan reference to an output file path. A reference that is
+// an existing file path is returned unchanged; otherwise it is treated as a
+// scan ID and looked up in the store.
+func (s *Store) Resolve(ref string) (string, error) {
+	if _, err := os.Stat(ref); err == nil {
+		return ref, nil
+	}
+
+	rec, err := s.Get(ref)
+	if err != nil {
+		return "", fmt.Errorf("%q is neither an existing file nor a known scan ID", ref)
+	}
+
+	if _, err := os.Stat(rec.OutputFile); err != nil {
+		return "", fmt.Errorf("output file of scan %s no longer exists: %s", rec.ID, rec.OutputFile)
+	}
+	return rec.OutputFile, nil
+}
+
+// SidecarPath returns the path of a named sidecar file inside a scan's
+// store directory, for artifacts that belong to a scan beyond its metadata.
+func (s *Store) SidecarPath(id, name string) string {
+	return filepath.Join(s.baseDir, id, name)
+}
+
+// FilterByCampaign narrows a record list to a single campaign name.
+func FilterByCampaign(records []*ScanRecord, campaign string) []*ScanRecord {
+	if campaign == "" {
+		return records
+	}
+	var filtered []*ScanRecord
+	for _, rec := range records {
+		if strings.EqualFold(rec.Campaign, campaign) {
+			filtered = append(filtered, rec)
+		}
+	}
+	return filtered
+}
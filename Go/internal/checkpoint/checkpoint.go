@@ -17,6 +17,7 @@ type Checkpoint struct {
 	Version          string          `json:"version"`
 	Timestamp        time.Time       `json:"timestamp"`
 	ProcessedTargets map[string]bool `json:"processed_targets"`
+	PartialTargets   map[string]bool `json:"partial_targets,omitempty"`
 	TotalTargets     int             `json:"total_targets"`
 	GraphNodes       []*graph.Node   `json:"nodes"`
 	GraphEdges       []*graph.Edge   `json:"edges"`
@@ -40,6 +41,7 @@ type Manager struct {
 	filepath         string
 	interval         time.Duration
 	processedTargets map[string]bool
+	partialTargets   map[string]bool
 	mu               sync.RWMutex
 	stopChan         chan struct{}
 	saveChan         chan struct{}
@@ -53,6 +55,7 @@ func NewManager(filepath string, interval time.Duration) *Manager {
 		filepath:         filepath,
 		interval:         interval,
 		processedTargets: make(map[string]bool),
+		partialTargets:   make(map[string]bool),
 		stopChan:         make(chan struct{}),
 		saveChan:         make(chan struct{}, 1),
 		enabled:          filepath != "",
@@ -69,6 +72,18 @@ func (m *Manager) GetFilepath() string {
 	return m.filepath
 }
 
+// MarkTargetStarted records a target as dispatched but not yet complete.
+// Targets still in this set when a checkpoint is saved were interrupted
+// mid-scan, so a resumed run knows to rescan them.
+func (m *Manager) MarkTargetStarted(target targets.Target) {
+	if !m.enabled {
+		return
+	}
+	m.mu.Lock()
+	m.partialTargets[target.Value] = true
+	m.mu.Unlock()
+}
+
 // MarkTargetProcessed marks a target as processed.
 func (m *Manager) MarkTargetProcessed(target targets.Target) {
 	if !m.enabled {
@@ -76,6 +91,7 @@ func (m *Manager) MarkTargetProcessed(target targets.Target) {
 	}
 	m.mu.Lock()
 	m.processedTargets[target.Value] = true
+	delete(m.partialTargets, target.Value)
 	m.mu.Unlock()
 }
 
@@ -155,6 +171,10 @@ func (m *Manager) saveCheckpoint(og *graph.OpenGraph, totalTargets int, stats St
 	for k, v := range m.processedTargets {
 		processed[k] = v
 	}
+	partial := make(map[string]bool)
+	for k, v := range m.partialTargets {
+		partial[k] = v
+	}
 	m.mu.RUnlock()
 
 	fmt.Fprintf(os.Stderr, "\r\033[K    [Checkpoint] Copying graph data (%d processed targets)...\n", len(processed))
@@ -164,6 +184,7 @@ func (m *Manager) saveCheckpoint(og *graph.OpenGraph, totalTargets int, stats St
 		Version:          "1.0.0",
 		Timestamp:        time.Now(),
 		ProcessedTargets: processed,
+		PartialTargets:   partial,
 		TotalTargets:     totalTargets,
 		GraphNodes:       nodes,
 		GraphEdges:       edges,
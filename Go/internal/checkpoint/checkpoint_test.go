@@ -221,3 +221,37 @@ func TestLoadInvalidJSON(t *testing.T) {
 		t.Error("Loading invalid JSON should return error")
 	}
 }
+
+func TestPartialTargets(t *testing.T) {
+	tempDir := t.TempDir()
+	cpFile := filepath.Join(tempDir, "test.checkpoint")
+
+	manager := NewManager(cpFile, time.Minute)
+	started := targets.Target{Value: "192.168.1.10", Type: "ip"}
+	finished := targets.Target{Value: "192.168.1.11", Type: "ip"}
+	manager.MarkTargetStarted(started)
+	manager.MarkTargetStarted(finished)
+	manager.MarkTargetProcessed(finished)
+
+	og, err := graph.NewOpenGraph("test")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og.Close()
+
+	manager.saveCheckpoint(og, 2, Statistics{})
+
+	cp, err := Load(cpFile)
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+	if !cp.PartialTargets["192.168.1.10"] {
+		t.Error("Interrupted target should be recorded as partial")
+	}
+	if cp.PartialTargets["192.168.1.11"] {
+		t.Error("Completed target should not be recorded as partial")
+	}
+	if !cp.ProcessedTargets["192.168.1.11"] {
+		t.Error("Completed target should be in processed targets")
+	}
+}
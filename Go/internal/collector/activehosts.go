@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HostActivity describes what a worker is currently doing on a host, for
+// the live progress panel.
+type HostActivity struct {
+	Share     string
+	Path      string
+	StartedAt time.Time
+}
+
+// ActiveHostInfo pairs a host name with its current activity.
+type ActiveHostInfo struct {
+	Host string
+	HostActivity
+}
+
+// SetHostActivity records the share and path a worker is currently walking
+// on the given host. The host's start time is preserved across updates.
+func (r *WorkerResults) SetHostActivity(host, share, path string) {
+	activity := HostActivity{Share: share, Path: path, StartedAt: time.Now()}
+	if prev, ok := r.hostActivity.Load(host); ok {
+		activity.StartedAt = prev.(HostActivity).StartedAt
+	}
+	r.hostActivity.Store(host, activity)
+}
+
+// ActiveHostActivity returns up to limit active hosts, longest-running
+// first. A limit of 0 returns all of them.
+func (r *WorkerResults) ActiveHostActivity(limit int) []ActiveHostInfo {
+	var infos []ActiveHostInfo
+	r.hostActivity.Range(func(key, value any) bool {
+		infos = append(infos, ActiveHostInfo{Host: key.(string), HostActivity: value.(HostActivity)})
+		return true
+	})
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].StartedAt.Before(infos[j].StartedAt)
+	})
+	if limit > 0 && len(infos) > limit {
+		infos = infos[:limit]
+	}
+	return infos
+}
+
+// activeHostShards is the number of shards in the active-host set. Each shard
+// has its own lock, so workers registering and unregistering different hosts
+// rarely contend with each other or with the progress tracker's snapshots.
+const activeHostShards = 16
+
+// activeHostSet is a sharded set of the host names currently being processed.
+// The zero value is ready to use.
+type activeHostSet struct {
+	shards [activeHostShards]activeHostShard
+}
+
+type activeHostShard struct {
+	mu    sync.Mutex
+	names map[string]struct{}
+}
+
+func (s *activeHostSet) shard(name string) *activeHostShard {
+	h := fnv.New32a()
+	h.Write([]byte(name)) //nolint:errcheck
+	return &s.shards[h.Sum32()%activeHostShards]
+}
+
+// Add inserts a host name into the set.
+func (s *activeHostSet) Add(name string) {
+	shard := s.shard(name)
+	shard.mu.Lock()
+	if shard.names == nil {
+		shard.names = make(map[string]struct{})
+	}
+	shard.names[name] = struct{}{}
+	shard.mu.Unlock()
+}
+
+// Remove deletes a host name from the set.
+func (s *activeHostSet) Remove(name string) {
+	shard := s.shard(name)
+	shard.mu.Lock()
+	delete(shard.names, name)
+	shard.mu.Unlock()
+}
+
+// Snapshot returns a copy of all host names currently in the set.
+func (s *activeHostSet) Snapshot() []string {
+	var names []string
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mu.Lock()
+		for name := range shard.names {
+			names = append(names, name)
+		}
+		shard.mu.Unlock()
+	}
+	return names
+}
@@ -0,0 +1,86 @@
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// Benchmarks for the per-file collection pipeline. Each iteration is one
+// file, so ns/op is the per-file dispatch cost. Run with
+//
+//	go test ./internal/collector -bench . -benchmem
+//
+// and compare against test/bench/baseline.txt before touching the handler
+// registry or the hashing path.
+
+var benchHandlerOnce sync.Once
+
+// benchContexts builds a synthetic directory tree of file contexts with the
+// extension mix of a typical file share; content is an in-memory 4 KiB blob.
+func benchContexts(n int) []*FileHandlerContext {
+	content := bytes.Repeat([]byte("sharehound"), 410)
+	extensions := []string{".txt", ".docx", ".xlsx", ".pdf", ".rdp", ".xml", ".log", ".ini"}
+
+	log := logger.NewLogger(config.NewConfig(false, nil), "")
+	contexts := make([]*FileHandlerContext, n)
+	for i := range contexts {
+		name := fmt.Sprintf("file%d%s", i, extensions[i%len(extensions)])
+		unc := fmt.Sprintf("\\\\fs01\\data\\dir%d\\%s", i%100, name)
+		contexts[i] = &FileHandlerContext{
+			Host:    "fs01",
+			Share:   "data",
+			Path:    fmt.Sprintf("dir%d\\%s", i%100, name),
+			UNCPath: unc,
+			Name:    name,
+			Size:    int64(len(content)),
+			Node:    graph.NewNode("FILE:" + unc),
+			Open: func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(content)), nil
+			},
+			Log: log,
+		}
+	}
+	return contexts
+}
+
+func BenchmarkRunFileHandlers(b *testing.B) {
+	benchHandlerOnce.Do(func() {
+		RegisterFileHandler(NewHashHandler(64 * 1024 * 1024))
+	})
+	contexts := benchContexts(1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runFileHandlers(contexts[i%len(contexts)])
+	}
+}
+
+func BenchmarkHashHandler(b *testing.B) {
+	for _, size := range []int{4 * 1024, 1024 * 1024} {
+		b.Run(fmt.Sprintf("%dKiB", size/1024), func(b *testing.B) {
+			handler := NewHashHandler(64 * 1024 * 1024)
+			content := make([]byte, size)
+			hctx := benchContexts(1)[0]
+			hctx.Size = int64(size)
+			hctx.Open = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(content)), nil
+			}
+
+			b.SetBytes(int64(size))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := handler.Handle(hctx); err != nil {
+					b.Fatalf("Handle: %v", err)
+				}
+			}
+		})
+	}
+}
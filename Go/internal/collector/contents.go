@@ -3,15 +3,18 @@ package collector
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/specterops/sharehound/internal/graph"
 	"github.com/specterops/sharehound/internal/logger"
 	"github.com/specterops/sharehound/internal/rules"
 	"github.com/specterops/sharehound/internal/smb"
+	"github.com/specterops/sharehound/internal/utils"
 	"github.com/specterops/sharehound/pkg/kinds"
 )
 
@@ -23,6 +26,7 @@ type TraversalCounts struct {
 	TotalDirectories     int64
 	SkippedDirectories   int64
 	ProcessedDirectories int64
+	SDQueryFailures      int64 // security descriptor reads that failed during this traversal
 }
 
 // Add adds another TraversalCounts to this one.
@@ -33,36 +37,77 @@ func (c *TraversalCounts) Add(other TraversalCounts) {
 	c.TotalDirectories += other.TotalDirectories
 	c.SkippedDirectories += other.SkippedDirectories
 	c.ProcessedDirectories += other.ProcessedDirectories
+	c.SDQueryFailures += other.SDQueryFailures
 }
 
 // WorkerResults holds shared worker result counters.
+//
+// All counters are atomic so hot paths (per-file and per-directory updates
+// from every worker goroutine) never contend on a shared lock. The only
+// mutexes left are per-shard locks in the active-host set and errMu around
+// the host error collections, which are touched at most once per failed
+// host operation.
 type WorkerResults struct {
-	Success int64
-	Errors  int64
+	Success atomic.Int64
+	Errors  atomic.Int64
 
-	TasksTotal    int64
-	TasksPending  int64
-	TasksFinished int64
+	TasksTotal    atomic.Int64
+	TasksPending  atomic.Int64
+	TasksFinished atomic.Int64
 
-	SharesTotal     int64
-	SharesProcessed int64
-	SharesSkipped   int64
-	SharesPending   int64
+	SharesTotal     atomic.Int64
+	SharesProcessed atomic.Int64
+	SharesSkipped   atomic.Int64
+	SharesPending   atomic.Int64
+	SharesAbandoned atomic.Int64 // shares never processed because the host timeout budget ran out
 
-	FilesTotal     int64
-	FilesProcessed int64
-	FilesSkipped   int64
-	FilesPending   int64
+	FilesTotal     atomic.Int64
+	FilesProcessed atomic.Int64
+	FilesSkipped   atomic.Int64
+	FilesPending   atomic.Int64
 
-	DirectoriesTotal     int64
-	DirectoriesProcessed int64
-	DirectoriesSkipped   int64
-	DirectoriesPending   int64
+	DirectoriesTotal     atomic.Int64
+	DirectoriesProcessed atomic.Int64
+	DirectoriesSkipped   atomic.Int64
+	DirectoriesPending   atomic.Int64
+
+	EdgesSuppressed atomic.Int64 // rights edges dropped by the SID filter (--ignore-sid)
+
+	SDQueryFailures atomic.Int64 // per-object security descriptor reads that failed, scan-wide
+
+	BytesProcessed atomic.Int64 // cumulative size of processed files, for throughput display
+
+	WorldWritableShares atomic.Int64 // shares granting write access to Everyone/Authenticated Users
 
 	// Active tracking for visibility
-	ActiveHosts       int64           // Number of hosts currently being processed
-	ActiveHostNames   map[string]bool // Names of hosts currently being processed
-	ActiveConnections int64           // Number of active SMB connections
+	ActiveHosts       atomic.Int64  // Number of hosts currently being processed
+	ActiveConnections atomic.Int64  // Number of active SMB connections
+	activeHostNames   activeHostSet // Names of hosts currently being processed
+	hostActivity      sync.Map      // host name -> HostActivity, for the live panel
+
+	// Host error taxonomy — cold path, guarded by errMu
+	errMu            sync.Mutex
+	errorsByCategory map[string]int64 // ClassifyError category -> count
+	hostErrors       []HostError      // one record per failed host operation
+}
+
+// AddActiveHost registers a host as being processed.
+func (r *WorkerResults) AddActiveHost(name string) {
+	r.ActiveHosts.Add(1)
+	r.activeHostNames.Add(name)
+	r.hostActivity.Store(name, HostActivity{StartedAt: time.Now()})
+}
+
+// RemoveActiveHost unregisters a host once processing finishes.
+func (r *WorkerResults) RemoveActiveHost(name string) {
+	r.ActiveHosts.Add(-1)
+	r.activeHostNames.Remove(name)
+	r.hostActivity.Delete(name)
+}
+
+// ActiveHostNames returns a snapshot of the hosts currently being processed.
+func (r *WorkerResults) ActiveHostNames() []string {
+	return r.activeHostNames.Snapshot()
 }
 
 // CollectContentsInShare collects contents of a share using BFS traversal.
@@ -71,7 +116,7 @@ func CollectContentsInShare(
 	ogc *graph.OpenGraphContext,
 	rulesEval *rules.Evaluator,
 	workerResults *WorkerResults,
-	resultsLock *sync.Mutex,
+	skipReport *SkipReport,
 	log logger.LoggerInterface,
 	maxDepth int,
 	timeoutFlag *atomic.Bool,
@@ -83,7 +128,7 @@ func CollectContentsInShare(
 		ogc,
 		rulesEval,
 		workerResults,
-		resultsLock,
+		skipReport,
 		log,
 		0,
 		maxDepth,
@@ -97,7 +142,7 @@ func collectContentsAtDepth(
 	ogc *graph.OpenGraphContext,
 	rulesEval *rules.Evaluator,
 	workerResults *WorkerResults,
-	resultsLock *sync.Mutex,
+	skipReport *SkipReport,
 	log logger.LoggerInterface,
 	depth int,
 	maxDepth int,
@@ -155,12 +200,28 @@ func collectContentsAtDepth(
 
 	hostName := smbSession.GetRemoteName()
 
+	if workerResults != nil {
+		workerResults.SetHostActivity(hostName, shareName, currentPath)
+	}
+
+	// Per-entry paths and rule objects are the traversal's allocation hot
+	// spot on shares with millions of files: paths come from a builder with
+	// a reused buffer and a precomputed share prefix, and the rule objects
+	// are mutated in place (the evaluator reads them synchronously)
+	pathBuilder := utils.NewPathBuilder(hostName, shareName)
+	ruleDir := &rules.RuleObjectDirectory{}
+	ruleFile := &rules.RuleObjectFile{}
+
 	for name, info := range contents {
 		// Check timeout periodically within the iteration loop.
 		// Without this, a directory with thousands of entries would process
 		// all of them even after the host timeout has fired.
 		if timeoutFlag != nil && timeoutFlag.Load() {
 			log.Debug("Timeout reached, stopping content enumeration")
+			skipReport.Add(SkipEntry{
+				Host: hostName, Share: shareName, Path: currentPath,
+				Kind: "subtree", Reason: SkipReasonHostTimeout, Depth: depth,
+			})
 			break
 		}
 
@@ -170,21 +231,44 @@ func collectContentsAtDepth(
 		}
 
 		// Build full path
-		fullPath := name
-		if currentPath != "" {
-			fullPath = filepath.Join(currentPath, name)
+		fullPath := pathBuilder.Join(currentPath, name)
+
+		// Exported name: NFC-composed, control characters and invalid UTF-8
+		// removed. SMB operations keep using the wire-exact name.
+		displayName := utils.NormalizeName(name)
+
+		// Build UNC path. Long paths come back in \\?\UNC form and invalid
+		// UTF-8 is cleaned up so node IDs stay stable and JSON-safe; the raw
+		// name stays untouched for the SMB operations below.
+		uncPath := pathBuilder.UNC(fullPath)
+
+		// Graph size budget exhausted (--max-nodes/--max-edges): keep
+		// walking the directory skeleton but stop emitting file nodes and
+		// querying their security descriptors.
+		if !info.IsDir && ogc.GraphLimitReached() {
+			counts.SkippedFiles++
+			skipReport.Add(SkipEntry{
+				Host: hostName, Share: shareName, Path: fullPath,
+				Kind: "file", Reason: SkipReasonGraphLimit, Depth: depth,
+			})
+			continue
 		}
 
-		// Build UNC path
-		uncPath := fmt.Sprintf("\\\\%s\\%s\\%s", hostName, shareName, fullPath)
-
-		// Collect NTFS rights
-		elementRights, _ := CollectNTFSRights(smbSession, ogc, fullPath, log)
+		// Collect NTFS rights. A failed security descriptor read is counted
+		// and stamped on the node below, so "no edges" and "access denied"
+		// stay distinguishable in the graph.
+		elementRights, label, sddl, sdErr := CollectNTFSRights(smbSession, ogc, fullPath, info.IsDir, log)
+		if sdErr != nil {
+			counts.SDQueryFailures++
+			if workerResults != nil {
+				workerResults.SDQueryFailures.Add(1)
+			}
+		}
 		ogc.SetElementRights(elementRights)
 
 		if info.IsDir {
 			// Directory
-			ruleDir := &rules.RuleObjectDirectory{
+			*ruleDir = rules.RuleObjectDirectory{
 				Name: name,
 				Path: fullPath,
 			}
@@ -192,27 +276,35 @@ func collectContentsAtDepth(
 			// Check if we can explore
 			if !rulesEval.CanExplore(ruleDir) {
 				counts.SkippedDirectories++
+				skipReport.Add(SkipEntry{
+					Host: hostName, Share: shareName, Path: fullPath,
+					Kind: "directory", Reason: SkipReasonRule, Depth: depth,
+				})
 				continue
 			}
 
 			counts.TotalDirectories++
 
 			// Track pending
-			if resultsLock != nil {
-				resultsLock.Lock()
-				workerResults.DirectoriesPending++
-				resultsLock.Unlock()
+			if workerResults != nil {
+				workerResults.DirectoriesPending.Add(1)
 			}
 
 			// Create directory node
 			dirNode := graph.NewNode(
 				"DIR:"+uncPath,
 				kinds.NodeKindDirectory,
-			).SetProperty("name", name).
+			).SetProperty("name", displayName).
 				SetProperty("Path", fullPath).
 				SetProperty("UNCPath", uncPath).
 				SetProperty("depth", depth)
 
+			// Keep the wire-exact name for traversal when normalization
+			// changed the exported one
+			if displayName != name {
+				dirNode.SetProperty("smbName", name)
+			}
+
 			// Add timestamp properties if available
 			if !info.CreatedTime.IsZero() {
 				dirNode.SetProperty("createdAt", info.CreatedTime.Unix())
@@ -221,6 +313,26 @@ func collectContentsAtDepth(
 				dirNode.SetProperty("modifiedAt", info.ModifiedTime.Unix())
 			}
 
+			// Mark directories whose subtree the depth limit will cut off, so
+			// the unexplored gap is visible on the node itself
+			if maxDepth > 0 && depth+1 >= maxDepth {
+				dirNode.SetProperty("subtreeSkipped", true).
+					SetProperty("skipReason", SkipReasonDepthLimit)
+				skipReport.Add(SkipEntry{
+					Host: hostName, Share: shareName, Path: fullPath,
+					Kind: "subtree", Reason: SkipReasonDepthLimit, Depth: depth + 1,
+				})
+			}
+
+			setIntegrityProperties(dirNode, label)
+			if sddl != "" {
+				dirNode.SetProperty("sddl", sddl)
+			}
+			if sdErr != nil {
+				dirNode.SetProperty("aclCollectionFailed", true).
+					SetProperty("aclFailureCategory", smb.ClassifyError(sdErr).Category)
+			}
+
 			ogc.SetElement(dirNode)
 
 			// Emit directory to the graph immediately upon discovery.
@@ -232,10 +344,8 @@ func collectContentsAtDepth(
 				counts.ProcessedDirectories++
 
 				// Decrement pending
-				if resultsLock != nil {
-					resultsLock.Lock()
-					workerResults.DirectoriesPending--
-					resultsLock.Unlock()
+				if workerResults != nil {
+					workerResults.DirectoriesPending.Add(-1)
 				}
 			}
 
@@ -249,7 +359,7 @@ func collectContentsAtDepth(
 			// File
 			ext := strings.ToLower(filepath.Ext(name))
 
-			ruleFile := &rules.RuleObjectFile{
+			*ruleFile = rules.RuleObjectFile{
 				Name:      name,
 				Path:      fullPath,
 				Size:      info.Size,
@@ -265,10 +375,8 @@ func collectContentsAtDepth(
 			counts.TotalFiles++
 
 			// Track pending
-			if resultsLock != nil {
-				resultsLock.Lock()
-				workerResults.FilesPending++
-				resultsLock.Unlock()
+			if workerResults != nil {
+				workerResults.FilesPending.Add(1)
 			}
 
 			log.Debug("📄 " + name)
@@ -277,7 +385,7 @@ func collectContentsAtDepth(
 			fileNode := graph.NewNode(
 				"FILE:"+uncPath,
 				kinds.NodeKindFile,
-			).SetProperty("name", name).
+			).SetProperty("name", displayName).
 				SetProperty("Path", fullPath).
 				SetProperty("UNCPath", uncPath).
 				SetProperty("fileSize", info.Size).
@@ -292,6 +400,15 @@ func collectContentsAtDepth(
 				fileNode.SetProperty("modifiedAt", info.ModifiedTime.Unix())
 			}
 
+			setIntegrityProperties(fileNode, label)
+			if sddl != "" {
+				fileNode.SetProperty("sddl", sddl)
+			}
+			if sdErr != nil {
+				fileNode.SetProperty("aclCollectionFailed", true).
+					SetProperty("aclFailureCategory", smb.ClassifyError(sdErr).Category)
+			}
+
 			ogc.SetElement(fileNode)
 
 			// If file has no NTFS rights (security descriptor not accessible),
@@ -319,14 +436,42 @@ func collectContentsAtDepth(
 			}
 
 			if rulesEval.CanProcess(ruleFile) {
+				// Give registered file handlers a chance to attach
+				// structured data before the node is committed
+				runFileHandlers(&FileHandlerContext{
+					Host:    hostName,
+					Share:   shareName,
+					Path:    fullPath,
+					UNCPath: uncPath,
+					Name:    name,
+					Size:    info.Size,
+					Node:    fileNode,
+					Graph:   ogc.GetGraph(),
+					Open: func() (io.ReadCloser, error) {
+						return smbSession.OpenFile(fullPath)
+					},
+					Log: log,
+				})
+
+				// Download the file as evidence when a COLLECT rule matches
+				if lc := getLootCollector(); lc != nil && rulesEval.ShouldCollect(ruleFile) {
+					err := lc.Collect(hostName, shareName, fullPath, info.Size, func() (io.ReadCloser, error) {
+						return smbSession.OpenFile(fullPath)
+					})
+					if err != nil {
+						log.Debug(fmt.Sprintf("Collection of %s skipped: %v", uncPath, err))
+					} else {
+						fileNode.SetProperty("collected", true)
+					}
+				}
+
 				ogc.AddPathToGraph()
 				counts.ProcessedFiles++
 
 				// Decrement pending
-				if resultsLock != nil {
-					resultsLock.Lock()
-					workerResults.FilesPending--
-					resultsLock.Unlock()
+				if workerResults != nil {
+					workerResults.FilesPending.Add(-1)
+					workerResults.BytesProcessed.Add(info.Size)
 				}
 			}
 		}
@@ -335,10 +480,17 @@ func collectContentsAtDepth(
 	}
 
 	// Process directories at next level (BFS)
-	for _, dir := range dirsToExplore {
+	for i, dir := range dirsToExplore {
 		// Check timeout
 		if timeoutFlag != nil && timeoutFlag.Load() {
 			log.Debug("Timeout reached, skipping remaining directories")
+			for _, remaining := range dirsToExplore[i:] {
+				skipReport.Add(SkipEntry{
+					Host: hostName, Share: shareName,
+					Path: remaining.node.GetStringProperty("Path"),
+					Kind: "subtree", Reason: SkipReasonHostTimeout, Depth: depth + 1,
+				})
+			}
 			break
 		}
 
@@ -351,7 +503,7 @@ func collectContentsAtDepth(
 			ogc,
 			rulesEval,
 			workerResults,
-			resultsLock,
+			skipReport,
 			log,
 			depth+1,
 			maxDepth,
@@ -361,16 +513,14 @@ func collectContentsAtDepth(
 		counts.Add(subCounts)
 
 		// Update worker results
-		if resultsLock != nil {
-			resultsLock.Lock()
-			workerResults.FilesTotal += subCounts.TotalFiles
-			workerResults.FilesSkipped += subCounts.SkippedFiles
-			workerResults.FilesProcessed += subCounts.ProcessedFiles
-			workerResults.DirectoriesTotal += subCounts.TotalDirectories
-			workerResults.DirectoriesSkipped += subCounts.SkippedDirectories
-			workerResults.DirectoriesProcessed += subCounts.ProcessedDirectories
-			workerResults.DirectoriesPending--
-			resultsLock.Unlock()
+		if workerResults != nil {
+			workerResults.FilesTotal.Add(subCounts.TotalFiles)
+			workerResults.FilesSkipped.Add(subCounts.SkippedFiles)
+			workerResults.FilesProcessed.Add(subCounts.ProcessedFiles)
+			workerResults.DirectoriesTotal.Add(subCounts.TotalDirectories)
+			workerResults.DirectoriesSkipped.Add(subCounts.SkippedDirectories)
+			workerResults.DirectoriesProcessed.Add(subCounts.ProcessedDirectories)
+			workerResults.DirectoriesPending.Add(-1)
 		}
 
 		ogc.PopPath()
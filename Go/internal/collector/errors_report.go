@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// HostError records one failed operation against a host, classified with
+// smb.ClassifyError so operators can triage failures by cause.
+type HostError struct {
+	Host     string `json:"host"`
+	Stage    string `json:"stage"` // "dns", "port-check", "connect", "list-shares"
+	Category string `json:"category"`
+	Message  string `json:"message"`
+}
+
+// AddHostError records a classified host error.
+func (r *WorkerResults) AddHostError(host, stage, category, message string) {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	if r.errorsByCategory == nil {
+		r.errorsByCategory = make(map[string]int64)
+	}
+	r.errorsByCategory[category]++
+	r.hostErrors = append(r.hostErrors, HostError{
+		Host:     host,
+		Stage:    stage,
+		Category: category,
+		Message:  message,
+	})
+}
+
+// HostErrorCount returns the number of recorded host errors.
+func (r *WorkerResults) HostErrorCount() int {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	return len(r.hostErrors)
+}
+
+// HostErrorCountFor returns the number of errors recorded against one host.
+func (r *WorkerResults) HostErrorCountFor(host string) int {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	count := 0
+	for _, hostError := range r.hostErrors {
+		if hostError.Host == host {
+			count++
+		}
+	}
+	return count
+}
+
+// ErrorCategoryCounts returns a copy of the per-category error counts.
+func (r *WorkerResults) ErrorCategoryCounts() map[string]int64 {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	counts := make(map[string]int64, len(r.errorsByCategory))
+	for category, count := range r.errorsByCategory {
+		counts[category] = count
+	}
+	return counts
+}
+
+// errorReport is the JSON layout of the errors sidecar file.
+type errorReport struct {
+	ByCategory map[string]int64 `json:"byCategory"`
+	Errors     []HostError      `json:"errors"`
+}
+
+// WriteErrorReport writes the per-host error breakdown as a JSON sidecar,
+// sorted by host for stable diffs.
+func WriteErrorReport(results *WorkerResults, path string) error {
+	results.errMu.Lock()
+	report := errorReport{
+		ByCategory: make(map[string]int64, len(results.errorsByCategory)),
+		Errors:     make([]HostError, len(results.hostErrors)),
+	}
+	for category, count := range results.errorsByCategory {
+		report.ByCategory[category] = count
+	}
+	copy(report.Errors, results.hostErrors)
+	results.errMu.Unlock()
+
+	sort.Slice(report.Errors, func(i, j int) bool {
+		if report.Errors[i].Host != report.Errors[j].Host {
+			return report.Errors[i].Host < report.Errors[j].Host
+		}
+		return report.Errors[i].Stage < report.Errors[j].Stage
+	})
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal error report: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,82 @@
+// Package collector provides data collection functionality for ShareHound.
+package collector
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// FileHandlerContext carries everything a handler needs to inspect a matched
+// file and attach data to the graph.
+type FileHandlerContext struct {
+	Host    string // host the share lives on
+	Share   string // share name
+	Path    string // path of the file within the share
+	UNCPath string // full UNC path, as recorded on the node
+	Name    string // file name
+	Size    int64  // file size in bytes
+
+	// Node is the file's graph node; handlers attach structured properties
+	// to it with SetProperty.
+	Node *graph.Node
+
+	// Graph is the OpenGraph under construction, for handlers that emit
+	// extra nodes or edges (for example one node per discovered credential).
+	Graph *graph.OpenGraph
+
+	// Open returns the file's content for reading. Handlers that only need
+	// metadata should not call it — every call is a fresh SMB open, and
+	// plain traversal never reads file data.
+	Open func() (io.ReadCloser, error)
+
+	Log logger.LoggerInterface
+}
+
+// FileHandler processes files matched during traversal. Implementations can
+// parse well-known formats (.rdp, web.config, unattend.xml, ...) and attach
+// structured properties or extra nodes without modifying the traversal core.
+// Handlers run on worker goroutines and must be safe for concurrent use.
+type FileHandler interface {
+	// Name identifies the handler in logs.
+	Name() string
+	// Matches reports whether the handler wants to see this file. It runs
+	// for every processed file and must be cheap — no I/O.
+	Matches(name string, size int64) bool
+	// Handle inspects the file and attaches data. Errors are logged and do
+	// not fail the traversal.
+	Handle(hctx *FileHandlerContext) error
+}
+
+var (
+	fileHandlersMu sync.RWMutex
+	fileHandlers   []FileHandler
+)
+
+// RegisterFileHandler registers a handler for files processed during
+// traversal, typically from an init function in the handler's package.
+// Handlers run in registration order.
+func RegisterFileHandler(h FileHandler) {
+	fileHandlersMu.Lock()
+	defer fileHandlersMu.Unlock()
+	fileHandlers = append(fileHandlers, h)
+}
+
+// runFileHandlers invokes every registered handler that matches the file.
+func runFileHandlers(hctx *FileHandlerContext) {
+	fileHandlersMu.RLock()
+	handlers := fileHandlers
+	fileHandlersMu.RUnlock()
+
+	for _, h := range handlers {
+		if !h.Matches(hctx.Name, hctx.Size) {
+			continue
+		}
+		if err := h.Handle(hctx); err != nil {
+			hctx.Log.Debug(fmt.Sprintf("File handler %s failed on %s: %v", h.Name(), hctx.UNCPath, err))
+		}
+	}
+}
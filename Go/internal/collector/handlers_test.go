@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// testFileHandler is a configurable handler for registry tests.
+type testFileHandler struct {
+	name    string
+	suffix  string
+	handled []string
+	err     error
+}
+
+func (h *testFileHandler) Name() string { return h.name }
+
+func (h *testFileHandler) Matches(name string, size int64) bool {
+	return strings.HasSuffix(strings.ToLower(name), h.suffix)
+}
+
+func (h *testFileHandler) Handle(hctx *FileHandlerContext) error {
+	h.handled = append(h.handled, hctx.Name)
+	if h.err != nil {
+		return h.err
+	}
+	hctx.Node.SetProperty("handledBy", h.name)
+	return nil
+}
+
+func newHandlerContext(name string) *FileHandlerContext {
+	return &FileHandlerContext{
+		Host:    "srv",
+		Share:   "data",
+		Path:    name,
+		UNCPath: "\\\\srv\\data\\" + name,
+		Name:    name,
+		Size:    int64(42),
+		Node:    graph.NewNode("FILE:\\\\srv\\data\\" + name),
+		Open: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader([]byte("content"))), nil
+		},
+		Log: logger.NewLogger(config.NewConfig(false, nil), ""),
+	}
+}
+
+func TestRunFileHandlersMatching(t *testing.T) {
+	handler := &testFileHandler{name: "rdp-parser", suffix: ".rdp"}
+	RegisterFileHandler(handler)
+
+	hctx := newHandlerContext("jump-host.rdp")
+	runFileHandlers(hctx)
+
+	if len(handler.handled) != 1 || handler.handled[0] != "jump-host.rdp" {
+		t.Fatalf("Expected handler to see jump-host.rdp, got %v", handler.handled)
+	}
+	if got := hctx.Node.GetStringProperty("handledBy"); got != "rdp-parser" {
+		t.Errorf("Expected handledBy property, got %q", got)
+	}
+
+	// A non-matching file must not reach the handler
+	runFileHandlers(newHandlerContext("notes.txt"))
+	if len(handler.handled) != 1 {
+		t.Errorf("Handler saw a non-matching file: %v", handler.handled)
+	}
+}
+
+func TestRunFileHandlersErrorDoesNotAbort(t *testing.T) {
+	failing := &testFileHandler{name: "broken", suffix: ".xml", err: errors.New("boom")}
+	following := &testFileHandler{name: "after", suffix: ".xml"}
+	RegisterFileHandler(failing)
+	RegisterFileHandler(following)
+
+	hctx := newHandlerContext("unattend.xml")
+	runFileHandlers(hctx)
+
+	if len(failing.handled) != 1 {
+		t.Error("Expected the failing handler to run")
+	}
+	if len(following.handled) != 1 {
+		t.Error("Expected handlers after a failure to still run")
+	}
+}
+
+func TestFileHandlerContextOpen(t *testing.T) {
+	hctx := newHandlerContext("web.config")
+	r, err := hctx.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "content" {
+		t.Errorf("Read %q, want %q", data, "content")
+	}
+}
@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// HashHandler computes a SHA-256 over the contents of files selected by the
+// ShareQL rules and records it as a node property, without storing any
+// content. The exported hashes correlate against DLP and known-sensitive-file
+// hash lists downstream. Registered through RegisterFileHandler when
+// --hash-files is set.
+type HashHandler struct {
+	maxSize int64
+}
+
+// NewHashHandler creates a hashing handler skipping files over maxSize bytes.
+func NewHashHandler(maxSize int64) *HashHandler {
+	return &HashHandler{maxSize: maxSize}
+}
+
+// Name implements FileHandler.
+func (h *HashHandler) Name() string {
+	return "sha256"
+}
+
+// Matches implements FileHandler. The decision is size-only: hashing means
+// reading the whole file, so the limit bounds the transfer per file.
+func (h *HashHandler) Matches(name string, size int64) bool {
+	return size > 0 && size <= h.maxSize
+}
+
+// Handle implements FileHandler. It streams the file through SHA-256 and
+// sets the sha256 property on the node.
+func (h *HashHandler) Handle(hctx *FileHandlerContext) error {
+	reader, err := hctx.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	hasher := sha256.New()
+	n, err := io.Copy(hasher, io.LimitReader(reader, h.maxSize+1))
+	if err != nil {
+		return err
+	}
+	// A hash of half the file would correlate against nothing; skip files
+	// that turn out bigger than advertised
+	if n > h.maxSize {
+		return fmt.Errorf("file exceeds the hashing size limit")
+	}
+
+	hctx.Node.SetProperty("sha256", hex.EncodeToString(hasher.Sum(nil)))
+	return nil
+}
@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestHashHandlerSetsProperty(t *testing.T) {
+	handler := NewHashHandler(1024)
+	hctx := newHandlerContext("report.docx")
+	if err := handler.Handle(hctx); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	// newHandlerContext serves the fixed content "content"
+	sum := sha256.Sum256([]byte("content"))
+	if got := hctx.Node.GetStringProperty("sha256"); got != hex.EncodeToString(sum[:]) {
+		t.Errorf("sha256 = %q", got)
+	}
+}
+
+func TestHashHandlerSizeLimit(t *testing.T) {
+	handler := NewHashHandler(100)
+	if !handler.Matches("a.txt", 100) {
+		t.Error("File at the limit should be hashed")
+	}
+	if handler.Matches("b.txt", 101) {
+		t.Error("File over the limit should be skipped")
+	}
+	if handler.Matches("empty.txt", 0) {
+		t.Error("Empty file should be skipped")
+	}
+
+	// A file streaming more bytes than advertised must not get a hash
+	oversized := NewHashHandler(3)
+	hctx := newHandlerContext("liar.txt")
+	if err := oversized.Handle(hctx); err == nil {
+		t.Error("Expected an error for oversized content")
+	}
+	if _, ok := hctx.Node.GetProperty("sha256"); ok {
+		t.Error("Oversized file should have no sha256 property")
+	}
+}
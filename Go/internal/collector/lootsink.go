@@ -0,0 +1,27 @@
+package collector
+
+import (
+	"sync"
+
+	"github.com/specterops/sharehound/internal/loot"
+)
+
+var (
+	lootMu        sync.RWMutex
+	lootCollector *loot.Collector
+)
+
+// SetLootCollector installs the evidence collector used for files matched by
+// COLLECT rules. Pass nil to disable collection.
+func SetLootCollector(c *loot.Collector) {
+	lootMu.Lock()
+	defer lootMu.Unlock()
+	lootCollector = c
+}
+
+// getLootCollector returns the installed collector, or nil.
+func getLootCollector() *loot.Collector {
+	lootMu.RLock()
+	defer lootMu.RUnlock()
+	return lootCollector
+}
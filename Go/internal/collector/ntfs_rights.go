@@ -8,28 +8,46 @@ import (
 )
 
 // CollectNTFSRights collects NTFS-level rights for a file or directory.
+// isDirectory selects the access-mask interpretation: the low mask bits are
+// overloaded by object type, so directory ACEs map to the directory edge
+// kinds (CanNTFSListDirectory and friends) instead of the file ones.
+// The mandatory integrity label from the SACL is returned alongside the
+// rights (nil when the object carries no label, i.e. default Medium), as is
+// the raw SDDL text when the context has SDDL capture enabled (--raw-sddl).
 func CollectNTFSRights(
 	smbSession *smb.SMBSession,
 	ogc *graph.OpenGraphContext,
 	filePath string,
+	isDirectory bool,
 	log logger.LoggerInterface,
-) (graph.ShareRights, error) {
+) (graph.ShareRights, *smb.MandatoryLabel, string, error) {
 	rights := make(graph.ShareRights)
 
-	// Get security descriptor for the file/directory
+	// Get security descriptor for the file/directory. The error is returned
+	// so callers can count and annotate the coverage gap — a swallowed
+	// failure here would be indistinguishable from an object with no ACEs.
 	sd, err := smbSession.GetFileSecurityDescriptor(filePath)
 	if err != nil {
 		log.Debug("[collect_ntfs_rights] Error getting security descriptor: " + err.Error())
-		return rights, nil
+		return rights, nil, "", err
 	}
 
 	if sd == nil {
-		return rights, nil
+		return rights, nil, "", nil
+	}
+
+	label := sd.MandatoryLabel()
+
+	// Rendering SDDL per object is only worth the allocations when someone
+	// asked for it
+	sddl := ""
+	if ogc.RecordSDDL() {
+		sddl = sd.SDDL()
 	}
 
 	// Check DACL
 	if sd.Dacl == nil {
-		return rights, nil
+		return rights, label, sddl, nil
 	}
 
 	// Process each ACE — only ACCESS_ALLOWED ACEs grant rights
@@ -47,12 +65,30 @@ func CollectNTFSRights(
 			continue
 		}
 
-		// Get NTFS rights for this mask
-		edgeKinds := smb.GetNTFSRightsForMask(ace.Mask)
+		// Get NTFS rights for this mask, interpreted for the object type
+		var edgeKinds []string
+		if isDirectory {
+			edgeKinds = smb.GetNTFSDirectoryRightsForMask(ace.Mask)
+		} else {
+			edgeKinds = smb.GetNTFSRightsForMask(ace.Mask)
+		}
 		if len(edgeKinds) > 0 {
 			rights[sid] = append(rights[sid], edgeKinds...)
 		}
 	}
 
-	return rights, nil
+	return rights, label, sddl, nil
+}
+
+// setIntegrityProperties copies a mandatory integrity label onto a node.
+// Objects without a label ACE default to Medium integrity and get no
+// properties, keeping the common case free of noise.
+func setIntegrityProperties(node *graph.Node, label *smb.MandatoryLabel) {
+	if label == nil {
+		return
+	}
+	node.SetProperty("integrityLevel", label.Level)
+	node.SetProperty("noWriteUp", label.NoWriteUp)
+	node.SetProperty("noReadUp", label.NoReadUp)
+	node.SetProperty("noExecuteUp", label.NoExecuteUp)
 }
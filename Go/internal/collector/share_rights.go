@@ -1,82 +1,138 @@
-// Package collector provides data collection functionality for ShareHound.
-package collector
-
-import (
-	"github.com/specterops/sharehound/internal/graph"
-	"github.com/specterops/sharehound/internal/logger"
-	"github.com/specterops/sharehound/internal/smb"
-)
-
-// CollectShareRights collects share-level rights for a share.
-func CollectShareRights(
-	smbSession *smb.SMBSession,
-	shareName string,
-	log logger.LoggerInterface,
-) (graph.ShareRights, error) {
-	rights := make(graph.ShareRights)
-
-	log.Debug("[collect_share_rights] Retrieving security descriptor for share: " + shareName)
-
-	// Try to get share security descriptor
-	sdBytes, err := smbSession.GetShareSecurityDescriptor(shareName)
-	usedFallback := false
-
-	if err != nil || len(sdBytes) == 0 {
-		// Try fallback: get root folder security descriptor
-		log.Debug("[collect_share_rights] Share-level security descriptor unavailable, trying root folder fallback...")
-		sdBytes, err = smbSession.GetShareRootSecurityDescriptor(shareName)
-		if err != nil || len(sdBytes) == 0 {
-			log.Warning("[collect_share_rights] Could not retrieve security descriptor for share: " + shareName)
-			return rights, nil
-		}
-		usedFallback = true
-		log.Debug("[collect_share_rights] Using root folder NTFS permissions as fallback")
-	}
-
-	_ = usedFallback
-
-	// Parse security descriptor
-	sd, err := smb.ParseSecurityDescriptor(sdBytes)
-	if err != nil {
-		log.Debug("[collect_share_rights] Failed to parse security descriptor: " + err.Error())
-		return rights, err
-	}
-
-	// Check DACL
-	if sd.Dacl == nil {
-		log.Debug("[collect_share_rights] DACL is None for share: " + shareName)
-		return rights, nil
-	}
-
-	if len(sd.Dacl.Aces) == 0 {
-		log.Debug("[collect_share_rights] DACL is empty (no ACEs) for share: " + shareName)
-		return rights, nil
-	}
-
-	log.Debug("[collect_share_rights] Processing " + string(rune(len(sd.Dacl.Aces)+'0')) + " ACE(s)")
-
-	// Process each ACE
-	for _, ace := range sd.Dacl.Aces {
-		// Only process ACCESS_ALLOWED ACEs
-		if !ace.IsAccessAllowed() {
-			continue
-		}
-
-		if ace.SID == nil {
-			continue
-		}
-
-		sid := ace.SID.String()
-		if sid == "" {
-			continue
-		}
-
-		// Get rights for this mask
-		edgeKinds := smb.GetShareRightsForMask(ace.Mask)
-		if len(edgeKinds) > 0 {
-			rights[sid] = append(rights[sid], edgeKinds...)
-		}
-	}
-
-	return rights, nil
-}
+// Package collector provides data collection functionality for ShareHound.
+package collector
+
+import (
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+	"github.com/specterops/sharehound/internal/smb"
+)
+
+// ACL source provenance values recorded on share rights edges so consumers
+// can judge how trustworthy the rights are: the SRVSVC share SD is
+// authoritative for the share boundary, the root NTFS SD is an
+// approximation, and an access probe proves only that the scanning account
+// could list the root.
+const (
+	ACLSourceSRVSVC      = "srvsvc"
+	ACLSourceRegistry    = "registry"
+	ACLSourceRootNTFS    = "ntfs-root"
+	ACLSourceAccessProbe = "access-probe"
+)
+
+// CollectShareRights collects share-level rights for a share.
+//
+// Sources are tried in order of decreasing trust: the SRVSVC share security
+// descriptor, the same blob read via Remote Registry (opt-in), the NTFS
+// security descriptor of the share root, then a plain access probe (listing
+// the share root). The second return value names
+// the source that produced the result (one of the ACLSource* constants, or
+// "" when every method failed) so it can be recorded as provenance; the
+// third is the descriptor rendered as SDDL, for --raw-sddl capture. When no
+// source succeeds the SRVSVC failure is returned as the error, so callers
+// can tell an unreadable ACL apart from one that simply grants nothing.
+func CollectShareRights(
+	smbSession *smb.SMBSession,
+	shareName string,
+	log logger.LoggerInterface,
+) (graph.ShareRights, string, string, error) {
+	log.Debug("[collect_share_rights] Retrieving security descriptor for share: " + shareName)
+
+	// 1. SRVSVC share security descriptor (authoritative for the share boundary).
+	// Its failure is kept as the representative cause when every method fails,
+	// since the fallbacks are often legitimately unavailable.
+	sdBytes, srvsvcErr := smbSession.GetShareSecurityDescriptor(shareName)
+	if srvsvcErr == nil && len(sdBytes) > 0 {
+		rights, sddl, parseErr := rightsFromSecurityDescriptor(sdBytes, log)
+		if parseErr == nil {
+			return rights, ACLSourceSRVSVC, sddl, nil
+		}
+		log.Debug("[collect_share_rights] Failed to parse SRVSVC security descriptor: " + parseErr.Error())
+		srvsvcErr = parseErr
+	}
+
+	// 2. Remote Registry (opt-in): the LanmanServer Shares\Security key
+	// holds the same share SD and is often still readable when SRVSVC
+	// denies NetrShareGetInfo level 502
+	if sdBytes, err := smbSession.GetShareSecurityDescriptorFromRegistry(shareName); err == nil && len(sdBytes) > 0 {
+		rights, sddl, parseErr := rightsFromSecurityDescriptor(sdBytes, log)
+		if parseErr == nil {
+			log.Debug("[collect_share_rights] Using share security descriptor from remote registry")
+			return rights, ACLSourceRegistry, sddl, nil
+		}
+		log.Debug("[collect_share_rights] Failed to parse registry security descriptor: " + parseErr.Error())
+	}
+
+	// 3. NTFS security descriptor of the share root directory
+	log.Debug("[collect_share_rights] Share-level security descriptor unavailable, trying root folder fallback...")
+	if sdBytes, err := smbSession.GetShareRootSecurityDescriptor(shareName); err == nil && len(sdBytes) > 0 {
+		rights, sddl, parseErr := rightsFromSecurityDescriptor(sdBytes, log)
+		if parseErr == nil {
+			log.Debug("[collect_share_rights] Using root folder NTFS permissions as fallback")
+			return rights, ACLSourceRootNTFS, sddl, nil
+		}
+		log.Debug("[collect_share_rights] Failed to parse root folder security descriptor: " + parseErr.Error())
+	}
+
+	// 4. Access probe: no ACL could be read, but a successful listing of the
+	// share root still proves the scanning account has read access. No
+	// SID-keyed rights can be derived from it, so the rights map stays empty.
+	if _, err := smbSession.ListContents(""); err == nil {
+		log.Debug("[collect_share_rights] No security descriptor available; access probe confirmed the share root is listable")
+		return make(graph.ShareRights), ACLSourceAccessProbe, "", nil
+	}
+
+	log.Warning("[collect_share_rights] Could not retrieve security descriptor for share: " + shareName)
+	return make(graph.ShareRights), "", "", srvsvcErr
+}
+
+// rightsFromSecurityDescriptor parses a binary security descriptor and maps
+// its ACCESS_ALLOWED ACEs to share rights edge kinds. The SDDL rendering of
+// the descriptor is returned alongside; at one per share it is cheap enough
+// to produce unconditionally.
+func rightsFromSecurityDescriptor(sdBytes []byte, log logger.LoggerInterface) (graph.ShareRights, string, error) {
+	rights := make(graph.ShareRights)
+
+	sd, err := smb.ParseSecurityDescriptor(sdBytes)
+	if err != nil {
+		return rights, "", err
+	}
+	sddl := sd.SDDL()
+
+	// Check DACL
+	if sd.Dacl == nil {
+		log.Debug("[collect_share_rights] DACL is None")
+		return rights, sddl, nil
+	}
+
+	if len(sd.Dacl.Aces) == 0 {
+		log.Debug("[collect_share_rights] DACL is empty (no ACEs)")
+		return rights, sddl, nil
+	}
+
+	log.Debug("[collect_share_rights] Processing " + string(rune(len(sd.Dacl.Aces)+'0')) + " ACE(s)")
+
+	// Process each ACE
+	for _, ace := range sd.Dacl.Aces {
+		// Only process ACCESS_ALLOWED ACEs
+		if !ace.IsAccessAllowed() {
+			continue
+		}
+
+		if ace.SID == nil {
+			continue
+		}
+
+		sid := ace.SID.String()
+		if sid == "" {
+			continue
+		}
+
+		// Get rights for this mask
+		edgeKinds := smb.GetShareRightsForMask(ace.Mask)
+		if len(edgeKinds) > 0 {
+			rights[sid] = append(rights[sid], edgeKinds...)
+		}
+	}
+
+	return rights, sddl, nil
+}
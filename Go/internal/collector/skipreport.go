@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Skip reasons recorded in the report and on skipped nodes.
+const (
+	SkipReasonRule        = "rule"
+	SkipReasonDepthLimit  = "depth-limit"
+	SkipReasonHostTimeout = "host-timeout"
+	SkipReasonGraphLimit  = "graph-limit"
+)
+
+// SkipEntry records one share, directory or subtree that was not collected.
+type SkipEntry struct {
+	Host   string `json:"host"`
+	Share  string `json:"share"`
+	Path   string `json:"path,omitempty"`
+	Kind   string `json:"kind"` // "share", "directory" or "subtree"
+	Reason string `json:"reason"`
+	Depth  int    `json:"depth"`
+}
+
+// SkipReport accumulates skipped work across workers so the gaps in a scan
+// are visible post-scan instead of being silently dropped.
+type SkipReport struct {
+	mu      sync.Mutex
+	entries []SkipEntry
+}
+
+// NewSkipReport creates an empty skip report.
+func NewSkipReport() *SkipReport {
+	return &SkipReport{}
+}
+
+// Add records a skipped item. Safe to call on a nil report, so call sites
+// don't have to check whether reporting is enabled.
+func (r *SkipReport) Add(entry SkipEntry) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+}
+
+// Count returns the number of recorded entries.
+func (r *SkipReport) Count() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// WriteFile writes the report as a JSON array, sorted by host, share and path
+// for stable diffs between runs.
+func (r *SkipReport) WriteFile(path string) error {
+	r.mu.Lock()
+	entries := make([]SkipEntry, len(r.entries))
+	copy(entries, r.entries)
+	r.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Host != entries[j].Host {
+			return entries[i].Host < entries[j].Host
+		}
+		if entries[i].Share != entries[j].Share {
+			return entries[i].Share < entries[j].Share
+		}
+		return entries[i].Path < entries[j].Path
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal skip report: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
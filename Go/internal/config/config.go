@@ -7,8 +7,10 @@ import (
 
 // Config holds the configuration settings for ShareHound.
 type Config struct {
-	debug    bool
-	noColors bool
+	debug              bool
+	noColors           bool
+	opsecStrict        bool
+	registrySDFallback bool
 }
 
 // NewConfig creates a new Config with the given settings.
@@ -42,6 +44,30 @@ func (c *Config) SetDebug(value bool) {
 	c.debug = value
 }
 
+// OpsecStrict returns whether the strict OPSEC profile is active. When set,
+// noisy behaviors (SRVSVC RPC calls, write probes) are avoided and
+// connections are rate-limited and jittered.
+func (c *Config) OpsecStrict() bool {
+	return c.opsecStrict
+}
+
+// SetOpsecStrict sets the strict OPSEC profile.
+func (c *Config) SetOpsecStrict(value bool) {
+	c.opsecStrict = value
+}
+
+// RegistrySDFallback returns whether share security descriptors may be read
+// from the LanmanServer registry key via Remote Registry when SRVSVC denies
+// NetrShareGetInfo.
+func (c *Config) RegistrySDFallback() bool {
+	return c.registrySDFallback
+}
+
+// SetRegistrySDFallback sets the Remote Registry fallback.
+func (c *Config) SetRegistrySDFallback(value bool) {
+	c.registrySDFallback = value
+}
+
 // NoColors returns whether colored output is disabled.
 func (c *Config) NoColors() bool {
 	return c.noColors
@@ -0,0 +1,250 @@
+// Package control provides a local control socket so an operator can pause
+// dispatching, skip a stuck host or query status of a running scan without
+// killing the process and relying on checkpoints.
+package control
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultSocketPath returns the default control socket location.
+func DefaultSocketPath() string {
+	return filepath.Join(os.TempDir(), "sharehound.sock")
+}
+
+// Server listens on a local stream socket and answers one-line commands:
+// pause, resume, status and skip-host <host>. Unix sockets are used on all
+// platforms; Go supports AF_UNIX on Windows 10 and later.
+type Server struct {
+	listener net.Listener
+	statusFn func() string
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	paused  bool
+	skip    map[string]struct{}           // hosts to drop when they come up for dispatch
+	active  map[string]func()             // in-flight hosts -> abort callback
+	setters map[string]func(string) error // runtime-adjustable settings
+}
+
+// NewServer creates a control server. statusFn is invoked for each status
+// command and should return a one-line scan summary.
+func NewServer(statusFn func() string) *Server {
+	s := &Server{
+		statusFn: statusFn,
+		skip:     make(map[string]struct{}),
+		active:   make(map[string]func()),
+		setters:  make(map[string]func(string) error),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Start begins listening on path. A stale socket file left behind by a
+// previous run is removed first.
+func (s *Server) Start(path string) error {
+	os.Remove(path) //nolint:errcheck
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on control socket: %w", err)
+	}
+	s.listener = listener
+	go s.acceptLoop()
+	return nil
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return // listener closed
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+	fmt.Fprintln(conn, s.dispatch(strings.TrimSpace(line))) //nolint:errcheck
+}
+
+func (s *Server) dispatch(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "error: empty command"
+	}
+
+	switch fields[0] {
+	case "pause":
+		s.mu.Lock()
+		s.paused = true
+		s.mu.Unlock()
+		return "paused: no new hosts will be dispatched"
+	case "resume":
+		s.mu.Lock()
+		s.paused = false
+		s.cond.Broadcast()
+		s.mu.Unlock()
+		return "resumed"
+	case "status":
+		s.mu.Lock()
+		paused := s.paused
+		s.mu.Unlock()
+		status := s.statusFn()
+		if paused {
+			status += " (paused)"
+		}
+		return status
+	case "skip-host":
+		if len(fields) != 2 {
+			return "usage: skip-host <host>"
+		}
+		return s.skipHost(fields[1])
+	case "set":
+		if len(fields) != 3 {
+			return "usage: set <setting> <value>"
+		}
+		return s.applySetting(fields[1], fields[2])
+	default:
+		return fmt.Sprintf("error: unknown command %q (supported: pause, resume, status, skip-host, set)", fields[0])
+	}
+}
+
+// applySetting routes a set command to the setter registered under name.
+func (s *Server) applySetting(name, value string) string {
+	s.mu.Lock()
+	setter, ok := s.setters[name]
+	if !ok {
+		names := make([]string, 0, len(s.setters))
+		for known := range s.setters {
+			names = append(names, known)
+		}
+		s.mu.Unlock()
+		sort.Strings(names)
+		return fmt.Sprintf("error: unknown setting %q (supported: %s)", name, strings.Join(names, ", "))
+	}
+	s.mu.Unlock()
+
+	if err := setter(value); err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return fmt.Sprintf("ok: %s = %s", name, value)
+}
+
+// skipHost aborts an in-flight host or, if it has not been dispatched yet,
+// marks it to be dropped when its turn comes.
+func (s *Server) skipHost(host string) string {
+	s.mu.Lock()
+	abort, running := s.active[host]
+	if !running {
+		s.skip[host] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	if running {
+		abort()
+		return fmt.Sprintf("aborting active host %s", host)
+	}
+	return fmt.Sprintf("host %s will be skipped when dispatched", host)
+}
+
+// RegisterSetting exposes a runtime-adjustable setting over the socket via
+// "set <name> <value>". The setter validates the value and applies it.
+func (s *Server) RegisterSetting(name string, setter func(value string) error) {
+	s.mu.Lock()
+	s.setters[name] = setter
+	s.mu.Unlock()
+}
+
+// WaitIfPaused blocks the dispatcher while the scan is paused.
+func (s *Server) WaitIfPaused() {
+	s.mu.Lock()
+	for s.paused {
+		s.cond.Wait()
+	}
+	s.mu.Unlock()
+}
+
+// ShouldSkip reports whether the operator asked to skip this host.
+func (s *Server) ShouldSkip(host string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, skip := s.skip[host]
+	return skip
+}
+
+// RegisterHost exposes an abort callback for an in-flight host so that
+// skip-host can unstick it.
+func (s *Server) RegisterHost(host string, abort func()) {
+	s.mu.Lock()
+	s.active[host] = abort
+	s.mu.Unlock()
+}
+
+// UnregisterHost removes a host's abort callback once processing finishes.
+func (s *Server) UnregisterHost(host string) {
+	s.mu.Lock()
+	delete(s.active, host)
+	s.mu.Unlock()
+}
+
+// AbortAllHosts invokes the abort callback of every in-flight host. It is
+// used when the scan deadline's grace period expires and the remaining hosts
+// must be cut off so checkpointing and export can run.
+func (s *Server) AbortAllHosts() {
+	s.mu.Lock()
+	aborts := make([]func(), 0, len(s.active))
+	for _, abort := range s.active {
+		aborts = append(aborts, abort)
+	}
+	s.mu.Unlock()
+
+	for _, abort := range aborts {
+		abort()
+	}
+}
+
+// Close stops the listener, removes the socket file and unblocks a paused
+// dispatcher so shutdown can proceed.
+func (s *Server) Close() {
+	if s.listener != nil {
+		path := s.listener.Addr().String()
+		s.listener.Close() //nolint:errcheck
+		os.Remove(path)    //nolint:errcheck
+	}
+	s.mu.Lock()
+	s.paused = false
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
+
+// Send delivers one command to the control socket at path and returns the
+// server's reply.
+func Send(path, command string) (string, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return "", fmt.Errorf("cannot reach control socket %s (is a scan running with --control-socket?): %w", path, err)
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return "", err
+	}
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(reply)), nil
+}
@@ -0,0 +1,131 @@
+package control
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestControlSocketCommands(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "ctl.sock")
+
+	server := NewServer(func() string { return "hosts: 1/2 done" })
+	if err := server.Start(socket); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer server.Close()
+
+	reply, err := Send(socket, "pause")
+	if err != nil {
+		t.Fatalf("Send(pause): %v", err)
+	}
+	if !strings.Contains(reply, "paused") {
+		t.Errorf("pause reply = %q, want it to mention paused", reply)
+	}
+
+	reply, err = Send(socket, "status")
+	if err != nil {
+		t.Fatalf("Send(status): %v", err)
+	}
+	if !strings.Contains(reply, "hosts: 1/2 done") || !strings.Contains(reply, "(paused)") {
+		t.Errorf("status reply = %q, want scan summary with paused marker", reply)
+	}
+
+	if _, err := Send(socket, "resume"); err != nil {
+		t.Fatalf("Send(resume): %v", err)
+	}
+	// Must not block after resume
+	server.WaitIfPaused()
+
+	reply, err = Send(socket, "skip-host 10.0.0.5")
+	if err != nil {
+		t.Fatalf("Send(skip-host): %v", err)
+	}
+	if !strings.Contains(reply, "10.0.0.5") {
+		t.Errorf("skip-host reply = %q, want it to name the host", reply)
+	}
+	if !server.ShouldSkip("10.0.0.5") {
+		t.Error("ShouldSkip(10.0.0.5) = false after skip-host")
+	}
+
+	if reply, _ := Send(socket, "bogus"); !strings.Contains(reply, "unknown command") {
+		t.Errorf("bogus reply = %q, want unknown command error", reply)
+	}
+}
+
+func TestSetCommand(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "ctl.sock")
+
+	server := NewServer(func() string { return "" })
+	if err := server.Start(socket); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer server.Close()
+
+	applied := ""
+	server.RegisterSetting("threads", func(value string) error {
+		applied = value
+		return nil
+	})
+
+	reply, err := Send(socket, "set threads 32")
+	if err != nil {
+		t.Fatalf("Send(set): %v", err)
+	}
+	if !strings.Contains(reply, "ok") || applied != "32" {
+		t.Errorf("set reply = %q, applied = %q, want ok with value 32", reply, applied)
+	}
+
+	if reply, _ := Send(socket, "set nope 1"); !strings.Contains(reply, "unknown setting") || !strings.Contains(reply, "threads") {
+		t.Errorf("set nope reply = %q, want unknown setting error listing threads", reply)
+	}
+
+	if reply, _ := Send(socket, "set threads"); !strings.Contains(reply, "usage") {
+		t.Errorf("incomplete set reply = %q, want usage error", reply)
+	}
+}
+
+func TestSkipHostAbortsActiveHost(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "ctl.sock")
+
+	server := NewServer(func() string { return "" })
+	if err := server.Start(socket); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer server.Close()
+
+	aborted := false
+	server.RegisterHost("fileserver01", func() { aborted = true })
+
+	reply, err := Send(socket, "skip-host fileserver01")
+	if err != nil {
+		t.Fatalf("Send(skip-host): %v", err)
+	}
+	if !strings.Contains(reply, "aborting") {
+		t.Errorf("skip-host reply = %q, want aborting message", reply)
+	}
+	if !aborted {
+		t.Error("abort callback was not invoked for active host")
+	}
+	if server.ShouldSkip("fileserver01") {
+		t.Error("active host should not be queued for skip")
+	}
+}
+
+func TestAbortAllHosts(t *testing.T) {
+	server := NewServer(func() string { return "" })
+
+	aborted := make(map[string]bool)
+	server.RegisterHost("fileserver01", func() { aborted["fileserver01"] = true })
+	server.RegisterHost("fileserver02", func() { aborted["fileserver02"] = true })
+	server.UnregisterHost("fileserver02")
+
+	server.AbortAllHosts()
+
+	if !aborted["fileserver01"] {
+		t.Error("abort callback was not invoked for in-flight host")
+	}
+	if aborted["fileserver02"] {
+		t.Error("abort callback was invoked for a host that already finished")
+	}
+}
@@ -25,6 +25,10 @@ type Credentials struct {
 	WindowsAuth bool
 	AESKey      string
 	KDCHost     string
+
+	// LocalAuth authenticates against local accounts by using the target
+	// hostname as the NTLM domain (workgroup hosts, standalone NAS devices).
+	LocalAuth bool
 }
 
 // NewCredentials creates a new Credentials instance.
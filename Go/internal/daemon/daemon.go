@@ -0,0 +1,173 @@
+// Package daemon runs recurring scans from a persistent configuration so
+// ShareHound can operate as a systemd unit or Windows service without an
+// external cron wrapper. Each scheduled run re-executes the sharehound
+// binary as a child process, keeping runs isolated and letting the normal
+// checkpoint and shutdown handling apply to every scan.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// Config is the persistent daemon configuration, loaded from a JSON file.
+type Config struct {
+	// Interval between scan starts, as a Go duration string (e.g. "24h").
+	Interval string `json:"interval"`
+	// RunOnStart starts the first scan immediately instead of waiting for
+	// the first interval to elapse.
+	RunOnStart bool `json:"run_on_start,omitempty"`
+	// Args are scan arguments exactly as they would appear on the command
+	// line: targets, credentials, rules, tuning.
+	Args []string `json:"args"`
+	// OutputDir receives one timestamped graph file per run.
+	OutputDir string `json:"output_dir"`
+	// ControlSocket, when set, is passed to every run so operators can
+	// pause, skip hosts and tune the active scan (see "sharehound ctl").
+	ControlSocket string `json:"control_socket,omitempty"`
+	// Campaign, when set, records every run in the campaign store under a
+	// shared campaign name.
+	Campaign string `json:"campaign,omitempty"`
+
+	interval time.Duration
+}
+
+// LoadConfig reads and validates a daemon configuration file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daemon configuration: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon configuration: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	if c.Interval == "" {
+		return fmt.Errorf("interval is required")
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid interval %q: %w", c.Interval, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	c.interval = d
+
+	if c.OutputDir == "" {
+		return fmt.Errorf("output_dir is required")
+	}
+	return nil
+}
+
+// scanArgs builds a run's command line from the configured scan arguments
+// plus the per-run output file and the daemon-level settings.
+func (c *Config) scanArgs(output string) []string {
+	args := append([]string{}, c.Args...)
+	args = append(args, "--output", output)
+	if c.ControlSocket != "" {
+		args = append(args, "--control-socket", c.ControlSocket)
+	}
+	if c.Campaign != "" {
+		args = append(args, "--campaign", c.Campaign)
+	}
+	return args
+}
+
+// Daemon schedules scans according to a Config.
+type Daemon struct {
+	cfg *Config
+	log logger.LoggerInterface
+	exe string
+}
+
+// New creates a daemon that re-executes the current binary for each run.
+func New(cfg *Config, log logger.LoggerInterface) (*Daemon, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("cannot locate own binary: %w", err)
+	}
+	return &Daemon{cfg: cfg, log: log, exe: exe}, nil
+}
+
+// Run executes scans on the configured schedule until stop is closed. A
+// stop arriving during a scan is forwarded to the child process so its own
+// graceful shutdown (checkpoint save, bounded grace period) applies.
+func (d *Daemon) Run(stop <-chan struct{}) error {
+	if err := os.MkdirAll(d.cfg.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("cannot create output directory: %w", err)
+	}
+
+	if d.cfg.RunOnStart {
+		d.runOnce(stop)
+	}
+
+	ticker := time.NewTicker(d.cfg.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			d.runOnce(stop)
+		}
+	}
+}
+
+func (d *Daemon) runOnce(stop <-chan struct{}) {
+	select {
+	case <-stop:
+		return
+	default:
+	}
+
+	output := filepath.Join(d.cfg.OutputDir, time.Now().Format("sharehound_20060102_150405.zip"))
+	d.log.Info(fmt.Sprintf("Starting scheduled scan, output \"%s\"", output))
+
+	cmd := exec.Command(d.exe, d.cfg.scanArgs(output)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		d.log.Error(fmt.Sprintf("Failed to start scan: %v", err))
+		return
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case <-stop:
+		d.log.Warning("Daemon stopping, terminating the active scan...")
+		terminate(cmd.Process)
+		<-done
+	case err := <-done:
+		if err != nil {
+			d.log.Error(fmt.Sprintf("Scheduled scan exited with error: %v", err))
+		} else {
+			d.log.Info("Scheduled scan completed")
+		}
+	}
+}
+
+// terminate asks the child to shut down gracefully, falling back to Kill
+// on platforms where the signal cannot be delivered (Windows).
+func terminate(p *os.Process) {
+	if err := p.Signal(syscall.SIGTERM); err != nil {
+		p.Kill() //nolint:errcheck
+	}
+}
@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "daemon.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `{
+		"interval": "24h",
+		"run_on_start": true,
+		"args": ["--targets-file", "/etc/sharehound/targets.txt"],
+		"output_dir": "/var/lib/sharehound",
+		"control_socket": "/run/sharehound.sock",
+		"campaign": "quarterly-audit"
+	}`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.interval != 24*time.Hour {
+		t.Errorf("interval = %v, want 24h", cfg.interval)
+	}
+	if !cfg.RunOnStart {
+		t.Error("run_on_start should be set")
+	}
+	if cfg.OutputDir != "/var/lib/sharehound" {
+		t.Errorf("output_dir = %q", cfg.OutputDir)
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing interval", `{"output_dir": "/tmp/out"}`, "interval is required"},
+		{"bad interval", `{"interval": "yearly", "output_dir": "/tmp/out"}`, "invalid interval"},
+		{"negative interval", `{"interval": "-1h", "output_dir": "/tmp/out"}`, "must be positive"},
+		{"missing output dir", `{"interval": "1h"}`, "output_dir is required"},
+		{"invalid json", `{`, "failed to parse"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("LoadConfig error = %v, want containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestScanArgs(t *testing.T) {
+	cfg := &Config{
+		Args:          []string{"--targets-file", "targets.txt"},
+		ControlSocket: "/run/sharehound.sock",
+		Campaign:      "quarterly-audit",
+	}
+
+	args := cfg.scanArgs("/var/lib/sharehound/run1.zip")
+	joined := strings.Join(args, " ")
+	if !strings.HasPrefix(joined, "--targets-file targets.txt") {
+		t.Errorf("configured args should come first: %v", args)
+	}
+	if !strings.Contains(joined, "--output /var/lib/sharehound/run1.zip") {
+		t.Errorf("output file missing: %v", args)
+	}
+	if !strings.Contains(joined, "--control-socket /run/sharehound.sock") {
+		t.Errorf("control socket missing: %v", args)
+	}
+	if !strings.Contains(joined, "--campaign quarterly-audit") {
+		t.Errorf("campaign missing: %v", args)
+	}
+
+	minimal := (&Config{Args: []string{"-tf", "t.txt"}}).scanArgs("out.zip")
+	if strings.Contains(strings.Join(minimal, " "), "--control-socket") {
+		t.Errorf("unset settings should not be passed: %v", minimal)
+	}
+}
@@ -0,0 +1,51 @@
+package distrib
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// SendGraph streams a finished scan's nodes and edges to the aggregator at
+// addr and waits for its acknowledgement, so the agent only reports success
+// once the results have been merged.
+func SendGraph(addr string, tlsConfig *tls.Config, agentName, scanID string, og *graph.OpenGraph, log logger.LoggerInterface) error {
+	conn, err := tls.Dial("tcp", addr, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to aggregator: %w", err)
+	}
+	defer conn.Close()
+
+	nodes, edges := og.GetNodesAndEdges()
+	log.Info(fmt.Sprintf("Streaming %d nodes, %d edges to aggregator %s", len(nodes), len(edges), addr))
+
+	encoder := json.NewEncoder(conn)
+	if err := encoder.Encode(Message{Type: MessageHello, Hello: &Hello{Agent: agentName, ScanID: scanID}}); err != nil {
+		return fmt.Errorf("failed to send hello: %w", err)
+	}
+	for _, node := range nodes {
+		if err := encoder.Encode(Message{Type: MessageNode, Node: node}); err != nil {
+			return fmt.Errorf("failed to send node: %w", err)
+		}
+	}
+	for _, edge := range edges {
+		if err := encoder.Encode(Message{Type: MessageEdge, Edge: edge}); err != nil {
+			return fmt.Errorf("failed to send edge: %w", err)
+		}
+	}
+	if err := encoder.Encode(Message{Type: MessageDone}); err != nil {
+		return fmt.Errorf("failed to send done marker: %w", err)
+	}
+
+	var ack Message
+	if err := json.NewDecoder(conn).Decode(&ack); err != nil {
+		return fmt.Errorf("aggregator did not acknowledge the stream: %w", err)
+	}
+	if ack.Type != MessageDone {
+		return fmt.Errorf("unexpected acknowledgement %q from aggregator", ack.Type)
+	}
+	return nil
+}
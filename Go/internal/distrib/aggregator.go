@@ -0,0 +1,108 @@
+package distrib
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// Aggregator accepts result streams from collector agents and merges them
+// into a single OpenGraph. Node and edge deduplication falls out of the
+// graph's own duplicate handling, so overlapping agent scopes are safe.
+type Aggregator struct {
+	og       *graph.OpenGraph
+	log      logger.LoggerInterface
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewAggregator creates an aggregator merging into og.
+func NewAggregator(og *graph.OpenGraph, log logger.LoggerInterface) *Aggregator {
+	return &Aggregator{og: og, log: log}
+}
+
+// Start begins accepting agent connections on addr. tlsConfig must require
+// client certificates (see ServerTLSConfig).
+func (a *Aggregator) Start(addr string, tlsConfig *tls.Config) error {
+	listener, err := tls.Listen("tcp", addr, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	a.listener = listener
+
+	a.wg.Add(1)
+	go a.acceptLoop()
+	return nil
+}
+
+func (a *Aggregator) acceptLoop() {
+	defer a.wg.Done()
+	for {
+		conn, err := a.listener.Accept()
+		if err != nil {
+			return // listener closed
+		}
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			defer conn.Close()
+			a.handle(conn)
+		}()
+	}
+}
+
+func (a *Aggregator) handle(conn net.Conn) {
+	agent := conn.RemoteAddr().String()
+	nodes, edges := 0, 0
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var msg Message
+		if err := decoder.Decode(&msg); err != nil {
+			if err != io.EOF {
+				a.log.Warning(fmt.Sprintf("Agent %s: stream ended abnormally after %d nodes, %d edges: %v", agent, nodes, edges, err))
+			}
+			return
+		}
+
+		switch msg.Type {
+		case MessageHello:
+			if msg.Hello != nil && msg.Hello.Agent != "" {
+				agent = msg.Hello.Agent
+			}
+			a.log.Info(fmt.Sprintf("Agent %s connected", agent))
+		case MessageNode:
+			if msg.Node != nil {
+				a.og.AddNode(msg.Node)
+				nodes++
+			}
+		case MessageEdge:
+			if msg.Edge != nil {
+				a.og.AddEdge(msg.Edge)
+				edges++
+			}
+		case MessageDone:
+			a.log.Info(fmt.Sprintf("Agent %s finished: %d nodes, %d edges merged", agent, nodes, edges))
+			// Acknowledge so the agent knows the stream was merged
+			json.NewEncoder(conn).Encode(Message{Type: MessageDone}) //nolint:errcheck
+			return
+		default:
+			a.log.Warning(fmt.Sprintf("Agent %s sent unknown message type %q, dropping connection", agent, msg.Type))
+			return
+		}
+	}
+}
+
+// Close stops accepting new agents and waits for active streams to finish.
+func (a *Aggregator) Close() {
+	if a.listener != nil {
+		a.listener.Close() //nolint:errcheck
+	}
+	a.wg.Wait()
+}
@@ -0,0 +1,178 @@
+package distrib
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// writeTestPKI generates a CA plus server and client keypairs signed by it,
+// writing PEM files into dir.
+func writeTestPKI(t *testing.T, dir string) (caFile, serverCert, serverKey, clientCert, clientKey string) {
+	t.Helper()
+
+	caPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sharehound-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caPriv.PublicKey, caPriv)
+	if err != nil {
+		t.Fatalf("CreateCertificate(CA): %v", err)
+	}
+	caParsed, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("ParseCertificate(CA): %v", err)
+	}
+
+	issue := func(name string, serial int64, usage x509.ExtKeyUsage) (certFile, keyFile string) {
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey: %v", err)
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			DNSNames:     []string{name},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caParsed, &priv.PublicKey, caPriv)
+		if err != nil {
+			t.Fatalf("CreateCertificate(%s): %v", name, err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(priv)
+		if err != nil {
+			t.Fatalf("MarshalECPrivateKey: %v", err)
+		}
+		certFile = writePEM(t, dir, name+".crt", "CERTIFICATE", der)
+		keyFile = writePEM(t, dir, name+".key", "EC PRIVATE KEY", keyDER)
+		return certFile, keyFile
+	}
+
+	caFile = writePEM(t, dir, "ca.crt", "CERTIFICATE", caDER)
+	serverCert, serverKey = issue("aggregator", 2, x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey = issue("agent01", 3, x509.ExtKeyUsageClientAuth)
+	return caFile, serverCert, serverKey, clientCert, clientKey
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile(%s): %v", name, err)
+	}
+	return path
+}
+
+func TestAgentStreamsGraphToAggregator(t *testing.T) {
+	dir := t.TempDir()
+	caFile, serverCert, serverKey, clientCert, clientKey := writeTestPKI(t, dir)
+	log := logger.NewLogger(config.NewConfig(false, nil), "")
+
+	merged, err := graph.NewOpenGraph("test")
+	if err != nil {
+		t.Fatalf("NewOpenGraph: %v", err)
+	}
+	defer merged.Close()
+
+	serverTLS, err := ServerTLSConfig(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+	agg := NewAggregator(merged, log)
+	if err := agg.Start("127.0.0.1:0", serverTLS); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	addr := agg.listener.Addr().String()
+
+	scanned, err := graph.NewOpenGraph("test")
+	if err != nil {
+		t.Fatalf("NewOpenGraph: %v", err)
+	}
+	defer scanned.Close()
+	scanned.AddNode(graph.NewNode("NSHOST:fs01", "TestKind").SetProperty("name", "fs01"))
+	scanned.AddNode(graph.NewNode("NSHOST:fs02", "TestKind"))
+	scanned.AddEdge(graph.NewEdge("NSHOST:fs01", "NSHOST:fs02", "TestEdge"))
+
+	clientTLS, err := ClientTLSConfig(clientCert, clientKey, caFile, "")
+	if err != nil {
+		t.Fatalf("ClientTLSConfig: %v", err)
+	}
+	if err := SendGraph(addr, clientTLS, "agent01", "scan1", scanned, log); err != nil {
+		t.Fatalf("SendGraph: %v", err)
+	}
+	agg.Close()
+
+	if merged.GetNodeCount() != 2 {
+		t.Errorf("merged node count = %d, want 2", merged.GetNodeCount())
+	}
+	if merged.GetEdgeCount() != 1 {
+		t.Errorf("merged edge count = %d, want 1", merged.GetEdgeCount())
+	}
+	if _, ok := merged.GetNode("NSHOST:fs01"); !ok {
+		t.Error("merged graph should contain NSHOST:fs01")
+	}
+}
+
+func TestAggregatorRejectsUnauthenticatedAgent(t *testing.T) {
+	dir := t.TempDir()
+	caFile, serverCert, serverKey, _, _ := writeTestPKI(t, dir)
+	log := logger.NewLogger(config.NewConfig(false, nil), "")
+
+	merged, err := graph.NewOpenGraph("test")
+	if err != nil {
+		t.Fatalf("NewOpenGraph: %v", err)
+	}
+	defer merged.Close()
+
+	serverTLS, err := ServerTLSConfig(serverCert, serverKey, caFile)
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+	agg := NewAggregator(merged, log)
+	if err := agg.Start("127.0.0.1:0", serverTLS); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer agg.Close()
+	addr := agg.listener.Addr().String()
+
+	// No client certificate: the handshake must fail
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err == nil {
+		// Some TLS stacks surface the rejection on first read/write
+		if _, err = conn.Write([]byte("{}\n")); err == nil {
+			_, err = conn.Read(make([]byte, 1))
+		}
+		conn.Close()
+	}
+	if err == nil {
+		t.Error("agent without a client certificate should be rejected")
+	}
+}
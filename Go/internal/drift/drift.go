@@ -0,0 +1,223 @@
+// Package drift compares the share permissions of two scans and surfaces
+// what changed between them. Its key signal is widened access — a principal
+// that gained write or full control on a share — since drift toward
+// excessive access is the risk metric monitoring is after.
+package drift
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+	"github.com/specterops/sharehound/internal/smb"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+// Event severities. Widened write access is high; any other new grant is
+// medium; revoked access is low and reported for completeness only.
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// Event is one share permission change between two scans.
+type Event struct {
+	Severity  string `json:"severity"`
+	Change    string `json:"change"` // "granted" or "revoked"
+	Right     string `json:"right"`
+	UNCPath   string `json:"uncPath"`
+	Share     string `json:"share"`
+	Principal string `json:"principal"`
+	SID       string `json:"sid"`
+}
+
+// Feed is the change feed stored alongside a scan, so permission drift is
+// reviewable after the fact without re-running the comparison.
+type Feed struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	OldScan     string    `json:"oldScan"`
+	NewScan     string    `json:"newScan"`
+	Events      []Event   `json:"events"`
+}
+
+// widenedRights are the share-level edge kinds whose appearance means a
+// principal gained write or control over a share.
+var widenedRights = map[string]bool{
+	kinds.EdgeKindCanGenericWrite: true,
+	kinds.EdgeKindCanGenericAll:   true,
+	kinds.EdgeKindCanShareWrite:   true,
+	kinds.EdgeKindCanWriteDacl:    true,
+	kinds.EdgeKindCanWriteOwner:   true,
+}
+
+// grant identifies one permission edge onto a share node.
+type grant struct {
+	shareID   string
+	principal string
+	kind      string
+}
+
+// Compare diffs the share permission edges of two scans. Grants present only
+// in the new scan are reported as "granted" (severity high when the right is
+// write-capable), grants present only in the old scan as "revoked".
+func Compare(oldNodes []*graph.Node, oldEdges []*graph.Edge, newNodes []*graph.Node, newEdges []*graph.Edge) []Event {
+	oldGrants, oldShares := collectGrants(oldNodes, oldEdges)
+	newGrants, shares := collectGrants(newNodes, newEdges)
+	for id, name := range oldShares {
+		if _, ok := shares[id]; !ok {
+			shares[id] = name
+		}
+	}
+
+	var events []Event
+	for g := range newGrants {
+		if _, existed := oldGrants[g]; existed {
+			continue
+		}
+		severity := SeverityMedium
+		if widenedRights[g.kind] {
+			severity = SeverityHigh
+		}
+		events = append(events, newEvent(g, "granted", severity, shares))
+	}
+	for g := range oldGrants {
+		if _, exists := newGrants[g]; exists {
+			continue
+		}
+		events = append(events, newEvent(g, "revoked", SeverityLow, shares))
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Severity != events[j].Severity {
+			return severityRank(events[i].Severity) < severityRank(events[j].Severity)
+		}
+		if events[i].UNCPath != events[j].UNCPath {
+			return events[i].UNCPath < events[j].UNCPath
+		}
+		return events[i].SID < events[j].SID
+	})
+	return events
+}
+
+// Widened counts the high-severity events in a change feed.
+func Widened(events []Event) int {
+	count := 0
+	for _, ev := range events {
+		if ev.Severity == SeverityHigh {
+			count++
+		}
+	}
+	return count
+}
+
+// WriteFeed stores a change feed as JSON at the given path.
+func WriteFeed(path string, feed *Feed) error {
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal change feed: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// webhookTimeout bounds the alert POST so a slow endpoint cannot stall the
+// comparison.
+const webhookTimeout = 10 * time.Second
+
+// Notify fires a JSON POST with the high-severity events to the configured
+// webhook. Failures are logged at debug level only — alerting must never
+// fail the comparison itself.
+func Notify(webhookURL string, events []Event, log logger.LoggerInterface) {
+	var widened []Event
+	for _, ev := range events {
+		if ev.Severity == SeverityHigh {
+			widened = append(widened, ev)
+		}
+	}
+	if len(widened) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"event":     "share_permissions_widened",
+		"count":     len(widened),
+		"events":    widened,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to build webhook payload: %v", err))
+		return
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		log.Debug(fmt.Sprintf("Webhook notification failed: %v", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Debug(fmt.Sprintf("Webhook endpoint returned status %d", resp.StatusCode))
+	}
+}
+
+// collectGrants indexes the Can* edges that end on a share node, plus the
+// share display names for labelling events.
+func collectGrants(nodes []*graph.Node, edges []*graph.Edge) (map[grant]struct{}, map[string]string) {
+	shares := make(map[string]string)
+	for _, node := range nodes {
+		for _, kind := range node.Kinds {
+			if kind == kinds.NodeKindNetworkShareSMB {
+				name, _ := node.Properties["displayName"].(string)
+				shares[node.ID] = name
+			}
+		}
+	}
+
+	grants := make(map[grant]struct{})
+	for _, edge := range edges {
+		if _, isShare := shares[edge.End.Value]; !isShare || !strings.HasPrefix(edge.Kind, "Can") {
+			continue
+		}
+		grants[grant{shareID: edge.End.Value, principal: edge.Start.Value, kind: edge.Kind}] = struct{}{}
+	}
+	return grants, shares
+}
+
+func newEvent(g grant, change, severity string, shares map[string]string) Event {
+	return Event{
+		Severity:  severity,
+		Change:    change,
+		Right:     g.kind,
+		UNCPath:   strings.TrimSuffix(g.shareID, "\\"),
+		Share:     shares[g.shareID],
+		Principal: principalLabel(g.principal),
+		SID:       g.principal,
+	}
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityHigh:
+		return 0
+	case SeverityMedium:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func principalLabel(sid string) string {
+	if name := smb.GetWellKnownName(sid); name != "" {
+		return fmt.Sprintf("%s (%s)", name, sid)
+	}
+	return sid
+}
@@ -0,0 +1,96 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+func shareNode(id, name string) *graph.Node {
+	return graph.NewNode(id, kinds.NodeKindNetworkShareSMB).SetProperty("displayName", name)
+}
+
+func TestCompareWidenedAccess(t *testing.T) {
+	shareID := "\\\\fs01\\data\\"
+	oldNodes := []*graph.Node{shareNode(shareID, "data")}
+	oldEdges := []*graph.Edge{
+		graph.NewEdge("S-1-5-11", shareID, kinds.EdgeKindCanShareRead),
+	}
+	newNodes := []*graph.Node{shareNode(shareID, "data")}
+	newEdges := []*graph.Edge{
+		graph.NewEdge("S-1-5-11", shareID, kinds.EdgeKindCanShareRead),
+		graph.NewEdge("S-1-1-0", shareID, kinds.EdgeKindCanShareWrite),
+	}
+
+	events := Compare(oldNodes, oldEdges, newNodes, newEdges)
+	if len(events) != 1 {
+		t.Fatalf("Compare returned %d events, want 1: %+v", len(events), events)
+	}
+	ev := events[0]
+	if ev.Severity != SeverityHigh || ev.Change != "granted" || ev.Right != kinds.EdgeKindCanShareWrite {
+		t.Errorf("event = %+v, want high-severity granted CanShareWrite", ev)
+	}
+	if ev.SID != "S-1-1-0" || ev.Share != "data" {
+		t.Errorf("event principal/share = %q/%q, want S-1-1-0/data", ev.SID, ev.Share)
+	}
+	if Widened(events) != 1 {
+		t.Errorf("Widened = %d, want 1", Widened(events))
+	}
+}
+
+func TestCompareReadGrantIsMedium(t *testing.T) {
+	shareID := "\\\\fs01\\data\\"
+	nodes := []*graph.Node{shareNode(shareID, "data")}
+	newEdges := []*graph.Edge{
+		graph.NewEdge("S-1-5-11", shareID, kinds.EdgeKindCanShareRead),
+	}
+
+	events := Compare(nodes, nil, nodes, newEdges)
+	if len(events) != 1 || events[0].Severity != SeverityMedium {
+		t.Fatalf("events = %+v, want one medium-severity event", events)
+	}
+	if Widened(events) != 0 {
+		t.Errorf("Widened = %d, want 0", Widened(events))
+	}
+}
+
+func TestCompareRevokedAccess(t *testing.T) {
+	shareID := "\\\\fs01\\data\\"
+	nodes := []*graph.Node{shareNode(shareID, "data")}
+	oldEdges := []*graph.Edge{
+		graph.NewEdge("S-1-1-0", shareID, kinds.EdgeKindCanGenericAll),
+	}
+
+	events := Compare(nodes, oldEdges, nodes, nil)
+	if len(events) != 1 {
+		t.Fatalf("Compare returned %d events, want 1", len(events))
+	}
+	if events[0].Severity != SeverityLow || events[0].Change != "revoked" {
+		t.Errorf("event = %+v, want low-severity revoked", events[0])
+	}
+}
+
+func TestCompareNoChanges(t *testing.T) {
+	shareID := "\\\\fs01\\data\\"
+	nodes := []*graph.Node{shareNode(shareID, "data")}
+	edges := []*graph.Edge{
+		graph.NewEdge("S-1-1-0", shareID, kinds.EdgeKindCanShareWrite),
+	}
+
+	if events := Compare(nodes, edges, nodes, edges); len(events) != 0 {
+		t.Errorf("Compare returned %d events for identical scans, want 0", len(events))
+	}
+}
+
+func TestCompareIgnoresNonShareEdges(t *testing.T) {
+	hostID := "NSHOST:fs01"
+	nodes := []*graph.Node{graph.NewNode(hostID, kinds.NodeKindNetworkShareHost)}
+	newEdges := []*graph.Edge{
+		graph.NewEdge("S-1-1-0", hostID, kinds.EdgeKindCanGenericAll),
+	}
+
+	if events := Compare(nodes, nil, nodes, newEdges); len(events) != 0 {
+		t.Errorf("Compare returned %d events for non-share edges, want 0", len(events))
+	}
+}
@@ -0,0 +1,153 @@
+// Package findings derives notable findings from a finished scan's graph —
+// world-writable shares, files flagged as likely secrets, accessible
+// administrative shares — and exports them in formats SIEM/SOAR platforms
+// ingest natively: STIX 2.1 bundles and OCSF Detection Finding events.
+package findings
+
+import (
+	"strings"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/smb"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+// Finding types.
+const (
+	TypeWorldWritableShare = "world-writable-share"
+	TypeLikelySecretFile   = "likely-secret-file"
+	TypeAdminShareAccess   = "admin-share-access"
+)
+
+// Severities, ordered. They map onto both STIX labels and OCSF severity IDs.
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// Finding is one notable condition discovered during a scan.
+type Finding struct {
+	Type      string
+	Severity  string
+	Host      string
+	UNCPath   string
+	Principal string // objectid of the granted principal, when relevant
+	Detail    string
+}
+
+// worldWritableSIDs mirrors the live alerting in the worker: Everyone and
+// Authenticated Users.
+var worldWritableSIDs = map[string]bool{"S-1-1-0": true, "S-1-5-11": true}
+
+// writeEdgeKinds are the share-level rights that make a share writable.
+var writeEdgeKinds = map[string]bool{
+	kinds.EdgeKindCanGenericWrite: true,
+	kinds.EdgeKindCanGenericAll:   true,
+	kinds.EdgeKindCanShareWrite:   true,
+}
+
+// FromGraph walks a scan's nodes and edges and derives findings.
+func FromGraph(nodes []*graph.Node, edges []*graph.Edge) []Finding {
+	var found []Finding
+
+	shareNodes := make(map[string]*graph.Node)
+	for _, node := range nodes {
+		if hasKind(node, kinds.NodeKindNetworkShareSMB) {
+			shareNodes[node.ID] = node
+		}
+	}
+
+	// World-writable shares: write rights granted to Everyone or
+	// Authenticated Users at share level
+	for _, edge := range edges {
+		if !writeEdgeKinds[edge.Kind] || !worldWritableSIDs[edge.Start.Value] {
+			continue
+		}
+		if _, ok := shareNodes[edge.End.Value]; !ok {
+			continue
+		}
+		principal := edge.Start.Value
+		if name := smb.GetWellKnownName(principal); name != "" {
+			principal = principal + " (" + name + ")"
+		}
+		found = append(found, Finding{
+			Type:      TypeWorldWritableShare,
+			Severity:  SeverityHigh,
+			Host:      hostFromShareID(edge.End.Value),
+			UNCPath:   strings.TrimSuffix(edge.End.Value, "\\"),
+			Principal: principal,
+			Detail:    "Share grants " + edge.Kind + " to " + principal,
+		})
+	}
+
+	// Accessible administrative shares: reaching ADMIN$ or a drive share
+	// means the scan account has administrative access to the host
+	for id, node := range shareNodes {
+		if !isAdminShare(node.GetStringProperty("displayName")) {
+			continue
+		}
+		found = append(found, Finding{
+			Type:     TypeAdminShareAccess,
+			Severity: SeverityMedium,
+			Host:     hostFromShareID(id),
+			UNCPath:  strings.TrimSuffix(id, "\\"),
+			Detail:   "Administrative share " + node.GetStringProperty("displayName") + " is accessible to the scan account",
+		})
+	}
+
+	// Files the secret triage flagged
+	for _, node := range nodes {
+		if !hasKind(node, kinds.NodeKindFile) {
+			continue
+		}
+		score, ok := node.GetProperty("likelySecret")
+		if !ok {
+			continue
+		}
+		severity := SeverityMedium
+		if f, ok := score.(float64); ok && f >= 0.7 {
+			severity = SeverityHigh
+		}
+		unc := strings.TrimPrefix(node.ID, "FILE:")
+		found = append(found, Finding{
+			Type:     TypeLikelySecretFile,
+			Severity: severity,
+			Host:     hostFromShareID(unc + "\\"),
+			UNCPath:  unc,
+			Detail:   "File content triage flagged this file as a likely secret",
+		})
+	}
+
+	return found
+}
+
+func hasKind(node *graph.Node, kind string) bool {
+	for _, k := range node.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// hostFromShareID extracts the host from a UNC-style node ID
+// (\\host\share\...).
+func hostFromShareID(id string) string {
+	trimmed := strings.TrimPrefix(id, "\\\\")
+	if idx := strings.Index(trimmed, "\\"); idx >= 0 {
+		return trimmed[:idx]
+	}
+	return trimmed
+}
+
+// isAdminShare reports whether a share name is an administrative default
+// share: ADMIN$ or a drive share such as C$. IPC$ and other hidden shares
+// do not imply administrative access.
+func isAdminShare(name string) bool {
+	upper := strings.ToUpper(name)
+	if upper == "ADMIN$" {
+		return true
+	}
+	return len(upper) == 2 && upper[1] == '$' && upper[0] >= 'A' && upper[0] <= 'Z'
+}
@@ -0,0 +1,191 @@
+package findings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+func sampleGraph() ([]*graph.Node, []*graph.Edge) {
+	nodes := []*graph.Node{
+		graph.NewNode("NSHOST:fs01", kinds.NodeKindNetworkShareHost),
+		graph.NewNode("\\\\fs01\\public\\", kinds.NodeKindNetworkShareSMB).
+			SetProperty("displayName", "public"),
+		graph.NewNode("\\\\fs01\\C$\\", kinds.NodeKindNetworkShareSMB).
+			SetProperty("displayName", "C$").
+			SetProperty("special", true),
+		graph.NewNode("\\\\fs01\\IPC$\\", kinds.NodeKindNetworkShareSMB).
+			SetProperty("displayName", "IPC$").
+			SetProperty("special", true),
+		graph.NewNode("FILE:\\\\fs01\\public\\id_rsa", kinds.NodeKindFile).
+			SetProperty("likelySecret", 0.9),
+		graph.NewNode("FILE:\\\\fs01\\public\\notes.txt", kinds.NodeKindFile),
+	}
+	edges := []*graph.Edge{
+		graph.NewEdge("S-1-1-0", "\\\\fs01\\public\\", kinds.EdgeKindCanShareWrite),
+		graph.NewEdge("S-1-1-0", "\\\\fs01\\public\\", kinds.EdgeKindCanShareRead),
+		graph.NewEdge("S-1-5-21-1-2-3-1104", "\\\\fs01\\public\\", kinds.EdgeKindCanGenericAll),
+	}
+	return nodes, edges
+}
+
+func findingsOfType(found []Finding, findingType string) []Finding {
+	var out []Finding
+	for _, f := range found {
+		if f.Type == findingType {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func TestFromGraph(t *testing.T) {
+	found := FromGraph(sampleGraph())
+
+	ww := findingsOfType(found, TypeWorldWritableShare)
+	if len(ww) != 1 {
+		t.Fatalf("world-writable findings = %d, want 1 (read rights and domain SIDs must not count)", len(ww))
+	}
+	if ww[0].Host != "fs01" || ww[0].UNCPath != "\\\\fs01\\public" || ww[0].Severity != SeverityHigh {
+		t.Errorf("world-writable finding = %+v", ww[0])
+	}
+	if !strings.Contains(ww[0].Principal, "Everyone") {
+		t.Errorf("principal should carry the well-known name: %q", ww[0].Principal)
+	}
+
+	admin := findingsOfType(found, TypeAdminShareAccess)
+	if len(admin) != 1 {
+		t.Fatalf("admin-share findings = %d, want 1 (IPC$ must not count)", len(admin))
+	}
+	if admin[0].UNCPath != "\\\\fs01\\C$" {
+		t.Errorf("admin-share finding = %+v", admin[0])
+	}
+
+	secrets := findingsOfType(found, TypeLikelySecretFile)
+	if len(secrets) != 1 {
+		t.Fatalf("secret findings = %d, want 1", len(secrets))
+	}
+	if secrets[0].Severity != SeverityHigh {
+		t.Errorf("score 0.9 should be high severity, got %s", secrets[0].Severity)
+	}
+}
+
+func TestWriteSTIX(t *testing.T) {
+	found := FromGraph(sampleGraph())
+	path := filepath.Join(t.TempDir(), "findings.stix.json")
+
+	if err := WriteSTIX(path, found, "scan1", time.Now()); err != nil {
+		t.Fatalf("WriteSTIX: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var bundle map[string]any
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if bundle["type"] != "bundle" {
+		t.Errorf("type = %v, want bundle", bundle["type"])
+	}
+	objects := bundle["objects"].([]any)
+	if len(objects) != len(found)+1 { // identity + one object per finding
+		t.Errorf("objects = %d, want %d", len(objects), len(found)+1)
+	}
+	first := objects[1].(map[string]any)
+	if !strings.HasPrefix(first["id"].(string), "x-sharehound-finding--") {
+		t.Errorf("finding id = %v", first["id"])
+	}
+
+	// Re-exporting the same scan must produce the same object IDs
+	if err := WriteSTIX(path, found, "scan1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("WriteSTIX: %v", err)
+	}
+	data2, _ := os.ReadFile(path)
+	var bundle2 map[string]any
+	json.Unmarshal(data2, &bundle2) //nolint:errcheck
+	second := bundle2["objects"].([]any)[1].(map[string]any)
+	if first["id"] != second["id"] {
+		t.Error("finding IDs should be deterministic across exports")
+	}
+}
+
+func TestWriteOCSF(t *testing.T) {
+	found := FromGraph(sampleGraph())
+	path := filepath.Join(t.TempDir(), "findings.ocsf.json")
+
+	if err := WriteOCSF(path, found, "scan1", time.Now()); err != nil {
+		t.Fatalf("WriteOCSF: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var events []map[string]any
+	if err := json.Unmarshal(data, &events); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(events) != len(found) {
+		t.Fatalf("events = %d, want %d", len(events), len(found))
+	}
+	ev := events[0]
+	if ev["class_uid"].(float64) != 2004 || ev["category_uid"].(float64) != 2 {
+		t.Errorf("event class/category = %v/%v", ev["class_uid"], ev["category_uid"])
+	}
+	if ev["severity_id"].(float64) == 0 {
+		t.Error("severity_id should be mapped")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	found := FromGraph(sampleGraph())
+	path := filepath.Join(t.TempDir(), "findings.sarif")
+
+	if err := WriteSARIF(path, found); err != nil {
+		t.Fatalf("WriteSARIF: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var log map[string]any
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log["version"] != "2.1.0" {
+		t.Errorf("version = %v", log["version"])
+	}
+
+	runs := log["runs"].([]any)
+	run := runs[0].(map[string]any)
+	results := run["results"].([]any)
+	if len(results) != len(found) {
+		t.Fatalf("results = %d, want %d", len(results), len(found))
+	}
+
+	levels := make(map[string]string)
+	for _, r := range results {
+		res := r.(map[string]any)
+		levels[res["ruleId"].(string)] = res["level"].(string)
+	}
+	if levels[TypeWorldWritableShare] != "error" {
+		t.Errorf("world-writable level = %q, want error", levels[TypeWorldWritableShare])
+	}
+	if levels[TypeAdminShareAccess] != "warning" {
+		t.Errorf("admin-share level = %q, want warning", levels[TypeAdminShareAccess])
+	}
+
+	driver := run["tool"].(map[string]any)["driver"].(map[string]any)
+	if len(driver["rules"].([]any)) == 0 {
+		t.Error("driver should declare reporting descriptors")
+	}
+}
@@ -0,0 +1,92 @@
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// OCSF Detection Finding (class_uid 2004) constants.
+const (
+	ocsfActivityCreate   = 1
+	ocsfCategoryFindings = 2
+	ocsfClassDetection   = 2004
+)
+
+// ocsfSeverityIDs maps finding severities onto the OCSF severity_id scale.
+var ocsfSeverityIDs = map[string]int{
+	SeverityLow:    2,
+	SeverityMedium: 3,
+	SeverityHigh:   4,
+}
+
+type ocsfProduct struct {
+	Name       string `json:"name"`
+	VendorName string `json:"vendor_name"`
+}
+
+type ocsfMetadata struct {
+	Product ocsfProduct `json:"product"`
+	Version string      `json:"version"`
+}
+
+type ocsfFindingInfo struct {
+	UID   string   `json:"uid"`
+	Title string   `json:"title"`
+	Types []string `json:"types"`
+}
+
+type ocsfResource struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type ocsfEvent struct {
+	ActivityID  int             `json:"activity_id"`
+	CategoryUID int             `json:"category_uid"`
+	ClassUID    int             `json:"class_uid"`
+	SeverityID  int             `json:"severity_id"`
+	Time        int64           `json:"time"`
+	Message     string          `json:"message"`
+	Metadata    ocsfMetadata    `json:"metadata"`
+	FindingInfo ocsfFindingInfo `json:"finding_info"`
+	Resources   []ocsfResource  `json:"resources,omitempty"`
+}
+
+// WriteOCSF writes the findings as an array of OCSF Detection Finding
+// events (class_uid 2004, OCSF schema version 1.1.0).
+func WriteOCSF(path string, found []Finding, scanID string, now time.Time) error {
+	events := make([]ocsfEvent, 0, len(found))
+	for _, f := range found {
+		resources := []ocsfResource{{Type: "share", Name: f.UNCPath}}
+		if f.Host != "" {
+			resources = append(resources, ocsfResource{Type: "host", Name: f.Host})
+		}
+
+		events = append(events, ocsfEvent{
+			ActivityID:  ocsfActivityCreate,
+			CategoryUID: ocsfCategoryFindings,
+			ClassUID:    ocsfClassDetection,
+			SeverityID:  ocsfSeverityIDs[f.Severity],
+			Time:        now.UnixMilli(),
+			Message:     f.Detail,
+			Metadata: ocsfMetadata{
+				Product: ocsfProduct{Name: "ShareHound", VendorName: "SpecterOps"},
+				Version: "1.1.0",
+			},
+			FindingInfo: ocsfFindingInfo{
+				UID:   stixUUID(scanID, f.Type, f.UNCPath, f.Principal),
+				Title: f.Type,
+				Types: []string{f.Type},
+			},
+			Resources: resources,
+		})
+	}
+
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal OCSF events: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,114 @@
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// sarifLevels maps finding severities onto SARIF result levels. "error" is
+// what makes CI pipelines fail, so only high-severity findings gate builds.
+var sarifLevels = map[string]string{
+	SeverityLow:    "note",
+	SeverityMedium: "warning",
+	SeverityHigh:   "error",
+}
+
+// sarifRuleDescriptions drive the reporting descriptors, one per finding
+// type.
+var sarifRuleDescriptions = map[string]string{
+	TypeWorldWritableShare: "A share grants write access to Everyone or Authenticated Users",
+	TypeLikelySecretFile:   "A readable file was flagged as a likely secret by content triage",
+	TypeAdminShareAccess:   "An administrative share is accessible to the scan account",
+}
+
+type sarifDescriptor struct {
+	ID               string            `json:"id"`
+	ShortDescription map[string]string `json:"shortDescription"`
+}
+
+type sarifDriver struct {
+	Name           string            `json:"name"`
+	InformationURI string            `json:"informationUri"`
+	Rules          []sarifDescriptor `json:"rules"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+}
+
+type sarifResult struct {
+	RuleID    string            `json:"ruleId"`
+	Level     string            `json:"level"`
+	Message   map[string]string `json:"message"`
+	Locations []sarifLocation   `json:"locations"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log so CI pipelines can
+// gate on share-permission policy violations. High-severity findings become
+// "error" results, which is what most SARIF consumers fail builds on.
+func WriteSARIF(path string, found []Finding) error {
+	seen := make(map[string]bool)
+	var rules []sarifDescriptor
+	results := make([]sarifResult, 0, len(found))
+
+	for _, f := range found {
+		if !seen[f.Type] {
+			seen[f.Type] = true
+			rules = append(rules, sarifDescriptor{
+				ID:               f.Type,
+				ShortDescription: map[string]string{"text": sarifRuleDescriptions[f.Type]},
+			})
+		}
+
+		result := sarifResult{
+			RuleID:  f.Type,
+			Level:   sarifLevels[f.Severity],
+			Message: map[string]string{"text": f.Detail},
+		}
+		var loc sarifLocation
+		// SARIF URIs use forward slashes; keep the UNC readable as file://
+		loc.PhysicalLocation.ArtifactLocation.URI = "file:" + strings.ReplaceAll(f.UNCPath, "\\", "/")
+		result.Locations = []sarifLocation{loc}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "ShareHound",
+				InformationURI: "https://github.com/specterops/sharehound",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF log: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,98 @@
+package findings
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// stixObject is the common shape of every object in the bundle; custom
+// finding properties ride in the x_ fields per the STIX 2.1 customization
+// rules.
+type stixObject struct {
+	Type          string   `json:"type"`
+	SpecVersion   string   `json:"spec_version"`
+	ID            string   `json:"id"`
+	Created       string   `json:"created"`
+	Modified      string   `json:"modified,omitempty"`
+	CreatedByRef  string   `json:"created_by_ref,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	IdentityClass string   `json:"identity_class,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+
+	FindingType string `json:"x_finding_type,omitempty"`
+	Severity    string `json:"x_severity,omitempty"`
+	Host        string `json:"x_host,omitempty"`
+	UNCPath     string `json:"x_unc_path,omitempty"`
+	Principal   string `json:"x_principal,omitempty"`
+	ScanID      string `json:"x_scan_id,omitempty"`
+}
+
+type stixBundle struct {
+	Type    string       `json:"type"`
+	ID      string       `json:"id"`
+	Objects []stixObject `json:"objects"`
+}
+
+// WriteSTIX writes the findings as a STIX 2.1 bundle of
+// x-sharehound-finding objects. Object IDs are derived deterministically
+// from the scan ID and finding identity, so re-exporting the same scan
+// produces the same bundle.
+func WriteSTIX(path string, found []Finding, scanID string, now time.Time) error {
+	created := now.UTC().Format(time.RFC3339)
+	identityID := "identity--" + stixUUID("sharehound-identity")
+
+	objects := []stixObject{{
+		Type:          "identity",
+		SpecVersion:   "2.1",
+		ID:            identityID,
+		Created:       created,
+		Name:          "ShareHound",
+		IdentityClass: "system",
+	}}
+
+	for _, f := range found {
+		objects = append(objects, stixObject{
+			Type:         "x-sharehound-finding",
+			SpecVersion:  "2.1",
+			ID:           "x-sharehound-finding--" + stixUUID(scanID, f.Type, f.UNCPath, f.Principal),
+			Created:      created,
+			Modified:     created,
+			CreatedByRef: identityID,
+			Description:  f.Detail,
+			Labels:       []string{f.Type, f.Severity},
+			FindingType:  f.Type,
+			Severity:     f.Severity,
+			Host:         f.Host,
+			UNCPath:      f.UNCPath,
+			Principal:    f.Principal,
+			ScanID:       scanID,
+		})
+	}
+
+	bundle := stixBundle{
+		Type:    "bundle",
+		ID:      "bundle--" + stixUUID(scanID, "bundle"),
+		Objects: objects,
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal STIX bundle: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// stixUUID derives a stable name-based UUID from its inputs, rendered with
+// the v5 version bits so consumers treat it as name-derived.
+func stixUUID(parts ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	b := h[:16]
+	b[6] = (b[6] & 0x0f) | 0x50
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Benchmarks for the graph ingest and export hot paths. Run with
+//
+//	go test ./internal/graph -bench . -benchmem
+//
+// and compare against test/bench/baseline.txt (see the README there) before
+// touching AddNode/AddEdge or the export streamer.
+
+// benchGraph builds a disk-backed graph with the given number of synthetic
+// file nodes and one Contains edge per node.
+func benchGraph(b *testing.B, nodes int) *OpenGraph {
+	b.Helper()
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		b.Fatalf("NewOpenGraph: %v", err)
+	}
+	b.Cleanup(func() { og.Close() })
+
+	for i := 0; i < nodes; i++ {
+		id := fmt.Sprintf("FILE:\\\\fs01\\data\\dir%d\\file%d.txt", i%100, i)
+		og.AddNode(NewNode(id, "File").
+			SetProperty("name", fmt.Sprintf("file%d.txt", i)).
+			SetProperty("size", int64(i*1024)))
+		og.AddEdge(NewEdge(fmt.Sprintf("DIR:\\\\fs01\\data\\dir%d", i%100), id, "Contains"))
+	}
+	return og
+}
+
+func BenchmarkAddNode(b *testing.B) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		b.Fatalf("NewOpenGraph: %v", err)
+	}
+	defer og.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		og.AddNode(NewNode(fmt.Sprintf("FILE:\\\\fs01\\data\\file%d.txt", i), "File").
+			SetProperty("name", fmt.Sprintf("file%d.txt", i)).
+			SetProperty("size", int64(i)))
+	}
+}
+
+func BenchmarkAddEdge(b *testing.B) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		b.Fatalf("NewOpenGraph: %v", err)
+	}
+	defer og.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		og.AddEdge(NewEdge("S-1-1-0", fmt.Sprintf("\\\\fs01\\share%d\\", i), "CanShareRead"))
+	}
+}
+
+// BenchmarkAddEdgeDuplicate measures the dedup fast path, which every edge
+// emitted during NTFS traversal goes through.
+func BenchmarkAddEdgeDuplicate(b *testing.B) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		b.Fatalf("NewOpenGraph: %v", err)
+	}
+	defer og.Close()
+	og.AddEdge(NewEdge("S-1-1-0", "\\\\fs01\\public\\", "CanShareRead"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		og.AddEdge(NewEdge("S-1-1-0", "\\\\fs01\\public\\", "CanShareRead"))
+	}
+}
+
+func BenchmarkExportToFile(b *testing.B) {
+	const nodes = 10000
+	og := benchGraph(b, nodes)
+	dir := b.TempDir()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out := filepath.Join(dir, fmt.Sprintf("export%d.zip", i))
+		if err := og.ExportToFile(out, true); err != nil {
+			b.Fatalf("ExportToFile: %v", err)
+		}
+		if i == 0 {
+			info, err := os.Stat(out)
+			if err != nil {
+				b.Fatalf("Stat: %v", err)
+			}
+			b.SetBytes(info.Size())
+		}
+		os.Remove(out)
+	}
+	b.ReportMetric(float64(nodes), "nodes/op")
+}
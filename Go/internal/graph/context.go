@@ -3,6 +3,7 @@ package graph
 
 import (
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/specterops/sharehound/internal/logger"
@@ -25,6 +26,7 @@ type OpenGraphContext struct {
 	host                *Node
 	share               *Node
 	shareRights         ShareRights
+	shareACLSource      string      // method that produced shareRights; stamped on share rights edges
 	shareRootNTFSRights ShareRights // NTFS rights for the share root directory; used as fallback for first-level files
 	path                []PathEntry
 	element             *Node
@@ -33,19 +35,31 @@ type OpenGraphContext struct {
 	totalEdgesCreated   int
 	hostShareEmitted    bool                // true once host+share+share-rights have been added to graph
 	emittedPathNodes    map[string]struct{} // directory node IDs already committed (edges + rights)
+	emittedPrincipals   map[string]struct{} // principal node IDs already emitted for rights edges
 	domainSuffix        string              // domain FQDN used to prefix non-domain SIDs (e.g. "THIS.DOMAIN.COM")
+	domainSID           string              // SID of the authenticated domain; classifies S-1-5-21-* principals as domain or machine-local
+	skipLocalPrincipals bool                // when true, drop edges granted to machine-local SAM accounts instead of tagging them
 	effectiveAccessOnly bool                // when true, skip granular NTFS rights edges for files/directories
+	impersonated        map[string][]string // test account SID -> SIDs in that account's token (impersonation sweep)
+	rollupEdges         bool                // when true, emit one HasNTFSAccess edge per SID instead of granular CanNTFS* edges
+	includeEdgeKinds    map[string]struct{} // when non-empty, only these permission edge kinds are emitted
+	excludeEdgeKinds    map[string]struct{} // permission edge kinds that are never emitted
+	omittedProperties   map[string]struct{} // node properties stripped before emission (--minimal-properties)
+	ignoredSIDs         []string            // SID patterns whose edges are suppressed ("S-1-5-80-*" or exact SIDs)
+	recordSDDL          bool                // when true, collectors render each security descriptor as SDDL for stamping on nodes (--raw-sddl)
+	suppressedEdges     int                 // rights edges dropped because their SID matched ignoredSIDs
 }
 
 // NewOpenGraphContext creates a new OpenGraphContext.
 func NewOpenGraphContext(graph *OpenGraph, log logger.LoggerInterface) *OpenGraphContext {
 	return &OpenGraphContext{
-		graph:            graph,
-		path:             make([]PathEntry, 0),
-		shareRights:      make(ShareRights),
-		elementRights:    make(ShareRights),
-		logger:           log,
-		emittedPathNodes: make(map[string]struct{}),
+		graph:             graph,
+		path:              make([]PathEntry, 0),
+		shareRights:       make(ShareRights),
+		elementRights:     make(ShareRights),
+		logger:            log,
+		emittedPathNodes:  make(map[string]struct{}),
+		emittedPrincipals: make(map[string]struct{}),
 	}
 }
 
@@ -59,6 +73,11 @@ func (c *OpenGraphContext) GetHost() *Node {
 	return c.host
 }
 
+// GetGraph returns the OpenGraph under construction.
+func (c *OpenGraphContext) GetGraph() *OpenGraph {
+	return c.graph
+}
+
 // SetDomainSuffix sets the domain FQDN used to prefix well-known SIDs
 // so that BloodHound can resolve them (e.g. "CORP.COM-S-1-1-0").
 func (c *OpenGraphContext) SetDomainSuffix(domain string) {
@@ -70,12 +89,162 @@ func (c *OpenGraphContext) GetDomainSuffix() string {
 	return c.domainSuffix
 }
 
+// GraphLimitReached reports whether the underlying graph has grown to a
+// configured --max-nodes/--max-edges cap, so collection can degrade to the
+// directory skeleton.
+func (c *OpenGraphContext) GraphLimitReached() bool {
+	return c.graph.LimitReached()
+}
+
+// SetDomainSID sets the SID of the authenticated domain (e.g.
+// "S-1-5-21-111-222-333"). When set, rights granted to S-1-5-21-* SIDs from
+// a different domain part — the scanned host's own local Administrators,
+// local users and so on — are tagged with a localPrincipal edge property, or
+// dropped entirely when SetSkipLocalPrincipals is enabled, so that queries
+// about domain-wide exposure are not polluted by local-account grants.
+func (c *OpenGraphContext) SetDomainSID(domainSID string) {
+	c.domainSID = domainSID
+}
+
+// SetSkipLocalPrincipals controls whether edges granted to machine-local SAM
+// accounts are dropped instead of tagged. Requires SetDomainSID.
+func (c *OpenGraphContext) SetSkipLocalPrincipals(v bool) {
+	c.skipLocalPrincipals = v
+}
+
+// isLocalPrincipal reports whether a SID belongs to the scanned machine's
+// local SAM rather than the authenticated domain. Only decidable when the
+// domain SID is known; well-known and BUILTIN SIDs are not classified here
+// because they are already distinguishable by shape.
+func (c *OpenGraphContext) isLocalPrincipal(sid string) bool {
+	return c.domainSID != "" && smb.IsDomainSID(sid) && smb.SIDDomainPart(sid) != c.domainSID
+}
+
 // SetEffectiveAccessOnly controls whether granular NTFS rights edges for files
 // and directories are suppressed, keeping only CanEffectiveRead/Write/Execute.
 func (c *OpenGraphContext) SetEffectiveAccessOnly(v bool) {
 	c.effectiveAccessOnly = v
 }
 
+// SetImpersonatedPrincipals installs the impersonation sweep table: each key
+// is a test account SID and each value the SIDs that appear in that account's
+// token. When set, AddEffectiveRightsToGraph additionally evaluates collected
+// DACLs against each account's token and emits effective access edges from
+// the account itself.
+func (c *OpenGraphContext) SetImpersonatedPrincipals(principals map[string][]string) {
+	c.impersonated = principals
+}
+
+// SetRollupEdges controls aggregate edge emission: instead of one edge per
+// granular NTFS right (up to 11 per ACE), a single HasNTFSAccess edge is
+// emitted per SID, carrying the rights list and a Read/Write/FullControl
+// category as edge properties.
+func (c *OpenGraphContext) SetRollupEdges(v bool) {
+	c.rollupEdges = v
+}
+
+// SetEdgeKindFilter installs the permission edge kind filter. When include is
+// non-empty only those kinds are emitted; kinds in exclude are always dropped.
+// Structural edges (Contains, HasNetworkShare, HostsNetworkShare) are not
+// affected — removing them would orphan the graph.
+func (c *OpenGraphContext) SetEdgeKindFilter(include, exclude []string) {
+	if len(include) > 0 {
+		c.includeEdgeKinds = make(map[string]struct{}, len(include))
+		for _, k := range include {
+			c.includeEdgeKinds[k] = struct{}{}
+		}
+	}
+	if len(exclude) > 0 {
+		c.excludeEdgeKinds = make(map[string]struct{}, len(exclude))
+		for _, k := range exclude {
+			c.excludeEdgeKinds[k] = struct{}{}
+		}
+	}
+}
+
+// filterEdgeKinds applies the include/exclude edge kind filter to a kinds list.
+func (c *OpenGraphContext) filterEdgeKinds(edgeKinds []string) []string {
+	if len(c.includeEdgeKinds) == 0 && len(c.excludeEdgeKinds) == 0 {
+		return edgeKinds
+	}
+
+	var filtered []string
+	for _, k := range edgeKinds {
+		if len(c.includeEdgeKinds) > 0 {
+			if _, ok := c.includeEdgeKinds[k]; !ok {
+				continue
+			}
+		}
+		if _, ok := c.excludeEdgeKinds[k]; ok {
+			continue
+		}
+		filtered = append(filtered, k)
+	}
+	return filtered
+}
+
+// SetOmittedProperties installs the list of node properties to strip before
+// nodes are written to the graph. Used by --minimal-properties to drop
+// timestamps, sizes and descriptions on enormous scans where only structure
+// and rights matter.
+func (c *OpenGraphContext) SetOmittedProperties(properties []string) {
+	if len(properties) == 0 {
+		return
+	}
+	c.omittedProperties = make(map[string]struct{}, len(properties))
+	for _, p := range properties {
+		c.omittedProperties[p] = struct{}{}
+	}
+}
+
+// stripOmittedProperties removes omitted properties from a node in place.
+func (c *OpenGraphContext) stripOmittedProperties(node *Node) {
+	if len(c.omittedProperties) == 0 || node == nil {
+		return
+	}
+	for p := range c.omittedProperties {
+		delete(node.Properties, p)
+	}
+}
+
+// SetRecordSDDL controls whether collectors should render each collected
+// security descriptor as SDDL so the exact ACL text ends up on the node.
+func (c *OpenGraphContext) SetRecordSDDL(v bool) {
+	c.recordSDDL = v
+}
+
+// RecordSDDL reports whether raw SDDL capture is enabled.
+func (c *OpenGraphContext) RecordSDDL() bool {
+	return c.recordSDDL
+}
+
+// SetIgnoredSIDs installs the SID filter list. Each entry is either an exact
+// SID or a prefix pattern ending in "*" (e.g. "S-1-5-80-*" for all service
+// SIDs). Rights edges whose principal matches are not emitted; the number of
+// suppressed edges is available via GetSuppressedEdges.
+func (c *OpenGraphContext) SetIgnoredSIDs(patterns []string) {
+	c.ignoredSIDs = patterns
+}
+
+// GetSuppressedEdges returns the number of edges dropped by the SID filter.
+func (c *OpenGraphContext) GetSuppressedEdges() int {
+	return c.suppressedEdges
+}
+
+// isIgnoredSID reports whether a SID matches any ignore pattern.
+func (c *OpenGraphContext) isIgnoredSID(sid string) bool {
+	for _, pattern := range c.ignoredSIDs {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(sid, prefix) {
+				return true
+			}
+		} else if sid == pattern {
+			return true
+		}
+	}
+	return false
+}
+
 // SetShare sets the share node.
 func (c *OpenGraphContext) SetShare(share *Node) {
 	c.share = share
@@ -96,6 +265,13 @@ func (c *OpenGraphContext) GetShareRights() ShareRights {
 	return c.shareRights
 }
 
+// SetShareACLSource records which collection method produced the share
+// rights (e.g. "srvsvc" or "ntfs-root") so that share rights edges carry
+// their provenance.
+func (c *OpenGraphContext) SetShareACLSource(source string) {
+	c.shareACLSource = source
+}
+
 // SetShareRootNTFSRights stores the NTFS-level rights of the share root directory.
 // These are used as a fallback when first-level files have no directly retrievable
 // NTFS security descriptor.
@@ -173,7 +349,12 @@ func (c *OpenGraphContext) SetDirectoryRights(rights ShareRights) {
 func (c *OpenGraphContext) GetStringPathFromRoot() string {
 	parts := make([]string, 0, len(c.path))
 	for _, entry := range c.path {
-		name := entry.Node.GetStringProperty("name")
+		// Prefer the wire-exact name when normalization changed the
+		// exported one; SMB servers resolve paths byte-for-byte
+		name := entry.Node.GetStringProperty("smbName")
+		if name == "" {
+			name = entry.Node.GetStringProperty("name")
+		}
 		if name != "" {
 			parts = append(parts, name)
 		}
@@ -204,6 +385,7 @@ func (c *OpenGraphContext) AddPathToGraph() {
 		c.hostShareEmitted = true
 
 		// Add host node
+		c.stripOmittedProperties(c.host)
 		c.graph.AddNodeWithoutValidation(c.host)
 
 		// Add HostsNetworkShare edge from BloodHound Computer to NetworkShareHost.
@@ -227,6 +409,8 @@ func (c *OpenGraphContext) AddPathToGraph() {
 		}
 
 		// Add share node
+		c.stampShareACLSummary()
+		c.stripOmittedProperties(c.share)
 		c.graph.AddNodeWithoutValidation(c.share)
 
 		// Add share rights
@@ -260,6 +444,7 @@ func (c *OpenGraphContext) AddPathToGraph() {
 		if _, already := c.emittedPathNodes[entry.Node.ID]; !already {
 			c.emittedPathNodes[entry.Node.ID] = struct{}{}
 
+			c.stripOmittedProperties(entry.Node)
 			c.graph.AddNodeWithoutValidation(entry.Node)
 			if !c.effectiveAccessOnly {
 				c.AddRightsToGraph(entry.Node.ID, entry.Rights, "directory", kinds.NodeKindDirectory)
@@ -297,6 +482,7 @@ func (c *OpenGraphContext) AddPathToGraph() {
 		}
 	}
 
+	c.stripOmittedProperties(c.element)
 	c.graph.AddNodeWithoutValidation(c.element)
 
 	elementType := "file"
@@ -329,6 +515,100 @@ func (c *OpenGraphContext) AddPathToGraph() {
 	}
 }
 
+// ensurePrincipalNode emits a node for the principal a rights edge starts
+// at, so edges do not dangle when the standard AD collection has not
+// ingested that principal. nodeID is the (possibly domain-prefixed) edge
+// start ID; rawSID is the SID as read from the ACL. Nodes get the Principal
+// kind plus User or Group where the type is knowable from the SID alone.
+func (c *OpenGraphContext) ensurePrincipalNode(nodeID, rawSID string) {
+	if _, done := c.emittedPrincipals[nodeID]; done {
+		return
+	}
+	c.emittedPrincipals[nodeID] = struct{}{}
+
+	nodeKinds := []string{kinds.NodeKindPrincipal}
+	if kind := smb.SIDPrincipalKind(rawSID); kind != "" {
+		nodeKinds = append(nodeKinds, kind)
+	}
+	node := NewNode(nodeID, nodeKinds...)
+	node.SetProperty("objectid", nodeID)
+	if name := smb.GetWellKnownName(rawSID); name != "" {
+		node.SetProperty("name", name)
+	}
+	c.graph.AddNode(node)
+}
+
+// stampShareACLSummary stores aggregate DACL properties on the share node
+// so table queries answer the common questions — how many principals, is
+// Everyone on the ACL, who can write, what is the worst right — without
+// path expansion. The summary counts the same principals and rights the
+// edges are emitted for, after the SID and edge-kind filters.
+func (c *OpenGraphContext) stampShareACLSummary() {
+	if c.share == nil || len(c.shareRights) == 0 {
+		return
+	}
+
+	principals := 0
+	writable := 0
+	everyone := false
+	maxRight := ""
+	for sid, edgeKinds := range c.shareRights {
+		if c.isIgnoredSID(sid) {
+			continue
+		}
+		edgeKinds = c.filterEdgeKinds(edgeKinds)
+		if len(edgeKinds) == 0 {
+			continue
+		}
+		if c.isLocalPrincipal(sid) && c.skipLocalPrincipals {
+			continue
+		}
+
+		principals++
+		if sid == "S-1-1-0" || sid == "S-1-5-11" {
+			everyone = true
+		}
+		canWrite := false
+		for _, kind := range edgeKinds {
+			class := kinds.RightClass(kind)
+			if class == kinds.RightClassWrite || class == kinds.RightClassControl {
+				canWrite = true
+			}
+			if rightClassRank(class) > rightClassRank(kinds.RightClass(maxRight)) {
+				maxRight = kind
+			}
+		}
+		if canWrite {
+			writable++
+		}
+	}
+
+	if principals == 0 {
+		return
+	}
+	c.share.SetProperty("principalCount", principals).
+		SetProperty("everyoneAccess", everyone).
+		SetProperty("writablePrincipals", writable)
+	if maxRight != "" {
+		c.share.SetProperty("maxRiskRight", maxRight)
+	}
+}
+
+// rightClassRank orders right classes by risk for the maxRiskRight summary.
+func rightClassRank(class string) int {
+	switch class {
+	case kinds.RightClassControl:
+		return 4
+	case kinds.RightClassWrite:
+		return 3
+	case kinds.RightClassExecute:
+		return 2
+	case kinds.RightClassRead:
+		return 1
+	}
+	return 0
+}
+
 // AddRightsToGraph adds rights edges to the graph.
 func (c *OpenGraphContext) AddRightsToGraph(elementID string, rights ShareRights, elementType string, nodeKind string) {
 	if rights == nil {
@@ -347,17 +627,66 @@ func (c *OpenGraphContext) AddRightsToGraph(elementID string, rights ShareRights
 
 	edgesCreated := 0
 	for sid, edgeKinds := range rights {
+		if c.isIgnoredSID(sid) {
+			c.suppressedEdges += len(edgeKinds)
+			continue
+		}
+
+		edgeKinds = c.filterEdgeKinds(edgeKinds)
+		if len(edgeKinds) == 0 {
+			continue
+		}
+
+		localPrincipal := c.isLocalPrincipal(sid)
+		if localPrincipal && c.skipLocalPrincipals {
+			c.suppressedEdges += len(edgeKinds)
+			continue
+		}
+
 		// Prefix non-domain SIDs with the domain FQDN so BloodHound can
 		// resolve well-known and BUILTIN principals (e.g. "CORP.COM-S-1-1-0",
 		// "CORP.COM-S-1-5-32-545"). Domain-relative SIDs (S-1-5-21-*) already
 		// contain the domain identifier and are used as-is.
-		edgeSID := sid
-		if c.domainSuffix != "" && !smb.IsDomainSID(sid) {
-			edgeSID = c.domainSuffix + "-" + sid
+		edgeSID := smb.SIDToObjectID(sid, c.domainSuffix)
+		c.ensurePrincipalNode(edgeSID, sid)
+
+		// Rollup mode: collapse the granular NTFS rights of this SID into a
+		// single HasNTFSAccess edge. Share-level rights are few per SID and
+		// are kept granular.
+		if c.rollupEdges && elementType != "share" {
+			rightsList := append([]string(nil), edgeKinds...)
+			sort.Strings(rightsList)
+
+			edge := NewEdge(edgeSID, elementID, kinds.EdgeKindHasNTFSAccess)
+			edge.SetEndKind(nodeKind)
+			edge.SetProperty("rights", rightsList)
+			edge.SetProperty("category", smb.RollupCategory(rightsList))
+			if localPrincipal {
+				edge.SetProperty("localPrincipal", true)
+			}
+			if desc, ok := kinds.EdgeDescriptions[kinds.EdgeKindHasNTFSAccess]; ok {
+				edge.SetProperty("description", desc)
+			}
+			if c.graph.AddEdgeWithoutValidation(edge) {
+				c.totalEdgesCreated++
+				edgesCreated++
+			}
+
+			if c.logger != nil {
+				c.logger.Debug("[add_rights_to_graph] Created rollup edge: " + edgeSID + " --[HasNTFSAccess]--> " + elementID)
+			}
+			continue
 		}
+
 		for _, edgeKind := range edgeKinds {
 			edge := NewEdge(edgeSID, elementID, edgeKind)
 			edge.SetEndKind(nodeKind)
+			if elementType == "share" && c.shareACLSource != "" {
+				edge.SetProperty("aclSource", c.shareACLSource)
+			}
+			if localPrincipal {
+				edge.SetProperty("localPrincipal", true)
+			}
 			if desc, ok := kinds.EdgeDescriptions[edgeKind]; ok {
 				edge.SetProperty("description", desc)
 			}
@@ -390,19 +719,31 @@ func (c *OpenGraphContext) AddRightsToGraph(elementID string, rights ShareRights
 func (c *OpenGraphContext) AddEffectiveRightsToGraph(nodeID string, nodeRights ShareRights, nodeKind string) {
 	for sid, ntfsKinds := range nodeRights {
 		shareKinds := c.shareRights[sid]
-		effective := smb.ComputeEffectiveRights(shareKinds, ntfsKinds)
+		effective := c.filterEdgeKinds(smb.ComputeEffectiveRights(shareKinds, ntfsKinds))
 		if len(effective) == 0 {
 			continue
 		}
 
-		edgeSID := sid
-		if c.domainSuffix != "" && !smb.IsDomainSID(sid) {
-			edgeSID = c.domainSuffix + "-" + sid
+		if c.isIgnoredSID(sid) {
+			c.suppressedEdges += len(effective)
+			continue
+		}
+
+		localPrincipal := c.isLocalPrincipal(sid)
+		if localPrincipal && c.skipLocalPrincipals {
+			c.suppressedEdges += len(effective)
+			continue
 		}
 
+		edgeSID := smb.SIDToObjectID(sid, c.domainSuffix)
+		c.ensurePrincipalNode(edgeSID, sid)
+
 		for _, edgeKind := range effective {
 			edge := NewEdge(edgeSID, nodeID, edgeKind)
 			edge.SetEndKind(nodeKind)
+			if localPrincipal {
+				edge.SetProperty("localPrincipal", true)
+			}
 			if desc, ok := kinds.EdgeDescriptions[edgeKind]; ok {
 				edge.SetProperty("description", desc)
 			}
@@ -415,6 +756,42 @@ func (c *OpenGraphContext) AddEffectiveRightsToGraph(nodeID string, nodeRights S
 			}
 		}
 	}
+
+	// Impersonation sweep: for each test account, union the rights granted to
+	// any SID in its token and intersect the two layers again.  This captures
+	// access inherited through group membership, which the per-SID pass above
+	// cannot see (e.g. share read via a group, NTFS read via Everyone).
+	for accountSID, tokenSIDs := range c.impersonated {
+		var shareKinds, ntfsKinds []string
+		for _, tokenSID := range tokenSIDs {
+			shareKinds = append(shareKinds, c.shareRights[tokenSID]...)
+			ntfsKinds = append(ntfsKinds, nodeRights[tokenSID]...)
+		}
+
+		effective := c.filterEdgeKinds(smb.ComputeEffectiveRights(shareKinds, ntfsKinds))
+		if len(effective) == 0 {
+			continue
+		}
+
+		accountID := smb.SIDToObjectID(accountSID, c.domainSuffix)
+		c.ensurePrincipalNode(accountID, accountSID)
+
+		for _, edgeKind := range effective {
+			edge := NewEdge(accountID, nodeID, edgeKind)
+			edge.SetEndKind(nodeKind)
+			edge.SetProperty("impersonated", true)
+			if desc, ok := kinds.EdgeDescriptions[edgeKind]; ok {
+				edge.SetProperty("description", desc)
+			}
+			if c.graph.AddEdgeWithoutValidation(edge) {
+				c.totalEdgesCreated++
+			}
+
+			if c.logger != nil {
+				c.logger.Debug("[add_effective_rights] Impersonation edge: " + accountID + " --[" + edgeKind + "]--> " + nodeID)
+			}
+		}
+	}
 }
 
 // GetTotalEdgesCreated returns the total number of edges created by this context.
@@ -3,6 +3,8 @@ package graph
 
 import (
 	"encoding/json"
+
+	"github.com/specterops/sharehound/pkg/kinds"
 )
 
 // EdgeEndpoint represents a node reference in an edge.
@@ -189,3 +191,17 @@ func parseEndpoint(data json.RawMessage, ep *EdgeEndpoint) error {
 
 	return nil
 }
+
+// stampCompositionHints adds the pathfinding hint properties BloodHound's
+// OpenGraph configuration keys on: "traversable" marks edges attack paths
+// may walk, and "rightClass" buckets rights edges into read/write/execute/
+// control so paths can be computed through shares, not just displayed.
+func stampCompositionHints(edge *Edge) {
+	if !kinds.Traversable(edge.Kind) {
+		return
+	}
+	edge.SetProperty("traversable", true)
+	if class := kinds.RightClass(edge.Kind); class != "" {
+		edge.SetProperty("rightClass", class)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/specterops/sharehound/pkg/kinds"
 )
 
 func TestNodeMarshalJSON(t *testing.T) {
@@ -414,3 +416,330 @@ func TestExportToFileZip(t *testing.T) {
 		t.Log("Warning: ZIP file is not smaller than JSON (may be expected for small files)")
 	}
 }
+
+func TestAddRightsToGraphEmitsPrincipalNodes(t *testing.T) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og.Close()
+
+	ctx := NewOpenGraphContext(og, nil)
+	ctx.SetDomainSuffix("corp.com")
+
+	rights := ShareRights{
+		"S-1-1-0":                   {"CanGenericRead"},
+		"S-1-5-21-111-222-333-512":  {"CanGenericAll"},
+		"S-1-5-21-111-222-333-1104": {"CanGenericWrite"},
+	}
+	ctx.AddRightsToGraph("share1", rights, "share", "NetworkShareSMB")
+	// A second element with the same principals must not duplicate nodes
+	ctx.AddRightsToGraph("share2", rights, "share", "NetworkShareSMB")
+
+	data, err := og.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to serialize graph: %v", err)
+	}
+	var output map[string]interface{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	nodes := output["graph"].(map[string]interface{})["nodes"].([]interface{})
+
+	byID := map[string]map[string]interface{}{}
+	for _, n := range nodes {
+		node := n.(map[string]interface{})
+		byID[node["id"].(string)] = node
+	}
+	if len(byID) != 3 {
+		t.Fatalf("Expected 3 principal nodes, got %d", len(byID))
+	}
+
+	nodeKinds := func(node map[string]interface{}) []string {
+		var out []string
+		for _, k := range node["kinds"].([]interface{}) {
+			out = append(out, k.(string))
+		}
+		return out
+	}
+
+	// Well-known SID: domain-prefixed ID, Group kind, resolved name
+	everyone, ok := byID["CORP.COM-S-1-1-0"]
+	if !ok {
+		t.Fatal("Missing domain-prefixed node for Everyone")
+	}
+	if got := nodeKinds(everyone); len(got) != 2 || got[0] != "Principal" || got[1] != "Group" {
+		t.Errorf("Everyone kinds = %v", got)
+	}
+	props := everyone["properties"].(map[string]interface{})
+	if props["objectid"] != "CORP.COM-S-1-1-0" || props["name"] != "Everyone" {
+		t.Errorf("Everyone properties = %v", props)
+	}
+
+	// Domain group RID keeps its SID as ID
+	admins, ok := byID["S-1-5-21-111-222-333-512"]
+	if !ok {
+		t.Fatal("Missing node for Domain Admins SID")
+	}
+	if got := nodeKinds(admins); len(got) != 2 || got[1] != "Group" {
+		t.Errorf("Domain Admins kinds = %v", got)
+	}
+
+	// Ordinary RID: Principal only, type unknown without a directory lookup
+	user, ok := byID["S-1-5-21-111-222-333-1104"]
+	if !ok {
+		t.Fatal("Missing node for ordinary domain SID")
+	}
+	if got := nodeKinds(user); len(got) != 1 || got[0] != "Principal" {
+		t.Errorf("Ordinary SID kinds = %v", got)
+	}
+}
+
+func TestAddRightsToGraphLocalPrincipals(t *testing.T) {
+	domainSID := "S-1-5-21-111-222-333"
+	rights := ShareRights{
+		"S-1-5-21-111-222-333-1104": {"CanGenericRead"}, // domain account
+		"S-1-5-21-999-888-777-500":  {"CanGenericAll"},  // machine-local Administrator
+	}
+
+	edgesByStart := func(og *OpenGraph) map[string]map[string]interface{} {
+		data, err := og.ToJSON()
+		if err != nil {
+			t.Fatalf("Failed to serialize graph: %v", err)
+		}
+		var output map[string]interface{}
+		if err := json.Unmarshal(data, &output); err != nil {
+			t.Fatalf("Failed to parse output: %v", err)
+		}
+		out := map[string]map[string]interface{}{}
+		for _, e := range output["graph"].(map[string]interface{})["edges"].([]interface{}) {
+			edge := e.(map[string]interface{})
+			out[edge["start"].(map[string]interface{})["value"].(string)] = edge
+		}
+		return out
+	}
+
+	// Tag mode: the local-account edge carries localPrincipal, the domain one doesn't
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og.Close()
+
+	ctx := NewOpenGraphContext(og, nil)
+	ctx.SetDomainSID(domainSID)
+	ctx.AddRightsToGraph("share1", rights, "share", "NetworkShareSMB")
+
+	edges := edgesByStart(og)
+	if len(edges) != 2 {
+		t.Fatalf("Expected 2 edges, got %d", len(edges))
+	}
+	local := edges["S-1-5-21-999-888-777-500"]["properties"].(map[string]interface{})
+	if local["localPrincipal"] != true {
+		t.Error("Local-account edge should carry localPrincipal=true")
+	}
+	domain := edges["S-1-5-21-111-222-333-1104"]["properties"].(map[string]interface{})
+	if _, ok := domain["localPrincipal"]; ok {
+		t.Error("Domain-account edge should not carry localPrincipal")
+	}
+
+	// Skip mode: the local-account edge is suppressed entirely
+	og2, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og2.Close()
+
+	ctx2 := NewOpenGraphContext(og2, nil)
+	ctx2.SetDomainSID(domainSID)
+	ctx2.SetSkipLocalPrincipals(true)
+	ctx2.AddRightsToGraph("share1", rights, "share", "NetworkShareSMB")
+
+	edges2 := edgesByStart(og2)
+	if len(edges2) != 1 {
+		t.Fatalf("Expected 1 edge in skip mode, got %d", len(edges2))
+	}
+	if _, ok := edges2["S-1-5-21-111-222-333-1104"]; !ok {
+		t.Error("Domain-account edge should survive skip mode")
+	}
+	if got := ctx2.GetSuppressedEdges(); got != 1 {
+		t.Errorf("GetSuppressedEdges() = %d, want 1", got)
+	}
+}
+
+func TestEdgeCompositionHints(t *testing.T) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og.Close()
+
+	rights := NewEdge("S-1-1-0", "\\\\srv\\share\\", kinds.EdgeKindCanShareWrite)
+	og.AddEdge(rights)
+	if rights.Properties["traversable"] != true {
+		t.Error("rights edge not marked traversable")
+	}
+	if rights.Properties["rightClass"] != kinds.RightClassWrite {
+		t.Errorf("rights edge rightClass = %v, want %q", rights.Properties["rightClass"], kinds.RightClassWrite)
+	}
+
+	contains := NewEdge("\\\\srv\\share\\", "\\\\srv\\share\\docs\\", kinds.EdgeKindContains)
+	og.AddEdge(contains)
+	if contains.Properties["traversable"] != true {
+		t.Error("Contains edge not marked traversable")
+	}
+	if _, ok := contains.Properties["rightClass"]; ok {
+		t.Error("Contains edge must not carry a rightClass")
+	}
+
+	scanned := NewEdge("scan1", "NSHOST:srv", kinds.EdgeKindScannedHost)
+	og.AddEdge(scanned)
+	if _, ok := scanned.Properties["traversable"]; ok {
+		t.Error("provenance edge must not be marked traversable")
+	}
+}
+
+func TestUpdateNodeMergesOnLoad(t *testing.T) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og.Close()
+
+	node := NewNode("NSHOST:fs01", "NetworkShareHost")
+	node.SetProperty("name", "fs01 (Network Share Host)")
+	og.AddNode(node)
+
+	update := NewNode("NSHOST:fs01", "NetworkShareHost")
+	update.SetProperty("scanDurationSeconds", 12.5)
+	og.UpdateNode(update)
+
+	if og.GetNodeCount() != 1 {
+		t.Errorf("GetNodeCount() = %d after UpdateNode, want 1", og.GetNodeCount())
+	}
+
+	exportFile := filepath.Join(t.TempDir(), "graph.json")
+	if err := og.ExportToFile(exportFile, true); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+
+	nodes, _, err := LoadExport(exportFile)
+	if err != nil {
+		t.Fatalf("LoadExport: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("LoadExport returned %d nodes, want the duplicates merged into 1", len(nodes))
+	}
+	if nodes[0].GetStringProperty("name") == "" {
+		t.Error("merged node lost the original name property")
+	}
+	if _, ok := nodes[0].Properties["scanDurationSeconds"]; !ok {
+		t.Error("merged node lost the updated scanDurationSeconds property")
+	}
+}
+
+func TestPruneEmptySubtrees(t *testing.T) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og.Close()
+
+	shareID := "\\\\fs01\\data\\"
+	og.AddNode(NewNode(shareID, kinds.NodeKindNetworkShareSMB))
+	og.AddNode(NewNode(shareID+"reports", kinds.NodeKindDirectory))
+	og.AddNode(NewNode(shareID+"reports\\q3.xlsx", kinds.NodeKindFile))
+	og.AddNode(NewNode(shareID+"empty", kinds.NodeKindDirectory))
+	og.AddNode(NewNode(shareID+"empty\\scratch.tmp", kinds.NodeKindFile))
+
+	og.AddEdge(NewEdge(shareID, shareID+"reports", kinds.EdgeKindContains))
+	og.AddEdge(NewEdge(shareID+"reports", shareID+"reports\\q3.xlsx", kinds.EdgeKindContains))
+	og.AddEdge(NewEdge(shareID, shareID+"empty", kinds.EdgeKindContains))
+	og.AddEdge(NewEdge(shareID+"empty", shareID+"empty\\scratch.tmp", kinds.EdgeKindContains))
+	og.AddEdge(NewEdge("S-1-1-0", shareID+"reports\\q3.xlsx", kinds.EdgeKindCanGenericRead))
+
+	removedNodes, removedEdges := og.PruneEmptySubtrees()
+	if removedNodes != 2 || removedEdges != 2 {
+		t.Fatalf("PruneEmptySubtrees removed %d nodes / %d edges, want 2 / 2", removedNodes, removedEdges)
+	}
+
+	nodes, edges := og.GetNodesAndEdges()
+	for _, node := range nodes {
+		if node.ID == shareID+"empty" || node.ID == shareID+"empty\\scratch.tmp" {
+			t.Errorf("node %q survived pruning", node.ID)
+		}
+	}
+	kept := map[string]bool{}
+	for _, node := range nodes {
+		kept[node.ID] = true
+	}
+	for _, id := range []string{shareID, shareID + "reports", shareID + "reports\\q3.xlsx"} {
+		if !kept[id] {
+			t.Errorf("node %q was pruned although its subtree carries rights", id)
+		}
+	}
+	for _, edge := range edges {
+		if edge.EndNode() == shareID+"empty" || edge.StartNode() == shareID+"empty" {
+			t.Errorf("edge touching a pruned node survived: %s -> %s", edge.StartNode(), edge.EndNode())
+		}
+	}
+}
+
+func TestExportToFileZstd(t *testing.T) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og.Close()
+
+	node := NewNode("node1", "TestType")
+	node.SetProperty("name", "first")
+	og.AddNode(node)
+	og.AddNode(NewNode("node2", "TestType"))
+	og.AddEdge(NewEdge("node1", "node2", "TestEdge"))
+
+	zstFile := filepath.Join(t.TempDir(), "test.json.zst")
+	if err := og.ExportToFile(zstFile, true); err != nil {
+		t.Fatalf("Failed to export to zstd: %v", err)
+	}
+
+	nodes, edges, err := LoadExport(zstFile)
+	if err != nil {
+		t.Fatalf("LoadExport: %v", err)
+	}
+	if len(nodes) != 2 || len(edges) != 1 {
+		t.Errorf("round trip returned %d nodes / %d edges, want 2 / 1", len(nodes), len(edges))
+	}
+}
+
+func TestStampShareACLSummary(t *testing.T) {
+	og, err := NewOpenGraph("ShareHound")
+	if err != nil {
+		t.Fatalf("Failed to create graph: %v", err)
+	}
+	defer og.Close()
+
+	ctx := NewOpenGraphContext(og, nil)
+	share := NewNode("\\\\fs01\\data\\", kinds.NodeKindNetworkShareSMB)
+	ctx.SetShare(share)
+	ctx.SetShareRights(ShareRights{
+		"S-1-1-0":                  {kinds.EdgeKindCanShareRead, kinds.EdgeKindCanShareWrite},
+		"S-1-5-21-111-222-333-500": {kinds.EdgeKindCanGenericAll},
+		"S-1-5-32-545":             {kinds.EdgeKindCanShareRead},
+	})
+
+	ctx.stampShareACLSummary()
+
+	if got := share.Properties["principalCount"]; got != 3 {
+		t.Errorf("principalCount = %v, want 3", got)
+	}
+	if got := share.Properties["everyoneAccess"]; got != true {
+		t.Errorf("everyoneAccess = %v, want true", got)
+	}
+	if got := share.Properties["writablePrincipals"]; got != 2 {
+		t.Errorf("writablePrincipals = %v, want 2", got)
+	}
+	if got := share.Properties["maxRiskRight"]; got != kinds.EdgeKindCanGenericAll {
+		t.Errorf("maxRiskRight = %v, want %s", got, kinds.EdgeKindCanGenericAll)
+	}
+}
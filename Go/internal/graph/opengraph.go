@@ -4,25 +4,32 @@ package graph
 import (
 	"archive/zip"
 	"bufio"
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/zstd"
 )
 
 // OpenGraph represents a BloodHound OpenGraph structure.
 //
 // Nodes and edges are stored on disk in temporary NDJSON files so that
-// memory usage stays bounded regardless of graph size.  Only node-ID strings
-// and edge identity keys are kept in memory for deduplication.
+// memory usage stays bounded regardless of graph size.  Only 128-bit node
+// ID digests and edge identity keys are kept in memory for deduplication.
 type OpenGraph struct {
 	SourceKind string
 
-	// In-memory dedup – only IDs/keys, not full objects.
-	nodeIDs   map[string]struct{}
+	// In-memory dedup – only digests/keys, not full objects.
+	nodeIDs   map[nodeKey]struct{}
 	edgeKeys  map[edgeKey]struct{}
 	edgeCount int
 
@@ -32,12 +39,40 @@ type OpenGraph struct {
 	nodeBuf  *bufio.Writer
 	edgeBuf  *bufio.Writer
 
+	// Optional live-output observers, invoked for every accepted node/edge.
+	onNode func(*Node)
+	onEdge func(*Edge)
+
+	// Soft size caps polled by collection (0 = no cap).
+	maxNodes int
+	maxEdges int
+
+	// Skip per-line revalidation during export (--fast-export).
+	fastExport bool
+
 	mu sync.Mutex
 }
 
+// Write buffer sizing for the NDJSON temp files. The default favors
+// throughput; memory-constrained runs can shrink it via
+// NewOpenGraphBuffered.
+const (
+	DefaultBufferSize = 256 * 1024
+	minBufferSize     = 4 * 1024
+)
+
 // NewOpenGraph creates a new OpenGraph instance with disk-backed storage.
 // The caller must call Close() when done to release temporary files.
 func NewOpenGraph(sourceKind string) (*OpenGraph, error) {
+	return NewOpenGraphBuffered(sourceKind, DefaultBufferSize)
+}
+
+// NewOpenGraphBuffered is NewOpenGraph with an explicit write buffer size
+// per temp file, clamped to a sane minimum.
+func NewOpenGraphBuffered(sourceKind string, bufferSize int) (*OpenGraph, error) {
+	if bufferSize < minBufferSize {
+		bufferSize = minBufferSize
+	}
 	nf, err := os.CreateTemp("", "sharehound-nodes-*.ndjson")
 	if err != nil {
 		return nil, fmt.Errorf("create node temp file: %w", err)
@@ -51,12 +86,12 @@ func NewOpenGraph(sourceKind string) (*OpenGraph, error) {
 
 	return &OpenGraph{
 		SourceKind: sourceKind,
-		nodeIDs:    make(map[string]struct{}),
+		nodeIDs:    make(map[nodeKey]struct{}),
 		edgeKeys:   make(map[edgeKey]struct{}),
 		nodeFile:   nf,
 		edgeFile:   ef,
-		nodeBuf:    bufio.NewWriterSize(nf, 256*1024),
-		edgeBuf:    bufio.NewWriterSize(ef, 256*1024),
+		nodeBuf:    bufio.NewWriterSize(nf, bufferSize),
+		edgeBuf:    bufio.NewWriterSize(ef, bufferSize),
 	}, nil
 }
 
@@ -80,6 +115,19 @@ func (g *OpenGraph) Close() error {
 	return firstErr
 }
 
+// nodeKey is a truncated SHA-256 digest of a node ID. Full UNC paths for
+// millions of file nodes dominated the dedup map's footprint; 128 bits keep
+// the map compact while leaving accidental collisions out of reach (the
+// birthday bound sits near 2^64 nodes).
+type nodeKey [16]byte
+
+func newNodeKey(id string) nodeKey {
+	sum := sha256.Sum256([]byte(id))
+	var key nodeKey
+	copy(key[:], sum[:16])
+	return key
+}
+
 type edgeKey struct {
 	startValue   string
 	startMatchBy string
@@ -119,11 +167,62 @@ func (g *OpenGraph) AddNode(node *Node) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if _, exists := g.nodeIDs[node.ID]; exists {
+	key := newNodeKey(node.ID)
+	if _, exists := g.nodeIDs[key]; exists {
 		return
 	}
-	g.nodeIDs[node.ID] = struct{}{}
+	g.nodeIDs[key] = struct{}{}
+	appendJSON(g.nodeBuf, node)
+	if g.onNode != nil {
+		g.onNode(node)
+	}
+}
+
+// SetLimits installs soft caps on the number of nodes and edges (0 = no
+// cap). The graph keeps accepting writes past a cap — callers poll
+// LimitReached and degrade collection instead, so an almost-complete scan
+// still produces an ingestable export.
+func (g *OpenGraph) SetLimits(maxNodes, maxEdges int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.maxNodes = maxNodes
+	g.maxEdges = maxEdges
+}
+
+// LimitReached reports whether the graph has grown to a configured cap.
+func (g *OpenGraph) LimitReached() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return (g.maxNodes > 0 && len(g.nodeIDs) >= g.maxNodes) ||
+		(g.maxEdges > 0 && g.edgeCount >= g.maxEdges)
+}
+
+// UpdateNode appends the node even when its ID has already been emitted.
+// The on-disk store is append-only, so this writes a second entry for the
+// ID; BloodHound upserts nodes by ID on ingest, merging the properties, and
+// LoadExport performs the same merge for local consumers. Use it for
+// properties that only become known after the node's first emission, such
+// as per-host scan metrics.
+func (g *OpenGraph) UpdateNode(node *Node) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.nodeIDs[newNodeKey(node.ID)] = struct{}{}
 	appendJSON(g.nodeBuf, node)
+	if g.onNode != nil {
+		g.onNode(node)
+	}
+}
+
+// SetObserver registers callbacks invoked for every node and edge accepted
+// into the graph (after deduplication), so output can be consumed live while
+// a scan runs. Set it before collection starts; the callbacks run under the
+// graph lock and must not block.
+func (g *OpenGraph) SetObserver(onNode func(*Node), onEdge func(*Edge)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onNode = onNode
+	g.onEdge = onEdge
 }
 
 // AddNodeWithoutValidation adds a node, deduplicating by ID.
@@ -147,8 +246,12 @@ func (g *OpenGraph) addEdgeLocked(edge *Edge) bool {
 		return false
 	}
 	g.edgeKeys[key] = struct{}{}
+	stampCompositionHints(edge)
 	appendJSON(g.edgeBuf, edge)
 	g.edgeCount++
+	if g.onEdge != nil {
+		g.onEdge(edge)
+	}
 	return true
 }
 
@@ -163,7 +266,7 @@ func (g *OpenGraph) AddEdgeWithoutValidation(edge *Edge) bool {
 // temp file and should only be used for rare/diagnostic lookups.
 func (g *OpenGraph) GetNode(id string) (*Node, bool) {
 	g.mu.Lock()
-	if _, exists := g.nodeIDs[id]; !exists {
+	if _, exists := g.nodeIDs[newNodeKey(id)]; !exists {
 		g.mu.Unlock()
 		return nil, false
 	}
@@ -189,6 +292,37 @@ func (g *OpenGraph) GetNode(id string) (*Node, bool) {
 	}
 }
 
+// GetNodeIDsWithPrefix returns the IDs of all nodes whose ID starts with
+// the given prefix, sorted for deterministic output. Only digests are kept
+// in memory, so this streams the node temp file; use it for low-volume
+// lookups (host nodes), not per-file queries.
+func (g *OpenGraph) GetNodeIDsWithPrefix(prefix string) []string {
+	g.mu.Lock()
+	g.nodeBuf.Flush() //nolint:errcheck
+	name := g.nodeFile.Name()
+	g.mu.Unlock()
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var ids []string
+	dec := json.NewDecoder(bufio.NewReaderSize(f, 64*1024))
+	for {
+		var node Node
+		if err := dec.Decode(&node); err != nil {
+			break
+		}
+		if strings.HasPrefix(node.ID, prefix) {
+			ids = append(ids, node.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 // GetNodeCount returns the number of unique nodes.
 func (g *OpenGraph) GetNodeCount() int {
 	g.mu.Lock()
@@ -244,6 +378,18 @@ func (g *OpenGraph) ExportToFileWithProgress(filename string, includeMetadata bo
 	edgeCount := g.edgeCount
 	nodeFileName := g.nodeFile.Name()
 	edgeFileName := g.edgeFile.Name()
+	validate := !g.fastExport
+
+	// Capture the flushed sizes while still holding the lock: collection
+	// may keep appending during the export (periodic snapshots), and the
+	// prefix up to the captured size always ends on a line boundary.
+	var nodeLimit, edgeLimit int64 = -1, -1
+	if info, err := g.nodeFile.Stat(); err == nil {
+		nodeLimit = info.Size()
+	}
+	if info, err := g.edgeFile.Stat(); err == nil {
+		edgeLimit = info.Size()
+	}
 	g.mu.Unlock()
 
 	if progress != nil {
@@ -258,12 +404,20 @@ func (g *OpenGraph) ExportToFileWithProgress(filename string, includeMetadata bo
 
 	bufWriter := bufio.NewWriterSize(file, 64*1024)
 
-	if strings.HasSuffix(strings.ToLower(filename), ".zip") {
+	switch {
+	case strings.HasSuffix(strings.ToLower(filename), ".zip"):
 		if progress != nil {
 			progress("Preparing ZIP archive", 0, 0)
 		}
 		zipWriter := zip.NewWriter(bufWriter)
 
+		// klauspost's flate is a drop-in deflate several times faster than
+		// the standard library's, which dominates export time on large
+		// graphs.
+		zipWriter.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(out, flate.DefaultCompression)
+		})
+
 		baseName := filepath.Base(filename)
 		jsonName := strings.TrimSuffix(baseName, ".zip")
 		if !strings.HasSuffix(jsonName, ".json") {
@@ -279,8 +433,8 @@ func (g *OpenGraph) ExportToFileWithProgress(filename string, includeMetadata bo
 			return err
 		}
 
-		if err := streamJSON(entryWriter, g.SourceKind, includeMetadata, progress,
-			nodeFileName, edgeFileName, nodeCount, edgeCount); err != nil {
+		if err := streamJSON(entryWriter, g.SourceKind, includeMetadata, validate, progress,
+			nodeFileName, edgeFileName, nodeLimit, edgeLimit, nodeCount, edgeCount); err != nil {
 			return err
 		}
 
@@ -290,9 +444,30 @@ func (g *OpenGraph) ExportToFileWithProgress(filename string, includeMetadata bo
 		if err := zipWriter.Close(); err != nil {
 			return err
 		}
-	} else {
-		if err := streamJSON(bufWriter, g.SourceKind, includeMetadata, progress,
-			nodeFileName, edgeFileName, nodeCount, edgeCount); err != nil {
+
+	case strings.HasSuffix(strings.ToLower(filename), ".zst"):
+		// zstd compresses on all cores, so the final compression step of a
+		// multi-GB export is no longer serialized on one.
+		zw, err := zstd.NewWriter(bufWriter,
+			zstd.WithEncoderConcurrency(runtime.GOMAXPROCS(0)))
+		if err != nil {
+			return err
+		}
+		if err := streamJSON(zw, g.SourceKind, includeMetadata, validate, progress,
+			nodeFileName, edgeFileName, nodeLimit, edgeLimit, nodeCount, edgeCount); err != nil {
+			zw.Close()
+			return err
+		}
+		if progress != nil {
+			progress("Finalizing zstd stream", 0, 0)
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+	default:
+		if err := streamJSON(bufWriter, g.SourceKind, includeMetadata, validate, progress,
+			nodeFileName, edgeFileName, nodeLimit, edgeLimit, nodeCount, edgeCount); err != nil {
 			return err
 		}
 	}
@@ -303,10 +478,20 @@ func (g *OpenGraph) ExportToFileWithProgress(filename string, includeMetadata bo
 	return bufWriter.Flush()
 }
 
+// SetFastExport disables per-line revalidation during export. Lines in the
+// temp files were produced by json.Marshal and are valid by construction;
+// the revalidation only guards against on-disk corruption, so skipping it
+// trades that safety net for a faster export of very large graphs.
+func (g *OpenGraph) SetFastExport(enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.fastExport = enabled
+}
+
 // streamJSON writes the graph as JSON by reading nodes and edges from
-// the NDJSON temp files.  Only one JSON object at a time is in memory.
-func streamJSON(w io.Writer, sourceKind string, includeMetadata bool, progress ProgressFunc,
-	nodeFileName, edgeFileName string, nodeCount, edgeCount int) error {
+// the NDJSON temp files.  Only one line at a time is in memory.
+func streamJSON(w io.Writer, sourceKind string, includeMetadata, validate bool, progress ProgressFunc,
+	nodeFileName, edgeFileName string, nodeLimit, edgeLimit int64, nodeCount, edgeCount int) error {
 
 	if _, err := w.Write([]byte("{\n")); err != nil {
 		return err
@@ -342,7 +527,7 @@ func streamJSON(w io.Writer, sourceKind string, includeMetadata bool, progress P
 	if err != nil {
 		return err
 	}
-	nIdx, err := streamArray(w, nf, nodeCount, nodeReportInterval, "Serializing nodes", progress)
+	nIdx, err := streamArray(w, limitReader(nf, nodeLimit), validate, nodeCount, nodeReportInterval, "Serializing nodes", progress)
 	nf.Close()
 	if err != nil {
 		return err
@@ -374,7 +559,7 @@ func streamJSON(w io.Writer, sourceKind string, includeMetadata bool, progress P
 	if err != nil {
 		return err
 	}
-	eIdx, err := streamArray(w, ef, edgeCount, edgeReportInterval, "Serializing edges", progress)
+	eIdx, err := streamArray(w, limitReader(ef, edgeLimit), validate, edgeCount, edgeReportInterval, "Serializing edges", progress)
 	ef.Close()
 	if err != nil {
 		return err
@@ -402,38 +587,138 @@ func streamJSON(w io.Writer, sourceKind string, includeMetadata bool, progress P
 }
 
 // streamArray reads NDJSON lines from src and writes them as a JSON
-// array body (without the surrounding brackets) into w.
-func streamArray(w io.Writer, src *os.File, total, reportInterval int, phase string, progress ProgressFunc) (int, error) {
-	dec := json.NewDecoder(bufio.NewReaderSize(src, 256*1024))
+// array body (without the surrounding brackets) into w. Lines are copied
+// raw — appendJSON wrote them with json.Marshal, one object per line, so
+// no decode/encode round trip is needed. With validate set, each line is
+// additionally syntax-checked to catch on-disk corruption before it
+// poisons the export.
+// limitReader bounds src to n bytes; a negative n means unbounded.
+func limitReader(src io.Reader, n int64) io.Reader {
+	if n < 0 {
+		return src
+	}
+	return io.LimitReader(src, n)
+}
+
+func streamArray(w io.Writer, src io.Reader, validate bool, total, reportInterval int, phase string, progress ProgressFunc) (int, error) {
+	reader := bufio.NewReaderSize(src, 256*1024)
 	idx := 0
 	for {
-		var raw json.RawMessage
-		if err := dec.Decode(&raw); err == io.EOF {
+		line, err := reader.ReadBytes('\n')
+		if len(line) == 0 && err == io.EOF {
 			break
-		} else if err != nil {
+		}
+		if err != nil && err != io.EOF {
 			return idx, err
 		}
 
+		line = bytes.TrimRight(line, "\n")
+		if len(line) == 0 {
+			continue
+		}
+		if validate && !json.Valid(line) {
+			return idx, fmt.Errorf("%s: invalid JSON on line %d of the temp store", phase, idx+1)
+		}
+
 		if idx > 0 {
-			if _, err := w.Write([]byte(",\n")); err != nil {
-				return idx, err
+			if _, werr := w.Write([]byte(",\n")); werr != nil {
+				return idx, werr
 			}
 		}
-		if _, err := w.Write([]byte("      ")); err != nil {
-			return idx, err
+		if _, werr := w.Write([]byte("      ")); werr != nil {
+			return idx, werr
 		}
-		if _, err := w.Write(raw); err != nil {
-			return idx, err
+		if _, werr := w.Write(line); werr != nil {
+			return idx, werr
 		}
 
 		idx++
 		if progress != nil && reportInterval > 0 && idx%reportInterval == 0 {
 			progress(phase, idx, total)
 		}
+
+		if err == io.EOF {
+			break
+		}
 	}
 	return idx, nil
 }
 
+// ---------- Import ----------------------------------------------------
+
+// LoadExport reads a previously exported graph file (plain JSON or the
+// zipped variant) back into memory, for post-scan tooling such as the
+// report generator.
+func LoadExport(filename string) ([]*Node, []*Edge, error) {
+	var data []byte
+	if strings.HasSuffix(strings.ToLower(filename), ".zip") {
+		zr, err := zip.OpenReader(filename)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open graph archive: %w", err)
+		}
+		defer zr.Close()
+
+		var entry *zip.File
+		for _, f := range zr.File {
+			if strings.HasSuffix(strings.ToLower(f.Name), ".json") {
+				entry = f
+				break
+			}
+		}
+		if entry == nil {
+			return nil, nil, fmt.Errorf("no JSON entry in %s", filename)
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rc.Close()
+		if data, err = io.ReadAll(rc); err != nil {
+			return nil, nil, err
+		}
+	} else if strings.HasSuffix(strings.ToLower(filename), ".zst") {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open graph file: %w", err)
+		}
+		defer f.Close()
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer zr.Close()
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(filename); err != nil {
+			return nil, nil, fmt.Errorf("failed to read graph file: %w", err)
+		}
+	}
+
+	var output openGraphOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse graph file: %w", err)
+	}
+
+	// Property-only updates (UpdateNode) appear as additional entries for an
+	// already-emitted ID; merge them the way BloodHound's ingest does.
+	nodes := make([]*Node, 0, len(output.Graph.Nodes))
+	byID := make(map[string]*Node, len(output.Graph.Nodes))
+	for _, node := range output.Graph.Nodes {
+		if existing, ok := byID[node.ID]; ok {
+			for key, value := range node.Properties {
+				existing.SetProperty(key, value)
+			}
+			continue
+		}
+		byID[node.ID] = node
+		nodes = append(nodes, node)
+	}
+	return nodes, output.Graph.Edges, nil
+}
+
 // ---------- Checkpoint helpers ----------------------------------------
 
 // GetNodesAndEdges reads all nodes and edges from disk for checkpointing.
@@ -486,7 +771,7 @@ func (g *OpenGraph) RestoreNodesAndEdges(nodes []*Node, edges []*Edge) {
 	defer g.mu.Unlock()
 
 	// Reset dedup state
-	g.nodeIDs = make(map[string]struct{}, len(nodes))
+	g.nodeIDs = make(map[nodeKey]struct{}, len(nodes))
 	g.edgeKeys = make(map[edgeKey]struct{}, len(edges))
 	g.edgeCount = 0
 
@@ -495,7 +780,7 @@ func (g *OpenGraph) RestoreNodesAndEdges(nodes []*Node, edges []*Edge) {
 	g.nodeFile.Seek(0, io.SeekStart) //nolint:errcheck
 	g.nodeBuf.Reset(g.nodeFile)
 	for _, node := range nodes {
-		g.nodeIDs[node.ID] = struct{}{}
+		g.nodeIDs[newNodeKey(node.ID)] = struct{}{}
 		appendJSON(g.nodeBuf, node)
 	}
 
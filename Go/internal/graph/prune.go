@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+// PruneEmptySubtrees removes Directory and File nodes that neither carry
+// rights edges themselves nor contain a descendant that does, together with
+// the Contains edges that only served to reach them. Pure structural nodes
+// often dominate graph size without adding analytic value; pruning them
+// keeps hosts, shares and every node a rights edge lands on. The pass
+// rewrites the on-disk store, so run it after collection has finished and
+// before export.
+func (g *OpenGraph) PruneEmptySubtrees() (removedNodes, removedEdges int) {
+	nodes, edges := g.GetNodesAndEdges()
+
+	// Containment parents and the set of node IDs rights edges land on.
+	parent := make(map[string]string)
+	keep := make(map[string]struct{})
+	for _, edge := range edges {
+		if edge.Kind == kinds.EdgeKindContains {
+			parent[edge.EndNode()] = edge.StartNode()
+			continue
+		}
+		if kinds.RightClass(edge.Kind) != "" || edge.Kind == kinds.EdgeKindHasNTFSAccess {
+			keep[edge.EndNode()] = struct{}{}
+		}
+	}
+
+	// Propagate upward: an ancestor of a kept node is kept as well, so the
+	// containment path from the share down to each rights-carrying node
+	// survives.
+	for id := range keep {
+		for {
+			parentID, ok := parent[id]
+			if !ok {
+				break
+			}
+			if _, done := keep[parentID]; done {
+				break
+			}
+			keep[parentID] = struct{}{}
+			id = parentID
+		}
+	}
+
+	// Only Directory and File nodes are candidates; hosts, shares and
+	// principals always survive.
+	removed := make(map[string]struct{})
+	keptNodes := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		prunable := len(node.Kinds) > 0 &&
+			(node.Kinds[0] == kinds.NodeKindDirectory || node.Kinds[0] == kinds.NodeKindFile)
+		if _, ok := keep[node.ID]; prunable && !ok {
+			removed[node.ID] = struct{}{}
+			continue
+		}
+		keptNodes = append(keptNodes, node)
+	}
+
+	keptEdges := make([]*Edge, 0, len(edges))
+	for _, edge := range edges {
+		if _, ok := removed[edge.StartNode()]; ok {
+			continue
+		}
+		if _, ok := removed[edge.EndNode()]; ok {
+			continue
+		}
+		keptEdges = append(keptEdges, edge)
+	}
+
+	removedNodes = len(nodes) - len(keptNodes)
+	removedEdges = len(edges) - len(keptEdges)
+	if removedNodes == 0 && removedEdges == 0 {
+		return 0, 0
+	}
+
+	g.RestoreNodesAndEdges(keptNodes, keptEdges)
+	return removedNodes, removedEdges
+}
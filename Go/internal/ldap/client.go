@@ -12,6 +12,7 @@ import (
 	ldapgssapi "github.com/go-ldap/ldap/v3/gssapi"
 	"github.com/specterops/sharehound/internal/auth"
 	"github.com/specterops/sharehound/internal/credentials"
+	"github.com/specterops/sharehound/internal/smb"
 )
 
 // Default page size for LDAP paging (AD default MaxPageSize is 1000)
@@ -27,6 +28,7 @@ type Client struct {
 	password    string
 	authKey     string
 	useLDAPS    bool
+	useGC       bool
 	useNTLM     bool
 	ntHash      string
 	useKerberos bool
@@ -43,6 +45,7 @@ type ClientOptions struct {
 	Hashes      string // LM:NT format
 	AuthKey     string
 	UseLDAPS    bool
+	UseGC       bool // connect to the Global Catalog (3268/3269) for forest-wide queries
 	UseKerberos bool
 	WindowsAuth bool
 	KDCHost     string
@@ -57,6 +60,7 @@ func NewClient(opts *ClientOptions) (*Client, error) {
 		password:    opts.Password,
 		authKey:     opts.AuthKey,
 		useLDAPS:    opts.UseLDAPS,
+		useGC:       opts.UseGC,
 		useKerberos: opts.UseKerberos,
 		windowsAuth: opts.WindowsAuth,
 		kdcHost:     opts.KDCHost,
@@ -83,14 +87,22 @@ func (c *Client) Connect() error {
 	var conn *ldap.Conn
 
 	if c.useLDAPS {
-		// LDAPS connection
+		// LDAPS connection (Global Catalog over TLS on 3269)
+		port := 636
+		if c.useGC {
+			port = 3269
+		}
 		tlsConfig := &tls.Config{
 			InsecureSkipVerify: true,
 		}
-		conn, err = ldap.DialTLS("tcp", fmt.Sprintf("%s:636", c.dcIP), tlsConfig)
+		conn, err = ldap.DialTLS("tcp", fmt.Sprintf("%s:%d", c.dcIP, port), tlsConfig)
 	} else {
-		// Plain LDAP connection
-		conn, err = ldap.Dial("tcp", fmt.Sprintf("%s:389", c.dcIP))
+		// Plain LDAP connection (Global Catalog on 3268)
+		port := 389
+		if c.useGC {
+			port = 3268
+		}
+		conn, err = ldap.Dial("tcp", fmt.Sprintf("%s:%d", c.dcIP, port))
 	}
 
 	if err != nil {
@@ -239,6 +251,222 @@ func (c *Client) hostnameFromEntry(entry *ldap.Entry) string {
 	return host + "." + c.domain
 }
 
+// GetForestDomains walks the crossRef objects in the configuration partition
+// and returns the DNS names of all domains in the forest. The configuration
+// naming context is discovered via the RootDSE so this works regardless of
+// which domain the client is bound to.
+func (c *Client) GetForestDomains() ([]string, error) {
+	configNC, err := c.configurationNamingContext()
+	if err != nil {
+		return nil, err
+	}
+
+	searchRequest := ldap.NewSearchRequest(
+		"CN=Partitions,"+configNC,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		// systemFlags bit 2 (FLAG_CR_NTDS_DOMAIN) marks domain partitions
+		"(&(objectClass=crossRef)(systemFlags:1.2.840.113556.1.4.803:=2))",
+		[]string{"dnsRoot", "nCName"},
+		nil,
+	)
+
+	sr, err := c.conn.SearchWithPaging(searchRequest, defaultPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("LDAP search for forest domains failed: %w", err)
+	}
+
+	var domains []string
+	for _, entry := range sr.Entries {
+		if dnsRoot := entry.GetAttributeValue("dnsRoot"); dnsRoot != "" {
+			domains = append(domains, dnsRoot)
+		}
+	}
+
+	return domains, nil
+}
+
+// GetComputersInDomain retrieves computer objects from a specific domain
+// partition. This requires a Global Catalog connection when the domain is
+// not the one the client is bound to.
+func (c *Client) GetComputersInDomain(domain string) ([]string, error) {
+	searchRequest := ldap.NewSearchRequest(
+		domainToBaseDN(domain),
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		"(&(objectCategory=computer)(objectClass=computer))",
+		[]string{"dNSHostName", "name"},
+		nil,
+	)
+
+	sr, err := c.conn.SearchWithPaging(searchRequest, defaultPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("LDAP search in domain %s failed: %w", domain, err)
+	}
+
+	var computers []string
+	for _, entry := range sr.Entries {
+		host := entry.GetAttributeValue("dNSHostName")
+		if host == "" {
+			host = entry.GetAttributeValue("name")
+		}
+		if host == "" {
+			continue
+		}
+		if !strings.Contains(host, ".") && domain != "" {
+			host = host + "." + domain
+		}
+		computers = append(computers, host)
+	}
+
+	return computers, nil
+}
+
+// GetAccountTokenSIDs resolves a user account to its SID and the SIDs that
+// would appear in its access token. Group membership comes from the
+// constructed tokenGroups attribute, which expands nested and domain-local
+// memberships server-side. The returned list also contains the account's own
+// SID plus Everyone (S-1-1-0) and Authenticated Users (S-1-5-11), which
+// Windows adds to every network logon token but which are never listed in
+// tokenGroups.
+func (c *Client) GetAccountTokenSIDs(sAMAccountName string) (string, []string, error) {
+	searchRequest := ldap.NewSearchRequest(
+		c.baseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf("(&(objectClass=user)(sAMAccountName=%s))", ldap.EscapeFilter(sAMAccountName)),
+		[]string{"objectSid"},
+		nil,
+	)
+
+	sr, err := c.conn.Search(searchRequest)
+	if err != nil {
+		return "", nil, fmt.Errorf("LDAP search for account %s failed: %w", sAMAccountName, err)
+	}
+	if len(sr.Entries) == 0 {
+		return "", nil, fmt.Errorf("account %s not found", sAMAccountName)
+	}
+
+	entry := sr.Entries[0]
+	accountSID, err := smb.ParseSID(entry.GetRawAttributeValue("objectSid"))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse objectSid of %s: %w", sAMAccountName, err)
+	}
+
+	// tokenGroups is a constructed attribute and only returned by a
+	// base-scope search against the object itself.
+	tgRequest := ldap.NewSearchRequest(
+		entry.DN,
+		ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)",
+		[]string{"tokenGroups"},
+		nil,
+	)
+
+	tgResult, err := c.conn.Search(tgRequest)
+	if err != nil {
+		return "", nil, fmt.Errorf("tokenGroups query for %s failed: %w", sAMAccountName, err)
+	}
+
+	tokenSIDs := []string{accountSID.String(), "S-1-1-0", "S-1-5-11"}
+	if len(tgResult.Entries) > 0 {
+		for _, raw := range tgResult.Entries[0].GetRawAttributeValues("tokenGroups") {
+			groupSID, err := smb.ParseSID(raw)
+			if err != nil {
+				continue
+			}
+			tokenSIDs = append(tokenSIDs, groupSID.String())
+		}
+	}
+
+	return accountSID.String(), tokenSIDs, nil
+}
+
+// GetDomainSID returns the SID of the bound domain (the objectSid of the
+// domain head object). It is the reference against which S-1-5-21-* SIDs
+// found in ACLs are classified: a matching domain part means a domain
+// principal, anything else is a machine-local SAM account.
+func (c *Client) GetDomainSID() (string, error) {
+	searchRequest := ldap.NewSearchRequest(
+		c.baseDN,
+		ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)",
+		[]string{"objectSid"},
+		nil,
+	)
+
+	sr, err := c.conn.Search(searchRequest)
+	if err != nil {
+		return "", fmt.Errorf("LDAP search for domain object failed: %w", err)
+	}
+	if len(sr.Entries) == 0 {
+		return "", fmt.Errorf("domain object %s not found", c.baseDN)
+	}
+
+	domainSID, err := smb.ParseSID(sr.Entries[0].GetRawAttributeValue("objectSid"))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse objectSid of %s: %w", c.baseDN, err)
+	}
+
+	return domainSID.String(), nil
+}
+
+// configurationNamingContext reads the configuration partition DN from the RootDSE.
+func (c *Client) configurationNamingContext() (string, error) {
+	searchRequest := ldap.NewSearchRequest(
+		"",
+		ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)",
+		[]string{"configurationNamingContext"},
+		nil,
+	)
+
+	sr, err := c.conn.Search(searchRequest)
+	if err != nil {
+		return "", fmt.Errorf("RootDSE query failed: %w", err)
+	}
+
+	if len(sr.Entries) > 0 {
+		if nc := sr.Entries[0].GetAttributeValue("configurationNamingContext"); nc != "" {
+			return nc, nil
+		}
+	}
+
+	// Fall back to deriving it from the bound domain
+	return "CN=Configuration," + c.baseDN, nil
+}
+
+// GetSubnetSites retrieves the subnet-to-site mapping from AD Sites and
+// Services, keyed by subnet CIDR with the site CN as value.
+func (c *Client) GetSubnetSites() (map[string]string, error) {
+	configDN := "CN=Subnets,CN=Sites,CN=Configuration," + c.baseDN
+
+	searchRequest := ldap.NewSearchRequest(
+		configDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=subnet)",
+		[]string{"cn", "siteObject"},
+		nil,
+	)
+
+	sr, err := c.conn.SearchWithPaging(searchRequest, defaultPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("LDAP search for subnets failed: %w", err)
+	}
+
+	sites := make(map[string]string)
+	for _, entry := range sr.Entries {
+		cn := entry.GetAttributeValue("cn")
+		siteDN := entry.GetAttributeValue("siteObject")
+		if cn == "" || siteDN == "" {
+			continue
+		}
+		// siteObject is a DN like "CN=Default-First-Site-Name,CN=Sites,...";
+		// keep only the site CN
+		rdn := strings.SplitN(siteDN, ",", 2)[0]
+		sites[cn] = strings.TrimPrefix(rdn, "CN=")
+	}
+
+	return sites, nil
+}
+
 // GetSubnets retrieves subnet objects from AD Sites and Services.
 func (c *Client) GetSubnets() ([]string, error) {
 	// Subnets are stored in CN=Subnets,CN=Sites,CN=Configuration,<baseDN>
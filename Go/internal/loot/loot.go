@@ -0,0 +1,205 @@
+// Package loot downloads files matched by COLLECT rules into a local
+// evidence directory. Every download is bounded by a per-file size limit and
+// a total byte budget, hashed while it is written, and indexed in an
+// append-only JSON-lines manifest, so interesting finds are preserved as
+// evidence in the same run that discovered them.
+package loot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/specterops/sharehound/internal/utils"
+)
+
+// ManifestName is the index file written inside the loot directory.
+const ManifestName = "manifest.jsonl"
+
+// Skip reasons returned by Collect; callers log these and move on.
+var (
+	ErrFileTooLarge    = errors.New("file exceeds the per-file collection limit")
+	ErrBudgetExhausted = errors.New("total collection budget is exhausted")
+)
+
+// Entry is one manifest line describing a collected file.
+type Entry struct {
+	UNCPath     string    `json:"uncPath"`
+	LocalPath   string    `json:"localPath"` // relative to the loot directory
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CollectedAt time.Time `json:"collectedAt"`
+}
+
+// Collector downloads matched files under a loot directory. It is safe for
+// concurrent use from worker goroutines.
+type Collector struct {
+	dir          string
+	perFileLimit int64
+	budget       int64
+
+	mu        sync.Mutex
+	used      int64
+	collected int
+	manifest  *os.File
+}
+
+// NewCollector creates the loot directory and opens its manifest.
+// perFileLimit caps each download and budget caps the run's total bytes.
+func NewCollector(dir string, perFileLimit, budget int64) (*Collector, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	manifest, err := os.OpenFile(filepath.Join(dir, ManifestName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Collector{
+		dir:          dir,
+		perFileLimit: perFileLimit,
+		budget:       budget,
+		manifest:     manifest,
+	}, nil
+}
+
+// Collect downloads one file into the loot directory, writing it under
+// host/share/path, and appends a manifest entry. size is the advertised file
+// size used for budget reservation; open provides the content.
+func (c *Collector) Collect(host, share, filePath string, size int64, open func() (io.ReadCloser, error)) error {
+	if size > c.perFileLimit {
+		return ErrFileTooLarge
+	}
+
+	// Reserve the advertised size up front so concurrent downloads cannot
+	// collectively blow the budget; settle against actual bytes below
+	c.mu.Lock()
+	if c.used+size > c.budget {
+		c.mu.Unlock()
+		return ErrBudgetExhausted
+	}
+	c.used += size
+	c.mu.Unlock()
+
+	refund := func() {
+		c.mu.Lock()
+		c.used -= size
+		c.mu.Unlock()
+	}
+
+	relPath, err := localRelPath(host, share, filePath)
+	if err != nil {
+		refund()
+		return err
+	}
+	localPath := filepath.Join(c.dir, relPath)
+	if err := os.MkdirAll(filepath.Dir(localPath), 0700); err != nil {
+		refund()
+		return err
+	}
+
+	reader, err := open()
+	if err != nil {
+		refund()
+		return err
+	}
+	defer reader.Close()
+
+	dst, err := os.OpenFile(localPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		refund()
+		return err
+	}
+
+	// Read one byte past the limit so a server advertising a small size
+	// cannot stream an oversized file into the loot directory
+	hasher := sha256.New()
+	written, err := io.Copy(io.MultiWriter(dst, hasher), io.LimitReader(reader, c.perFileLimit+1))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > c.perFileLimit {
+		err = ErrFileTooLarge
+	}
+	if err != nil {
+		os.Remove(localPath)
+		refund()
+		return err
+	}
+
+	entry := Entry{
+		UNCPath:     utils.UNCPath(host, share, filePath),
+		LocalPath:   filepath.ToSlash(relPath),
+		Size:        written,
+		SHA256:      hex.EncodeToString(hasher.Sum(nil)),
+		CollectedAt: time.Now().UTC(),
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		refund()
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Settle the reservation against what was actually written
+	c.used += written - size
+	if _, err := c.manifest.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	c.collected++
+	return nil
+}
+
+// Collected returns the number of files and bytes downloaded so far.
+func (c *Collector) Collected() (files int, bytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.collected, c.used
+}
+
+// Close flushes and closes the manifest.
+func (c *Collector) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.manifest.Close()
+}
+
+// localRelPath maps a remote file onto a path relative to the loot
+// directory, refusing components that would escape it.
+func localRelPath(host, share, filePath string) (string, error) {
+	components := []string{sanitizeComponent(host), sanitizeComponent(share)}
+	for _, part := range strings.Split(strings.ReplaceAll(filePath, "\\", "/"), "/") {
+		part = sanitizeComponent(part)
+		if part == "" || part == "." {
+			continue
+		}
+		if part == ".." {
+			return "", fmt.Errorf("refusing path traversal in %q", filePath)
+		}
+		components = append(components, part)
+	}
+	if len(components) < 3 {
+		return "", fmt.Errorf("empty file path")
+	}
+	return filepath.Join(components...), nil
+}
+
+// sanitizeComponent makes one path component safe for the local filesystem.
+func sanitizeComponent(name string) string {
+	name = utils.NormalizeName(name)
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,200 @@
+// Package policy evaluates declarative share-permission assertions against
+// a scan's graph so pipelines can gate on compliance. A policy file is YAML
+// (JSON, being a YAML subset, parses too):
+//
+//	assertions:
+//	  - name: no-write-for-everyone
+//	    deny_write: [Everyone, Authenticated Users]
+//	  - name: admin-shares-restricted
+//	    share: ADMIN$
+//	    allow_only: [SYSTEM, Administrators, "S-1-5-21-*-512"]
+//
+// Principals are matched by SID, by well-known name (case-insensitive), or
+// by glob over the SID, so domain-relative groups like Domain Admins can be
+// expressed as "S-1-5-21-*-512". Share patterns are globs over the share
+// display name or its UNC path; an empty pattern matches every share.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/smb"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+// Assertion is one compliance rule. Exactly which principals it constrains
+// depends on which of the three lists are set: DenyWrite and DenyRead name
+// principals that must not hold the respective right on matching shares,
+// AllowOnly is the exhaustive list of principals permitted any access.
+type Assertion struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Share       string   `yaml:"share,omitempty"`
+	DenyWrite   []string `yaml:"deny_write,omitempty"`
+	DenyRead    []string `yaml:"deny_read,omitempty"`
+	AllowOnly   []string `yaml:"allow_only,omitempty"`
+}
+
+// Policy is a parsed policy file.
+type Policy struct {
+	Assertions []Assertion `yaml:"assertions"`
+}
+
+// Violation is one failed assertion instance: the named assertion, the share
+// it failed on and the principal that triggered it.
+type Violation struct {
+	Assertion string
+	UNCPath   string
+	Principal string
+	Detail    string
+}
+
+// Load reads and validates a policy file.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *Policy) validate() error {
+	if len(p.Assertions) == 0 {
+		return fmt.Errorf("policy declares no assertions")
+	}
+	for i, a := range p.Assertions {
+		if a.Name == "" {
+			return fmt.Errorf("assertion %d has no name", i)
+		}
+		if len(a.DenyWrite) == 0 && len(a.DenyRead) == 0 && len(a.AllowOnly) == 0 {
+			return fmt.Errorf("assertion %q declares no deny_write, deny_read or allow_only list", a.Name)
+		}
+		if a.Share != "" {
+			if _, err := path.Match(strings.ToLower(a.Share), "probe"); err != nil {
+				return fmt.Errorf("assertion %q has an invalid share pattern %q: %w", a.Name, a.Share, err)
+			}
+		}
+	}
+	return nil
+}
+
+// writeRightKinds and readRightKinds are the share-level rights each deny
+// list constrains; GENERIC_ALL counts as both.
+var writeRightKinds = map[string]bool{
+	kinds.EdgeKindCanGenericWrite: true,
+	kinds.EdgeKindCanGenericAll:   true,
+	kinds.EdgeKindCanShareWrite:   true,
+}
+
+var readRightKinds = map[string]bool{
+	kinds.EdgeKindCanGenericRead: true,
+	kinds.EdgeKindCanGenericAll:  true,
+	kinds.EdgeKindCanShareRead:   true,
+}
+
+// Evaluate checks every assertion against the scan graph and returns all
+// violations in assertion order.
+func (p *Policy) Evaluate(nodes []*graph.Node, edges []*graph.Edge) []Violation {
+	// Index the share nodes once; assertions match on display name or UNC
+	shareNames := make(map[string]string)
+	for _, node := range nodes {
+		for _, kind := range node.Kinds {
+			if kind == kinds.NodeKindNetworkShareSMB {
+				name, _ := node.Properties["displayName"].(string)
+				shareNames[node.ID] = name
+			}
+		}
+	}
+
+	var violations []Violation
+	for _, a := range p.Assertions {
+		for _, edge := range edges {
+			shareName, isShare := shareNames[edge.End.Value]
+			if !isShare || !strings.HasPrefix(edge.Kind, "Can") {
+				continue
+			}
+			uncPath := strings.TrimSuffix(edge.End.Value, "\\")
+			if !matchShare(a.Share, shareName, uncPath) {
+				continue
+			}
+			principal := edge.Start.Value
+
+			if writeRightKinds[edge.Kind] && matchAnyPrincipal(a.DenyWrite, principal) {
+				violations = append(violations, Violation{
+					Assertion: a.Name,
+					UNCPath:   uncPath,
+					Principal: principalLabel(principal),
+					Detail:    fmt.Sprintf("share grants %s (write) to a denied principal", edge.Kind),
+				})
+			}
+			if readRightKinds[edge.Kind] && matchAnyPrincipal(a.DenyRead, principal) {
+				violations = append(violations, Violation{
+					Assertion: a.Name,
+					UNCPath:   uncPath,
+					Principal: principalLabel(principal),
+					Detail:    fmt.Sprintf("share grants %s (read) to a denied principal", edge.Kind),
+				})
+			}
+			if len(a.AllowOnly) > 0 && !matchAnyPrincipal(a.AllowOnly, principal) {
+				violations = append(violations, Violation{
+					Assertion: a.Name,
+					UNCPath:   uncPath,
+					Principal: principalLabel(principal),
+					Detail:    fmt.Sprintf("share grants %s to a principal outside the allow list", edge.Kind),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// matchShare matches the assertion's share pattern against the display name
+// and the UNC path; no pattern means every share.
+func matchShare(pattern, shareName, uncPath string) bool {
+	if pattern == "" {
+		return true
+	}
+	pattern = strings.ToLower(pattern)
+	if ok, _ := path.Match(pattern, strings.ToLower(shareName)); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, strings.ToLower(strings.ReplaceAll(uncPath, "\\", "/")))
+	return ok
+}
+
+// matchAnyPrincipal reports whether the principal SID matches any entry in
+// the list: exact SID, glob over the SID, or the well-known name.
+func matchAnyPrincipal(entries []string, sid string) bool {
+	wellKnown := strings.ToLower(smb.GetWellKnownName(sid))
+	for _, entry := range entries {
+		lower := strings.ToLower(entry)
+		if lower == strings.ToLower(sid) || (wellKnown != "" && lower == wellKnown) {
+			return true
+		}
+		if ok, _ := path.Match(lower, strings.ToLower(sid)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// principalLabel renders a SID with its well-known name when there is one.
+func principalLabel(sid string) string {
+	if name := smb.GetWellKnownName(sid); name != "" {
+		return fmt.Sprintf("%s (%s)", name, sid)
+	}
+	return sid
+}
@@ -0,0 +1,137 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+func sampleGraph() ([]*graph.Node, []*graph.Edge) {
+	nodes := []*graph.Node{
+		graph.NewNode("\\\\fs01\\public\\", kinds.NodeKindNetworkShareSMB).
+			SetProperty("displayName", "public"),
+		graph.NewNode("\\\\fs01\\ADMIN$\\", kinds.NodeKindNetworkShareSMB).
+			SetProperty("displayName", "ADMIN$"),
+	}
+	edges := []*graph.Edge{
+		graph.NewEdge("S-1-1-0", "\\\\fs01\\public\\", kinds.EdgeKindCanShareWrite),
+		graph.NewEdge("S-1-1-0", "\\\\fs01\\public\\", kinds.EdgeKindCanShareRead),
+		graph.NewEdge("S-1-5-21-1-2-3-512", "\\\\fs01\\ADMIN$\\", kinds.EdgeKindCanGenericAll),
+		graph.NewEdge("S-1-5-21-1-2-3-1104", "\\\\fs01\\ADMIN$\\", kinds.EdgeKindCanShareRead),
+	}
+	return nodes, edges
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	content := `assertions:
+  - name: no-write-for-everyone
+    deny_write: [Everyone]
+  - name: admin-restricted
+    share: ADMIN$
+    allow_only: ["S-1-5-21-*-512"]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(p.Assertions) != 2 {
+		t.Errorf("assertions = %d, want 2", len(p.Assertions))
+	}
+	if p.Assertions[1].Share != "ADMIN$" || len(p.Assertions[1].AllowOnly) != 1 {
+		t.Errorf("second assertion = %+v, want share ADMIN$ with one allow_only entry", p.Assertions[1])
+	}
+}
+
+func TestLoadJSONCompat(t *testing.T) {
+	// JSON is a YAML subset, so pre-existing JSON policy files keep working
+	path := filepath.Join(t.TempDir(), "policy.json")
+	content := `{"assertions": [{"name": "no-write-for-everyone", "deny_write": ["Everyone"]}]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(p.Assertions) != 1 || len(p.Assertions[0].DenyWrite) != 1 {
+		t.Errorf("assertions = %+v, want one assertion with one deny_write entry", p.Assertions)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no assertions", `{"assertions": []}`, "no assertions"},
+		{"unnamed", `{"assertions": [{"deny_write": ["Everyone"]}]}`, "no name"},
+		{"empty lists", `{"assertions": [{"name": "x"}]}`, "declares no"},
+		{"bad pattern", `{"assertions": [{"name": "x", "share": "[", "deny_write": ["Everyone"]}]}`, "share pattern"},
+		{"bad syntax", `{`, "parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvaluateDenyWrite(t *testing.T) {
+	p := &Policy{Assertions: []Assertion{
+		{Name: "no-write-for-everyone", DenyWrite: []string{"Everyone"}},
+	}}
+	violations := p.Evaluate(sampleGraph())
+
+	if len(violations) != 1 {
+		t.Fatalf("violations = %d, want 1 (read rights must not count)", len(violations))
+	}
+	v := violations[0]
+	if v.Assertion != "no-write-for-everyone" || v.UNCPath != "\\\\fs01\\public" {
+		t.Errorf("violation = %+v", v)
+	}
+	if !strings.Contains(v.Principal, "Everyone") {
+		t.Errorf("principal should carry the well-known name: %q", v.Principal)
+	}
+}
+
+func TestEvaluateAllowOnly(t *testing.T) {
+	p := &Policy{Assertions: []Assertion{
+		{Name: "admin-restricted", Share: "ADMIN$", AllowOnly: []string{"S-1-5-21-*-512"}},
+	}}
+	violations := p.Evaluate(sampleGraph())
+
+	// Domain Admins (-512) matches the glob; the plain domain account does not
+	if len(violations) != 1 {
+		t.Fatalf("violations = %d, want 1", len(violations))
+	}
+	if violations[0].Principal != "S-1-5-21-1-2-3-1104" {
+		t.Errorf("violation = %+v", violations[0])
+	}
+}
+
+func TestEvaluateCompliant(t *testing.T) {
+	p := &Policy{Assertions: []Assertion{
+		{Name: "no-write-for-everyone", Share: "ADMIN$", DenyWrite: []string{"Everyone"}},
+	}}
+	if violations := p.Evaluate(sampleGraph()); len(violations) != 0 {
+		t.Errorf("violations = %+v, want none", violations)
+	}
+}
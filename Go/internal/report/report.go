@@ -0,0 +1,295 @@
+// Package report renders a standalone HTML executive report from exported
+// graph data: summary statistics, the most exposed shares, and principal
+// exposure tables with inline bar charts. The output is a single
+// self-contained file (no scripts, no external assets) so it can be mailed
+// to stakeholders who will never open BloodHound.
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/specterops/sharehound/internal/findings"
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/smb"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+// topShareCount and topPrincipalCount bound the tables so the report stays
+// readable on large environments.
+const (
+	topShareCount     = 20
+	topPrincipalCount = 25
+)
+
+// ShareExposure is one row of the top-exposed-shares table.
+type ShareExposure struct {
+	UNCPath       string
+	Principals    int
+	WorldWritable bool
+	WidthPct      int
+}
+
+// PrincipalExposure is one row of the principal exposure table.
+type PrincipalExposure struct {
+	Principal string
+	Readable  int
+	Writable  int
+	WidthPct  int
+}
+
+// Data is everything the template renders.
+type Data struct {
+	Source      string
+	GeneratedAt string
+
+	Hosts       int
+	Shares      int
+	Directories int
+	Files       int
+
+	WorldWritableShares int
+	AdminShares         int
+	LikelySecrets       int
+
+	TopShares     []ShareExposure
+	TopPrincipals []PrincipalExposure
+}
+
+// writeEdgeKinds are the share-level rights counted as write exposure.
+var writeEdgeKinds = map[string]bool{
+	kinds.EdgeKindCanGenericWrite: true,
+	kinds.EdgeKindCanGenericAll:   true,
+	kinds.EdgeKindCanShareWrite:   true,
+}
+
+// Build derives the report data from a scan's nodes and edges. source is a
+// label for the title (scan ID or file name).
+func Build(nodes []*graph.Node, edges []*graph.Edge, source, generatedAt string) *Data {
+	data := &Data{Source: source, GeneratedAt: generatedAt}
+
+	shareNodes := make(map[string]bool)
+	for _, node := range nodes {
+		for _, kind := range node.Kinds {
+			switch kind {
+			case kinds.NodeKindNetworkShareHost:
+				data.Hosts++
+			case kinds.NodeKindNetworkShareSMB:
+				data.Shares++
+				shareNodes[node.ID] = true
+			case kinds.NodeKindDirectory:
+				data.Directories++
+			case kinds.NodeKindFile:
+				data.Files++
+			}
+		}
+	}
+
+	for _, f := range findings.FromGraph(nodes, edges) {
+		switch f.Type {
+		case findings.TypeWorldWritableShare:
+			data.WorldWritableShares++
+		case findings.TypeAdminShareAccess:
+			data.AdminShares++
+		case findings.TypeLikelySecretFile:
+			data.LikelySecrets++
+		}
+	}
+
+	// Per-share principal counts and per-principal share counts, derived
+	// from the share-level rights edges
+	sharePrincipals := make(map[string]map[string]bool)
+	shareWorldWritable := make(map[string]bool)
+	principalReadable := make(map[string]map[string]bool)
+	principalWritable := make(map[string]map[string]bool)
+
+	for _, edge := range edges {
+		if !strings.HasPrefix(edge.Kind, "Can") || !shareNodes[edge.End.Value] {
+			continue
+		}
+		share, principal := edge.End.Value, edge.Start.Value
+
+		if sharePrincipals[share] == nil {
+			sharePrincipals[share] = make(map[string]bool)
+		}
+		sharePrincipals[share][principal] = true
+
+		if writeEdgeKinds[edge.Kind] {
+			if principalWritable[principal] == nil {
+				principalWritable[principal] = make(map[string]bool)
+			}
+			principalWritable[principal][share] = true
+			if principal == "S-1-1-0" || principal == "S-1-5-11" {
+				shareWorldWritable[share] = true
+			}
+		} else {
+			if principalReadable[principal] == nil {
+				principalReadable[principal] = make(map[string]bool)
+			}
+			principalReadable[principal][share] = true
+		}
+	}
+
+	for share, principals := range sharePrincipals {
+		data.TopShares = append(data.TopShares, ShareExposure{
+			UNCPath:       strings.TrimSuffix(share, "\\"),
+			Principals:    len(principals),
+			WorldWritable: shareWorldWritable[share],
+		})
+	}
+	sort.Slice(data.TopShares, func(i, j int) bool {
+		if data.TopShares[i].Principals != data.TopShares[j].Principals {
+			return data.TopShares[i].Principals > data.TopShares[j].Principals
+		}
+		return data.TopShares[i].UNCPath < data.TopShares[j].UNCPath
+	})
+	if len(data.TopShares) > topShareCount {
+		data.TopShares = data.TopShares[:topShareCount]
+	}
+	if len(data.TopShares) > 0 {
+		max := data.TopShares[0].Principals
+		for i := range data.TopShares {
+			data.TopShares[i].WidthPct = pct(data.TopShares[i].Principals, max)
+		}
+	}
+
+	principals := make(map[string]bool)
+	for p := range principalReadable {
+		principals[p] = true
+	}
+	for p := range principalWritable {
+		principals[p] = true
+	}
+	for p := range principals {
+		data.TopPrincipals = append(data.TopPrincipals, PrincipalExposure{
+			Principal: principalLabel(p),
+			Readable:  len(principalReadable[p]),
+			Writable:  len(principalWritable[p]),
+		})
+	}
+	sort.Slice(data.TopPrincipals, func(i, j int) bool {
+		pi, pj := data.TopPrincipals[i], data.TopPrincipals[j]
+		if pi.Writable != pj.Writable {
+			return pi.Writable > pj.Writable
+		}
+		if pi.Readable != pj.Readable {
+			return pi.Readable > pj.Readable
+		}
+		return pi.Principal < pj.Principal
+	})
+	if len(data.TopPrincipals) > topPrincipalCount {
+		data.TopPrincipals = data.TopPrincipals[:topPrincipalCount]
+	}
+	if len(data.TopPrincipals) > 0 {
+		max := 1
+		for _, p := range data.TopPrincipals {
+			if p.Readable+p.Writable > max {
+				max = p.Readable + p.Writable
+			}
+		}
+		for i := range data.TopPrincipals {
+			p := &data.TopPrincipals[i]
+			p.WidthPct = pct(p.Readable+p.Writable, max)
+		}
+	}
+
+	return data
+}
+
+func pct(value, max int) int {
+	if max <= 0 {
+		return 0
+	}
+	return value * 100 / max
+}
+
+// principalLabel renders a principal objectid with its well-known name when
+// there is one.
+func principalLabel(principal string) string {
+	if name := smb.GetWellKnownName(principal); name != "" {
+		return fmt.Sprintf("%s (%s)", name, principal)
+	}
+	return principal
+}
+
+// Render writes the HTML report for data to w.
+func Render(w io.Writer, data *Data) error {
+	return reportTemplate.Execute(w, data)
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>ShareHound Report — {{.Source}}</title>
+<style>
+  body { font-family: "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 960px; color: #1a1a2e; }
+  h1 { border-bottom: 3px solid #0b6e4f; padding-bottom: 0.3em; }
+  h2 { margin-top: 2em; color: #0b6e4f; }
+  .meta { color: #666; font-size: 0.9em; }
+  .cards { display: flex; flex-wrap: wrap; gap: 1em; margin: 1.5em 0; }
+  .card { flex: 1 1 120px; background: #f4f6f5; border-radius: 6px; padding: 1em; text-align: center; }
+  .card .num { font-size: 1.8em; font-weight: bold; }
+  .card.alert .num { color: #b3261e; }
+  table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+  th, td { text-align: left; padding: 0.4em 0.7em; border-bottom: 1px solid #ddd; font-size: 0.92em; }
+  th { background: #f4f6f5; }
+  td.num { text-align: right; font-variant-numeric: tabular-nums; }
+  .bar { background: #0b6e4f; height: 0.8em; border-radius: 2px; min-width: 2px; }
+  .bar.ww { background: #b3261e; }
+  .badge { background: #b3261e; color: #fff; border-radius: 3px; padding: 0.1em 0.4em; font-size: 0.8em; }
+</style>
+</head>
+<body>
+<h1>ShareHound Share Exposure Report</h1>
+<p class="meta">Source: {{.Source}} &middot; Generated: {{.GeneratedAt}}</p>
+
+<div class="cards">
+  <div class="card"><div class="num">{{.Hosts}}</div>Hosts</div>
+  <div class="card"><div class="num">{{.Shares}}</div>Shares</div>
+  <div class="card"><div class="num">{{.Directories}}</div>Directories</div>
+  <div class="card"><div class="num">{{.Files}}</div>Files</div>
+  <div class="card alert"><div class="num">{{.WorldWritableShares}}</div>World-writable shares</div>
+  <div class="card alert"><div class="num">{{.AdminShares}}</div>Admin shares reachable</div>
+  <div class="card alert"><div class="num">{{.LikelySecrets}}</div>Likely secret files</div>
+</div>
+
+<h2>Most Exposed Shares</h2>
+{{if .TopShares}}
+<table>
+  <tr><th>Share</th><th>Principals with access</th><th></th></tr>
+  {{range .TopShares}}
+  <tr>
+    <td>{{.UNCPath}} {{if .WorldWritable}}<span class="badge">world-writable</span>{{end}}</td>
+    <td class="num">{{.Principals}}</td>
+    <td style="width: 40%"><div class="bar{{if .WorldWritable}} ww{{end}}" style="width: {{.WidthPct}}%"></div></td>
+  </tr>
+  {{end}}
+</table>
+{{else}}
+<p>No share-level rights were collected.</p>
+{{end}}
+
+<h2>Principal Exposure</h2>
+{{if .TopPrincipals}}
+<table>
+  <tr><th>Principal</th><th>Shares readable</th><th>Shares writable</th><th></th></tr>
+  {{range .TopPrincipals}}
+  <tr>
+    <td>{{.Principal}}</td>
+    <td class="num">{{.Readable}}</td>
+    <td class="num">{{.Writable}}</td>
+    <td style="width: 30%"><div class="bar" style="width: {{.WidthPct}}%"></div></td>
+  </tr>
+  {{end}}
+</table>
+{{else}}
+<p>No principals were observed in share ACLs.</p>
+{{end}}
+
+</body>
+</html>
+`))
@@ -0,0 +1,99 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+func sampleGraph() ([]*graph.Node, []*graph.Edge) {
+	nodes := []*graph.Node{
+		graph.NewNode("NSHOST:fs01", kinds.NodeKindNetworkShareHost),
+		graph.NewNode("\\\\fs01\\public\\", kinds.NodeKindNetworkShareSMB).
+			SetProperty("displayName", "public"),
+		graph.NewNode("\\\\fs01\\finance\\", kinds.NodeKindNetworkShareSMB).
+			SetProperty("displayName", "finance"),
+		graph.NewNode("DIR:\\\\fs01\\public\\reports", kinds.NodeKindDirectory),
+		graph.NewNode("FILE:\\\\fs01\\public\\readme.txt", kinds.NodeKindFile),
+	}
+	edges := []*graph.Edge{
+		graph.NewEdge("S-1-1-0", "\\\\fs01\\public\\", kinds.EdgeKindCanShareWrite),
+		graph.NewEdge("S-1-5-11", "\\\\fs01\\public\\", kinds.EdgeKindCanShareRead),
+		graph.NewEdge("S-1-5-21-1-2-3-1104", "\\\\fs01\\public\\", kinds.EdgeKindCanShareRead),
+		graph.NewEdge("S-1-5-21-1-2-3-1104", "\\\\fs01\\finance\\", kinds.EdgeKindCanGenericAll),
+	}
+	return nodes, edges
+}
+
+func TestBuild(t *testing.T) {
+	nodes, edges := sampleGraph()
+	data := Build(nodes, edges, "scan1", "2026-09-01 12:00")
+
+	if data.Hosts != 1 || data.Shares != 2 || data.Directories != 1 || data.Files != 1 {
+		t.Errorf("counts = %d/%d/%d/%d, want 1/2/1/1", data.Hosts, data.Shares, data.Directories, data.Files)
+	}
+	if data.WorldWritableShares != 1 {
+		t.Errorf("world-writable shares = %d, want 1", data.WorldWritableShares)
+	}
+
+	if len(data.TopShares) != 2 {
+		t.Fatalf("top shares = %d, want 2", len(data.TopShares))
+	}
+	// public has three distinct principals, finance one
+	if data.TopShares[0].UNCPath != "\\\\fs01\\public" || data.TopShares[0].Principals != 3 {
+		t.Errorf("top share = %+v", data.TopShares[0])
+	}
+	if !data.TopShares[0].WorldWritable {
+		t.Error("public should be marked world-writable")
+	}
+	if data.TopShares[0].WidthPct != 100 {
+		t.Errorf("top share bar width = %d, want 100", data.TopShares[0].WidthPct)
+	}
+
+	if len(data.TopPrincipals) != 3 {
+		t.Fatalf("top principals = %d, want 3", len(data.TopPrincipals))
+	}
+	// The domain account writes one share and reads another, so it sorts
+	// ahead of Everyone (writable only); well-known names are shown
+	if data.TopPrincipals[0].Principal != "S-1-5-21-1-2-3-1104" || data.TopPrincipals[0].Writable != 1 {
+		t.Errorf("first principal = %+v", data.TopPrincipals[0])
+	}
+	var everyone *PrincipalExposure
+	for i := range data.TopPrincipals {
+		if strings.Contains(data.TopPrincipals[i].Principal, "Everyone") {
+			everyone = &data.TopPrincipals[i]
+		}
+	}
+	if everyone == nil || everyone.Writable != 1 {
+		t.Errorf("Everyone should appear with one writable share: %+v", everyone)
+	}
+}
+
+func TestRender(t *testing.T) {
+	nodes, edges := sampleGraph()
+	data := Build(nodes, edges, "scan1", "2026-09-01 12:00")
+
+	var buf bytes.Buffer
+	if err := Render(&buf, data); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		"ShareHound Share Exposure Report",
+		"scan1",
+		"\\\\fs01\\public",
+		"world-writable",
+		"Everyone",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script") {
+		t.Error("report must not contain scripts")
+	}
+}
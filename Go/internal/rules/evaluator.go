@@ -2,6 +2,7 @@
 package rules
 
 import (
+	"path"
 	"strings"
 )
 
@@ -13,68 +14,148 @@ type EvaluationContext struct {
 	Depth     int
 }
 
-// GetFieldValue returns the value of a field from the context.
-func (c *EvaluationContext) GetFieldValue(field string) interface{} {
-	field = strings.ToUpper(field)
+// fieldAccessor reads one field out of an evaluation context.
+type fieldAccessor func(c *EvaluationContext) interface{}
 
-	switch field {
-	case "DEPTH":
-		return c.Depth
+// fieldAccessors maps canonical (upper-case) field names to accessors.
+// Conditions resolve their accessor once at compile time instead of
+// re-uppercasing the field name for every object they evaluate.
+var fieldAccessors = map[string]fieldAccessor{
+	"DEPTH": func(c *EvaluationContext) interface{} { return c.Depth },
 
-	case "SHARE.NAME":
+	"SHARE.NAME": func(c *EvaluationContext) interface{} {
 		if c.Share != nil {
 			return c.Share.Name
 		}
-	case "SHARE.DESCRIPTION":
+		return nil
+	},
+	"SHARE.DESCRIPTION": func(c *EvaluationContext) interface{} {
 		if c.Share != nil {
 			return c.Share.Description
 		}
-	case "SHARE.HIDDEN":
+		return nil
+	},
+	"SHARE.HIDDEN": func(c *EvaluationContext) interface{} {
 		if c.Share != nil {
 			return c.Share.Hidden
 		}
+		return nil
+	},
 
-	case "FILE.NAME":
+	"FILE.NAME": func(c *EvaluationContext) interface{} {
 		if c.File != nil {
 			return c.File.Name
 		}
-	case "FILE.PATH":
+		return nil
+	},
+	"FILE.PATH": func(c *EvaluationContext) interface{} {
 		if c.File != nil {
 			return c.File.Path
 		}
-	case "FILE.SIZE":
+		return nil
+	},
+	"FILE.SIZE": func(c *EvaluationContext) interface{} {
 		if c.File != nil {
 			return c.File.Size
 		}
-	case "FILE.EXTENSION":
+		return nil
+	},
+	"FILE.EXTENSION": func(c *EvaluationContext) interface{} {
 		if c.File != nil {
 			return c.File.Extension
 		}
+		return nil
+	},
 
-	case "DIR.NAME", "DIRECTORY.NAME":
+	"DIR.NAME": func(c *EvaluationContext) interface{} {
 		if c.Directory != nil {
 			return c.Directory.Name
 		}
-	case "DIR.PATH", "DIRECTORY.PATH":
+		return nil
+	},
+	"DIR.PATH": func(c *EvaluationContext) interface{} {
 		if c.Directory != nil {
 			return c.Directory.Path
 		}
-	}
+		return nil
+	},
+}
+
+func init() {
+	// DIRECTORY.* are aliases for DIR.*
+	fieldAccessors["DIRECTORY.NAME"] = fieldAccessors["DIR.NAME"]
+	fieldAccessors["DIRECTORY.PATH"] = fieldAccessors["DIR.PATH"]
+}
+
+// resolveField returns the accessor for a field name, or nil if the field is
+// unknown. Field names are case-insensitive.
+func resolveField(field string) fieldAccessor {
+	return fieldAccessors[strings.ToUpper(field)]
+}
 
+// GetFieldValue returns the value of a field from the context.
+func (c *EvaluationContext) GetFieldValue(field string) interface{} {
+	if accessor := resolveField(field); accessor != nil {
+		return accessor(c)
+	}
 	return nil
 }
 
 // Evaluator evaluates rules against objects.
+//
+// The rule set is immutable after construction, so a single Evaluator may be
+// shared across goroutines — but the Set* methods and CanExplore/CanProcess
+// mutate one shared context and are only safe from a single goroutine.
+// Parallel traversal must use EvaluateFor (or the CanExploreFor/CanProcessFor
+// wrappers) with a per-goroutine context instead.
 type Evaluator struct {
-	rules   []Rule
-	context *EvaluationContext
+	rules        []Rule
+	defaultAllow bool
+	context      *EvaluationContext
 }
 
 // NewEvaluator creates a new rule evaluator.
 func NewEvaluator(rules []Rule) *Evaluator {
+	// Resolve the default behavior and compile every condition up front so
+	// nothing mutates the rule set once evaluation starts.
+	defaultAllow := true
+	for _, rule := range rules {
+		if rule.IsDefault {
+			defaultAllow = rule.DefaultBehavior == ActionAllow
+			break
+		}
+	}
+	for i := range rules {
+		rules[i].sharePatternFolded = strings.ToLower(rules[i].SharePattern)
+		precompileCondition(rules[i].Condition)
+	}
+
 	return &Evaluator{
-		rules:   rules,
-		context: &EvaluationContext{},
+		rules:        rules,
+		defaultAllow: defaultAllow,
+		context:      &EvaluationContext{},
+	}
+}
+
+// precompileCondition walks a condition tree and compiles any field
+// conditions that were built as struct literals rather than through the
+// parser, so lazy compilation never races during evaluation.
+func precompileCondition(cond Condition) {
+	switch c := cond.(type) {
+	case *FieldCondition:
+		if !c.compiled {
+			c.compile()
+		}
+	case *NotCondition:
+		precompileCondition(c.Inner)
+	case *AndCondition:
+		for _, inner := range c.Conditions {
+			precompileCondition(inner)
+		}
+	case *OrCondition:
+		for _, inner := range c.Conditions {
+			precompileCondition(inner)
+		}
 	}
 }
 
@@ -105,46 +186,82 @@ func (e *Evaluator) GetContext() *EvaluationContext {
 
 // CanExplore checks if an object can be explored (for directories and shares).
 func (e *Evaluator) CanExplore(obj interface{}) bool {
-	// Set context based on object type
-	switch v := obj.(type) {
-	case *RuleObjectShare:
-		e.context.Share = v
-	case *RuleObjectDirectory:
-		e.context.Directory = v
-	}
-
-	return e.evaluate(ScopeExploration)
+	applyObject(e.context, obj)
+	return e.evaluateIn(e.context, ScopeExploration)
 }
 
 // CanProcess checks if an object can be processed (added to graph).
 func (e *Evaluator) CanProcess(obj interface{}) bool {
-	// Set context based on object type
+	applyObject(e.context, obj)
+	return e.evaluateIn(e.context, ScopeProcessing)
+}
+
+// EvaluateFor evaluates the rules for obj in the given scope without touching
+// the evaluator's shared context. env carries the surrounding traversal state
+// (share, depth, parent directory) and is not modified; pass nil for an empty
+// environment. Safe for concurrent use as long as each goroutine owns its env.
+func (e *Evaluator) EvaluateFor(obj interface{}, env *EvaluationContext, scope RuleScope) bool {
+	var local EvaluationContext
+	if env != nil {
+		local = *env
+	}
+	applyObject(&local, obj)
+	return e.evaluateIn(&local, scope)
+}
+
+// CanExploreFor is EvaluateFor with the exploration scope.
+func (e *Evaluator) CanExploreFor(obj interface{}, env *EvaluationContext) bool {
+	return e.EvaluateFor(obj, env, ScopeExploration)
+}
+
+// CanProcessFor is EvaluateFor with the processing scope.
+func (e *Evaluator) CanProcessFor(obj interface{}, env *EvaluationContext) bool {
+	return e.EvaluateFor(obj, env, ScopeProcessing)
+}
+
+// ShouldCollect reports whether any COLLECT rule matches the object. Unlike
+// allow/deny evaluation there is no default: a file is only collected when a
+// COLLECT rule explicitly matches it.
+func (e *Evaluator) ShouldCollect(obj interface{}) bool {
+	applyObject(e.context, obj)
+	return e.collectIn(e.context)
+}
+
+// ShouldCollectFor is ShouldCollect against a caller-owned environment, with
+// the same concurrency contract as EvaluateFor.
+func (e *Evaluator) ShouldCollectFor(obj interface{}, env *EvaluationContext) bool {
+	var local EvaluationContext
+	if env != nil {
+		local = *env
+	}
+	applyObject(&local, obj)
+	return e.collectIn(&local)
+}
+
+// matchShareName reports whether a share name matches a case-folded glob
+// pattern ('*' and '?' wildcards, as in path.Match).
+func matchShareName(foldedPattern, name string) bool {
+	ok, err := path.Match(foldedPattern, strings.ToLower(name))
+	return err == nil && ok
+}
+
+// applyObject sets the context slot matching the object's type.
+func applyObject(ctx *EvaluationContext, obj interface{}) {
 	switch v := obj.(type) {
 	case *RuleObjectShare:
-		e.context.Share = v
+		ctx.Share = v
 	case *RuleObjectFile:
-		e.context.File = v
+		ctx.File = v
 	case *RuleObjectDirectory:
-		e.context.Directory = v
+		ctx.Directory = v
 	}
-
-	return e.evaluate(ScopeProcessing)
 }
 
-// evaluate runs the rules and returns the final decision.
-func (e *Evaluator) evaluate(scope RuleScope) bool {
-	// Find default behavior
-	defaultAllow := true
-	for _, rule := range e.rules {
-		if rule.IsDefault {
-			defaultAllow = rule.DefaultBehavior == ActionAllow
-			break
-		}
-	}
-
+// evaluateIn runs the rules against a context and returns the final decision.
+func (e *Evaluator) evaluateIn(ctx *EvaluationContext, scope RuleScope) bool {
 	// Evaluate rules in order
 	for _, rule := range e.rules {
-		if rule.IsDefault {
+		if rule.IsDefault || rule.Action == ActionCollect {
 			continue
 		}
 
@@ -153,11 +270,49 @@ func (e *Evaluator) evaluate(scope RuleScope) bool {
 			continue
 		}
 
+		// Check if rule applies to the current share
+		if rule.sharePatternFolded != "" {
+			if ctx.Share == nil || !matchShareName(rule.sharePatternFolded, ctx.Share.Name) {
+				continue
+			}
+		}
+
 		// Evaluate condition
-		if rule.Condition == nil || rule.Condition.Evaluate(e.context) {
+		if rule.Condition == nil || rule.Condition.Evaluate(ctx) {
 			return rule.Action == ActionAllow
 		}
 	}
 
-	return defaultAllow
+	return e.defaultAllow
+}
+
+// collectIn runs only the COLLECT rules against a context.
+func (e *Evaluator) collectIn(ctx *EvaluationContext) bool {
+	for _, rule := range e.rules {
+		if rule.Action != ActionCollect {
+			continue
+		}
+
+		if rule.sharePatternFolded != "" {
+			if ctx.Share == nil || !matchShareName(rule.sharePatternFolded, ctx.Share.Name) {
+				continue
+			}
+		}
+
+		if rule.Condition == nil || rule.Condition.Evaluate(ctx) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasCollectRules reports whether a rule set contains any COLLECT rule.
+func HasCollectRules(ruleSet []Rule) bool {
+	for _, rule := range ruleSet {
+		if rule.Action == ActionCollect {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"testing"
+)
+
+// Benchmarks for rule evaluation. Every share, directory, and file seen
+// during traversal passes through CanExplore/CanProcess, so evaluation has
+// to stay cheap relative to the SMB round trips around it. Run with:
+//
+//	go test -bench=. -benchmem ./internal/rules
+
+// benchRules parses a rule set or fails the benchmark.
+func benchRules(b *testing.B, input string) []Rule {
+	b.Helper()
+	rules, errs := NewParser().Parse(input)
+	if len(errs) > 0 {
+		b.Fatalf("Parse errors: %v", errs)
+	}
+	return rules
+}
+
+func BenchmarkCanExploreDefaultRules(b *testing.B) {
+	rules, errs := NewParser().ParseStrings(DefaultRules)
+	if len(errs) > 0 {
+		b.Fatalf("Parse errors: %v", errs)
+	}
+	evaluator := NewEvaluator(rules)
+	share := &RuleObjectShare{Name: "data"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evaluator.CanExplore(share)
+	}
+}
+
+func BenchmarkCanProcessExtensionList(b *testing.B) {
+	rules := benchRules(b, `
+DEFAULT: DENY
+ALLOW PROCESSING IF FILE.EXTENSION IN ['.txt', '.doc', '.docx', '.xls', '.xlsx', '.pdf', '.config', '.ini', '.xml', '.ps1']
+`)
+	evaluator := NewEvaluator(rules)
+	files := []*RuleObjectFile{
+		{Name: "notes.TXT", Extension: ".TXT"},
+		{Name: "setup.exe", Extension: ".exe"},
+		{Name: "web.config", Extension: ".config"},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evaluator.CanProcess(files[i%len(files)])
+	}
+}
+
+func BenchmarkCanProcessMatches(b *testing.B) {
+	rules := benchRules(b, `
+DEFAULT: DENY
+ALLOW PROCESSING IF FILE.NAME MATCHES '(?i)(passw|secret|credential)'
+`)
+	evaluator := NewEvaluator(rules)
+	files := []*RuleObjectFile{
+		{Name: "passwords.xlsx"},
+		{Name: "quarterly-report.docx"},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evaluator.CanProcess(files[i%len(files)])
+	}
+}
+
+func BenchmarkCanExploreCompoundCondition(b *testing.B) {
+	rules := benchRules(b, `
+DEFAULT: ALLOW
+DENY EXPLORATION IF DEPTH > 5 AND DIR.NAME IN ['node_modules', '.git', 'temp', 'cache']
+`)
+	evaluator := NewEvaluator(rules)
+	evaluator.SetDepth(7)
+	dir := &RuleObjectDirectory{Name: "reports", Path: "finance\\2024\\reports"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evaluator.CanExplore(dir)
+	}
+}
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"sync"
 	"testing"
 )
 
@@ -259,3 +260,177 @@ DENY EXPLORATION IF DEPTH > 2
 		t.Error("Expected dir at depth 3 to not be explorable")
 	}
 }
+
+func TestEvaluatorShareSections(t *testing.T) {
+	parser := NewParser()
+	rules, errs := parser.Parse(`
+DEFAULT: ALLOW
+
+[share: 'home*']
+DENY EXPLORATION IF DEPTH > 2
+
+[share: 'dist*']
+DENY PROCESSING
+`)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+
+	evaluator := NewEvaluator(rules)
+
+	tests := []struct {
+		name      string
+		shareName string
+		depth     int
+		scope     RuleScope
+		want      bool
+	}{
+		{"Deep dir in home share denied", "homes", 5, ScopeExploration, false},
+		{"Shallow dir in home share allowed", "home-users", 1, ScopeExploration, true},
+		{"Deep dir elsewhere allowed", "data", 5, ScopeExploration, true},
+		{"Section match is case-insensitive", "HOME$", 5, ScopeExploration, false},
+		{"Processing denied on dist shares", "dist-packages", 0, ScopeProcessing, false},
+		{"Processing allowed elsewhere", "homes", 0, ScopeProcessing, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &EvaluationContext{
+				Share: &RuleObjectShare{Name: tt.shareName},
+				Depth: tt.depth,
+			}
+			dir := &RuleObjectDirectory{Name: "dir"}
+			if got := evaluator.EvaluateFor(dir, env, tt.scope); got != tt.want {
+				t.Errorf("Expected %v for share %q at depth %d, got %v",
+					tt.want, tt.shareName, tt.depth, got)
+			}
+		})
+	}
+}
+
+func TestEvaluateForDoesNotMutateContext(t *testing.T) {
+	parser := NewParser()
+	rules, _ := parser.Parse(`
+DEFAULT: ALLOW
+DENY EXPLORATION IF DEPTH > 3
+`)
+
+	evaluator := NewEvaluator(rules)
+	evaluator.SetDepth(1)
+
+	env := &EvaluationContext{Depth: 5}
+	dir := &RuleObjectDirectory{Name: "deep"}
+
+	if evaluator.EvaluateFor(dir, env, ScopeExploration) {
+		t.Error("Expected EvaluateFor to deny at env depth 5")
+	}
+
+	// Neither the caller's env nor the evaluator's shared context may change.
+	if env.Directory != nil {
+		t.Error("EvaluateFor mutated the caller's environment")
+	}
+	if evaluator.GetContext().Depth != 1 || evaluator.GetContext().Directory != nil {
+		t.Error("EvaluateFor mutated the evaluator's shared context")
+	}
+
+	// The shared-context path still works independently.
+	if !evaluator.CanExplore(dir) {
+		t.Error("Expected CanExplore to allow at shared-context depth 1")
+	}
+}
+
+func TestEvaluateForParallel(t *testing.T) {
+	parser := NewParser()
+	rules, _ := parser.Parse(`
+DEFAULT: DENY
+ALLOW PROCESSING IF FILE.EXTENSION IN ['.txt', '.pdf']
+DENY EXPLORATION IF DEPTH > 2
+ALLOW EXPLORATION
+`)
+
+	evaluator := NewEvaluator(rules)
+
+	// Each goroutine owns its env; the evaluator is shared. Run under -race
+	// to catch any shared mutable state in the rule set.
+	var wg sync.WaitGroup
+	errs := make(chan string, 64)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(depth int) {
+			defer wg.Done()
+			env := &EvaluationContext{Depth: depth}
+			for i := 0; i < 100; i++ {
+				dir := &RuleObjectDirectory{Name: "dir"}
+				if got, want := evaluator.CanExploreFor(dir, env), depth <= 2; got != want {
+					errs <- "CanExploreFor disagreed with its goroutine's depth"
+					return
+				}
+				file := &RuleObjectFile{Name: "a.txt", Extension: ".txt"}
+				if !evaluator.CanProcessFor(file, env) {
+					errs <- "CanProcessFor denied an allowed extension"
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
+
+func TestShouldCollect(t *testing.T) {
+	parser := NewParser()
+	ruleSet, errs := parser.Parse(`DEFAULT: ALLOW
+DENY PROCESSING IF FILE.EXTENSION IN ['.iso']
+COLLECT IF FILE.EXTENSION IN ['.kdbx', '.pem']`)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+	evaluator := NewEvaluator(ruleSet)
+
+	vault := &RuleObjectFile{Name: "vault.kdbx", Extension: ".kdbx"}
+	if !evaluator.ShouldCollect(vault) {
+		t.Error("vault.kdbx should be collected")
+	}
+	if !evaluator.CanProcess(vault) {
+		t.Error("COLLECT rules must not affect allow/deny evaluation")
+	}
+
+	notes := &RuleObjectFile{Name: "notes.txt", Extension: ".txt"}
+	if evaluator.ShouldCollect(notes) {
+		t.Error("notes.txt matches no COLLECT rule")
+	}
+	if !evaluator.CanProcess(notes) {
+		t.Error("notes.txt should still be processed by DEFAULT: ALLOW")
+	}
+
+	iso := &RuleObjectFile{Name: "img.iso", Extension: ".iso"}
+	if evaluator.CanProcess(iso) {
+		t.Error("img.iso should be denied processing")
+	}
+}
+
+func TestShouldCollectForShareSections(t *testing.T) {
+	parser := NewParser()
+	ruleSet, errs := parser.Parse(`DEFAULT: ALLOW
+[share: 'home*']
+COLLECT IF FILE.NAME = 'id_rsa'
+[global]`)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+	evaluator := NewEvaluator(ruleSet)
+	key := &RuleObjectFile{Name: "id_rsa"}
+
+	homeEnv := &EvaluationContext{Share: &RuleObjectShare{Name: "homes"}}
+	if !evaluator.ShouldCollectFor(key, homeEnv) {
+		t.Error("id_rsa on a home share should be collected")
+	}
+	distEnv := &EvaluationContext{Share: &RuleObjectShare{Name: "dist"}}
+	if evaluator.ShouldCollectFor(key, distEnv) {
+		t.Error("id_rsa outside the section's shares should not be collected")
+	}
+}
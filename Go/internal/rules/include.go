@@ -0,0 +1,137 @@
+// Package rules provides ShareQL rule parsing and evaluation.
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseFile parses a rules file from disk, expanding INCLUDE directives:
+//
+//	INCLUDE 'corporate-baseline.rules'
+//	DENY EXPLORATION IF SHARE.NAME = 'backup$'
+//
+// Relative include paths resolve against the directory of the including
+// file, so a rule set stays portable when the whole directory moves.
+// Includes may nest; a file including itself, directly or through a chain,
+// is reported as an error rather than recursing forever.
+//
+// Besides the parsed rules, ParseFile returns the raw contents of every
+// file read in inclusion order, so callers can fingerprint the effective
+// rule set (the scan metadata hash must change when an included baseline
+// changes, not just the top-level file).
+func (p *Parser) ParseFile(path string) ([]Rule, []string, []error) {
+	return p.parseFile(path, nil)
+}
+
+// parseFile implements ParseFile. visiting holds the absolute paths of the
+// files currently being expanded, outermost first, for cycle detection.
+func (p *Parser) parseFile(path string, visiting []string) ([]Rule, []string, []error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, []error{fmt.Errorf("resolving rules file %s: %v", path, err)}
+	}
+	for _, v := range visiting {
+		if v == abs {
+			return nil, nil, []error{fmt.Errorf("include cycle: %s", strings.Join(append(visiting, abs), " -> "))}
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, []error{fmt.Errorf("reading rules file %s: %v", path, err)}
+	}
+
+	visiting = append(visiting, abs)
+	rules := []Rule{}
+	sources := []string{string(content)}
+	var errs []error
+
+	var section string
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		pattern, isSection, err := parseSectionHeader(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s line %d: %v", path, i+1, err))
+			continue
+		}
+		if isSection {
+			section = pattern
+			continue
+		}
+
+		target, isInclude, err := parseIncludeDirective(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s line %d: %v", path, i+1, err))
+			continue
+		}
+		if isInclude {
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(path), target)
+			}
+			included, includedSources, includeErrs := p.parseFile(target, visiting)
+			// An include inside a [share: ...] section scopes the
+			// included rules too, unless they carry their own section
+			if section != "" {
+				for j := range included {
+					if included[j].SharePattern == "" && !included[j].IsDefault {
+						included[j].SharePattern = section
+					}
+				}
+			}
+			rules = append(rules, included...)
+			sources = append(sources, includedSources...)
+			errs = append(errs, includeErrs...)
+			continue
+		}
+
+		rule, err := p.parseLine(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s line %d: %v", path, i+1, err))
+			continue
+		}
+		if section != "" {
+			if rule.IsDefault {
+				errs = append(errs, fmt.Errorf("%s line %d: DEFAULT is not allowed inside a [share: ...] section", path, i+1))
+				continue
+			}
+			rule.SharePattern = section
+		}
+		rule.Source = path
+		rule.Line = i + 1
+		rules = append(rules, *rule)
+	}
+
+	return rules, sources, errs
+}
+
+// parseIncludeDirective recognizes an INCLUDE line and extracts its quoted
+// path. Returns isInclude=false for ordinary rule lines.
+func parseIncludeDirective(line string) (target string, isInclude bool, err error) {
+	if !strings.HasPrefix(strings.ToUpper(line), "INCLUDE") {
+		return "", false, nil
+	}
+	rest := strings.TrimSpace(line[len("INCLUDE"):])
+	if len(rest) == len(line)-len("INCLUDE") && rest != "" {
+		// No whitespace after the keyword: a field name like INCLUDED.X,
+		// not a directive. Rules never start with a field, so this only
+		// matters for producing a sensible parse error later.
+		return "", false, nil
+	}
+	if len(rest) >= 2 &&
+		((rest[0] == '\'' && rest[len(rest)-1] == '\'') ||
+			(rest[0] == '"' && rest[len(rest)-1] == '"')) {
+		target = rest[1 : len(rest)-1]
+		if target == "" {
+			return "", true, fmt.Errorf("INCLUDE path is empty")
+		}
+		return target, true, nil
+	}
+	return "", true, fmt.Errorf("INCLUDE expects a quoted path, got: %s", rest)
+}
@@ -0,0 +1,157 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeRulesFile writes a rules file into dir and returns its path.
+func writeRulesFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestParseFileWithoutIncludes(t *testing.T) {
+	dir := t.TempDir()
+	path := writeRulesFile(t, dir, "scan.rules", `
+DEFAULT: ALLOW
+DENY EXPLORATION IF SHARE.NAME = 'backup$'
+`)
+
+	rules, sources, errs := NewParser().ParseFile(path)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	if len(sources) != 1 {
+		t.Fatalf("Expected 1 source, got %d", len(sources))
+	}
+}
+
+func TestParseFileInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeRulesFile(t, dir, "baseline.rules", `
+DEFAULT: ALLOW
+DENY EXPLORATION IF SHARE.NAME IN ['c$', 'admin$', 'ipc$']
+`)
+	top := writeRulesFile(t, dir, "engagement.rules", `
+# Corporate baseline first, then engagement-specific overrides
+INCLUDE 'baseline.rules'
+DENY PROCESSING IF FILE.SIZE > 10000000
+`)
+
+	rules, sources, errs := NewParser().ParseFile(top)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("Expected 3 rules (2 included + 1 local), got %d", len(rules))
+	}
+	// Included rules splice in at the position of the directive
+	if !rules[0].IsDefault {
+		t.Error("Expected the included DEFAULT rule first")
+	}
+	if rules[2].Scope != ScopeProcessing {
+		t.Errorf("Expected the local rule last, got scope %q", rules[2].Scope)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("Expected 2 sources (top + included), got %d", len(sources))
+	}
+}
+
+func TestParseFileIncludeRelativeToIncludingFile(t *testing.T) {
+	dir := t.TempDir()
+	writeRulesFile(t, dir, filepath.Join("shared", "baseline.rules"), "DEFAULT: DENY\n")
+	writeRulesFile(t, dir, filepath.Join("shared", "extra.rules"), "ALLOW EXPLORATION\n")
+	// baseline.rules lives next to extra.rules, not next to the top file
+	writeRulesFile(t, dir, filepath.Join("shared", "all.rules"), "INCLUDE 'baseline.rules'\nINCLUDE 'extra.rules'\n")
+	top := writeRulesFile(t, dir, "scan.rules", "INCLUDE 'shared/all.rules'\n")
+
+	rules, _, errs := NewParser().ParseFile(top)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules through nested includes, got %d", len(rules))
+	}
+}
+
+func TestParseFileIncludeInsideSection(t *testing.T) {
+	dir := t.TempDir()
+	writeRulesFile(t, dir, "deep-dive.rules", "DENY EXPLORATION IF DEPTH > 10\n")
+	top := writeRulesFile(t, dir, "scan.rules", `
+DEFAULT: ALLOW
+[share: 'home*']
+INCLUDE 'deep-dive.rules'
+`)
+
+	rules, _, errs := NewParser().ParseFile(top)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	// Rules included inside a section inherit its share pattern
+	if rules[1].SharePattern != "home*" {
+		t.Errorf("Expected included rule to inherit pattern 'home*', got %q", rules[1].SharePattern)
+	}
+}
+
+func TestParseFileIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeRulesFile(t, dir, "a.rules", "INCLUDE 'b.rules'\n")
+	writeRulesFile(t, dir, "b.rules", "INCLUDE 'a.rules'\n")
+
+	_, _, errs := NewParser().ParseFile(filepath.Join(dir, "a.rules"))
+	if len(errs) == 0 {
+		t.Fatal("Expected a cycle error")
+	}
+	found := false
+	for _, err := range errs {
+		if strings.Contains(err.Error(), "include cycle") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected an include cycle error, got: %v", errs)
+	}
+}
+
+func TestParseFileIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"Missing file", "INCLUDE 'nonexistent.rules'\n", "reading rules file"},
+		{"Unquoted path", "INCLUDE baseline.rules\n", "quoted path"},
+		{"Empty path", "INCLUDE ''\n", "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeRulesFile(t, dir, "bad.rules", tt.content)
+			_, _, errs := NewParser().ParseFile(path)
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+			}
+			if !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, errs[0])
+			}
+		})
+	}
+}
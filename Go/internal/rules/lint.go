@@ -0,0 +1,137 @@
+// Package rules provides ShareQL rule parsing and evaluation.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LintWarning describes a suspicious construct found in a parsed rule set.
+type LintWarning struct {
+	Source  string // file the rule came from, empty for inline rules
+	Line    int
+	Message string
+}
+
+func (w LintWarning) String() string {
+	if w.Source != "" {
+		return fmt.Sprintf("%s:%d: %s", w.Source, w.Line, w.Message)
+	}
+	return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+}
+
+// Lint statically analyzes a parsed rule set and reports rules that can
+// never take effect: rules shadowed by an earlier unconditional ALLOW/DENY,
+// conditions on unknown fields or invalid patterns (which always evaluate
+// false), and conditions on fields that are never set in the rule's scope.
+// Warnings carry the source file and line recorded at parse time.
+func Lint(ruleSet []Rule) []LintWarning {
+	var warnings []LintWarning
+	warn := func(r Rule, format string, args ...interface{}) {
+		warnings = append(warnings, LintWarning{
+			Source:  r.Source,
+			Line:    r.Line,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	// terminated records, per scope, the line of the unconditional unscoped
+	// rule after which no later rule in that scope can match.
+	terminated := map[RuleScope]Rule{}
+	seenDefault := false
+
+	for _, rule := range ruleSet {
+		if rule.IsDefault {
+			if seenDefault {
+				warn(rule, "duplicate DEFAULT rule is ignored; only the first one applies")
+			}
+			seenDefault = true
+			continue
+		}
+
+		// Shadowing by earlier unconditional rules. COLLECT rules run in
+		// their own pass, so only an unconditional DENY can make one dead
+		// (the files it would match are never processed).
+		scopes := []RuleScope{rule.Scope}
+		if rule.Scope == ScopeAll {
+			scopes = []RuleScope{ScopeExploration, ScopeProcessing}
+		}
+		unreachable := true
+		var shadow Rule
+		for _, scope := range scopes {
+			if t, ok := terminated[scope]; ok && (rule.Action != ActionCollect || t.Action == ActionDeny) {
+				shadow = t
+			} else {
+				unreachable = false
+			}
+		}
+		if unreachable {
+			warn(rule, "unreachable: shadowed by unconditional %s at %s",
+				shadow.Action, lintPosition(shadow))
+			continue
+		}
+
+		if isUnconditional(rule) && rule.SharePattern == "" && rule.Action != ActionCollect {
+			for _, scope := range scopes {
+				if _, ok := terminated[scope]; !ok {
+					terminated[scope] = rule
+				}
+			}
+		}
+
+		// Always-false or scope-mismatched conditions
+		walkFieldConditions(rule.Condition, func(c *FieldCondition) {
+			if resolveField(c.Field) == nil {
+				warn(rule, "unknown field %q; the condition always evaluates false", c.Field)
+				return
+			}
+			if c.Comparator == "MATCHES" {
+				if _, err := regexp.Compile(toString(c.Value)); err != nil {
+					warn(rule, "invalid MATCHES pattern %q: %v", toString(c.Value), err)
+				}
+			}
+			if rule.Scope == ScopeExploration &&
+				strings.HasPrefix(strings.ToUpper(c.Field), "FILE.") {
+				warn(rule, "field %q is never set during exploration; the condition always evaluates false", c.Field)
+			}
+		})
+	}
+
+	return warnings
+}
+
+// lintPosition formats a rule's parse position for inclusion in a message.
+func lintPosition(r Rule) string {
+	if r.Source != "" {
+		return fmt.Sprintf("%s:%d", r.Source, r.Line)
+	}
+	return fmt.Sprintf("line %d", r.Line)
+}
+
+// isUnconditional reports whether a rule matches every object in its scope.
+func isUnconditional(r Rule) bool {
+	if r.Condition == nil {
+		return true
+	}
+	_, always := r.Condition.(*AlwaysTrueCondition)
+	return always
+}
+
+// walkFieldConditions calls fn for every field condition in a condition tree.
+func walkFieldConditions(cond Condition, fn func(*FieldCondition)) {
+	switch c := cond.(type) {
+	case *FieldCondition:
+		fn(c)
+	case *NotCondition:
+		walkFieldConditions(c.Inner, fn)
+	case *AndCondition:
+		for _, inner := range c.Conditions {
+			walkFieldConditions(inner, fn)
+		}
+	case *OrCondition:
+		for _, inner := range c.Conditions {
+			walkFieldConditions(inner, fn)
+		}
+	}
+}
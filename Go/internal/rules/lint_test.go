@@ -0,0 +1,165 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+// lintInput parses a rule set and lints it, failing on parse errors.
+func lintInput(t *testing.T, input string) []LintWarning {
+	t.Helper()
+	rules, errs := NewParser().Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+	return Lint(rules)
+}
+
+func TestLintCleanRules(t *testing.T) {
+	warnings := lintInput(t, `
+DEFAULT: ALLOW
+DENY EXPLORATION IF SHARE.NAME IN ['c$', 'admin$', 'ipc$']
+DENY PROCESSING IF FILE.SIZE > 10000000
+ALLOW EXPLORATION
+`)
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+}
+
+func TestLintUnreachableAfterUnconditional(t *testing.T) {
+	warnings := lintInput(t, `
+DEFAULT: ALLOW
+ALLOW EXPLORATION
+DENY EXPLORATION IF SHARE.NAME = 'c$'
+`)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0].Message, "unreachable") {
+		t.Errorf("Expected an unreachable warning, got: %v", warnings[0])
+	}
+	if warnings[0].Line != 4 {
+		t.Errorf("Expected warning on line 4, got line %d", warnings[0].Line)
+	}
+	if !strings.Contains(warnings[0].Message, "line 3") {
+		t.Errorf("Expected the shadowing rule's line in the message, got: %v", warnings[0])
+	}
+}
+
+func TestLintUnconditionalOtherScopeDoesNotShadow(t *testing.T) {
+	warnings := lintInput(t, `
+DEFAULT: DENY
+ALLOW EXPLORATION
+ALLOW PROCESSING IF FILE.EXTENSION IN ['.txt']
+`)
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings across scopes, got: %v", warnings)
+	}
+}
+
+func TestLintScopedUnconditionalDoesNotShadow(t *testing.T) {
+	// An unconditional rule inside a [share: ...] section only matches some
+	// shares, so later rules stay reachable.
+	warnings := lintInput(t, `
+DEFAULT: ALLOW
+[share: 'dist*']
+DENY EXPLORATION
+[global]
+DENY EXPLORATION IF DEPTH > 5
+`)
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got: %v", warnings)
+	}
+}
+
+func TestLintUnknownField(t *testing.T) {
+	warnings := lintInput(t, `
+DEFAULT: ALLOW
+DENY EXPLORATION IF SHARE.TYPE = 'IPC'
+`)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0].Message, "unknown field") ||
+		!strings.Contains(warnings[0].Message, "SHARE.TYPE") {
+		t.Errorf("Expected an unknown-field warning naming SHARE.TYPE, got: %v", warnings[0])
+	}
+}
+
+func TestLintInvalidMatchesPattern(t *testing.T) {
+	warnings := lintInput(t, `
+DEFAULT: ALLOW
+DENY PROCESSING IF FILE.NAME MATCHES '('
+`)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0].Message, "MATCHES") {
+		t.Errorf("Expected an invalid-pattern warning, got: %v", warnings[0])
+	}
+}
+
+func TestLintFileFieldInExplorationScope(t *testing.T) {
+	warnings := lintInput(t, `
+DEFAULT: ALLOW
+DENY EXPLORATION IF FILE.EXTENSION = '.exe'
+`)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0].Message, "never set during exploration") {
+		t.Errorf("Expected a scope-mismatch warning, got: %v", warnings[0])
+	}
+}
+
+func TestLintDuplicateDefault(t *testing.T) {
+	warnings := lintInput(t, `
+DEFAULT: ALLOW
+DEFAULT: DENY
+`)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0].Message, "duplicate DEFAULT") {
+		t.Errorf("Expected a duplicate-DEFAULT warning, got: %v", warnings[0])
+	}
+}
+
+func TestLintDefaultRulesAreClean(t *testing.T) {
+	rules, errs := NewParser().ParseStrings(DefaultRules)
+	if len(errs) > 0 {
+		t.Fatalf("Parse errors: %v", errs)
+	}
+	if warnings := Lint(rules); len(warnings) != 0 {
+		t.Errorf("The shipped default rules must lint clean, got: %v", warnings)
+	}
+}
+
+func TestLintCollectRules(t *testing.T) {
+	// An unconditional ALLOW ends the processing scope for allow/deny
+	// rules, but COLLECT rules run in their own pass and stay live
+	warnings := lintInput(t, `DEFAULT: ALLOW
+ALLOW PROCESSING
+COLLECT IF FILE.EXTENSION IN ['.pem']`)
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	// After an unconditional DENY nothing is processed, so nothing can
+	// be collected either
+	warnings = lintInput(t, `DEFAULT: ALLOW
+DENY PROCESSING
+COLLECT IF FILE.EXTENSION IN ['.pem']`)
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Message, "unreachable") {
+		t.Errorf("Expected an unreachable warning, got %v", warnings)
+	}
+
+	// An unconditional COLLECT must not terminate the scope
+	warnings = lintInput(t, `DEFAULT: ALLOW
+COLLECT
+DENY PROCESSING IF FILE.SIZE > 1000`)
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+}
@@ -3,6 +3,7 @@ package rules
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
 )
@@ -29,24 +30,82 @@ func (p *Parser) Parse(input string) ([]Rule, []error) {
 
 	lines := strings.Split(input, "\n")
 
+	var section string
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
 			continue
 		}
 
+		pattern, isSection, err := parseSectionHeader(line)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("line %d: %v", i+1, err))
+			continue
+		}
+		if isSection {
+			section = pattern
+			continue
+		}
+
 		rule, err := p.parseLine(line)
 		if err != nil {
 			errors = append(errors, fmt.Errorf("line %d: %v", i+1, err))
 			continue
 		}
 
+		if section != "" {
+			if rule.IsDefault {
+				errors = append(errors, fmt.Errorf("line %d: DEFAULT is not allowed inside a [share: ...] section", i+1))
+				continue
+			}
+			rule.SharePattern = section
+		}
+
+		rule.Line = i + 1
 		rules = append(rules, *rule)
 	}
 
 	return rules, errors
 }
 
+// parseSectionHeader recognizes a section header line. [share: 'home*']
+// scopes the rules that follow to shares whose name matches the glob
+// pattern; [global] ends the section. Returns isSection=false for rule lines.
+func parseSectionHeader(line string) (pattern string, isSection bool, err error) {
+	if !strings.HasPrefix(line, "[") {
+		return "", false, nil
+	}
+	if !strings.HasSuffix(line, "]") {
+		return "", true, fmt.Errorf("unterminated section header: %s", line)
+	}
+
+	inner := strings.TrimSpace(line[1 : len(line)-1])
+	if strings.EqualFold(inner, "global") {
+		return "", true, nil
+	}
+
+	const prefix = "share:"
+	if len(inner) < len(prefix) || !strings.EqualFold(inner[:len(prefix)], prefix) {
+		return "", true, fmt.Errorf("unknown section header: %s", line)
+	}
+
+	rest := strings.TrimSpace(inner[len(prefix):])
+	if len(rest) < 2 ||
+		!((rest[0] == '\'' && rest[len(rest)-1] == '\'') ||
+			(rest[0] == '"' && rest[len(rest)-1] == '"')) {
+		return "", true, fmt.Errorf("[share: ...] expects a quoted pattern, got: %s", rest)
+	}
+	pattern = rest[1 : len(rest)-1]
+	if pattern == "" {
+		return "", true, fmt.Errorf("[share: ...] pattern is empty")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", true, fmt.Errorf("invalid share pattern %q: %v", pattern, err)
+	}
+
+	return pattern, true, nil
+}
+
 // ParseStrings parses multiple rule strings.
 func (p *Parser) ParseStrings(inputs []string) ([]Rule, []error) {
 	combined := strings.Join(inputs, "\n")
@@ -83,8 +142,11 @@ func (p *Parser) parseLine(line string) (*Rule, error) {
 	} else if strings.HasPrefix(upper, "DENY") {
 		action = ActionDeny
 		remaining = strings.TrimPrefix(line, line[:4])
+	} else if strings.HasPrefix(upper, "COLLECT") {
+		action = ActionCollect
+		remaining = strings.TrimPrefix(line, line[:7])
 	} else {
-		return nil, fmt.Errorf("rule must start with ALLOW, DENY, or DEFAULT")
+		return nil, fmt.Errorf("rule must start with ALLOW, DENY, COLLECT, or DEFAULT")
 	}
 
 	remaining = strings.TrimSpace(remaining)
@@ -100,6 +162,15 @@ func (p *Parser) parseLine(line string) (*Rule, error) {
 		remaining = strings.TrimSpace(remaining[10:])
 	}
 
+	// Collection only happens for files that pass processing, so a COLLECT
+	// rule is always in the processing scope
+	if action == ActionCollect {
+		if scope == ScopeExploration {
+			return nil, fmt.Errorf("COLLECT rules apply to processing, not exploration")
+		}
+		scope = ScopeProcessing
+	}
+
 	// Check for IF condition
 	upperRemaining = strings.ToUpper(remaining)
 	if strings.HasPrefix(upperRemaining, "IF ") {
@@ -203,11 +274,7 @@ func (p *Parser) parseComparison(input string) (Condition, error) {
 				return nil, err
 			}
 
-			return &FieldCondition{
-				Field:      field,
-				Comparator: comp,
-				Value:      value,
-			}, nil
+			return NewFieldCondition(field, comp, value), nil
 		}
 	}
 
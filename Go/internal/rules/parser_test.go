@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -237,6 +238,64 @@ DENY EXPLORATION IF SHARE.NAME = 'test'
 	}
 }
 
+func TestParseShareSections(t *testing.T) {
+	parser := NewParser()
+
+	input := `
+DEFAULT: ALLOW
+DENY EXPLORATION IF SHARE.NAME = 'ipc$'
+
+[share: 'home*']
+DENY EXPLORATION IF DEPTH > 10
+
+[SHARE: "dist"]
+DENY PROCESSING
+
+[global]
+ALLOW EXPLORATION
+`
+
+	rules, errors := parser.Parse(input)
+	if len(errors) > 0 {
+		t.Fatalf("Parse errors: %v", errors)
+	}
+	if len(rules) != 5 {
+		t.Fatalf("Expected 5 rules, got %d", len(rules))
+	}
+
+	wantPatterns := []string{"", "", "home*", "dist", ""}
+	for i, want := range wantPatterns {
+		if rules[i].SharePattern != want {
+			t.Errorf("Rule %d: expected share pattern %q, got %q", i, want, rules[i].SharePattern)
+		}
+	}
+}
+
+func TestParseShareSectionErrors(t *testing.T) {
+	parser := NewParser()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"Unterminated header", "[share: 'home*'\nALLOW"},
+		{"Unknown section", "[host: 'dc01']"},
+		{"Unquoted pattern", "[share: home*]"},
+		{"Empty pattern", "[share: '']"},
+		{"Invalid glob", "[share: '[']"},
+		{"DEFAULT inside section", "[share: 'home*']\nDEFAULT: DENY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errors := parser.Parse(tt.input)
+			if len(errors) == 0 {
+				t.Error("Expected parse error but got none")
+			}
+		})
+	}
+}
+
 func TestParseInvalidRule(t *testing.T) {
 	parser := NewParser()
 
@@ -265,3 +324,33 @@ func TestParseInvalidRule(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCollectRules(t *testing.T) {
+	parser := NewParser()
+	ruleSet, errors := parser.Parse(`DEFAULT: ALLOW
+COLLECT IF FILE.EXTENSION IN ['.kdbx', '.pem']
+COLLECT PROCESSING IF FILE.NAME = 'web.config'
+COLLECT`)
+	if len(errors) > 0 {
+		t.Fatalf("Unexpected parse errors: %v", errors)
+	}
+	if len(ruleSet) != 4 {
+		t.Fatalf("Expected 4 rules, got %d", len(ruleSet))
+	}
+	for _, i := range []int{1, 2, 3} {
+		if ruleSet[i].Action != ActionCollect {
+			t.Errorf("Rule %d action = %q, want COLLECT", i, ruleSet[i].Action)
+		}
+		if ruleSet[i].Scope != ScopeProcessing {
+			t.Errorf("Rule %d scope = %q, want PROCESSING", i, ruleSet[i].Scope)
+		}
+	}
+}
+
+func TestParseCollectExplorationError(t *testing.T) {
+	parser := NewParser()
+	_, errors := parser.Parse("COLLECT EXPLORATION IF DEPTH > 2")
+	if len(errors) != 1 || !strings.Contains(errors[0].Error(), "COLLECT rules apply to processing") {
+		t.Errorf("Expected a COLLECT scope error, got %v", errors)
+	}
+}
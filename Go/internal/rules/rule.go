@@ -12,6 +12,10 @@ type RuleAction string
 const (
 	ActionAllow RuleAction = "ALLOW"
 	ActionDeny  RuleAction = "DENY"
+
+	// ActionCollect marks files for download into the loot directory. It is
+	// evaluated in a separate pass and never decides allow/deny.
+	ActionCollect RuleAction = "COLLECT"
 )
 
 // RuleScope represents what the rule applies to.
@@ -30,6 +34,18 @@ type Rule struct {
 	Action          RuleAction
 	Scope           RuleScope
 	Condition       Condition
+
+	// SharePattern restricts the rule to shares whose name matches the
+	// glob pattern of the enclosing [share: '...'] section. Empty means
+	// the rule applies everywhere.
+	SharePattern string
+
+	// Source and Line record where the rule was parsed from, for
+	// diagnostics. Source is empty for rules not read from a file.
+	Source string
+	Line   int
+
+	sharePatternFolded string // case-folded copy, filled in by NewEvaluator
 }
 
 // Condition represents a rule condition.
@@ -49,11 +65,56 @@ type FieldCondition struct {
 	Field      string
 	Comparator string
 	Value      interface{}
+
+	// Precompiled state filled in by compile(). Rules are evaluated once per
+	// share, directory, and file, so field resolution, list splitting, and
+	// regexp compilation must not happen on that path.
+	accessor fieldAccessor       // resolved field lookup, nil if unknown
+	valueSet map[string]struct{} // case-folded lookup set for IN / NOT IN
+	pattern  *regexp.Regexp      // compiled pattern for MATCHES, nil if invalid
+	compiled bool
+}
+
+// NewFieldCondition builds a field condition and precompiles its field
+// accessor, lookup set, and regular expression.
+func NewFieldCondition(field, comparator string, value interface{}) *FieldCondition {
+	c := &FieldCondition{
+		Field:      field,
+		Comparator: comparator,
+		Value:      value,
+	}
+	c.compile()
+	return c
+}
+
+// compile resolves everything about the condition that does not depend on the
+// evaluation context. Conditions constructed as struct literals are compiled
+// lazily on first evaluation instead.
+func (c *FieldCondition) compile() {
+	c.accessor = resolveField(c.Field)
+
+	switch c.Comparator {
+	case "IN", "NOT IN":
+		c.valueSet = foldList(c.Value)
+	case "MATCHES":
+		if re, err := regexp.Compile(toString(c.Value)); err == nil {
+			c.pattern = re
+		}
+	}
+
+	c.compiled = true
 }
 
 func (c *FieldCondition) Evaluate(ctx *EvaluationContext) bool {
+	if !c.compiled {
+		c.compile()
+	}
+
 	// Get the field value from context
-	fieldValue := ctx.GetFieldValue(c.Field)
+	var fieldValue interface{}
+	if c.accessor != nil {
+		fieldValue = c.accessor(ctx)
+	}
 
 	// Compare based on comparator
 	switch c.Comparator {
@@ -70,11 +131,11 @@ func (c *FieldCondition) Evaluate(ctx *EvaluationContext) bool {
 	case ">=":
 		return compareGreater(fieldValue, c.Value) || compareEqual(fieldValue, c.Value)
 	case "IN":
-		return compareIn(fieldValue, c.Value)
+		return compareInSet(fieldValue, c.valueSet)
 	case "NOT IN":
-		return !compareIn(fieldValue, c.Value)
+		return !compareInSet(fieldValue, c.valueSet)
 	case "MATCHES":
-		return compareMatches(fieldValue, c.Value)
+		return c.pattern != nil && c.pattern.MatchString(toString(fieldValue))
 	default:
 		return false
 	}
@@ -143,38 +204,36 @@ func compareGreater(a, b interface{}) bool {
 	return false
 }
 
-func compareIn(value, list interface{}) bool {
-	listSlice, ok := list.([]interface{})
-	if !ok {
-		listStrSlice, ok := list.([]string)
-		if ok {
-			listSlice = make([]interface{}, len(listStrSlice))
-			for i, s := range listStrSlice {
-				listSlice[i] = s
-			}
-		} else {
-			return false
+// foldList converts an IN / NOT IN list into a case-folded lookup set so
+// membership checks are a single map probe instead of a linear scan that
+// re-folds every item.
+func foldList(list interface{}) map[string]struct{} {
+	var items []interface{}
+	switch l := list.(type) {
+	case []interface{}:
+		items = l
+	case []string:
+		items = make([]interface{}, len(l))
+		for i, s := range l {
+			items[i] = s
 		}
+	default:
+		return nil
 	}
 
-	for _, item := range listSlice {
-		if compareEqual(value, item) {
-			return true
-		}
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		set[strings.ToLower(toString(item))] = struct{}{}
 	}
-	return false
+	return set
 }
 
-func compareMatches(value, pattern interface{}) bool {
-	valueStr := toString(value)
-	patternStr := toString(pattern)
-
-	re, err := regexp.Compile(patternStr)
-	if err != nil {
+func compareInSet(value interface{}, set map[string]struct{}) bool {
+	if set == nil {
 		return false
 	}
-
-	return re.MatchString(valueStr)
+	_, ok := set[strings.ToLower(toString(value))]
+	return ok
 }
 
 func toString(v interface{}) string {
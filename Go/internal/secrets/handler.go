@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/specterops/sharehound/internal/collector"
+)
+
+// Handler samples the contents of files selected by the ShareQL rules and
+// attaches a likelySecret score to their nodes. It is registered through
+// collector.RegisterFileHandler when --secret-triage is set.
+type Handler struct {
+	sampleSize int64
+}
+
+// NewHandler creates a triage handler reading at most sampleSize bytes from
+// each end of a file.
+func NewHandler(sampleSize int64) *Handler {
+	return &Handler{sampleSize: sampleSize}
+}
+
+// Name implements collector.FileHandler.
+func (h *Handler) Name() string {
+	return "secret-triage"
+}
+
+// Matches implements collector.FileHandler. Every non-empty file the rules
+// selected is a candidate; the sample size bounds the cost, not the file.
+func (h *Handler) Matches(name string, size int64) bool {
+	return size > 0
+}
+
+// Handle implements collector.FileHandler. It reads the head sample, seeks
+// to the tail sample when the reader supports it, and records the score.
+func (h *Handler) Handle(hctx *collector.FileHandlerContext) error {
+	reader, err := hctx.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	head := make([]byte, min(h.sampleSize, hctx.Size))
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	head = head[:n]
+
+	// The tail sample needs a seekable reader; SMB file handles and
+	// os.File both are. A plain stream just scores on the head.
+	var tail []byte
+	if remaining := hctx.Size - int64(n); remaining > 0 {
+		if seeker, ok := reader.(io.Seeker); ok {
+			tailSize := min(h.sampleSize, remaining)
+			if _, err := seeker.Seek(-tailSize, io.SeekEnd); err == nil {
+				tail = make([]byte, tailSize)
+				if m, err := io.ReadFull(reader, tail); err == nil || err == io.ErrUnexpectedEOF {
+					tail = tail[:m]
+				} else {
+					tail = nil
+				}
+			}
+		}
+	}
+
+	result := Analyze(head, tail)
+	if result.Score == 0 {
+		return nil
+	}
+	hctx.Node.SetProperty("likelySecret", math.Round(result.Score*100)/100)
+	hctx.Node.SetProperty("secretIndicators", result.Indicators)
+	hctx.Log.Debug(fmt.Sprintf("Secret triage flagged %s (score %.2f: %v)", hctx.UNCPath, result.Score, result.Indicators))
+	return nil
+}
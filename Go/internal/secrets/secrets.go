@@ -0,0 +1,113 @@
+// Package secrets implements a lightweight heuristic triage of file contents
+// for likely secret material. It never downloads whole files: only the first
+// and last few KB are sampled, then scored on Shannon entropy and well-known
+// credential formats (PEM private key headers, AWS access key IDs, JWTs).
+// The result lands on the file node as a likelySecret score so analysts can
+// rank what to pull manually, without the cost of full content scanning.
+package secrets
+
+import (
+	"math"
+	"regexp"
+)
+
+// Indicator names recorded in the secretIndicators node property.
+const (
+	IndicatorPEMPrivateKey = "pem-private-key"
+	IndicatorAWSAccessKey  = "aws-access-key"
+	IndicatorJWT           = "jwt"
+	IndicatorHighEntropy   = "high-entropy-text"
+)
+
+var (
+	pemPrivateKeyPattern = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----`)
+	awsAccessKeyPattern  = regexp.MustCompile(`\b(AKIA|ASIA)[A-Z0-9]{16}\b`)
+	jwtPattern           = regexp.MustCompile(`eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)
+)
+
+// High-entropy scoring only applies to mostly printable samples: compressed
+// or encrypted binary formats (zip, jpeg, ...) sit near 8 bits/byte and
+// would otherwise drown the ranking in false positives.
+const (
+	printableThreshold = 0.9
+	entropyThreshold   = 5.0 // bits/byte; English text is ~4.1-4.5, base64 blobs ~6
+)
+
+// Result is the outcome of analyzing a file's samples.
+type Result struct {
+	// Score is the likelySecret score in [0, 1]; 0 means nothing matched.
+	Score float64
+	// Indicators lists the heuristics that fired, strongest first.
+	Indicators []string
+	// Entropy is the Shannon entropy of the sample in bits per byte.
+	Entropy float64
+}
+
+// Analyze scores the head and tail samples of a file. Either sample may be
+// empty; tail is nil when the file fits in one sample or cannot be seeked.
+func Analyze(head, tail []byte) Result {
+	sample := head
+	if len(tail) > 0 {
+		sample = make([]byte, 0, len(head)+len(tail))
+		sample = append(sample, head...)
+		sample = append(sample, tail...)
+	}
+
+	result := Result{Entropy: Entropy(sample)}
+	record := func(indicator string, score float64) {
+		result.Indicators = append(result.Indicators, indicator)
+		if score > result.Score {
+			result.Score = score
+		}
+	}
+
+	if pemPrivateKeyPattern.Match(sample) {
+		record(IndicatorPEMPrivateKey, 1.0)
+	}
+	if awsAccessKeyPattern.Match(sample) {
+		record(IndicatorAWSAccessKey, 0.9)
+	}
+	if jwtPattern.Match(sample) {
+		record(IndicatorJWT, 0.8)
+	}
+	if result.Entropy >= entropyThreshold && printableRatio(sample) >= printableThreshold {
+		record(IndicatorHighEntropy, 0.5)
+	}
+	return result
+}
+
+// Entropy computes the Shannon entropy of data in bits per byte.
+func Entropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	total := float64(len(data))
+	entropy := 0.0
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// printableRatio returns the fraction of bytes that are printable ASCII or
+// common whitespace.
+func printableRatio(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+	printable := 0
+	for _, b := range data {
+		if (b >= 0x20 && b < 0x7f) || b == '\n' || b == '\r' || b == '\t' {
+			printable++
+		}
+	}
+	return float64(printable) / float64(len(data))
+}
@@ -0,0 +1,338 @@
+// Package shell provides the interactive exploration prompt behind the
+// "shell" subcommand: a small REPL over an authenticated SMB session so
+// operators can manually verify findings (list, read ACLs, download) without
+// switching to smbclient and re-authenticating.
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/specterops/sharehound/internal/smb"
+	"github.com/specterops/sharehound/internal/utils"
+)
+
+// Session is the slice of SMBSession the shell drives. It is an interface so
+// command handling can be exercised against a fake in tests.
+type Session interface {
+	ListShares() (map[string]smb.ShareInfo, error)
+	SetShare(shareName string) error
+	GetCurrentShare() string
+	ListContents(dirPath string) (map[string]smb.FileInfo, error)
+	OpenFile(filePath string) (io.ReadCloser, error)
+	GetFileSecurityDescriptor(filePath string) (*smb.SecurityDescriptor, error)
+	GetShareSecurityDescriptor(shareName string) ([]byte, error)
+}
+
+// Shell is an interactive prompt over a connected SMB session.
+type Shell struct {
+	session Session
+	out     io.Writer
+	host    string
+	cwd     string // current directory within the selected share, "" = root
+}
+
+// New creates a shell over an authenticated session. host is only used in
+// the prompt and in download messages.
+func New(session Session, host string, out io.Writer) *Shell {
+	return &Shell{session: session, out: out, host: host}
+}
+
+// Run reads command lines from in until EOF or an exit command.
+func (s *Shell) Run(in io.Reader) error {
+	fmt.Fprintln(s.out, "Type 'help' for commands, 'exit' to quit")
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(s.out, "%s> ", s.prompt())
+		if !scanner.Scan() {
+			fmt.Fprintln(s.out)
+			return scanner.Err()
+		}
+		if s.Execute(scanner.Text()) {
+			return nil
+		}
+	}
+}
+
+func (s *Shell) prompt() string {
+	p := "\\\\" + s.host
+	if share := s.session.GetCurrentShare(); share != "" {
+		p += "\\" + share
+		if s.cwd != "" {
+			p += "\\" + s.cwd
+		}
+	}
+	return p
+}
+
+// Execute runs a single command line and reports whether the shell should
+// exit. Errors are printed, never returned: a failed command must not end
+// the session.
+func (s *Shell) Execute(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	cmd, args := fields[0], fields[1:]
+
+	switch strings.ToLower(cmd) {
+	case "exit", "quit":
+		return true
+	case "help":
+		s.cmdHelp()
+	case "shares":
+		s.cmdShares()
+	case "use":
+		s.cmdUse(args)
+	case "ls", "dir":
+		s.cmdLs(args)
+	case "cd":
+		s.cmdCd(args)
+	case "pwd":
+		fmt.Fprintln(s.out, s.prompt())
+	case "acl":
+		s.cmdACL(args)
+	case "get":
+		s.cmdGet(args)
+	default:
+		fmt.Fprintf(s.out, "Unknown command %q; type 'help'\n", cmd)
+	}
+	return false
+}
+
+func (s *Shell) cmdHelp() {
+	fmt.Fprint(s.out, `Commands:
+  shares              List shares on the host
+  use <share>         Connect to a share
+  ls [path]           List the current or given directory
+  cd <path>           Change directory within the share
+  pwd                 Print the current location
+  acl [path|share]    Show the ACL of a file, directory, or share
+  get <remote> [local] Download a file
+  exit                Quit the shell
+`)
+}
+
+func (s *Shell) cmdShares() {
+	shares, err := s.session.ListShares()
+	if err != nil {
+		fmt.Fprintf(s.out, "Failed to list shares: %v\n", err)
+		return
+	}
+
+	names := make([]string, 0, len(shares))
+	for _, info := range shares {
+		names = append(names, info.Name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		info := shares[strings.ToLower(name)]
+		fmt.Fprintf(s.out, "  %-20s %-30s %s\n", name, strings.Join(info.Type, ","), info.Comment)
+	}
+}
+
+func (s *Shell) cmdUse(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(s.out, "Usage: use <share>")
+		return
+	}
+	if err := s.session.SetShare(args[0]); err != nil {
+		fmt.Fprintf(s.out, "Failed to connect to share %s: %v\n", args[0], err)
+		return
+	}
+	s.cwd = ""
+}
+
+func (s *Shell) cmdLs(args []string) {
+	if s.session.GetCurrentShare() == "" {
+		fmt.Fprintln(s.out, "No share selected; run 'use <share>' first")
+		return
+	}
+	path := s.cwd
+	if len(args) > 0 {
+		path = s.resolve(args[0])
+	}
+
+	contents, err := s.session.ListContents(path)
+	if err != nil {
+		fmt.Fprintf(s.out, "Failed to list %s: %v\n", displayPath(path), err)
+		return
+	}
+
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		info := contents[name]
+		if info.IsDir {
+			fmt.Fprintf(s.out, "  %-12s %19s  %s\n", "<DIR>", timestamp(info), name)
+		} else {
+			fmt.Fprintf(s.out, "  %-12s %19s  %s\n", utils.FormatFileSize(info.Size), timestamp(info), name)
+		}
+	}
+}
+
+func timestamp(info smb.FileInfo) string {
+	if info.ModifiedTime.IsZero() {
+		return ""
+	}
+	return info.ModifiedTime.Format("2006-01-02 15:04:05")
+}
+
+func (s *Shell) cmdCd(args []string) {
+	if s.session.GetCurrentShare() == "" {
+		fmt.Fprintln(s.out, "No share selected; run 'use <share>' first")
+		return
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(s.out, "Usage: cd <path>")
+		return
+	}
+
+	target := s.resolve(args[0])
+	if _, err := s.session.ListContents(target); err != nil {
+		fmt.Fprintf(s.out, "Cannot enter %s: %v\n", displayPath(target), err)
+		return
+	}
+	s.cwd = target
+}
+
+func (s *Shell) cmdACL(args []string) {
+	// Without a selected share the argument names a share; with one it is a
+	// file or directory path (default: the current directory).
+	if s.session.GetCurrentShare() == "" {
+		if len(args) != 1 {
+			fmt.Fprintln(s.out, "Usage: acl <share> (or 'use' a share and run 'acl [path]')")
+			return
+		}
+		sdBytes, err := s.session.GetShareSecurityDescriptor(args[0])
+		if err != nil {
+			fmt.Fprintf(s.out, "Failed to read ACL of share %s: %v\n", args[0], err)
+			return
+		}
+		sd, err := smb.ParseSecurityDescriptor(sdBytes)
+		if err != nil {
+			fmt.Fprintf(s.out, "Failed to parse security descriptor: %v\n", err)
+			return
+		}
+		s.printSD(sd)
+		return
+	}
+
+	path := s.cwd
+	if len(args) > 0 {
+		path = s.resolve(args[0])
+	}
+	sd, err := s.session.GetFileSecurityDescriptor(path)
+	if err != nil {
+		fmt.Fprintf(s.out, "Failed to read ACL of %s: %v\n", displayPath(path), err)
+		return
+	}
+	s.printSD(sd)
+}
+
+func (s *Shell) printSD(sd *smb.SecurityDescriptor) {
+	if sd.OwnerSID != nil {
+		fmt.Fprintf(s.out, "Owner: %s\n", sidLabel(sd.OwnerSID.String()))
+	}
+	if sd.GroupSID != nil {
+		fmt.Fprintf(s.out, "Group: %s\n", sidLabel(sd.GroupSID.String()))
+	}
+	if sd.Dacl == nil || len(sd.Dacl.Aces) == 0 {
+		fmt.Fprintln(s.out, "No DACL (everyone has full access)")
+		return
+	}
+	for _, ace := range sd.Dacl.Aces {
+		verb := "ALLOW"
+		if ace.AceType == 1 {
+			verb = "DENY"
+		}
+		rights := smb.GetNTFSRightsForMask(ace.Mask)
+		fmt.Fprintf(s.out, "  %-5s %-45s %s\n", verb, sidLabel(ace.SID.String()), strings.Join(rights, ","))
+	}
+}
+
+func sidLabel(sid string) string {
+	if name := smb.GetWellKnownName(sid); name != "" {
+		return sid + " (" + name + ")"
+	}
+	return sid
+}
+
+func (s *Shell) cmdGet(args []string) {
+	if s.session.GetCurrentShare() == "" {
+		fmt.Fprintln(s.out, "No share selected; run 'use <share>' first")
+		return
+	}
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Fprintln(s.out, "Usage: get <remote> [local]")
+		return
+	}
+
+	remote := s.resolve(args[0])
+	local := filepath.Base(strings.ReplaceAll(remote, "\\", "/"))
+	if len(args) == 2 {
+		local = args[1]
+	}
+
+	reader, err := s.session.OpenFile(remote)
+	if err != nil {
+		fmt.Fprintf(s.out, "Failed to open %s: %v\n", displayPath(remote), err)
+		return
+	}
+	defer reader.Close()
+
+	file, err := os.Create(local)
+	if err != nil {
+		fmt.Fprintf(s.out, "Failed to create %s: %v\n", local, err)
+		return
+	}
+	defer file.Close()
+
+	written, err := io.Copy(file, reader)
+	if err != nil {
+		fmt.Fprintf(s.out, "Download of %s failed after %d bytes: %v\n", displayPath(remote), written, err)
+		return
+	}
+	fmt.Fprintf(s.out, "Downloaded %s to %s (%s)\n", displayPath(remote), local, utils.FormatFileSize(written))
+}
+
+// resolve turns a user-supplied path into a share-relative path, applying
+// the current directory and collapsing "." and "..".
+func (s *Shell) resolve(arg string) string {
+	arg = strings.ReplaceAll(arg, "/", "\\")
+
+	var parts []string
+	if !strings.HasPrefix(arg, "\\") && s.cwd != "" {
+		parts = strings.Split(s.cwd, "\\")
+	}
+	for _, part := range strings.Split(strings.Trim(arg, "\\"), "\\") {
+		switch part {
+		case "", ".":
+		case "..":
+			if len(parts) > 0 {
+				parts = parts[:len(parts)-1]
+			}
+		default:
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\\")
+}
+
+// displayPath renders a share-relative path for messages, with the share
+// root shown as "\".
+func displayPath(path string) string {
+	if path == "" {
+		return "\\"
+	}
+	return path
+}
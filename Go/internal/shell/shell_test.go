@@ -0,0 +1,163 @@
+package shell
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/specterops/sharehound/internal/smb"
+)
+
+// fakeSession implements Session over an in-memory share layout.
+type fakeSession struct {
+	shares       map[string]smb.ShareInfo
+	currentShare string
+	dirs         map[string]map[string]smb.FileInfo // share-relative path -> contents
+	files        map[string]string                  // share-relative path -> content
+}
+
+func (f *fakeSession) ListShares() (map[string]smb.ShareInfo, error) {
+	return f.shares, nil
+}
+
+func (f *fakeSession) SetShare(shareName string) error {
+	if _, ok := f.shares[strings.ToLower(shareName)]; !ok {
+		return fmt.Errorf("no such share: %s", shareName)
+	}
+	f.currentShare = shareName
+	return nil
+}
+
+func (f *fakeSession) GetCurrentShare() string {
+	return f.currentShare
+}
+
+func (f *fakeSession) ListContents(dirPath string) (map[string]smb.FileInfo, error) {
+	contents, ok := f.dirs[dirPath]
+	if !ok {
+		return nil, fmt.Errorf("no such directory: %s", dirPath)
+	}
+	return contents, nil
+}
+
+func (f *fakeSession) OpenFile(filePath string) (io.ReadCloser, error) {
+	content, ok := f.files[filePath]
+	if !ok {
+		return nil, fmt.Errorf("no such file: %s", filePath)
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (f *fakeSession) GetFileSecurityDescriptor(filePath string) (*smb.SecurityDescriptor, error) {
+	if _, ok := f.files[filePath]; !ok {
+		if _, ok := f.dirs[filePath]; !ok {
+			return nil, fmt.Errorf("no such path: %s", filePath)
+		}
+	}
+	return &smb.SecurityDescriptor{}, nil
+}
+
+func (f *fakeSession) GetShareSecurityDescriptor(shareName string) ([]byte, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func newFakeSession() *fakeSession {
+	return &fakeSession{
+		shares: map[string]smb.ShareInfo{
+			"data": {Name: "data", Type: []string{"STYPE_DISKTREE"}, Comment: "Data share"},
+			"ipc$": {Name: "IPC$", Type: []string{"STYPE_IPC"}},
+		},
+		dirs: map[string]map[string]smb.FileInfo{
+			"": {
+				"backup":     {Name: "backup", IsDir: true},
+				"readme.txt": {Name: "readme.txt", Size: 12},
+			},
+			"backup": {
+				"keys.txt": {Name: "keys.txt", Size: 5},
+			},
+		},
+		files: map[string]string{
+			"readme.txt":       "hello shares",
+			"backup\\keys.txt": "keys!",
+		},
+	}
+}
+
+func runShell(t *testing.T, commands ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	sh := New(newFakeSession(), "srv01", &out)
+	for _, cmd := range commands {
+		if sh.Execute(cmd) {
+			break
+		}
+	}
+	return out.String()
+}
+
+func TestShellShares(t *testing.T) {
+	out := runShell(t, "shares")
+	if !strings.Contains(out, "data") || !strings.Contains(out, "Data share") {
+		t.Errorf("shares output missing entries:\n%s", out)
+	}
+}
+
+func TestShellLsRequiresShare(t *testing.T) {
+	out := runShell(t, "ls")
+	if !strings.Contains(out, "No share selected") {
+		t.Errorf("ls without share should prompt for 'use':\n%s", out)
+	}
+}
+
+func TestShellUseAndLs(t *testing.T) {
+	out := runShell(t, "use data", "ls")
+	if !strings.Contains(out, "readme.txt") || !strings.Contains(out, "<DIR>") {
+		t.Errorf("ls output missing entries:\n%s", out)
+	}
+}
+
+func TestShellCdAndResolve(t *testing.T) {
+	out := runShell(t, "use data", "cd backup", "ls", "cd ..", "ls")
+	if !strings.Contains(out, "keys.txt") {
+		t.Errorf("ls after cd should list subdirectory:\n%s", out)
+	}
+	if !strings.Contains(out, "readme.txt") {
+		t.Errorf("cd .. should return to the root:\n%s", out)
+	}
+
+	out = runShell(t, "use data", "cd nowhere")
+	if !strings.Contains(out, "Cannot enter nowhere") {
+		t.Errorf("cd into a missing directory should fail:\n%s", out)
+	}
+}
+
+func TestShellGet(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+	sh := New(newFakeSession(), "srv01", &out)
+	sh.Execute("use data")
+	sh.Execute("get backup\\keys.txt " + dir + "/keys.txt")
+	if !strings.Contains(out.String(), "Downloaded") {
+		t.Errorf("get should report the download:\n%s", out.String())
+	}
+}
+
+func TestShellUnknownCommand(t *testing.T) {
+	out := runShell(t, "frobnicate")
+	if !strings.Contains(out, "Unknown command") {
+		t.Errorf("unknown commands should be reported:\n%s", out)
+	}
+}
+
+func TestShellRunExits(t *testing.T) {
+	var out bytes.Buffer
+	sh := New(newFakeSession(), "srv01", &out)
+	if err := sh.Run(strings.NewReader("shares\nexit\n")); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(out.String(), "data") {
+		t.Errorf("Run should execute commands before exit:\n%s", out.String())
+	}
+}
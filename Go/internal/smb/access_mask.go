@@ -117,26 +117,56 @@ var NTFSRightsMapping = map[string]uint32{
 	kinds.EdgeKindCanNTFSWriteAttributes: NTFS_FILE_WRITE_ATTRIBUTES,
 }
 
-// GetShareRightsForMask returns the edge kinds for a given access mask using share-level mapping.
+// NTFSDirectoryRightsMapping maps edge kinds to NTFS-level access mask flags
+// for directory objects.  The low bits are overloaded by object type: on a
+// directory 0x1 is FILE_LIST_DIRECTORY rather than FILE_READ_DATA, 0x2 is
+// FILE_ADD_FILE, 0x4 is FILE_ADD_SUBDIRECTORY and 0x20 is FILE_TRAVERSE, so
+// directory ACEs are reported with the directory meaning instead of the
+// file one.  Standard and generic rights are identical for both types.
+var NTFSDirectoryRightsMapping = map[string]uint32{
+	kinds.EdgeKindCanNTFSGenericRead:          NTFS_GENERIC_READ,
+	kinds.EdgeKindCanNTFSGenericWrite:         NTFS_GENERIC_WRITE,
+	kinds.EdgeKindCanNTFSGenericExecute:       NTFS_GENERIC_EXECUTE,
+	kinds.EdgeKindCanNTFSGenericAll:           NTFS_GENERIC_ALL,
+	kinds.EdgeKindCanNTFSAccessSystemSecurity: NTFS_ACCESS_SYSTEM_SECURITY,
+	kinds.EdgeKindCanNTFSSynchronize:          NTFS_SYNCHRONIZE,
+	kinds.EdgeKindCanNTFSWriteOwner:           NTFS_WRITE_OWNER,
+	kinds.EdgeKindCanNTFSWriteDacl:            NTFS_WRITE_DACL,
+	kinds.EdgeKindCanNTFSReadControl:          NTFS_READ_CONTROL,
+	kinds.EdgeKindCanNTFSDelete:               NTFS_DELETE,
+	// Object-specific rights with their directory meaning
+	kinds.EdgeKindCanNTFSListDirectory:   NTFS_FILE_READ_DATA,
+	kinds.EdgeKindCanNTFSAddFile:         NTFS_FILE_WRITE_DATA,
+	kinds.EdgeKindCanNTFSAddSubdirectory: NTFS_FILE_APPEND_DATA,
+	kinds.EdgeKindCanNTFSReadEA:          NTFS_FILE_READ_EA,
+	kinds.EdgeKindCanNTFSWriteEA:         NTFS_FILE_WRITE_EA,
+	kinds.EdgeKindCanNTFSTraverse:        NTFS_FILE_EXECUTE,
+	kinds.EdgeKindCanNTFSDeleteChild:     NTFS_FILE_DELETE_CHILD,
+	kinds.EdgeKindCanNTFSReadAttributes:  NTFS_FILE_READ_ATTRIBUTES,
+	kinds.EdgeKindCanNTFSWriteAttributes: NTFS_FILE_WRITE_ATTRIBUTES,
+}
+
+// GetShareRightsForMask returns the edge kinds for a given access mask using
+// share-level mapping. The result is cached per mask and shared between
+// callers; treat it as read-only.
 func GetShareRightsForMask(mask uint32) []string {
-	var rights []string
-	for edgeKind, flag := range ShareRightsMapping {
-		if mask&flag != 0 {
-			rights = append(rights, edgeKind)
-		}
-	}
-	return rights
+	return maskRights.get(maskCacheShare, mask, ShareRightsMapping)
 }
 
-// GetNTFSRightsForMask returns the edge kinds for a given access mask using NTFS-level mapping.
+// GetNTFSRightsForMask returns the edge kinds for a given access mask using
+// the NTFS-level mapping for file objects. The result is cached per mask and
+// shared between callers; treat it as read-only.
 func GetNTFSRightsForMask(mask uint32) []string {
-	var rights []string
-	for edgeKind, flag := range NTFSRightsMapping {
-		if mask&flag != 0 {
-			rights = append(rights, edgeKind)
-		}
-	}
-	return rights
+	return maskRights.get(maskCacheFile, mask, NTFSRightsMapping)
+}
+
+// GetNTFSDirectoryRightsForMask returns the edge kinds for a given access
+// mask using the NTFS-level mapping for directory objects, where the
+// overloaded low bits carry their directory meaning (FILE_LIST_DIRECTORY,
+// FILE_ADD_FILE, FILE_ADD_SUBDIRECTORY, FILE_TRAVERSE). The result is
+// cached per mask and shared between callers; treat it as read-only.
+func GetNTFSDirectoryRightsForMask(mask uint32) []string {
+	return maskRights.get(maskCacheDirectory, mask, NTFSDirectoryRightsMapping)
 }
 
 // hasAny returns true if edgeKinds contains any of the targets.
@@ -151,6 +181,31 @@ func hasAny(edgeKinds []string, targets ...string) bool {
 	return false
 }
 
+// RollupCategory summarizes a set of NTFS edge kinds into a coarse category:
+// "FullControl" when GENERIC_ALL is granted, "Write" when any write-class
+// right is present, otherwise "Read".
+func RollupCategory(ntfsKinds []string) string {
+	if hasAny(ntfsKinds, kinds.EdgeKindCanNTFSGenericAll) {
+		return "FullControl"
+	}
+	if hasAny(ntfsKinds,
+		kinds.EdgeKindCanNTFSGenericWrite,
+		kinds.EdgeKindCanNTFSWriteData,
+		kinds.EdgeKindCanNTFSAppendData,
+		kinds.EdgeKindCanNTFSAddFile,
+		kinds.EdgeKindCanNTFSAddSubdirectory,
+		kinds.EdgeKindCanNTFSWriteEA,
+		kinds.EdgeKindCanNTFSWriteAttributes,
+		kinds.EdgeKindCanNTFSWriteDacl,
+		kinds.EdgeKindCanNTFSWriteOwner,
+		kinds.EdgeKindCanNTFSDelete,
+		kinds.EdgeKindCanNTFSDeleteChild,
+	) {
+		return "Write"
+	}
+	return "Read"
+}
+
 // ComputeEffectiveRights returns the effective access edge kinds for a single SID
 // by intersecting its share-level generic rights with its NTFS-level generic rights.
 //
@@ -171,9 +226,9 @@ func ComputeEffectiveRights(shareKinds, ntfsKinds []string) []string {
 	writeShare := hasAny(shareKinds, kinds.EdgeKindCanGenericWrite, kinds.EdgeKindCanGenericAll, kinds.EdgeKindCanShareWrite)
 	execShare  := hasAny(shareKinds, kinds.EdgeKindCanGenericExecute, kinds.EdgeKindCanGenericAll, kinds.EdgeKindCanShareExecute)
 
-	readNTFS  := hasAny(ntfsKinds, kinds.EdgeKindCanNTFSGenericRead, kinds.EdgeKindCanNTFSGenericAll, kinds.EdgeKindCanNTFSReadData)
-	writeNTFS := hasAny(ntfsKinds, kinds.EdgeKindCanNTFSGenericWrite, kinds.EdgeKindCanNTFSGenericAll, kinds.EdgeKindCanNTFSWriteData)
-	execNTFS  := hasAny(ntfsKinds, kinds.EdgeKindCanNTFSGenericExecute, kinds.EdgeKindCanNTFSGenericAll, kinds.EdgeKindCanNTFSExecute)
+	readNTFS  := hasAny(ntfsKinds, kinds.EdgeKindCanNTFSGenericRead, kinds.EdgeKindCanNTFSGenericAll, kinds.EdgeKindCanNTFSReadData, kinds.EdgeKindCanNTFSListDirectory)
+	writeNTFS := hasAny(ntfsKinds, kinds.EdgeKindCanNTFSGenericWrite, kinds.EdgeKindCanNTFSGenericAll, kinds.EdgeKindCanNTFSWriteData, kinds.EdgeKindCanNTFSAddFile)
+	execNTFS  := hasAny(ntfsKinds, kinds.EdgeKindCanNTFSGenericExecute, kinds.EdgeKindCanNTFSGenericAll, kinds.EdgeKindCanNTFSExecute, kinds.EdgeKindCanNTFSTraverse)
 
 	var effective []string
 	if readShare && readNTFS {
@@ -25,10 +25,12 @@ const (
 
 // Error categories
 const (
-	ErrorCategoryProtocol = "PROTOCOL"
-	ErrorCategoryAuth     = "AUTH"
-	ErrorCategoryNetwork  = "NETWORK"
-	ErrorCategoryUnknown  = "UNKNOWN"
+	ErrorCategoryProtocol     = "PROTOCOL"
+	ErrorCategoryAuth         = "AUTH"
+	ErrorCategoryAccessDenied = "ACCESS_DENIED"
+	ErrorCategoryNetwork      = "NETWORK"
+	ErrorCategoryTimeout      = "TIMEOUT"
+	ErrorCategoryUnknown      = "UNKNOWN"
 )
 
 // ErrorClassification contains information about a classified SMB error.
@@ -75,7 +77,7 @@ func ClassifyError(err error) ErrorClassification {
 
 	if strings.Contains(errStr, "access denied") {
 		return ErrorClassification{
-			Category:    ErrorCategoryAuth,
+			Category:    ErrorCategoryAccessDenied,
 			Message:     "Access denied - insufficient privileges",
 			ShouldRetry: false,
 		}
@@ -105,12 +107,24 @@ func ClassifyError(err error) ErrorClassification {
 		}
 	}
 
+	// Check for timeouts before generic network issues so slow-but-reachable
+	// hosts are distinguishable from unreachable ones
+	if strings.Contains(errStr, "timeout") ||
+		strings.Contains(errStr, "timed out") ||
+		strings.Contains(errStr, "deadline exceeded") {
+		return ErrorClassification{
+			Category:    ErrorCategoryTimeout,
+			Message:     "Operation timed out",
+			ShouldRetry: false,
+		}
+	}
+
 	// Check for network issues
 	if strings.Contains(errStr, "network") ||
 		strings.Contains(errStr, "connection refused") ||
 		strings.Contains(errStr, "unreachable") ||
-		strings.Contains(errStr, "timeout") ||
-		strings.Contains(errStr, "timed out") {
+		strings.Contains(errStr, "no such host") ||
+		strings.Contains(errStr, "dns") {
 		return ErrorClassification{
 			Category:    ErrorCategoryNetwork,
 			Message:     "Network connectivity issue",
@@ -146,3 +160,13 @@ var (
 	ErrPathNotFound                   = errors.New("path not found")
 	ErrSecurityDescriptorNotSupported = errors.New("security descriptor query not supported")
 )
+
+// Malformed-input errors returned by the binary parsers. These parse data
+// received from remote servers, so callers can use errors.Is to distinguish
+// a hostile or corrupt blob from transport failures.
+var (
+	ErrMalformedSecurityDescriptor = errors.New("malformed security descriptor")
+	ErrMalformedACL                = errors.New("malformed ACL")
+	ErrMalformedSID                = errors.New("malformed SID")
+	ErrMalformedSDDL               = errors.New("malformed SDDL string")
+)
@@ -0,0 +1,28 @@
+package smb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyErrorCategories(t *testing.T) {
+	cases := []struct {
+		err      error
+		category string
+	}{
+		{errors.New("logon failure: unknown user name or bad password"), ErrorCategoryAuth},
+		{errors.New("response error: access denied"), ErrorCategoryAccessDenied},
+		{errors.New("dial tcp 10.0.0.1:445: i/o timeout"), ErrorCategoryTimeout},
+		{errors.New("context deadline exceeded"), ErrorCategoryTimeout},
+		{errors.New("dial tcp 10.0.0.1:445: connection refused"), ErrorCategoryNetwork},
+		{errors.New("lookup fileserver01: no such host"), ErrorCategoryNetwork},
+		{errors.New("smb dialect negotiation failed"), ErrorCategoryProtocol},
+		{errors.New("something unexpected"), ErrorCategoryUnknown},
+	}
+
+	for _, tc := range cases {
+		if got := ClassifyError(tc.err).Category; got != tc.category {
+			t.Errorf("ClassifyError(%q) = %s, want %s", tc.err, got, tc.category)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+//go:build stress
+
+// Stress harness for the lock-free ForceClose semantics documented on
+// SMBSession.ForceClose. These tests race ForceClose against a Connect that
+// is blocked on a mock net.Conn transport, and are meant to run under the
+// race detector:
+//
+//	go test -race -tags=stress ./internal/smb -run TestStress
+//
+// Coverage against a live server (Mount/ReadDir/security descriptor queries
+// under ForceClose) lives in the integration tests.
+package smb
+
+import (
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/credentials"
+)
+
+// blockingConn is a mock net.Conn whose reads block until the conn is
+// closed, emulating a peer that never answers the SMB negotiation.
+type blockingConn struct {
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newBlockingConn() *blockingConn {
+	return &blockingConn{closed: make(chan struct{})}
+}
+
+func (c *blockingConn) Read(b []byte) (int, error) {
+	<-c.closed
+	return 0, io.EOF
+}
+
+func (c *blockingConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, io.ErrClosedPipe
+	default:
+		return len(b), nil
+	}
+}
+
+func (c *blockingConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *blockingConn) LocalAddr() net.Addr                { return &net.TCPAddr{} }
+func (c *blockingConn) RemoteAddr() net.Addr               { return &net.TCPAddr{} }
+func (c *blockingConn) SetDeadline(t time.Time) error      { return nil }
+func (c *blockingConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *blockingConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// listenSink opens a local TCP listener so Connect's port probe succeeds.
+// Accepted connections are held open and discarded.
+func listenSink(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open listener: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			if _, err := ln.Accept(); err != nil {
+				return
+			}
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func newStressSession(port int) *SMBSession {
+	creds := &credentials.Credentials{
+		Username: "stress",
+		Password: "stress",
+	}
+	return NewSMBSession("127.0.0.1", port, 10*time.Second, creds, "localhost", "", &config.Config{}, newTestLogger(nil))
+}
+
+// TestStressForceCloseInterruptsConnect verifies that ForceClose reliably
+// unblocks a Connect stuck in SMB negotiation against a transport that
+// never responds.
+func TestStressForceCloseInterruptsConnect(t *testing.T) {
+	port := listenSink(t)
+	origDial := dialTCP
+	defer func() { dialTCP = origDial }()
+
+	for i := 0; i < 50; i++ {
+		conn := newBlockingConn()
+		dialTCP = func(string, time.Duration) (net.Conn, error) { return conn, nil }
+
+		sess := newStressSession(port)
+		done := make(chan error, 1)
+		go func() { done <- sess.Connect() }()
+
+		// Give Connect a moment to reach the blocking negotiation read
+		time.Sleep(2 * time.Millisecond)
+		sess.ForceClose()
+
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Fatal("Connect succeeded against a transport that never responds")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("ForceClose did not interrupt the blocked Connect")
+		}
+		sess.Close()
+	}
+}
+
+// TestStressForceCloseStorm hammers one session from several goroutines the
+// way the watchdog ticker, pool shutdown, and worker teardown can overlap in
+// production, locking in that concurrent ForceClose/Close/status calls never
+// deadlock and never resurrect a force-closed session.
+func TestStressForceCloseStorm(t *testing.T) {
+	port := listenSink(t)
+	origDial := dialTCP
+	defer func() { dialTCP = origDial }()
+
+	conn := newBlockingConn()
+	dialTCP = func(string, time.Duration) (net.Conn, error) { return conn, nil }
+
+	sess := newStressSession(port)
+	done := make(chan error, 1)
+	go func() { done <- sess.Connect() }()
+	time.Sleep(2 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deadline := time.Now().Add(200 * time.Millisecond)
+			for time.Now().Before(deadline) {
+				sess.ForceClose()
+				sess.IsConnected()
+				sess.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Connect still blocked after the ForceClose storm")
+	}
+	if sess.IsConnected() {
+		t.Fatal("session reports connected after ForceClose")
+	}
+}
@@ -0,0 +1,152 @@
+package smb
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Fuzz targets for the binary parsers that consume attacker-adjacent data:
+// security descriptors, SIDs, and ACLs arrive from remote file servers, and
+// SRVSVC stubs from remote RPC endpoints. None of them may panic or
+// over-read, whatever the input. Run at length with, e.g.:
+//
+//	go test -fuzz=FuzzParseSecurityDescriptor -fuzztime=60s ./internal/smb
+
+// seedSDCorpus adds every blob from testdata/sd to the fuzz seed corpus.
+func seedSDCorpus(f *testing.F) {
+	f.Helper()
+	entries, err := os.ReadDir(filepath.Join("testdata", "sd"))
+	if err != nil {
+		f.Fatalf("failed to read SD corpus: %v", err)
+	}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".sd.hex") {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join("testdata", "sd", entry.Name()))
+		if err != nil {
+			f.Fatalf("failed to read %s: %v", entry.Name(), err)
+		}
+		blob, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+		if err != nil {
+			f.Fatalf("%s is not valid hex: %v", entry.Name(), err)
+		}
+		f.Add(blob)
+	}
+}
+
+func FuzzParseSecurityDescriptor(f *testing.F) {
+	seedSDCorpus(f)
+	f.Add([]byte{})
+	f.Add([]byte{0x01, 0x00, 0x04, 0x80})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sd, err := ParseSecurityDescriptor(data)
+		if err != nil {
+			return
+		}
+		// Exercise the accessors that walk the parsed structures
+		sd.MandatoryLabel()
+		if sd.OwnerSID != nil {
+			_ = sd.OwnerSID.String()
+		}
+		if sd.Dacl != nil {
+			for _, ace := range sd.Dacl.Aces {
+				_ = ace.AceTypeName()
+			}
+		}
+	})
+}
+
+func FuzzParseSID(f *testing.F) {
+	// S-1-5-32-544 and a max-length SID with 15 sub-authorities
+	f.Add([]byte{0x01, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05, 0x20, 0x00, 0x00, 0x00, 0x20, 0x02, 0x00, 0x00})
+	long := []byte{0x01, 0x0F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05}
+	for i := 0; i < 15; i++ {
+		long = append(long, byte(i), 0x00, 0x00, 0x00)
+	}
+	f.Add(long)
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sid, err := ParseSID(data)
+		if err != nil {
+			return
+		}
+		_ = sid.String()
+	})
+}
+
+func FuzzParseACL(f *testing.F) {
+	// Empty ACL and a one-ACE ACL for Everyone
+	f.Add([]byte{0x02, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00})
+	f.Add([]byte{
+		0x02, 0x00, 0x1c, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x14, 0x00, 0xff, 0x01, 0x1f, 0x00,
+		0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+	})
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		acl, err := ParseACL(data)
+		if err != nil {
+			return
+		}
+		if len(acl.Aces) > int(acl.AceCount) {
+			t.Errorf("parsed %d ACEs from an ACL declaring %d", len(acl.Aces), acl.AceCount)
+		}
+	})
+}
+
+func FuzzParseShareInfoResponse(f *testing.F) {
+	// A plausible stub: pointers, fixed part, then a self-relative SD and
+	// the trailing return code
+	stub := []byte{0x01, 0x00, 0x00, 0x00, 0xf6, 0x01, 0x00, 0x00}
+	stub = append(stub, loadFuzzSeedSD(f)...)
+	stub = append(stub, 0x00, 0x00, 0x00, 0x00)
+	f.Add(stub)
+	f.Add([]byte{})
+	f.Add([]byte{0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}) // error return code
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		client := &SRVSVCClient{}
+		sd, err := client.parseShareInfoResponse(data)
+		if err != nil {
+			return
+		}
+		if len(sd) > len(data) {
+			t.Errorf("extracted %d bytes of SD from a %d-byte stub", len(sd), len(data))
+		}
+	})
+}
+
+func loadFuzzSeedSD(f *testing.F) []byte {
+	f.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "sd", "share_server2019_default.sd.hex"))
+	if err != nil {
+		f.Fatalf("failed to read seed SD: %v", err)
+	}
+	blob, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		f.Fatalf("seed SD is not valid hex: %v", err)
+	}
+	return blob
+}
+
+func FuzzParseShareEnumResponse(f *testing.F) {
+	f.Add([]byte{})
+	// Zero entries, success return code
+	f.Add([]byte{
+		0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parseShareEnumResponse(data) //nolint:errcheck
+	})
+}
@@ -0,0 +1,59 @@
+package smb
+
+import "sync"
+
+// Object type tags distinguishing cache keys for the three rights mappings.
+const (
+	maskCacheShare = iota
+	maskCacheFile
+	maskCacheDirectory
+)
+
+// maskRightsCacheLimit bounds cache growth: real environments reuse a few
+// dozen distinct masks, so anything near the limit means a hostile or broken
+// server is handing out unique masks and further entries are not kept.
+const maskRightsCacheLimit = 4096
+
+// maskRightsCache memoizes access-mask-to-edge-kind derivation, keyed by
+// (object type, mask). Rights derivation runs for every ACE of every file,
+// but the same masks recur constantly, so after warm-up nearly every lookup
+// is a read-locked map hit instead of a walk over the full mapping.
+type maskRightsCache struct {
+	mu      sync.RWMutex
+	entries map[uint64][]string
+}
+
+var maskRights = &maskRightsCache{entries: make(map[uint64][]string)}
+
+// get returns the edge kinds for mask under the given mapping. The returned
+// slice is shared between callers and must be treated as read-only.
+func (c *maskRightsCache) get(objectType int, mask uint32, mapping map[string]uint32) []string {
+	key := uint64(objectType)<<32 | uint64(mask)
+
+	c.mu.RLock()
+	rights, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok {
+		return rights
+	}
+
+	rights = rightsForMask(mask, mapping)
+
+	c.mu.Lock()
+	if len(c.entries) < maskRightsCacheLimit {
+		c.entries[key] = rights
+	}
+	c.mu.Unlock()
+	return rights
+}
+
+// rightsForMask collects the edge kinds whose mapping bit is set in mask.
+func rightsForMask(mask uint32, mapping map[string]uint32) []string {
+	var rights []string
+	for edgeKind, flag := range mapping {
+		if mask&flag != 0 {
+			rights = append(rights, edgeKind)
+		}
+	}
+	return rights
+}
@@ -0,0 +1,52 @@
+package smb
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestMaskRightsCacheMatchesComputation(t *testing.T) {
+	// Cached lookups must return exactly what a direct mapping walk produces
+	masks := []uint32{0x001200a9, 0x001301bf, 0x001f01ff, 0x10000000, 0x00000040, 0}
+	for _, mask := range masks {
+		got := append([]string(nil), GetNTFSRightsForMask(mask)...)
+		want := rightsForMask(mask, NTFSRightsMapping)
+		sort.Strings(got)
+		sort.Strings(want)
+		if len(got) != len(want) {
+			t.Fatalf("mask 0x%08x: got %v, want %v", mask, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("mask 0x%08x: got %v, want %v", mask, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestMaskRightsCacheSharesResult(t *testing.T) {
+	// Repeated lookups of the same (object type, mask) pair must hit the
+	// cache and hand back the same backing slice.
+	first := GetNTFSDirectoryRightsForMask(0x001200a9)
+	second := GetNTFSDirectoryRightsForMask(0x001200a9)
+	if len(first) == 0 {
+		t.Fatal("expected rights for the standard read mask")
+	}
+	if &first[0] != &second[0] {
+		t.Error("repeated lookup did not return the cached slice")
+	}
+}
+
+func TestMaskRightsCacheKeysByObjectType(t *testing.T) {
+	// The same mask must not leak a file interpretation into a directory
+	// lookup or vice versa.
+	fileRights := GetNTFSRightsForMask(0x00000001)
+	dirRights := GetNTFSDirectoryRightsForMask(0x00000001)
+	if len(fileRights) != 1 || fileRights[0] != "CanNTFSReadData" {
+		t.Errorf("file rights for 0x1 = %v, want [CanNTFSReadData]", fileRights)
+	}
+	if len(dirRights) != 1 || dirRights[0] != "CanNTFSListDirectory" {
+		t.Errorf("directory rights for 0x1 = %v, want [CanNTFSListDirectory]", dirRights)
+	}
+}
@@ -0,0 +1,95 @@
+package smb
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// NetBIOS session service packet types (RFC 1002 §4.3).
+const (
+	nbSessionRequest   = 0x81
+	nbPositiveResponse = 0x82
+	nbNegativeResponse = 0x83
+	nbRetargetResponse = 0x84
+)
+
+// netbiosSessionRequest performs the NetBIOS session service handshake that
+// port 139 requires before SMB negotiation. Direct-TCP SMB on 445 uses the
+// same 4-byte framing for the messages that follow, so once the server
+// answers with a positive session response the connection can be handed to
+// the regular SMB dialer unchanged.
+func netbiosSessionRequest(conn net.Conn, calledName, callingName string, timeout time.Duration) error {
+	packet := make([]byte, 0, 72)
+	packet = append(packet, nbSessionRequest, 0, 0, 68)
+	packet = append(packet, encodeNetBIOSName(calledName, 0x20)...)  // server service
+	packet = append(packet, encodeNetBIOSName(callingName, 0x00)...) // workstation service
+
+	deadline := time.Now().Add(timeout)
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	defer conn.SetDeadline(time.Time{}) //nolint:errcheck
+
+	if _, err := conn.Write(packet); err != nil {
+		return fmt.Errorf("failed to send session request: %w", err)
+	}
+
+	var hdr [4]byte
+	if _, err := io.ReadFull(conn, hdr[:]); err != nil {
+		return fmt.Errorf("failed to read session response: %w", err)
+	}
+	length := int(hdr[1]&0x01)<<16 | int(hdr[2])<<8 | int(hdr[3])
+	body := make([]byte, length)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return fmt.Errorf("failed to read session response body: %w", err)
+	}
+
+	switch hdr[0] {
+	case nbPositiveResponse:
+		return nil
+	case nbNegativeResponse:
+		code := byte(0)
+		if len(body) > 0 {
+			code = body[0]
+		}
+		return fmt.Errorf("session request refused for called name %q (error 0x%02x)", calledName, code)
+	case nbRetargetResponse:
+		return fmt.Errorf("server asked to retarget the session, which is not supported")
+	default:
+		return fmt.Errorf("unexpected session response type 0x%02x", hdr[0])
+	}
+}
+
+// netbiosCalledName derives the called name for the session request. Short
+// dot-free host names are NetBIOS names already; for IPs and FQDNs the
+// generic "*SMBSERVER" name is used, which servers accept regardless of
+// their configured name.
+func netbiosCalledName(remoteName string) string {
+	name := strings.ToUpper(remoteName)
+	if name != "" && len(name) <= 15 && !strings.Contains(name, ".") && net.ParseIP(name) == nil {
+		return name
+	}
+	return "*SMBSERVER"
+}
+
+// encodeNetBIOSName applies RFC 1001 first-level encoding: the name is
+// space-padded to 15 characters, the service suffix appended, and each byte
+// split into two nibbles offset from 'A', wrapped as a single DNS-style
+// label.
+func encodeNetBIOSName(name string, suffix byte) []byte {
+	if len(name) > 15 {
+		name = name[:15]
+	}
+	padded := []byte(fmt.Sprintf("%-15s", strings.ToUpper(name)))
+	padded = append(padded, suffix)
+
+	encoded := make([]byte, 0, 34)
+	encoded = append(encoded, 32) // label length
+	for _, b := range padded {
+		encoded = append(encoded, 'A'+(b>>4), 'A'+(b&0x0f))
+	}
+	return append(encoded, 0) // terminating empty label
+}
@@ -0,0 +1,73 @@
+package smb
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/specterops/sharehound/internal/smb/smbtest"
+)
+
+func TestEncodeNetBIOSName(t *testing.T) {
+	// "FRED" with the server service suffix, matching the space-padded
+	// RFC 1001 §14.1 reference example
+	encoded := encodeNetBIOSName("FRED", 0x20)
+	want := []byte("\x20EGFCEFEECACACACACACACACACACACACA\x00")
+	if !bytes.Equal(encoded, want) {
+		t.Errorf("encodeNetBIOSName(FRED) = %q, want %q", encoded, want)
+	}
+	if len(encoded) != 34 {
+		t.Errorf("encoded length = %d, want 34", len(encoded))
+	}
+}
+
+func TestNetbiosCalledName(t *testing.T) {
+	tests := []struct {
+		remoteName string
+		want       string
+	}{
+		{"fileserver01", "FILESERVER01"},
+		{"fs01.corp.example.com", "*SMBSERVER"},
+		{"192.168.1.10", "*SMBSERVER"},
+		{"a-name-that-is-too-long", "*SMBSERVER"},
+		{"", "*SMBSERVER"},
+	}
+	for _, tt := range tests {
+		if got := netbiosCalledName(tt.remoteName); got != tt.want {
+			t.Errorf("netbiosCalledName(%q) = %q, want %q", tt.remoteName, got, tt.want)
+		}
+	}
+}
+
+func TestNetbiosSessionRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		wantErr  bool
+	}{
+		{"positive", []byte{nbPositiveResponse, 0, 0, 0}, false},
+		{"negative", []byte{nbNegativeResponse, 0, 0, 1, 0x82}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, err := smbtest.NewReplay([][]byte{tt.response})
+			if err != nil {
+				t.Fatalf("smbtest.NewReplay: %v", err)
+			}
+			defer srv.Close()
+
+			conn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", srv.Host(), srv.Port()))
+			if err != nil {
+				t.Fatalf("net.Dial: %v", err)
+			}
+			defer conn.Close()
+
+			err = netbiosSessionRequest(conn, "FILESERVER01", "SHAREHOUND", 2*time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("netbiosSessionRequest error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package smb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/medianexapp/go-smb2"
+)
+
+// MS-SAMR constants
+const (
+	// Named pipe for the Security Account Manager service
+	samrPipeName = "samr"
+
+	// Operation numbers
+	opSamrCloseHandle       = 1
+	opSamrOpenDomain        = 7
+	opSamrOpenAlias         = 27
+	opSamrGetMembersInAlias = 33
+	opSamrConnect2          = 57
+
+	// MAXIMUM_ALLOWED keeps the requested access mask independent of the
+	// server's exact rights model, matching what standard tooling asks for.
+	samrMaximumAllowed = 0x02000000
+
+	// SAMR handles are 20-byte opaque context handles
+	samrHandleSize = 20
+)
+
+// Well-known alias RIDs in the BUILTIN domain (S-1-5-32).
+const (
+	AliasAdministrators     uint32 = 544
+	AliasRemoteDesktopUsers uint32 = 555
+)
+
+// SAMRClient provides access to the Security Account Manager (MS-SAMR) for
+// sampling local group membership, e.g. who is in BUILTIN\Administrators.
+type SAMRClient struct {
+	share     *smb2.Share
+	pipe      *smb2.File
+	callID    uint32
+	contextID uint16
+
+	serverHandle []byte
+	domainHandle []byte
+}
+
+// NewSAMRClient creates a new SAMR client over the session's IPC$ share.
+func NewSAMRClient(session *smb2.Session) (*SAMRClient, error) {
+	// Connect to IPC$ share
+	share, err := session.Mount("IPC$")
+	if err != nil {
+		return nil, fmt.Errorf("failed to mount IPC$: %w", err)
+	}
+
+	// Open the samr named pipe
+	pipe, err := share.OpenFile(samrPipeName, 0x12019f, 0)
+	if err != nil {
+		share.Umount()
+		return nil, fmt.Errorf("failed to open samr pipe: %w", err)
+	}
+
+	client := &SAMRClient{
+		share:  share,
+		pipe:   pipe,
+		callID: 1,
+	}
+
+	// Bind to SAMR interface
+	if err := client.bind(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to bind to SAMR: %w", err)
+	}
+
+	return client, nil
+}
+
+// Close releases the open handles and closes the SAMR client.
+func (c *SAMRClient) Close() {
+	if c.domainHandle != nil {
+		c.closeHandle(c.domainHandle)
+		c.domainHandle = nil
+	}
+	if c.serverHandle != nil {
+		c.closeHandle(c.serverHandle)
+		c.serverHandle = nil
+	}
+	if c.pipe != nil {
+		c.pipe.Close()
+	}
+	if c.share != nil {
+		c.share.Umount()
+	}
+}
+
+// bind performs RPC bind to the SAMR interface.
+func (c *SAMRClient) bind() error {
+	// SAMR UUID: 12345778-1234-abcd-ef00-0123456789ac
+	uuid := []byte{
+		0x78, 0x57, 0x34, 0x12, 0x34, 0x12, 0xcd, 0xab,
+		0xef, 0x00, 0x01, 0x23, 0x45, 0x67, 0x89, 0xac,
+	}
+
+	if err := bindPipe(c.pipe, c.callID, uuid, 1, 0); err != nil {
+		return err
+	}
+
+	c.callID++
+	return nil
+}
+
+// call sends one request PDU and returns the reassembled response stub.
+func (c *SAMRClient) call(opNum uint16, stub []byte) ([]byte, error) {
+	request := buildRequestPDU(c.callID, c.contextID, opNum, stub)
+	c.callID++
+
+	if _, err := c.pipe.Write(request); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return readResponseStub(c.pipe)
+}
+
+// GetAliasMembers returns the member SIDs of a BUILTIN alias, identified by
+// its well-known RID (e.g. AliasAdministrators). The server and BUILTIN
+// domain handles are opened on first use and reused for further lookups.
+func (c *SAMRClient) GetAliasMembers(serverName string, aliasRID uint32) ([]string, error) {
+	if err := c.openBuiltinDomain(serverName); err != nil {
+		return nil, err
+	}
+
+	aliasHandle, err := c.openAlias(aliasRID)
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeHandle(aliasHandle)
+
+	stub, err := c.call(opSamrGetMembersInAlias, aliasHandle)
+	if err != nil {
+		return nil, fmt.Errorf("SamrGetMembersInAlias failed: %w", err)
+	}
+	return parseAliasMembersResponse(stub)
+}
+
+// openBuiltinDomain connects to the SAM server and opens the BUILTIN domain
+// (S-1-5-32), which holds the local group aliases.
+func (c *SAMRClient) openBuiltinDomain(serverName string) error {
+	if c.domainHandle != nil {
+		return nil
+	}
+
+	// SamrConnect2: unique pointer to the server name, desired access
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020000)) // Referent ID
+	writeNDRWString(&buf, "\\\\"+serverName)
+	binary.Write(&buf, binary.LittleEndian, uint32(samrMaximumAllowed))
+
+	stub, err := c.call(opSamrConnect2, buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("SamrConnect2 failed: %w", err)
+	}
+	handle, status, err := samrHandleAndStatus(stub)
+	if err != nil {
+		return fmt.Errorf("SamrConnect2: %w", err)
+	}
+	if status != 0 {
+		return fmt.Errorf("SamrConnect2 returned status 0x%08x", status)
+	}
+	c.serverHandle = handle
+
+	// SamrOpenDomain: server handle, desired access, BUILTIN domain SID as
+	// a conformant RPC_SID (subauthority count, then the binary SID)
+	buf.Reset()
+	buf.Write(c.serverHandle)
+	binary.Write(&buf, binary.LittleEndian, uint32(samrMaximumAllowed))
+	binary.Write(&buf, binary.LittleEndian, uint32(1))  // SubAuthorityCount (conformance)
+	buf.Write([]byte{0x01, 0x01, 0, 0, 0, 0, 0, 0x05})  // Revision, count, NT authority
+	binary.Write(&buf, binary.LittleEndian, uint32(32)) // SECURITY_BUILTIN_DOMAIN_RID
+
+	stub, err = c.call(opSamrOpenDomain, buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("SamrOpenDomain failed: %w", err)
+	}
+	handle, status, err = samrHandleAndStatus(stub)
+	if err != nil {
+		return fmt.Errorf("SamrOpenDomain: %w", err)
+	}
+	if status != 0 {
+		return fmt.Errorf("SamrOpenDomain returned status 0x%08x", status)
+	}
+	c.domainHandle = handle
+
+	return nil
+}
+
+// openAlias opens a local group alias in the BUILTIN domain by RID.
+func (c *SAMRClient) openAlias(aliasRID uint32) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(c.domainHandle)
+	binary.Write(&buf, binary.LittleEndian, uint32(samrMaximumAllowed))
+	binary.Write(&buf, binary.LittleEndian, aliasRID)
+
+	stub, err := c.call(opSamrOpenAlias, buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("SamrOpenAlias failed: %w", err)
+	}
+	handle, status, err := samrHandleAndStatus(stub)
+	if err != nil {
+		return nil, fmt.Errorf("SamrOpenAlias: %w", err)
+	}
+	if status != 0 {
+		return nil, fmt.Errorf("SamrOpenAlias(%d) returned status 0x%08x", aliasRID, status)
+	}
+	return handle, nil
+}
+
+// closeHandle releases a SAMR context handle, best effort.
+func (c *SAMRClient) closeHandle(handle []byte) {
+	c.call(opSamrCloseHandle, handle) //nolint:errcheck
+}
+
+// samrHandleAndStatus splits a response stub consisting of a context handle
+// followed by the operation's return code.
+func samrHandleAndStatus(stub []byte) ([]byte, uint32, error) {
+	if len(stub) < samrHandleSize+4 {
+		return nil, 0, fmt.Errorf("response stub too short")
+	}
+	return stub[:samrHandleSize], binary.LittleEndian.Uint32(stub[samrHandleSize : samrHandleSize+4]), nil
+}
+
+// parseAliasMembersResponse parses a SamrGetMembersInAlias response stub: a
+// SAMPR_PSID_ARRAY (count, pointer array, then one conformant RPC_SID per
+// member) followed by the return status.
+func parseAliasMembersResponse(stub []byte) ([]string, error) {
+	if len(stub) < 12 {
+		return nil, fmt.Errorf("response stub too short")
+	}
+	status := binary.LittleEndian.Uint32(stub[len(stub)-4:])
+	if status != 0 {
+		return nil, fmt.Errorf("SamrGetMembersInAlias returned status 0x%08x", status)
+	}
+
+	count := int(binary.LittleEndian.Uint32(stub[0:4]))
+	arrayPtr := binary.LittleEndian.Uint32(stub[4:8])
+	if count == 0 || arrayPtr == 0 {
+		return nil, nil
+	}
+
+	// MaxCount of the pointer array, then one referent ID per member
+	offset := 12 + 4*count
+	if offset > len(stub) {
+		return nil, fmt.Errorf("truncated SID pointer array")
+	}
+
+	sids := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if offset+4 > len(stub) {
+			return nil, fmt.Errorf("truncated SID array")
+		}
+		subAuthCount := int(binary.LittleEndian.Uint32(stub[offset : offset+4]))
+		offset += 4
+
+		sidLen := 8 + 4*subAuthCount
+		if offset+sidLen > len(stub) {
+			return nil, fmt.Errorf("truncated SID entry")
+		}
+		sid, err := ParseSID(stub[offset : offset+sidLen])
+		if err != nil {
+			return nil, fmt.Errorf("invalid member SID: %w", err)
+		}
+		sids = append(sids, sid.String())
+		offset += sidLen
+	}
+
+	return sids, nil
+}
@@ -0,0 +1,93 @@
+package smb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// writeRPCSID appends a conformant RPC_SID (subauthority count, then the
+// binary SID) for an NT-authority SID with the given subauthorities.
+func writeRPCSID(buf *bytes.Buffer, subAuths ...uint32) {
+	binary.Write(buf, binary.LittleEndian, uint32(len(subAuths))) // conformance
+	buf.Write([]byte{0x01, byte(len(subAuths)), 0, 0, 0, 0, 0, 0x05})
+	for _, sa := range subAuths {
+		binary.Write(buf, binary.LittleEndian, sa)
+	}
+}
+
+func TestParseAliasMembersResponse(t *testing.T) {
+	// SamrGetMembersInAlias response stub: SAMPR_PSID_ARRAY (count, pointer
+	// array, one conformant RPC_SID per member), then the return status.
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(2))          // Count
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020000)) // Sids referent ID
+	binary.Write(&stub, binary.LittleEndian, uint32(2))          // MaxCount
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020004)) // SID referent IDs
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020008))
+	writeRPCSID(&stub, 21, 111, 222, 333, 500)          // domain Administrator
+	writeRPCSID(&stub, 32, 544)                         // BUILTIN\Administrators
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // Return status
+
+	sids, err := parseAliasMembersResponse(stub.Bytes())
+	if err != nil {
+		t.Fatalf("parseAliasMembersResponse: %v", err)
+	}
+	want := []string{"S-1-5-21-111-222-333-500", "S-1-5-32-544"}
+	if len(sids) != len(want) {
+		t.Fatalf("got %d SIDs, want %d: %v", len(sids), len(want), sids)
+	}
+	for i := range want {
+		if sids[i] != want[i] {
+			t.Errorf("sids[%d] = %q, want %q", i, sids[i], want[i])
+		}
+	}
+}
+
+func TestParseAliasMembersResponseEmpty(t *testing.T) {
+	// An empty alias returns Count 0 with a null Sids pointer.
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // Count
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // Sids: null
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // Return status
+
+	sids, err := parseAliasMembersResponse(stub.Bytes())
+	if err != nil {
+		t.Fatalf("parseAliasMembersResponse: %v", err)
+	}
+	if len(sids) != 0 {
+		t.Errorf("got %d SIDs from an empty alias, want 0", len(sids))
+	}
+}
+
+func TestParseAliasMembersResponseError(t *testing.T) {
+	// STATUS_ACCESS_DENIED with null output pointers
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // Count
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // Sids: null
+	binary.Write(&stub, binary.LittleEndian, uint32(0xc0000022)) // Return status
+
+	_, err := parseAliasMembersResponse(stub.Bytes())
+	if err == nil {
+		t.Fatal("parseAliasMembersResponse accepted an error response")
+	}
+	if !strings.Contains(err.Error(), "0xc0000022") {
+		t.Errorf("error = %q, want it to carry the return status", err)
+	}
+}
+
+func TestParseAliasMembersResponseTruncated(t *testing.T) {
+	// Count claims two members but only the pointer array is present.
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(2))          // Count
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020000)) // Sids referent ID
+	binary.Write(&stub, binary.LittleEndian, uint32(2))          // MaxCount
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020004))
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020008))
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // Return status
+
+	if _, err := parseAliasMembersResponse(stub.Bytes()); err == nil {
+		t.Fatal("parseAliasMembersResponse accepted a truncated stub")
+	}
+}
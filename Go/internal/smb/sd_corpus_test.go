@@ -0,0 +1,169 @@
+package smb
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadSDBlob reads a hex-encoded security descriptor from the corpus in
+// testdata/sd (see the README there for provenance and anonymization).
+func loadSDBlob(t *testing.T, name string) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "sd", name+".sd.hex"))
+	if err != nil {
+		t.Fatalf("failed to read corpus blob %s: %v", name, err)
+	}
+	blob, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		t.Fatalf("corpus blob %s is not valid hex: %v", name, err)
+	}
+	return blob
+}
+
+const corpusDomain = "S-1-5-21-1004336348-1177238915-682003330"
+
+func TestParseSecurityDescriptorCorpus(t *testing.T) {
+	tests := []struct {
+		name     string
+		owner    string
+		group    string
+		daclAces int
+		saclAces int
+		check    func(t *testing.T, sd *SecurityDescriptor)
+	}{
+		{
+			name:     "share_server2019_default",
+			owner:    "S-1-5-32-544",
+			group:    "S-1-5-18",
+			daclAces: 3,
+			check: func(t *testing.T, sd *SecurityDescriptor) {
+				first := sd.Dacl.Aces[0]
+				if !first.IsAccessAllowed() || first.SID.String() != "S-1-1-0" {
+					t.Errorf("first ACE = %s for %v, want allow for S-1-1-0", first.AceTypeName(), first.SID)
+				}
+				if first.Mask != 0x001200A9 {
+					t.Errorf("Everyone mask = 0x%08x, want 0x001200A9 (read+execute)", first.Mask)
+				}
+			},
+		},
+		{
+			name:     "dfs_root",
+			owner:    corpusDomain + "-512",
+			group:    corpusDomain + "-513",
+			daclAces: 4,
+			check: func(t *testing.T, sd *SecurityDescriptor) {
+				if sd.Control&SE_DACL_AUTO_INHERITED == 0 {
+					t.Errorf("control = 0x%04x, want SE_DACL_AUTO_INHERITED set", sd.Control)
+				}
+				deny := sd.Dacl.Aces[0]
+				if !deny.IsAccessDenied() || deny.SID.String() != corpusDomain+"-1342" {
+					t.Errorf("first ACE = %s for %v, want deny for %s-1342", deny.AceTypeName(), deny.SID, corpusDomain)
+				}
+				if sd.Dacl.Aces[2].AceFlags&INHERITED_ACE == 0 {
+					t.Error("Authenticated Users ACE lost its INHERITED_ACE flag")
+				}
+			},
+		},
+		{
+			name:     "huge_dacl",
+			owner:    "S-1-5-32-544",
+			group:    corpusDomain + "-513",
+			daclAces: 200,
+			check: func(t *testing.T, sd *SecurityDescriptor) {
+				last := sd.Dacl.Aces[199]
+				if got, want := last.SID.String(), corpusDomain+"-1299"; got != want {
+					t.Errorf("last ACE SID = %s, want %s", got, want)
+				}
+			},
+		},
+		{
+			name:     "resource_sids",
+			owner:    "S-1-5-32-544",
+			group:    "S-1-5-18",
+			daclAces: 2,
+			check: func(t *testing.T, sd *SecurityDescriptor) {
+				if got, want := sd.Dacl.Aces[0].SID.String(), "S-1-12-1-2129625397-1055173224-2418260334-4018354"; got != want {
+					t.Errorf("AAD principal SID = %s, want %s", got, want)
+				}
+				if got, want := sd.Dacl.Aces[1].SID.String(), "S-1-18-1"; got != want {
+					t.Errorf("authentication authority SID = %s, want %s", got, want)
+				}
+			},
+		},
+		{
+			name:     "dac_conditional_ace",
+			owner:    "S-1-5-32-544",
+			group:    "S-1-5-18",
+			daclAces: 2,
+			check: func(t *testing.T, sd *SecurityDescriptor) {
+				// The conditional callback ACE is opaque to us, but its
+				// AceSize (which includes the trailing application data)
+				// must be honored so the following ACE parses cleanly.
+				if got := sd.Dacl.Aces[0].AceType; got != ACCESS_ALLOWED_CALLBACK_ACE_TYPE {
+					t.Errorf("first ACE type = 0x%02x, want callback allow", got)
+				}
+				second := sd.Dacl.Aces[1]
+				if !second.IsAccessAllowed() || second.SID.String() != "S-1-5-32-544" {
+					t.Errorf("ACE after the conditional ACE = %s for %v, want allow for S-1-5-32-544", second.AceTypeName(), second.SID)
+				}
+			},
+		},
+		{
+			name:     "sacl_label_and_audit",
+			owner:    "S-1-5-32-544",
+			group:    "S-1-5-18",
+			daclAces: 1,
+			saclAces: 2,
+			check: func(t *testing.T, sd *SecurityDescriptor) {
+				label := sd.MandatoryLabel()
+				if label == nil {
+					t.Fatal("expected a mandatory label in the SACL")
+				}
+				if label.Level != "High" || !label.NoWriteUp {
+					t.Errorf("label = %+v, want High with NoWriteUp", label)
+				}
+				if got := sd.Sacl.Aces[0].AceType; got != SYSTEM_AUDIT_ACE_TYPE {
+					t.Errorf("first SACL ACE type = 0x%02x, want SYSTEM_AUDIT", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, err := ParseSecurityDescriptor(loadSDBlob(t, tt.name))
+			if err != nil {
+				t.Fatalf("ParseSecurityDescriptor: %v", err)
+			}
+
+			if sd.OwnerSID == nil || sd.OwnerSID.String() != tt.owner {
+				t.Errorf("owner = %v, want %s", sd.OwnerSID, tt.owner)
+			}
+			if sd.GroupSID == nil || sd.GroupSID.String() != tt.group {
+				t.Errorf("group = %v, want %s", sd.GroupSID, tt.group)
+			}
+
+			if sd.Dacl == nil {
+				t.Fatal("DACL missing")
+			}
+			if len(sd.Dacl.Aces) != tt.daclAces {
+				t.Fatalf("DACL has %d ACEs, want %d", len(sd.Dacl.Aces), tt.daclAces)
+			}
+			if tt.saclAces > 0 {
+				if sd.Sacl == nil {
+					t.Fatal("SACL missing")
+				}
+				if len(sd.Sacl.Aces) != tt.saclAces {
+					t.Fatalf("SACL has %d ACEs, want %d", len(sd.Sacl.Aces), tt.saclAces)
+				}
+			}
+
+			if tt.check != nil {
+				tt.check(t, sd)
+			}
+		})
+	}
+}
@@ -0,0 +1,472 @@
+package smb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sddlSIDAliases maps well-known SIDs to their two-letter SDDL aliases
+// (MS-DTYP section 2.4.1.1), matching what icacls and Get-Acl display so
+// exported ACL text lines up with what an auditor sees on the host itself.
+var sddlSIDAliases = map[string]string{
+	"S-1-1-0":      "WD", // Everyone
+	"S-1-3-0":      "CO", // Creator Owner
+	"S-1-3-1":      "CG", // Creator Group
+	"S-1-3-4":      "OW", // Owner Rights
+	"S-1-5-2":      "NU", // Network logon
+	"S-1-5-4":      "IU", // Interactive logon
+	"S-1-5-6":      "SU", // Service logon
+	"S-1-5-7":      "AN", // Anonymous
+	"S-1-5-9":      "ED", // Enterprise Domain Controllers
+	"S-1-5-10":     "PS", // Principal Self
+	"S-1-5-11":     "AU", // Authenticated Users
+	"S-1-5-12":     "RC", // Restricted Code
+	"S-1-5-18":     "SY", // Local System
+	"S-1-5-19":     "LS", // Local Service
+	"S-1-5-20":     "NS", // Network Service
+	"S-1-5-32-544": "BA", // BUILTIN\Administrators
+	"S-1-5-32-545": "BU", // BUILTIN\Users
+	"S-1-5-32-546": "BG", // BUILTIN\Guests
+	"S-1-5-32-547": "PU", // BUILTIN\Power Users
+	"S-1-5-32-548": "AO", // BUILTIN\Account Operators
+	"S-1-5-32-549": "SO", // BUILTIN\Server Operators
+	"S-1-5-32-550": "PO", // BUILTIN\Print Operators
+	"S-1-5-32-551": "BO", // BUILTIN\Backup Operators
+	"S-1-5-32-552": "RE", // BUILTIN\Replicator
+	"S-1-5-32-554": "RU", // BUILTIN\Pre-Windows 2000 Compatible Access
+	"S-1-5-32-555": "RD", // BUILTIN\Remote Desktop Users
+	"S-1-5-32-556": "NO", // BUILTIN\Network Configuration Operators
+	"S-1-16-4096":  "LW", // Low mandatory level
+	"S-1-16-8192":  "ME", // Medium mandatory level
+	"S-1-16-12288": "HI", // High mandatory level
+	"S-1-16-16384": "SI", // System mandatory level
+}
+
+// sddlSIDFromAlias is the reverse of sddlSIDAliases, built once at init.
+var sddlSIDFromAlias = func() map[string]string {
+	m := make(map[string]string, len(sddlSIDAliases))
+	for sid, alias := range sddlSIDAliases {
+		m[alias] = sid
+	}
+	return m
+}()
+
+// SDDL renders the security descriptor in Security Descriptor Definition
+// Language so the exact ACL text can be stored alongside the derived rights
+// edges and re-verified with standard Windows tooling. Access masks are
+// rendered as hex (always valid SDDL and free of rights-token ambiguity)
+// and well-known SIDs use their two-letter aliases. ACE types the parser
+// does not decode (object and callback ACEs) are omitted, since their
+// payloads are not retained.
+func (sd *SecurityDescriptor) SDDL() string {
+	if sd == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	if sd.OwnerSID != nil {
+		b.WriteString("O:")
+		b.WriteString(sddlSIDString(sd.OwnerSID))
+	}
+	if sd.GroupSID != nil {
+		b.WriteString("G:")
+		b.WriteString(sddlSIDString(sd.GroupSID))
+	}
+	if sd.Control&SE_DACL_PRESENT != 0 {
+		b.WriteString("D:")
+		b.WriteString(sddlACLString(sd.Dacl,
+			sd.Control&SE_DACL_PROTECTED != 0,
+			sd.Control&SE_DACL_AUTO_INHERIT_REQ != 0,
+			sd.Control&SE_DACL_AUTO_INHERITED != 0))
+	}
+	if sd.Control&SE_SACL_PRESENT != 0 {
+		b.WriteString("S:")
+		b.WriteString(sddlACLString(sd.Sacl,
+			sd.Control&SE_SACL_PROTECTED != 0,
+			sd.Control&SE_SACL_AUTO_INHERIT_REQ != 0,
+			sd.Control&SE_SACL_AUTO_INHERITED != 0))
+	}
+	return b.String()
+}
+
+// sddlACLString renders one ACL component: the control-derived flags, then
+// one parenthesized string per ACE. A present-but-NULL ACL grants everything
+// to everyone, which SDDL spells NO_ACCESS_CONTROL.
+func sddlACLString(acl *ACL, protected, autoInheritReq, autoInherited bool) string {
+	var b strings.Builder
+	if protected {
+		b.WriteString("P")
+	}
+	if autoInheritReq {
+		b.WriteString("AR")
+	}
+	if autoInherited {
+		b.WriteString("AI")
+	}
+	if acl == nil {
+		b.WriteString("NO_ACCESS_CONTROL")
+		return b.String()
+	}
+	for _, ace := range acl.Aces {
+		b.WriteString(sddlACEString(&ace))
+	}
+	return b.String()
+}
+
+// sddlACEString renders one ACE as "(type;flags;rights;;;sid)". The two
+// empty fields are the object GUIDs, which only object ACEs carry.
+func sddlACEString(ace *ACE) string {
+	var aceType string
+	switch ace.AceType {
+	case ACCESS_ALLOWED_ACE_TYPE:
+		aceType = "A"
+	case ACCESS_DENIED_ACE_TYPE:
+		aceType = "D"
+	case SYSTEM_AUDIT_ACE_TYPE:
+		aceType = "AU"
+	case SYSTEM_ALARM_ACE_TYPE:
+		aceType = "AL"
+	case SYSTEM_MANDATORY_LABEL_ACE_TYPE:
+		aceType = "ML"
+	default:
+		// Mask and SID were not decoded for this type; rendering a stub
+		// would misstate the ACL, so leave it out.
+		return ""
+	}
+
+	var flags strings.Builder
+	if ace.AceFlags&OBJECT_INHERIT_ACE != 0 {
+		flags.WriteString("OI")
+	}
+	if ace.AceFlags&CONTAINER_INHERIT_ACE != 0 {
+		flags.WriteString("CI")
+	}
+	if ace.AceFlags&NO_PROPAGATE_INHERIT_ACE != 0 {
+		flags.WriteString("NP")
+	}
+	if ace.AceFlags&INHERIT_ONLY_ACE != 0 {
+		flags.WriteString("IO")
+	}
+	if ace.AceFlags&INHERITED_ACE != 0 {
+		flags.WriteString("ID")
+	}
+	if ace.AceFlags&SUCCESSFUL_ACCESS_ACE_FLAG != 0 {
+		flags.WriteString("SA")
+	}
+	if ace.AceFlags&FAILED_ACCESS_ACE_FLAG != 0 {
+		flags.WriteString("FA")
+	}
+
+	sid := ""
+	if ace.SID != nil {
+		sid = sddlSIDString(ace.SID)
+	}
+
+	return fmt.Sprintf("(%s;%s;0x%x;;;%s)", aceType, flags.String(), ace.Mask, sid)
+}
+
+// sddlSIDString returns the two-letter SDDL alias for well-known SIDs and
+// the S-1-... form for everything else.
+func sddlSIDString(sid *SID) string {
+	s := sid.String()
+	if alias, ok := sddlSIDAliases[s]; ok {
+		return alias
+	}
+	return s
+}
+
+// sddlRightsTokens maps the SDDL access rights tokens Windows emits to their
+// mask values (MS-DTYP section 2.4.4.4). The formatter always writes hex,
+// but parsed input accepts either form.
+var sddlRightsTokens = map[string]uint32{
+	"GA": 0x10000000, // GENERIC_ALL
+	"GX": 0x20000000, // GENERIC_EXECUTE
+	"GW": 0x40000000, // GENERIC_WRITE
+	"GR": 0x80000000, // GENERIC_READ
+	"SD": 0x00010000, // DELETE
+	"RC": 0x00020000, // READ_CONTROL
+	"WD": 0x00040000, // WRITE_DAC
+	"WO": 0x00080000, // WRITE_OWNER
+	"CC": 0x00000001,
+	"DC": 0x00000002,
+	"LC": 0x00000004,
+	"SW": 0x00000008,
+	"RP": 0x00000010,
+	"WP": 0x00000020,
+	"DT": 0x00000040,
+	"LO": 0x00000080,
+	"CR": 0x00000100,
+	"FA": 0x001f01ff, // FILE_ALL_ACCESS
+	"FR": 0x00120089, // FILE_GENERIC_READ
+	"FW": 0x00120116, // FILE_GENERIC_WRITE
+	"FX": 0x001200a0, // FILE_GENERIC_EXECUTE
+	"KA": 0x000f003f, // KEY_ALL_ACCESS
+	"KR": 0x00020019, // KEY_READ
+	"KW": 0x00020006, // KEY_WRITE
+	"KX": 0x00020019, // KEY_EXECUTE
+	"NW": SYSTEM_MANDATORY_LABEL_NO_WRITE_UP,
+	"NR": SYSTEM_MANDATORY_LABEL_NO_READ_UP,
+	"NX": SYSTEM_MANDATORY_LABEL_NO_EXECUTE_UP,
+}
+
+// sddlACETypes maps ACE type tokens to the parsed type constants. Object and
+// callback ACEs are deliberately absent: the binary parser does not decode
+// their payloads, so accepting them here would build descriptors the rest of
+// the package misrepresents.
+var sddlACETypes = map[string]uint8{
+	"A":  ACCESS_ALLOWED_ACE_TYPE,
+	"D":  ACCESS_DENIED_ACE_TYPE,
+	"AU": SYSTEM_AUDIT_ACE_TYPE,
+	"AL": SYSTEM_ALARM_ACE_TYPE,
+	"ML": SYSTEM_MANDATORY_LABEL_ACE_TYPE,
+}
+
+// sddlACEFlags maps ACE flag tokens to their bits.
+var sddlACEFlags = map[string]uint8{
+	"OI": OBJECT_INHERIT_ACE,
+	"CI": CONTAINER_INHERIT_ACE,
+	"NP": NO_PROPAGATE_INHERIT_ACE,
+	"IO": INHERIT_ONLY_ACE,
+	"ID": INHERITED_ACE,
+	"SA": SUCCESSFUL_ACCESS_ACE_FLAG,
+	"FA": FAILED_ACCESS_ACE_FLAG,
+}
+
+// ParseSDDL parses an SDDL string into a SecurityDescriptor, the inverse of
+// SDDL(). It accepts what Windows tooling emits for file system objects:
+// owner/group with aliases or S-1-... SIDs, DACL/SACL control flags, and
+// ACEs with token or hex access masks. Object and callback ACE types are
+// rejected because the binary parser does not carry their payloads either.
+func ParseSDDL(input string) (*SecurityDescriptor, error) {
+	s := strings.ToUpper(strings.TrimSpace(input))
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty input", ErrMalformedSDDL)
+	}
+
+	sd := &SecurityDescriptor{Revision: 1, Control: SE_SELF_RELATIVE}
+
+	for len(s) > 0 {
+		if len(s) < 2 || s[1] != ':' {
+			return nil, fmt.Errorf("%w: expected component marker at %q", ErrMalformedSDDL, s)
+		}
+		marker := s[0]
+		value := s[2:]
+
+		// The component value runs until the next top-level "X:" marker;
+		// colons inside parenthesized ACEs do not terminate it.
+		end := len(value)
+		depth := 0
+		for i := 0; i < len(value); i++ {
+			switch value[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			case ':':
+				if depth == 0 && i > 0 {
+					end = i - 1
+				}
+			}
+			if end != len(value) {
+				break
+			}
+		}
+		component := value[:end]
+		s = value[end:]
+
+		switch marker {
+		case 'O':
+			sid, err := sidFromSDDL(component)
+			if err != nil {
+				return nil, fmt.Errorf("owner: %w", err)
+			}
+			sd.OwnerSID = sid
+		case 'G':
+			sid, err := sidFromSDDL(component)
+			if err != nil {
+				return nil, fmt.Errorf("group: %w", err)
+			}
+			sd.GroupSID = sid
+		case 'D':
+			acl, control, err := parseSDDLACL(component,
+				SE_DACL_PROTECTED, SE_DACL_AUTO_INHERIT_REQ, SE_DACL_AUTO_INHERITED)
+			if err != nil {
+				return nil, fmt.Errorf("DACL: %w", err)
+			}
+			sd.Dacl = acl
+			sd.Control |= SE_DACL_PRESENT | control
+		case 'S':
+			acl, control, err := parseSDDLACL(component,
+				SE_SACL_PROTECTED, SE_SACL_AUTO_INHERIT_REQ, SE_SACL_AUTO_INHERITED)
+			if err != nil {
+				return nil, fmt.Errorf("SACL: %w", err)
+			}
+			sd.Sacl = acl
+			sd.Control |= SE_SACL_PRESENT | control
+		default:
+			return nil, fmt.Errorf("%w: unknown component %q", ErrMalformedSDDL, string(marker))
+		}
+	}
+
+	return sd, nil
+}
+
+// parseSDDLACL parses one ACL component: optional control flags followed by
+// zero or more parenthesized ACEs. A NO_ACCESS_CONTROL flag yields a nil ACL
+// with only the present bit set (a NULL ACL on the wire).
+func parseSDDLACL(s string, protectedBit, autoInheritReqBit, autoInheritedBit uint16) (*ACL, uint16, error) {
+	var control uint16
+	for len(s) > 0 && s[0] != '(' {
+		switch {
+		case strings.HasPrefix(s, "NO_ACCESS_CONTROL"):
+			s = s[len("NO_ACCESS_CONTROL"):]
+			if s != "" {
+				return nil, 0, fmt.Errorf("%w: trailing data %q after NO_ACCESS_CONTROL", ErrMalformedSDDL, s)
+			}
+			return nil, control, nil
+		case strings.HasPrefix(s, "AR"):
+			control |= autoInheritReqBit
+			s = s[2:]
+		case strings.HasPrefix(s, "AI"):
+			control |= autoInheritedBit
+			s = s[2:]
+		case s[0] == 'P':
+			control |= protectedBit
+			s = s[1:]
+		default:
+			return nil, 0, fmt.Errorf("%w: unknown ACL flags %q", ErrMalformedSDDL, s)
+		}
+	}
+
+	acl := &ACL{AclRevision: 2, AclSize: 8}
+	for len(s) > 0 {
+		if s[0] != '(' {
+			return nil, 0, fmt.Errorf("%w: expected ACE at %q", ErrMalformedSDDL, s)
+		}
+		close := strings.IndexByte(s, ')')
+		if close < 0 {
+			return nil, 0, fmt.Errorf("%w: unterminated ACE %q", ErrMalformedSDDL, s)
+		}
+		ace, err := parseSDDLACE(s[1:close])
+		if err != nil {
+			return nil, 0, err
+		}
+		acl.Aces = append(acl.Aces, *ace)
+		acl.AclSize += ace.AceSize
+		s = s[close+1:]
+	}
+	acl.AceCount = uint16(len(acl.Aces))
+	return acl, control, nil
+}
+
+// parseSDDLACE parses the body of one "(type;flags;rights;;;sid)" ACE.
+func parseSDDLACE(s string) (*ACE, error) {
+	fields := strings.Split(s, ";")
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("%w: ACE %q has %d fields, want 6", ErrMalformedSDDL, s, len(fields))
+	}
+
+	aceType, ok := sddlACETypes[fields[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported ACE type %q", ErrMalformedSDDL, fields[0])
+	}
+
+	var aceFlags uint8
+	for rest := fields[1]; rest != ""; rest = rest[2:] {
+		if len(rest) < 2 {
+			return nil, fmt.Errorf("%w: odd ACE flags %q", ErrMalformedSDDL, fields[1])
+		}
+		bit, ok := sddlACEFlags[rest[:2]]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown ACE flag %q", ErrMalformedSDDL, rest[:2])
+		}
+		aceFlags |= bit
+	}
+
+	mask, err := parseSDDLRights(fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	if fields[3] != "" || fields[4] != "" {
+		return nil, fmt.Errorf("%w: object GUIDs are not supported in ACE %q", ErrMalformedSDDL, s)
+	}
+
+	sid, err := sidFromSDDL(fields[5])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ACE{
+		AceType:  aceType,
+		AceFlags: aceFlags,
+		AceSize:  uint16(8 + sid.Size()),
+		Mask:     mask,
+		SID:      sid,
+	}, nil
+}
+
+// parseSDDLRights parses an access mask written either as hex or as a run of
+// two-letter rights tokens ("GRGW").
+func parseSDDLRights(s string) (uint32, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if strings.HasPrefix(s, "0X") {
+		mask, err := strconv.ParseUint(s[2:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("%w: bad access mask %q", ErrMalformedSDDL, s)
+		}
+		return uint32(mask), nil
+	}
+	var mask uint32
+	for ; s != ""; s = s[2:] {
+		if len(s) < 2 {
+			return 0, fmt.Errorf("%w: odd rights string", ErrMalformedSDDL)
+		}
+		bits, ok := sddlRightsTokens[s[:2]]
+		if !ok {
+			return 0, fmt.Errorf("%w: unknown rights token %q", ErrMalformedSDDL, s[:2])
+		}
+		mask |= bits
+	}
+	return mask, nil
+}
+
+// sidFromSDDL resolves a two-letter alias or parses an S-1-... SID string.
+func sidFromSDDL(s string) (*SID, error) {
+	if resolved, ok := sddlSIDFromAlias[s]; ok {
+		s = resolved
+	}
+	if !strings.HasPrefix(s, "S-") {
+		return nil, fmt.Errorf("%w: unknown SID alias %q", ErrMalformedSDDL, s)
+	}
+
+	parts := strings.Split(s, "-")
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("%w: SID %q too short", ErrMalformedSDDL, s)
+	}
+	revision, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad SID revision in %q", ErrMalformedSDDL, s)
+	}
+	authority, err := strconv.ParseUint(parts[2], 10, 48)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad identifier authority in %q", ErrMalformedSDDL, s)
+	}
+
+	sid := &SID{Revision: uint8(revision)}
+	for i := 0; i < 6; i++ {
+		sid.IdentifierAuthority[i] = byte(authority >> (40 - 8*i))
+	}
+	for _, part := range parts[3:] {
+		sub, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad subauthority %q in %q", ErrMalformedSDDL, part, s)
+		}
+		sid.SubAuthorities = append(sid.SubAuthorities, uint32(sub))
+	}
+	sid.SubAuthorityCount = uint8(len(sid.SubAuthorities))
+	return sid, nil
+}
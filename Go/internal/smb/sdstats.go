@@ -0,0 +1,45 @@
+package smb
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SecurityQueryStats accumulates the count and cumulative latency of
+// security descriptor queries across all sessions of one host, so per-host
+// averages can be stamped on the host node. All methods are safe on a nil
+// receiver, so sessions without stats attached pay no cost.
+type SecurityQueryStats struct {
+	queries atomic.Int64
+	nanos   atomic.Int64
+}
+
+// record accumulates one security descriptor query.
+func (s *SecurityQueryStats) record(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.queries.Add(1)
+	s.nanos.Add(int64(d))
+}
+
+// Queries returns how many security descriptor queries have been recorded.
+func (s *SecurityQueryStats) Queries() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.queries.Load()
+}
+
+// AverageLatency returns the mean security descriptor query latency, or
+// zero when nothing has been recorded.
+func (s *SecurityQueryStats) AverageLatency() time.Duration {
+	if s == nil {
+		return 0
+	}
+	queries := s.queries.Load()
+	if queries == 0 {
+		return 0
+	}
+	return time.Duration(s.nanos.Load() / queries)
+}
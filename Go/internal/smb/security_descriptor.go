@@ -46,6 +46,24 @@ const (
 	SYSTEM_MANDATORY_LABEL_ACE_TYPE         = 0x11
 )
 
+// Mandatory label policy flags (SYSTEM_MANDATORY_LABEL ACE mask)
+const (
+	SYSTEM_MANDATORY_LABEL_NO_WRITE_UP   = 0x01
+	SYSTEM_MANDATORY_LABEL_NO_READ_UP    = 0x02
+	SYSTEM_MANDATORY_LABEL_NO_EXECUTE_UP = 0x04
+)
+
+// Mandatory integrity level RIDs (sub-authority of S-1-16-X label SIDs)
+const (
+	SECURITY_MANDATORY_UNTRUSTED_RID         = 0x0000
+	SECURITY_MANDATORY_LOW_RID               = 0x1000
+	SECURITY_MANDATORY_MEDIUM_RID            = 0x2000
+	SECURITY_MANDATORY_MEDIUM_PLUS_RID       = 0x2100
+	SECURITY_MANDATORY_HIGH_RID              = 0x3000
+	SECURITY_MANDATORY_SYSTEM_RID            = 0x4000
+	SECURITY_MANDATORY_PROTECTED_PROCESS_RID = 0x5000
+)
+
 // ACE flag constants
 const (
 	OBJECT_INHERIT_ACE         = 0x01
@@ -87,10 +105,20 @@ type ACE struct {
 	SID      *SID
 }
 
+// MaxSecurityDescriptorSize caps accepted security descriptor blobs. Each
+// ACL is wire-limited to 64 KiB (AclSize is 16 bits), so genuine
+// self-relative descriptors stay well below this; anything larger is a
+// hostile or corrupt server response.
+const MaxSecurityDescriptorSize = 256 << 10
+
 // ParseSecurityDescriptor parses a binary security descriptor.
 func ParseSecurityDescriptor(data []byte) (*SecurityDescriptor, error) {
 	if len(data) < 20 {
-		return nil, fmt.Errorf("security descriptor too short: %d bytes", len(data))
+		return nil, fmt.Errorf("%w: too short: %d bytes", ErrMalformedSecurityDescriptor, len(data))
+	}
+	if len(data) > MaxSecurityDescriptorSize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the %d byte cap",
+			ErrMalformedSecurityDescriptor, len(data), MaxSecurityDescriptorSize)
 	}
 
 	sd := &SecurityDescriptor{
@@ -104,36 +132,66 @@ func ParseSecurityDescriptor(data []byte) (*SecurityDescriptor, error) {
 	offsetSacl := binary.LittleEndian.Uint32(data[12:16])
 	offsetDacl := binary.LittleEndian.Uint32(data[16:20])
 
+	// A nonzero offset must land inside the blob and past the fixed header;
+	// a blob that points elsewhere is malformed rather than best-effort
+	// parseable, since silently dropping components would hide ACEs.
+	checkOffset := func(offset uint32, what string) error {
+		if offset == 0 {
+			return nil
+		}
+		if offset < 20 || int(offset) >= len(data) {
+			return fmt.Errorf("%w: %s offset %d outside descriptor of %d bytes",
+				ErrMalformedSecurityDescriptor, what, offset, len(data))
+		}
+		return nil
+	}
+	if err := checkOffset(offsetOwner, "owner"); err != nil {
+		return nil, err
+	}
+	if err := checkOffset(offsetGroup, "group"); err != nil {
+		return nil, err
+	}
+	if err := checkOffset(offsetSacl, "SACL"); err != nil {
+		return nil, err
+	}
+	if err := checkOffset(offsetDacl, "DACL"); err != nil {
+		return nil, err
+	}
+
 	// Parse Owner SID
-	if offsetOwner > 0 && int(offsetOwner) < len(data) {
+	if offsetOwner > 0 {
 		sid, err := ParseSID(data[offsetOwner:])
-		if err == nil {
-			sd.OwnerSID = sid
+		if err != nil {
+			return nil, fmt.Errorf("owner: %w", err)
 		}
+		sd.OwnerSID = sid
 	}
 
 	// Parse Group SID
-	if offsetGroup > 0 && int(offsetGroup) < len(data) {
+	if offsetGroup > 0 {
 		sid, err := ParseSID(data[offsetGroup:])
-		if err == nil {
-			sd.GroupSID = sid
+		if err != nil {
+			return nil, fmt.Errorf("group: %w", err)
 		}
+		sd.GroupSID = sid
 	}
 
 	// Parse SACL (System ACL) - usually not needed but parse for completeness
-	if offsetSacl > 0 && (sd.Control&SE_SACL_PRESENT) != 0 && int(offsetSacl) < len(data) {
+	if offsetSacl > 0 && (sd.Control&SE_SACL_PRESENT) != 0 {
 		acl, err := ParseACL(data[offsetSacl:])
-		if err == nil {
-			sd.Sacl = acl
+		if err != nil {
+			return nil, fmt.Errorf("SACL: %w", err)
 		}
+		sd.Sacl = acl
 	}
 
 	// Parse DACL (Discretionary ACL) - this is what we mainly care about
-	if offsetDacl > 0 && (sd.Control&SE_DACL_PRESENT) != 0 && int(offsetDacl) < len(data) {
+	if offsetDacl > 0 && (sd.Control&SE_DACL_PRESENT) != 0 {
 		acl, err := ParseACL(data[offsetDacl:])
-		if err == nil {
-			sd.Dacl = acl
+		if err != nil {
+			return nil, fmt.Errorf("DACL: %w", err)
 		}
+		sd.Dacl = acl
 	}
 
 	return sd, nil
@@ -142,7 +200,7 @@ func ParseSecurityDescriptor(data []byte) (*SecurityDescriptor, error) {
 // ParseACL parses a binary ACL.
 func ParseACL(data []byte) (*ACL, error) {
 	if len(data) < 8 {
-		return nil, fmt.Errorf("ACL too short: %d bytes", len(data))
+		return nil, fmt.Errorf("%w: too short: %d bytes", ErrMalformedACL, len(data))
 	}
 
 	acl := &ACL{
@@ -153,12 +211,30 @@ func ParseACL(data []byte) (*ACL, error) {
 		Sbz2:        binary.LittleEndian.Uint16(data[6:8]),
 	}
 
-	// Parse ACEs
+	// The declared size must cover the header and be backed by real data,
+	// and every ACE needs at least its 4-byte header, so an ACE count that
+	// cannot fit in AclSize is malformed rather than worth a best-effort
+	// walk (or a large Aces allocation).
+	if int(acl.AclSize) < 8 {
+		return nil, fmt.Errorf("%w: declared size %d below header size", ErrMalformedACL, acl.AclSize)
+	}
+	if int(acl.AclSize) > len(data) {
+		return nil, fmt.Errorf("%w: declared size %d exceeds %d available bytes", ErrMalformedACL, acl.AclSize, len(data))
+	}
+	if int(acl.AceCount)*4 > int(acl.AclSize)-8 {
+		return nil, fmt.Errorf("%w: %d ACEs cannot fit in %d bytes", ErrMalformedACL, acl.AceCount, acl.AclSize)
+	}
+
+	// Parse ACEs, confined to the declared ACL size
 	offset := 8
-	for i := 0; i < int(acl.AceCount) && offset < len(data); i++ {
-		ace, size, err := ParseACE(data[offset:])
+	limit := int(acl.AclSize)
+	for i := 0; i < int(acl.AceCount); i++ {
+		if offset >= limit {
+			return nil, fmt.Errorf("%w: ACE %d starts past the declared size", ErrMalformedACL, i)
+		}
+		ace, size, err := ParseACE(data[offset:limit])
 		if err != nil {
-			break
+			return nil, fmt.Errorf("ACE %d: %w", i, err)
 		}
 		acl.Aces = append(acl.Aces, *ace)
 		offset += size
@@ -170,7 +246,7 @@ func ParseACL(data []byte) (*ACL, error) {
 // ParseACE parses a binary ACE and returns the ACE and its size.
 func ParseACE(data []byte) (*ACE, int, error) {
 	if len(data) < 4 {
-		return nil, 0, fmt.Errorf("ACE header too short")
+		return nil, 0, fmt.Errorf("%w: ACE header too short", ErrMalformedACL)
 	}
 
 	ace := &ACE{
@@ -179,31 +255,95 @@ func ParseACE(data []byte) (*ACE, int, error) {
 		AceSize:  binary.LittleEndian.Uint16(data[2:4]),
 	}
 
+	// A size below the header would make the ACE walk stand still
+	if ace.AceSize < 4 {
+		return nil, 0, fmt.Errorf("%w: ACE declares size %d", ErrMalformedACL, ace.AceSize)
+	}
 	if len(data) < int(ace.AceSize) {
-		return nil, 0, fmt.Errorf("ACE data too short")
+		return nil, 0, fmt.Errorf("%w: ACE data too short", ErrMalformedACL)
 	}
 
-	// For standard ACE types, parse the mask and SID
+	// For standard ACE types, parse the mask and SID.  Mandatory label ACEs
+	// share the same layout: the mask carries the policy flags and the SID is
+	// the integrity label (S-1-16-X).
 	switch ace.AceType {
 	case ACCESS_ALLOWED_ACE_TYPE, ACCESS_DENIED_ACE_TYPE,
-		SYSTEM_AUDIT_ACE_TYPE, SYSTEM_ALARM_ACE_TYPE:
-		if len(data) < 8 {
-			return nil, 0, fmt.Errorf("ACE too short for mask")
+		SYSTEM_AUDIT_ACE_TYPE, SYSTEM_ALARM_ACE_TYPE,
+		SYSTEM_MANDATORY_LABEL_ACE_TYPE:
+		if ace.AceSize < 8 {
+			return nil, 0, fmt.Errorf("%w: ACE too short for mask", ErrMalformedACL)
 		}
 		ace.Mask = binary.LittleEndian.Uint32(data[4:8])
 
-		// Parse SID starting at offset 8
-		if len(data) > 8 {
-			sid, err := ParseSID(data[8:])
-			if err == nil {
-				ace.SID = sid
+		// Parse SID starting at offset 8, confined to the declared ACE size
+		// so a lying SID cannot bleed into the next ACE
+		if ace.AceSize > 8 {
+			sid, err := ParseSID(data[8:ace.AceSize])
+			if err != nil {
+				return nil, 0, err
 			}
+			ace.SID = sid
 		}
 	}
 
 	return ace, int(ace.AceSize), nil
 }
 
+// MandatoryLabel holds the integrity level and policy of a
+// SYSTEM_MANDATORY_LABEL ACE found in the SACL.
+type MandatoryLabel struct {
+	Level       string // "Untrusted", "Low", "Medium", "MediumPlus", "High", "System", "ProtectedProcess" or the raw label SID
+	NoWriteUp   bool
+	NoReadUp    bool
+	NoExecuteUp bool
+}
+
+// MandatoryLabel returns the mandatory integrity label from the SACL, or nil
+// when no label ACE is present (the object then defaults to Medium integrity).
+func (sd *SecurityDescriptor) MandatoryLabel() *MandatoryLabel {
+	if sd == nil || sd.Sacl == nil {
+		return nil
+	}
+
+	for _, ace := range sd.Sacl.Aces {
+		if ace.AceType != SYSTEM_MANDATORY_LABEL_ACE_TYPE || ace.SID == nil {
+			continue
+		}
+		if len(ace.SID.SubAuthorities) == 0 {
+			continue
+		}
+
+		label := &MandatoryLabel{
+			NoWriteUp:   ace.Mask&SYSTEM_MANDATORY_LABEL_NO_WRITE_UP != 0,
+			NoReadUp:    ace.Mask&SYSTEM_MANDATORY_LABEL_NO_READ_UP != 0,
+			NoExecuteUp: ace.Mask&SYSTEM_MANDATORY_LABEL_NO_EXECUTE_UP != 0,
+		}
+
+		switch ace.SID.SubAuthorities[0] {
+		case SECURITY_MANDATORY_UNTRUSTED_RID:
+			label.Level = "Untrusted"
+		case SECURITY_MANDATORY_LOW_RID:
+			label.Level = "Low"
+		case SECURITY_MANDATORY_MEDIUM_RID:
+			label.Level = "Medium"
+		case SECURITY_MANDATORY_MEDIUM_PLUS_RID:
+			label.Level = "MediumPlus"
+		case SECURITY_MANDATORY_HIGH_RID:
+			label.Level = "High"
+		case SECURITY_MANDATORY_SYSTEM_RID:
+			label.Level = "System"
+		case SECURITY_MANDATORY_PROTECTED_PROCESS_RID:
+			label.Level = "ProtectedProcess"
+		default:
+			label.Level = ace.SID.String()
+		}
+
+		return label
+	}
+
+	return nil
+}
+
 // IsAccessAllowed returns true if this is an ACCESS_ALLOWED ACE.
 func (a *ACE) IsAccessAllowed() bool {
 	return a.AceType == ACCESS_ALLOWED_ACE_TYPE
@@ -230,6 +370,8 @@ func (a *ACE) AceTypeName() string {
 		return "SYSTEM_AUDIT"
 	case SYSTEM_ALARM_ACE_TYPE:
 		return "SYSTEM_ALARM"
+	case SYSTEM_MANDATORY_LABEL_ACE_TYPE:
+		return "SYSTEM_MANDATORY_LABEL"
 	default:
 		return fmt.Sprintf("UNKNOWN(%d)", a.AceType)
 	}
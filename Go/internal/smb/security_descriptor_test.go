@@ -1,6 +1,7 @@
 package smb
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -190,6 +191,65 @@ func TestGetNTFSRightsForMaskSingleBits(t *testing.T) {
 	}
 }
 
+func TestGetNTFSDirectoryRightsForMaskSingleBits(t *testing.T) {
+	tests := []struct {
+		mask     uint32
+		expected string
+	}{
+		{0x00000001, "CanNTFSListDirectory"},
+		{0x00000002, "CanNTFSAddFile"},
+		{0x00000004, "CanNTFSAddSubdirectory"},
+		{0x00000008, "CanNTFSReadEA"},
+		{0x00000010, "CanNTFSWriteEA"},
+		{0x00000020, "CanNTFSTraverse"},
+		{0x00000040, "CanNTFSDeleteChild"},
+		{0x00000080, "CanNTFSReadAttributes"},
+		{0x00000100, "CanNTFSWriteAttributes"},
+	}
+
+	for _, tt := range tests {
+		rights := GetNTFSDirectoryRightsForMask(tt.mask)
+		found := false
+		for _, r := range rights {
+			if r == tt.expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected %s for mask 0x%08x, got %v", tt.expected, tt.mask, rights)
+		}
+		if len(rights) != 1 {
+			t.Errorf("Expected exactly 1 right for mask 0x%08x, got %d: %v", tt.mask, len(rights), rights)
+		}
+	}
+}
+
+func TestComputeEffectiveRightsWithDirectoryKinds(t *testing.T) {
+	// A directory ACE mapped with the directory mapping must still intersect
+	// into effective rights
+	shareKinds := []string{"CanShareRead", "CanShareWrite", "CanShareExecute"}
+	ntfsKinds := []string{"CanNTFSListDirectory", "CanNTFSAddFile", "CanNTFSTraverse"}
+
+	effective := ComputeEffectiveRights(shareKinds, ntfsKinds)
+
+	expected := map[string]bool{
+		"CanEffectiveRead":    false,
+		"CanEffectiveWrite":   false,
+		"CanEffectiveExecute": false,
+	}
+	for _, e := range effective {
+		if _, ok := expected[e]; ok {
+			expected[e] = true
+		}
+	}
+	for kind, found := range expected {
+		if !found {
+			t.Errorf("Expected %s from directory NTFS kinds, but it was missing", kind)
+		}
+	}
+}
+
 func TestComputeEffectiveRightsWithGranularNTFS(t *testing.T) {
 	// Test that granular NTFS permissions (e.g., FILE_READ_DATA) contribute to effective rights
 	shareKinds := []string{"CanGenericRead", "CanGenericWrite", "CanGenericExecute"}
@@ -215,6 +275,78 @@ func TestComputeEffectiveRightsWithGranularNTFS(t *testing.T) {
 	}
 }
 
+func TestMandatoryLabel(t *testing.T) {
+	// SD with a SACL holding a single SYSTEM_MANDATORY_LABEL ACE:
+	// High integrity (S-1-16-12288) with NO_WRITE_UP policy.
+	sdWithLabel := []byte{
+		// Header (20 bytes)
+		0x01,       // Revision
+		0x00,       // Sbz1
+		0x10, 0x80, // Control (SE_SELF_RELATIVE | SE_SACL_PRESENT)
+		0x00, 0x00, 0x00, 0x00, // OwnerOffset
+		0x00, 0x00, 0x00, 0x00, // GroupOffset
+		0x14, 0x00, 0x00, 0x00, // SaclOffset (20)
+		0x00, 0x00, 0x00, 0x00, // DaclOffset
+
+		// SACL at offset 20 (28 bytes)
+		0x02,       // Revision
+		0x00,       // Sbz1
+		0x1c, 0x00, // AclSize (28)
+		0x01, 0x00, // AceCount (1)
+		0x00, 0x00, // Sbz2
+
+		// ACE: SYSTEM_MANDATORY_LABEL for S-1-16-12288 (High)
+		0x11,       // AceType (SYSTEM_MANDATORY_LABEL)
+		0x00,       // AceFlags
+		0x14, 0x00, // AceSize (20)
+		0x01, 0x00, 0x00, 0x00, // Mask (NO_WRITE_UP)
+		// SID: S-1-16-12288
+		0x01,                               // Revision
+		0x01,                               // SubAuthorityCount
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x10, // IdentifierAuthority (16)
+		0x00, 0x30, 0x00, 0x00, // SubAuthority[0] (0x3000)
+	}
+
+	sd, err := ParseSecurityDescriptor(sdWithLabel)
+	if err != nil {
+		t.Fatalf("Failed to parse security descriptor: %v", err)
+	}
+
+	label := sd.MandatoryLabel()
+	if label == nil {
+		t.Fatal("Expected mandatory label to be present")
+	}
+	if label.Level != "High" {
+		t.Errorf("Expected integrity level High, got %s", label.Level)
+	}
+	if !label.NoWriteUp {
+		t.Error("Expected NoWriteUp policy to be set")
+	}
+	if label.NoReadUp || label.NoExecuteUp {
+		t.Error("Expected NoReadUp and NoExecuteUp to be unset")
+	}
+}
+
+func TestMandatoryLabelAbsent(t *testing.T) {
+	sdNoSACL := []byte{
+		0x01,       // Revision
+		0x00,       // Sbz1
+		0x00, 0x80, // Control (SE_SELF_RELATIVE only)
+		0x00, 0x00, 0x00, 0x00, // OwnerOffset
+		0x00, 0x00, 0x00, 0x00, // GroupOffset
+		0x00, 0x00, 0x00, 0x00, // SaclOffset
+		0x00, 0x00, 0x00, 0x00, // DaclOffset
+	}
+
+	sd, err := ParseSecurityDescriptor(sdNoSACL)
+	if err != nil {
+		t.Fatalf("Failed to parse SD without SACL: %v", err)
+	}
+	if sd.MandatoryLabel() != nil {
+		t.Error("Expected no mandatory label for SD without SACL")
+	}
+}
+
 func TestParseSecurityDescriptorNoDACL(t *testing.T) {
 	// Test SD without DACL
 	sdNoDACL := []byte{
@@ -236,3 +368,63 @@ func TestParseSecurityDescriptorNoDACL(t *testing.T) {
 		t.Error("Expected DACL to be nil")
 	}
 }
+
+func TestParseSecurityDescriptorRejectsBadOffsets(t *testing.T) {
+	// Owner offset points past the end of the blob
+	badOffset := []byte{
+		0x01,       // Revision
+		0x00,       // Sbz1
+		0x00, 0x80, // Control (SE_SELF_RELATIVE)
+		0x64, 0x00, 0x00, 0x00, // OwnerOffset (100, past end)
+		0x00, 0x00, 0x00, 0x00, // GroupOffset
+		0x00, 0x00, 0x00, 0x00, // SaclOffset
+		0x00, 0x00, 0x00, 0x00, // DaclOffset
+	}
+
+	_, err := ParseSecurityDescriptor(badOffset)
+	if !errors.Is(err, ErrMalformedSecurityDescriptor) {
+		t.Errorf("ParseSecurityDescriptor error = %v, want ErrMalformedSecurityDescriptor", err)
+	}
+}
+
+func TestParseSecurityDescriptorSizeCap(t *testing.T) {
+	oversized := make([]byte, MaxSecurityDescriptorSize+1)
+	oversized[0] = 0x01
+
+	_, err := ParseSecurityDescriptor(oversized)
+	if !errors.Is(err, ErrMalformedSecurityDescriptor) {
+		t.Errorf("ParseSecurityDescriptor error = %v, want ErrMalformedSecurityDescriptor", err)
+	}
+}
+
+func TestParseACLRejectsImpossibleAceCount(t *testing.T) {
+	// AclSize 8 cannot hold 500 ACEs
+	impossible := []byte{
+		0x02, 0x00, // Revision, Sbz1
+		0x08, 0x00, // AclSize (8)
+		0xf4, 0x01, // AceCount (500)
+		0x00, 0x00, // Sbz2
+	}
+
+	_, err := ParseACL(impossible)
+	if !errors.Is(err, ErrMalformedACL) {
+		t.Errorf("ParseACL error = %v, want ErrMalformedACL", err)
+	}
+}
+
+func TestParseACLRejectsZeroSizeACE(t *testing.T) {
+	// A zero-size ACE would stall the ACE walk without an explicit check
+	zeroSize := []byte{
+		0x02, 0x00, // Revision, Sbz1
+		0x10, 0x00, // AclSize (16)
+		0x01, 0x00, // AceCount (1)
+		0x00, 0x00, // Sbz2
+		0x00, 0x00, 0x00, 0x00, // ACE header with AceSize 0
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	_, err := ParseACL(zeroSize)
+	if !errors.Is(err, ErrMalformedACL) {
+		t.Errorf("ParseACL error = %v, want ErrMalformedACL", err)
+	}
+}
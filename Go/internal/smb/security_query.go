@@ -15,4 +15,5 @@ const (
 	GROUP_SECURITY_INFORMATION = 0x00000002
 	DACL_SECURITY_INFORMATION  = 0x00000004
 	SACL_SECURITY_INFORMATION  = 0x00000008
+	LABEL_SECURITY_INFORMATION = 0x00000010
 )
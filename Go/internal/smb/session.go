@@ -4,6 +4,7 @@ package smb
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"path"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/medianexapp/go-smb2"
+	"github.com/specterops/sharehound/internal/audit"
 	"github.com/specterops/sharehound/internal/auth"
 	"github.com/specterops/sharehound/internal/config"
 	"github.com/specterops/sharehound/internal/credentials"
@@ -47,10 +49,12 @@ type SMBSession struct {
 	advertisedName string
 	credentials    *credentials.Credentials
 
-	conn      net.Conn
-	session   *smb2.Session
-	share     *smb2.Share
-	connected bool
+	connMu      sync.Mutex // guards conn only; never held across network I/O
+	conn        net.Conn
+	session     *smb2.Session
+	share       *smb2.Share
+	connected   bool
+	multiplexed bool // session is borrowed from another SMBSession's transport
 
 	availableShares map[string]ShareInfo
 	currentShare    string
@@ -62,6 +66,9 @@ type SMBSession struct {
 	// SRVSVC client for share-level security descriptors
 	srvsvcClient *SRVSVCClient
 
+	// Shared per-host security descriptor query stats (nil = not tracked)
+	sdStats *SecurityQueryStats
+
 	// For forceful timeout handling
 	mu sync.Mutex
 }
@@ -94,6 +101,19 @@ func NewSMBSession(
 	}
 }
 
+// dialTCP opens the transport connection for Connect. It is a variable so the
+// stress harness can substitute a mock net.Conn (see forceclose_stress_test.go).
+var dialTCP = func(address string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", address, timeout)
+}
+
+// Default SMB transport ports: direct TCP, and the NetBIOS session service
+// used as a fallback on legacy segments where 445 is filtered.
+const (
+	PortSMBDirect = 445
+	PortNetBIOS   = 139
+)
+
 // Connect establishes a connection to the SMB server.
 func (s *SMBSession) Connect() error {
 	s.log.Debug(fmt.Sprintf("[>] Connecting to remote SMB server '%s'...", s.host))
@@ -101,22 +121,53 @@ func (s *SMBSession) Connect() error {
 	// Check if port is open first
 	ok, err := utils.IsPortOpen(s.host, s.port, s.timeout)
 	if !ok {
-		s.log.Debug(fmt.Sprintf("Could not connect to '%s:%d', %v", s.host, s.port, err))
-		return ErrConnectionFailed
+		// Legacy segments and some appliances filter 445 but still expose
+		// SMB through the NetBIOS session service
+		if s.port == PortSMBDirect {
+			if ok139, _ := utils.IsPortOpen(s.host, PortNetBIOS, s.timeout); ok139 {
+				s.log.Debug(fmt.Sprintf("Port 445 on '%s' is filtered, falling back to NetBIOS session on 139", s.host))
+				s.port = PortNetBIOS
+				ok = true
+			}
+		}
+		if !ok {
+			s.log.Debug(fmt.Sprintf("Could not connect to '%s:%d', %v", s.host, s.port, err))
+			return ErrConnectionFailed
+		}
 	}
 
 	// Connect to SMB server
 	address := fmt.Sprintf("%s:%d", s.host, s.port)
-	conn, err := net.DialTimeout("tcp", address, s.timeout)
+	conn, err := dialTCP(address, s.timeout)
 	if err != nil {
 		s.log.Debug(fmt.Sprintf("[NETWORK] Could not connect to '%s': %v", address, err))
 		return ErrConnectionFailed
 	}
+
+	// Publish the conn so a concurrent ForceClose can see it and interrupt
+	// the SMB negotiation below.
+	s.connMu.Lock()
 	s.conn = conn
+	s.connMu.Unlock()
+
+	// Port 139 requires the NetBIOS session handshake before negotiation
+	if s.port == PortNetBIOS {
+		if err := netbiosSessionRequest(conn, netbiosCalledName(s.remoteName), s.advertisedName, s.timeout); err != nil {
+			s.log.Debug(fmt.Sprintf("NetBIOS session setup with '%s' failed: %v", s.remoteName, err))
+			conn.Close()
+			s.connMu.Lock()
+			s.conn = nil
+			s.connMu.Unlock()
+			return ErrConnectionFailed
+		}
+	}
 
 	initiator, authMode, err := s.newInitiator()
 	if err != nil {
 		conn.Close()
+		s.connMu.Lock()
+		s.conn = nil
+		s.connMu.Unlock()
 		return fmt.Errorf("failed to initialize SMB authentication: %w", err)
 	}
 
@@ -135,17 +186,36 @@ func (s *SMBSession) Connect() error {
 		classification := ClassifyError(err)
 		s.log.Debug(fmt.Sprintf("[%s] Authentication failed: %s", classification.Category, classification.Message))
 		conn.Close()
+		s.connMu.Lock()
 		s.conn = nil
+		s.connMu.Unlock()
 		if s.credentials.WindowsAuth && s.activateNativeWindowsFallback("SMB authentication failed") {
+			s.mu.Lock()
 			s.connected = true
+			s.mu.Unlock()
+			audit.Record("host_connect", s.host)
 			s.log.Debug(fmt.Sprintf("[+] Using Windows-native SMB access to '%s' with current logon session", s.remoteName))
 			return nil
 		}
 		return ErrAuthFailed
 	}
 
+	// Check if we were force-closed while negotiating; ForceClose nils the
+	// conn after closing it, so a successful-looking dial must not resurrect
+	// the session.
+	s.connMu.Lock()
+	forceClosed := s.conn == nil
+	s.connMu.Unlock()
+	if forceClosed {
+		session.Logoff()
+		return ErrConnectionFailed
+	}
+
+	s.mu.Lock()
 	s.session = session
 	s.connected = true
+	s.mu.Unlock()
+	audit.Record("host_connect", s.host)
 	if s.credentials.WindowsAuth {
 		if err := s.enableNativeWindowsFallback(); err != nil {
 			s.log.Debug(fmt.Sprintf("Windows-native SMB fallback is unavailable for '%s': %v", s.remoteName, err))
@@ -158,6 +228,39 @@ func (s *SMBSession) Connect() error {
 	return nil
 }
 
+// ConnectMultiplexed attaches this session to the authenticated SMB session
+// of parent instead of dialing a new TCP connection. Tree connects and file
+// handles opened through this session are multiplexed over the parent's
+// transport (SMB2 credit accounting is handled per connection by the
+// underlying library), so additional workers on the same host produce no
+// extra TCP connections or authentication events. The parent must stay open
+// for the lifetime of this session.
+func (s *SMBSession) ConnectMultiplexed(parent *SMBSession) error {
+	parent.mu.Lock()
+	session := parent.session
+	ok := parent.connected && session != nil && !parent.nativeWindows
+	parent.mu.Unlock()
+	if !ok {
+		return ErrNotConnected
+	}
+
+	s.mu.Lock()
+	s.session = session
+	s.multiplexed = true
+	s.connected = true
+	s.mu.Unlock()
+
+	s.log.Debug(fmt.Sprintf("[+] Multiplexing SMB operations to '%s' over the existing authenticated session", s.host))
+	return nil
+}
+
+// Multiplexed reports whether this session shares another session's transport.
+func (s *SMBSession) Multiplexed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.multiplexed
+}
+
 func (s *SMBSession) newInitiator() (smb2.Initiator, string, error) {
 	if s.credentials.WindowsAuth {
 		initiator, err := newSSPIKrb5Initiator(auth.ServicePrincipal("cifs", s.remoteName))
@@ -168,7 +271,7 @@ func (s *SMBSession) newInitiator() (smb2.Initiator, string, error) {
 	}
 
 	if s.credentials.UseKerberos {
-		client, err := auth.NewKerberosClient(auth.KerberosOptions{
+		client, err := auth.SharedKerberosClient(auth.KerberosOptions{
 			Domain:     s.credentials.Domain,
 			Username:   s.credentials.Username,
 			Password:   s.credentials.Password,
@@ -178,16 +281,25 @@ func (s *SMBSession) newInitiator() (smb2.Initiator, string, error) {
 		if err != nil {
 			return nil, "", err
 		}
+		logins, reuses := auth.KerberosClientStats()
+		s.log.Debug(fmt.Sprintf("Kerberos ticket cache: %d AS exchange(s), %d client reuse(s)", logins, reuses))
 		return &smb2.Krb5Initiator{
 			Client:    client,
 			TargetSPN: auth.ServicePrincipal("cifs", s.remoteName),
 		}, "Kerberos", nil
 	}
 
+	domain := s.credentials.Domain
+	if s.credentials.LocalAuth {
+		// Local account authentication: the target hostname is the NTLM
+		// domain, so workgroup hosts validate against their local SAM.
+		domain = strings.SplitN(s.remoteName, ".", 2)[0]
+	}
+
 	return &smb2.NTLMInitiator{
 		User:     s.credentials.Username,
 		Password: s.credentials.Password,
-		Domain:   s.credentials.Domain,
+		Domain:   domain,
 		Hash:     s.credentials.NTRaw,
 	}, "NTLM", nil
 }
@@ -218,14 +330,20 @@ func (s *SMBSession) Close() error {
 		s.share = nil
 	}
 	if s.session != nil {
-		s.session.Logoff()
+		// Multiplexed sessions borrow another session's authenticated
+		// session; logging off here would sever every sibling sharing it.
+		if !s.multiplexed {
+			s.session.Logoff()
+		}
 		s.session = nil
 	}
 	s.closeNativeWindowsFallback()
+	s.connMu.Lock()
 	if s.conn != nil {
 		s.conn.Close()
 		s.conn = nil
 	}
+	s.connMu.Unlock()
 	s.connected = false
 	s.log.Debug("[+] SMB connection closed successfully.")
 	return nil
@@ -238,11 +356,14 @@ func (s *SMBSession) Close() error {
 func (s *SMBSession) ForceClose() error {
 	if s.mu.TryLock() {
 		// Got the lock - full cleanup path
-		if s.conn != nil {
+		s.connMu.Lock()
+		conn := s.conn
+		s.conn = nil
+		s.connMu.Unlock()
+		if conn != nil {
 			s.log.Debug(fmt.Sprintf("[FORCECLOSE] Closing connection for %s", s.host))
-			s.conn.SetDeadline(time.Now())
-			s.conn.Close()
-			s.conn = nil
+			conn.SetDeadline(time.Now())
+			conn.Close()
 		} else {
 			s.log.Debug(fmt.Sprintf("[FORCECLOSE] No connection to close for %s", s.host))
 		}
@@ -261,12 +382,12 @@ func (s *SMBSession) ForceClose() error {
 	// Could not acquire lock - another goroutine holds it and is likely
 	// blocked on network I/O. Directly close the TCP connection to interrupt
 	// the blocking operation. net.Conn.Close() is safe for concurrent use
-	// and will cause any blocked Read/Write to return with an error.
-	// We read s.conn without the lock; this is a controlled race that is
-	// safe because: (a) conn is set once in Connect() and only nil'd under
-	// mu, (b) Close() on net.Conn is goroutine-safe, (c) the worst case is
-	// a nil-check race which we guard with the nil check.
+	// and will cause any blocked Read/Write to return with an error. conn is
+	// guarded by the dedicated connMu, which is never held across network
+	// I/O, so this path cannot block behind the stuck operation.
+	s.connMu.Lock()
 	conn := s.conn
+	s.connMu.Unlock()
 	if conn != nil {
 		s.log.Debug(fmt.Sprintf("[FORCECLOSE] Lock held - force-closing TCP for %s", s.host))
 		conn.SetDeadline(time.Now())
@@ -336,6 +457,25 @@ func (s *SMBSession) ListShares() (map[string]ShareInfo, error) {
 
 	s.availableShares = make(map[string]ShareInfo)
 
+	// Prefer SRVSVC NetrShareEnum (level 1), which exposes the raw STYPE
+	// flags and remarks. Fall back to go-smb2's name-only enumeration when
+	// the RPC path is unavailable (e.g. hardened or non-Windows servers).
+	if client, err := s.getSRVSVCClient(); err == nil {
+		if enumShares, err := client.EnumShares(s.remoteName); err == nil && len(enumShares) > 0 {
+			for _, es := range enumShares {
+				s.availableShares[strings.ToLower(es.Name)] = ShareInfo{
+					Name:    es.Name,
+					Type:    utils.STYPEMask(es.Type),
+					RawType: es.Type,
+					Comment: es.Comment,
+				}
+			}
+			return s.availableShares, nil
+		} else if err != nil {
+			s.log.Debug(fmt.Sprintf("NetrShareEnum failed, falling back to name-only enumeration: %v", err))
+		}
+	}
+
 	names, err := session.ListSharenames()
 	if err != nil {
 		if s.activateNativeWindowsFallback("share enumeration failed") {
@@ -365,7 +505,14 @@ func (s *SMBSession) ListShares() (map[string]ShareInfo, error) {
 // IMPORTANT: Does NOT hold s.mu during network operations (Mount/Umount)
 // to allow ForceClose to interrupt blocked I/O.
 func (s *SMBSession) SetShare(shareName string) error {
+	// Log only real transitions; BFS traversal re-sets the same share at
+	// every depth and would otherwise flood the audit log.
+	newMount := shareName != s.currentShare
+
 	if s.nativeWindows {
+		if newMount {
+			audit.Record("share_mount", fmt.Sprintf("\\\\%s\\%s", s.remoteName, shareName))
+		}
 		s.currentShare = shareName
 		s.currentCwd = ""
 		return nil
@@ -376,6 +523,17 @@ func (s *SMBSession) SetShare(shareName string) error {
 		s.mu.Unlock()
 		return ErrNotConnected
 	}
+
+	// Reuse the existing tree connect when the requested share is already
+	// mounted: BFS traversal re-enters here at every directory level, and
+	// unmounting/remounting each time would multiply TREE_CONNECT requests
+	// on the wire for no benefit.
+	if !newMount && s.share != nil {
+		s.mu.Unlock()
+		s.currentCwd = ""
+		return nil
+	}
+
 	session := s.session
 	oldShare := s.share
 	s.share = nil // Mark as transitioning
@@ -390,6 +548,9 @@ func (s *SMBSession) SetShare(shareName string) error {
 	share, err := session.Mount(shareName)
 	if err != nil {
 		if s.activateNativeWindowsFallback(fmt.Sprintf("mounting share '%s' failed", shareName)) {
+			if newMount {
+				audit.Record("share_mount", fmt.Sprintf("\\\\%s\\%s", s.remoteName, shareName))
+			}
 			s.currentShare = shareName
 			s.currentCwd = ""
 			return nil
@@ -411,6 +572,10 @@ func (s *SMBSession) SetShare(shareName string) error {
 	s.currentShare = shareName
 	s.currentCwd = ""
 
+	if newMount {
+		audit.Record("share_mount", fmt.Sprintf("\\\\%s\\%s", s.remoteName, shareName))
+	}
+
 	return nil
 }
 
@@ -483,9 +648,41 @@ func (s *SMBSession) ListContents(dirPath string) (map[string]FileInfo, error) {
 	return contents, nil
 }
 
+// OpenFile opens a file on the current share for reading. The caller owns
+// the returned reader and must close it. Used by file handlers and content
+// scanners; plain traversal never opens file data.
+func (s *SMBSession) OpenFile(filePath string) (io.ReadCloser, error) {
+	audit.Record("file_read", fmt.Sprintf("\\\\%s\\%s\\%s", s.remoteName, s.currentShare,
+		strings.ReplaceAll(filePath, "/", "\\")))
+
+	if s.nativeWindows {
+		return s.openFileNative(filePath)
+	}
+
+	s.mu.Lock()
+	if s.share == nil || !s.connected {
+		s.mu.Unlock()
+		return nil, ErrShareNotSet
+	}
+	share := s.share
+	s.mu.Unlock()
+
+	fullPath := strings.ReplaceAll(filePath, "/", "\\")
+	if fullPath == "" {
+		return nil, ErrPathNotFound
+	}
+
+	return share.Open(fullPath)
+}
+
 // GetFileSecurityDescriptor gets the NTFS security descriptor for a file or directory.
-// This uses the medianexapp/go-smb2 fork which has native SecurityInfoRaw() support.
+// The query goes through QuerySecurityDescriptor (SMB2 QUERY_INFO, SecInfo class).
 func (s *SMBSession) GetFileSecurityDescriptor(filePath string) (*SecurityDescriptor, error) {
+	defer func(start time.Time) { s.sdStats.record(time.Since(start)) }(time.Now())
+
+	audit.Record("file_open", fmt.Sprintf("\\\\%s\\%s\\%s", s.remoteName, s.currentShare,
+		strings.ReplaceAll(filePath, "/", "\\")))
+
 	if s.nativeWindows {
 		sdBytes, err := s.getFileSecurityDescriptorNative(filePath)
 		if err != nil || len(sdBytes) == 0 {
@@ -508,8 +705,7 @@ func (s *SMBSession) GetFileSecurityDescriptor(filePath string) (*SecurityDescri
 		fullPath = "."
 	}
 
-	// Try to get security descriptor using go:linkname approach
-	sdBytes, err := QuerySecurityDescriptorLinked(share, fullPath)
+	sdBytes, err := QuerySecurityDescriptor(share, fullPath)
 	if err != nil {
 		if s.activateNativeWindowsFallback(fmt.Sprintf("querying security descriptor for '%s' failed", fullPath)) {
 			sdBytes, nativeErr := s.getFileSecurityDescriptorNative(filePath)
@@ -531,12 +727,12 @@ func (s *SMBSession) GetFileSecurityDescriptor(filePath string) (*SecurityDescri
 	return ParseSecurityDescriptor(sdBytes)
 }
 
-// GetShareSecurityDescriptor gets the share-level security descriptor via SRVSVC RPC.
+// getSRVSVCClient returns the lazily-initialized SRVSVC client for this session.
 // IMPORTANT: Does NOT hold s.mu during SRVSVC client creation (network I/O)
 // to allow ForceClose to interrupt blocked operations.
-func (s *SMBSession) GetShareSecurityDescriptor(shareName string) ([]byte, error) {
-	if s.nativeWindows {
-		return nil, fmt.Errorf("share-level security descriptor unavailable in Windows-native SMB fallback")
+func (s *SMBSession) getSRVSVCClient() (*SRVSVCClient, error) {
+	if s.config.OpsecStrict() {
+		return nil, fmt.Errorf("SRVSVC disabled by strict OPSEC profile")
 	}
 
 	s.mu.Lock()
@@ -548,29 +744,47 @@ func (s *SMBSession) GetShareSecurityDescriptor(shareName string) ([]byte, error
 	srvsvcClient := s.srvsvcClient
 	s.mu.Unlock()
 
-	// Initialize SRVSVC client if needed — WITHOUT holding mutex
-	if srvsvcClient == nil {
-		client, err := NewSRVSVCClient(session)
-		if err != nil {
-			s.log.Debug(fmt.Sprintf("Failed to create SRVSVC client: %v", err))
-			return nil, fmt.Errorf("SRVSVC not available: %w", err)
-		}
-		// Store client under lock
-		s.mu.Lock()
-		if !s.connected {
-			s.mu.Unlock()
-			client.Close()
-			return nil, ErrNotConnected
-		}
-		if s.srvsvcClient == nil {
-			s.srvsvcClient = client
-			srvsvcClient = client
-		} else {
-			// Another goroutine already created it — use theirs, close ours
-			client.Close()
-			srvsvcClient = s.srvsvcClient
-		}
+	if srvsvcClient != nil {
+		return srvsvcClient, nil
+	}
+
+	// Create the client WITHOUT holding the mutex
+	client, err := NewSRVSVCClient(session)
+	if err != nil {
+		s.log.Debug(fmt.Sprintf("Failed to create SRVSVC client: %v", err))
+		return nil, fmt.Errorf("SRVSVC not available: %w", err)
+	}
+
+	// Store client under lock
+	s.mu.Lock()
+	if !s.connected {
 		s.mu.Unlock()
+		client.Close()
+		return nil, ErrNotConnected
+	}
+	if s.srvsvcClient == nil {
+		s.srvsvcClient = client
+	} else {
+		// Another goroutine already created it — use theirs, close ours
+		client.Close()
+		client = s.srvsvcClient
+	}
+	s.mu.Unlock()
+
+	return client, nil
+}
+
+// GetShareSecurityDescriptor gets the share-level security descriptor via SRVSVC RPC.
+func (s *SMBSession) GetShareSecurityDescriptor(shareName string) ([]byte, error) {
+	defer func(start time.Time) { s.sdStats.record(time.Since(start)) }(time.Now())
+
+	if s.nativeWindows {
+		return nil, fmt.Errorf("share-level security descriptor unavailable in Windows-native SMB fallback")
+	}
+
+	srvsvcClient, err := s.getSRVSVCClient()
+	if err != nil {
+		return nil, err
 	}
 
 	// Query share security descriptor via SRVSVC
@@ -583,10 +797,92 @@ func (s *SMBSession) GetShareSecurityDescriptor(shareName string) ([]byte, error
 	return sd, nil
 }
 
+// GetShareSecurityDescriptorFromRegistry reads the share's security
+// descriptor blob from the LanmanServer registry key via Remote Registry
+// (MS-RRP). This is an opt-in fallback for hardened hosts where SRVSVC
+// denies NetrShareGetInfo level 502; it requires the Remote Registry
+// service to be running on the target.
+func (s *SMBSession) GetShareSecurityDescriptorFromRegistry(shareName string) ([]byte, error) {
+	if !s.config.RegistrySDFallback() {
+		return nil, fmt.Errorf("registry security descriptor fallback not enabled")
+	}
+	if s.nativeWindows {
+		return nil, fmt.Errorf("remote registry unavailable in Windows-native SMB fallback")
+	}
+	if s.config.OpsecStrict() {
+		return nil, fmt.Errorf("remote registry disabled by strict OPSEC profile")
+	}
+
+	s.mu.Lock()
+	if !s.connected || s.session == nil {
+		s.mu.Unlock()
+		return nil, ErrNotConnected
+	}
+	session := s.session
+	s.mu.Unlock()
+
+	// Create the client per call WITHOUT holding the mutex; unlike SRVSVC
+	// this path is rare enough that caching is not worth the state.
+	client, err := NewWinRegClient(session)
+	if err != nil {
+		s.log.Debug(fmt.Sprintf("Remote Registry not available: %v", err))
+		return nil, err
+	}
+	defer client.Close()
+
+	sd, err := client.GetShareSecurity(shareName)
+	if err != nil {
+		s.log.Debug(fmt.Sprintf("Failed to read share security from registry: %v", err))
+		return nil, err
+	}
+	return sd, nil
+}
+
+// GetLocalGroupMembers queries SAMR for the members of the given BUILTIN
+// aliases (by well-known RID, e.g. AliasAdministrators) and returns their
+// SIDs keyed by RID. Aliases the server refuses to open are skipped rather
+// than failing the whole lookup.
+func (s *SMBSession) GetLocalGroupMembers(aliasRIDs ...uint32) (map[uint32][]string, error) {
+	if s.nativeWindows {
+		return nil, fmt.Errorf("SAMR unavailable in Windows-native SMB fallback")
+	}
+	if s.config.OpsecStrict() {
+		return nil, fmt.Errorf("SAMR disabled by strict OPSEC profile")
+	}
+
+	s.mu.Lock()
+	if !s.connected || s.session == nil {
+		s.mu.Unlock()
+		return nil, ErrNotConnected
+	}
+	session := s.session
+	s.mu.Unlock()
+
+	// Create the client per call WITHOUT holding the mutex; local group
+	// sampling runs once per host, so caching is not worth the state.
+	client, err := NewSAMRClient(session)
+	if err != nil {
+		s.log.Debug(fmt.Sprintf("SAMR not available: %v", err))
+		return nil, err
+	}
+	defer client.Close()
+
+	members := make(map[uint32][]string, len(aliasRIDs))
+	for _, rid := range aliasRIDs {
+		sids, err := client.GetAliasMembers(s.remoteName, rid)
+		if err != nil {
+			s.log.Debug(fmt.Sprintf("SAMR lookup for alias %d failed: %v", rid, err))
+			continue
+		}
+		members[rid] = sids
+	}
+	return members, nil
+}
+
 // GetShareRootSecurityDescriptor gets the NTFS security descriptor of the share root.
 // This is used as a fallback when SRVSVC is not available.
-// It uses QuerySecurityDescriptorLinked (medianexapp/go-smb2 fork) to query the
-// root directory's security descriptor, matching the Python implementation's fallback.
+// It uses QuerySecurityDescriptor to query the root directory's security
+// descriptor, matching the Python implementation's fallback.
 func (s *SMBSession) GetShareRootSecurityDescriptor(shareName string) ([]byte, error) {
 	if s.nativeWindows {
 		return s.getShareRootSecurityDescriptorNative(shareName)
@@ -611,10 +907,10 @@ func (s *SMBSession) GetShareRootSecurityDescriptor(shareName string) ([]byte, e
 	}
 	defer share.Umount()
 
-	// Use QuerySecurityDescriptorLinked to get the root directory's security descriptor.
-	// This is the same method used by GetFileSecurityDescriptor for files/directories,
-	// applied to the root path "." of the share.
-	sdBytes, err := QuerySecurityDescriptorLinked(share, ".")
+	// Query the root directory's security descriptor. This is the same method
+	// used by GetFileSecurityDescriptor for files/directories, applied to the
+	// root path "." of the share.
+	sdBytes, err := QuerySecurityDescriptor(share, ".")
 	if err != nil {
 		if s.activateNativeWindowsFallback(fmt.Sprintf("querying root security descriptor for share '%s' failed", shareName)) {
 			return s.getShareRootSecurityDescriptorNative(shareName)
@@ -631,6 +927,53 @@ func (s *SMBSession) GetShareRootSecurityDescriptor(shareName string) ([]byte, e
 	return sdBytes, nil
 }
 
+// GetServerInfo retrieves server platform, version and role information via
+// SRVSVC NetrServerGetInfo at level 101.
+func (s *SMBSession) GetServerInfo() (*ServerInfo101, error) {
+	if s.nativeWindows {
+		return nil, fmt.Errorf("server info unavailable in Windows-native SMB fallback")
+	}
+
+	srvsvcClient, err := s.getSRVSVCClient()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := srvsvcClient.GetServerInfo(s.remoteName)
+	if err != nil {
+		s.log.Debug(fmt.Sprintf("Failed to get server info via SRVSVC: %v", err))
+		return nil, err
+	}
+	return info, nil
+}
+
+// EnumSessions enumerates active sessions on the server via SRVSVC
+// NetrSessionEnum at level 10. Most servers restrict this to administrators,
+// so callers should treat failures as expected.
+func (s *SMBSession) EnumSessions() ([]SessionInfo10, error) {
+	if s.nativeWindows {
+		return nil, fmt.Errorf("session enumeration unavailable in Windows-native SMB fallback")
+	}
+
+	srvsvcClient, err := s.getSRVSVCClient()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := srvsvcClient.EnumSessions(s.remoteName)
+	if err != nil {
+		s.log.Debug(fmt.Sprintf("Failed to enumerate sessions via SRVSVC: %v", err))
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SetSecurityQueryStats attaches a shared accumulator for security
+// descriptor query timing; pass nil to disable tracking.
+func (s *SMBSession) SetSecurityQueryStats(stats *SecurityQueryStats) {
+	s.sdStats = stats
+}
+
 // GetSession returns the underlying SMB2 session.
 func (s *SMBSession) GetSession() *smb2.Session {
 	return s.session
@@ -2,9 +2,11 @@
 package smb
 
 import (
+	"crypto/sha1"
 	"encoding/binary"
 	"fmt"
 	"strings"
+	"unicode/utf16"
 )
 
 // SID represents a Windows Security Identifier.
@@ -15,10 +17,15 @@ type SID struct {
 	SubAuthorities      []uint32
 }
 
+// SIDMaxSubAuthorities is the maximum sub-authority count Windows permits in
+// a SID (SID_MAX_SUB_AUTHORITIES in MS-DTYP). Higher counts only occur in
+// malformed or hostile data.
+const SIDMaxSubAuthorities = 15
+
 // ParseSID parses a binary SID into a SID structure.
 func ParseSID(data []byte) (*SID, error) {
 	if len(data) < 8 {
-		return nil, fmt.Errorf("SID data too short: %d bytes", len(data))
+		return nil, fmt.Errorf("%w: data too short: %d bytes", ErrMalformedSID, len(data))
 	}
 
 	sid := &SID{
@@ -28,9 +35,14 @@ func ParseSID(data []byte) (*SID, error) {
 
 	copy(sid.IdentifierAuthority[:], data[2:8])
 
+	if sid.SubAuthorityCount > SIDMaxSubAuthorities {
+		return nil, fmt.Errorf("%w: %d sub-authorities exceeds the Windows maximum of %d",
+			ErrMalformedSID, sid.SubAuthorityCount, SIDMaxSubAuthorities)
+	}
+
 	expectedLen := 8 + int(sid.SubAuthorityCount)*4
 	if len(data) < expectedLen {
-		return nil, fmt.Errorf("SID data too short for %d sub-authorities", sid.SubAuthorityCount)
+		return nil, fmt.Errorf("%w: data too short for %d sub-authorities", ErrMalformedSID, sid.SubAuthorityCount)
 	}
 
 	sid.SubAuthorities = make([]uint32, sid.SubAuthorityCount)
@@ -113,6 +125,30 @@ var WellKnownSIDs = map[string]string{
 	"S-1-5-32-552": "BUILTIN\\Replicators",
 }
 
+// TrustedInstallerSID is the service SID of the Windows Modules Installer
+// (NT SERVICE\TrustedInstaller), which owns most of the Windows directory.
+const TrustedInstallerSID = "S-1-5-80-956008885-3418522649-1831038044-1853292631-2271478464"
+
+// ServiceSID derives the service SID (S-1-5-80-...) for a service name.
+// Windows computes it as the SHA-1 hash of the upper-case service name in
+// UTF-16LE, split into five little-endian sub-authorities.
+func ServiceSID(serviceName string) string {
+	utf16Chars := utf16.Encode([]rune(strings.ToUpper(serviceName)))
+	buf := make([]byte, len(utf16Chars)*2)
+	for i, ch := range utf16Chars {
+		binary.LittleEndian.PutUint16(buf[i*2:], ch)
+	}
+
+	hash := sha1.Sum(buf)
+
+	var sb strings.Builder
+	sb.WriteString("S-1-5-80")
+	for i := 0; i < 5; i++ {
+		sb.WriteString(fmt.Sprintf("-%d", binary.LittleEndian.Uint32(hash[i*4:])))
+	}
+	return sb.String()
+}
+
 // GetWellKnownName returns the name for a well-known SID, or empty string if not known.
 func GetWellKnownName(sidString string) string {
 	return WellKnownSIDs[sidString]
@@ -126,6 +162,36 @@ func IsDomainSID(sidString string) bool {
 	return strings.HasPrefix(sidString, "S-1-5-21-")
 }
 
+// SIDToObjectID converts a SID into the objectid BloodHound matches on.
+// Domain-relative SIDs (S-1-5-21-*) already embed the domain identifier and
+// are returned unchanged; every other SID (well-known, BUILTIN, service) is
+// prefixed with the upper-cased domain FQDN, e.g. "CORP.COM-S-1-1-0". With
+// no domain configured the SID is returned unchanged. Every edge emission
+// path must go through this normalizer so the same principal always gets
+// the same objectid.
+func SIDToObjectID(sid, domain string) string {
+	if domain == "" || IsDomainSID(sid) {
+		return sid
+	}
+	return strings.ToUpper(domain) + "-" + sid
+}
+
+// SIDDomainPart returns the domain identifier portion of a domain-relative
+// SID, i.e. "S-1-5-21-<a>-<b>-<c>" with the trailing RID stripped. Two
+// accounts belong to the same domain (or the same machine's local SAM)
+// exactly when their domain parts are equal. For non-domain SIDs it returns
+// the empty string.
+func SIDDomainPart(sidString string) string {
+	if !IsDomainSID(sidString) {
+		return ""
+	}
+	idx := strings.LastIndex(sidString, "-")
+	if idx <= len("S-1-5-21") {
+		return ""
+	}
+	return sidString[:idx]
+}
+
 // IsEveryone returns true if this is the Everyone SID (S-1-1-0).
 func (s *SID) IsEveryone() bool {
 	return s.String() == "S-1-1-0"
@@ -140,3 +206,53 @@ func (s *SID) IsBuiltinAdministrators() bool {
 func (s *SID) IsLocalSystem() bool {
 	return s.String() == "S-1-5-18"
 }
+
+// wellKnownUserSIDs are well-known SIDs that represent a single account
+// rather than a collective.
+var wellKnownUserSIDs = map[string]bool{
+	"S-1-5-18": true, // Local System
+	"S-1-5-19": true, // Local Service
+	"S-1-5-20": true, // Network Service
+}
+
+// domainGroupRIDs are the RIDs of the built-in domain groups
+// (Domain Admins, Domain Users, Enterprise Admins, ...).
+var domainGroupRIDs = map[int]bool{
+	498: true, 512: true, 513: true, 514: true, 515: true, 516: true,
+	517: true, 518: true, 519: true, 520: true, 521: true, 522: true,
+	525: true, 526: true, 527: true, 553: true, 571: true, 572: true,
+}
+
+// domainUserRIDs are the RIDs of the built-in domain accounts
+// (Administrator, Guest, krbtgt).
+var domainUserRIDs = map[int]bool{
+	500: true, 501: true, 502: true,
+}
+
+// SIDPrincipalKind classifies a SID as "User" or "Group" where the type is
+// knowable from the SID alone: well-known collectives and BUILTIN aliases
+// are groups, well-known service accounts and the fixed domain account RIDs
+// are users. For SIDs whose type would need a directory lookup (ordinary
+// domain RIDs, service SIDs) it returns the empty string.
+func SIDPrincipalKind(sidString string) string {
+	if wellKnownUserSIDs[sidString] {
+		return "User"
+	}
+	if _, known := WellKnownSIDs[sidString]; known {
+		return "Group"
+	}
+	if IsDomainSID(sidString) {
+		if idx := strings.LastIndex(sidString, "-"); idx >= 0 {
+			var rid int
+			if _, err := fmt.Sscanf(sidString[idx+1:], "%d", &rid); err == nil {
+				if domainUserRIDs[rid] {
+					return "User"
+				}
+				if domainGroupRIDs[rid] {
+					return "Group"
+				}
+			}
+		}
+	}
+	return ""
+}
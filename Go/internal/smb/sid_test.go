@@ -1,6 +1,7 @@
 package smb
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -166,3 +167,99 @@ func TestSIDSize(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceSID(t *testing.T) {
+	tests := []struct {
+		service  string
+		expected string
+	}{
+		{"TrustedInstaller", TrustedInstallerSID},
+		{"trustedinstaller", TrustedInstallerSID}, // case-insensitive
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			if got := ServiceSID(tt.service); got != tt.expected {
+				t.Errorf("ServiceSID(%q) = %s, expected %s", tt.service, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseSIDSubAuthorityCap(t *testing.T) {
+	// 16 sub-authorities: one past SID_MAX_SUB_AUTHORITIES
+	data := []byte{0x01, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05}
+	for i := 0; i < 16; i++ {
+		data = append(data, byte(i), 0x00, 0x00, 0x00)
+	}
+
+	_, err := ParseSID(data)
+	if !errors.Is(err, ErrMalformedSID) {
+		t.Errorf("ParseSID error = %v, want ErrMalformedSID", err)
+	}
+}
+
+func TestSIDPrincipalKind(t *testing.T) {
+	tests := []struct {
+		sid  string
+		want string
+	}{
+		{"S-1-1-0", "Group"},                       // Everyone
+		{"S-1-5-11", "Group"},                      // Authenticated Users
+		{"S-1-5-32-544", "Group"},                  // BUILTIN\Administrators
+		{"S-1-5-18", "User"},                       // Local System
+		{"S-1-5-19", "User"},                       // Local Service
+		{"S-1-5-21-111-222-333-500", "User"},       // Administrator
+		{"S-1-5-21-111-222-333-512", "Group"},      // Domain Admins
+		{"S-1-5-21-111-222-333-519", "Group"},      // Enterprise Admins
+		{"S-1-5-21-111-222-333-1104", ""},          // ordinary domain RID
+		{TrustedInstallerSID, ""},                  // service SID
+		{"not-a-sid", ""},
+	}
+
+	for _, tt := range tests {
+		if got := SIDPrincipalKind(tt.sid); got != tt.want {
+			t.Errorf("SIDPrincipalKind(%q) = %q, want %q", tt.sid, got, tt.want)
+		}
+	}
+}
+
+func TestSIDToObjectID(t *testing.T) {
+	tests := []struct {
+		sid    string
+		domain string
+		want   string
+	}{
+		{"S-1-1-0", "CORP.COM", "CORP.COM-S-1-1-0"},                           // well-known
+		{"S-1-5-32-544", "CORP.COM", "CORP.COM-S-1-5-32-544"},                 // BUILTIN alias
+		{"S-1-5-21-111-222-333-1104", "CORP.COM", "S-1-5-21-111-222-333-1104"}, // domain SID unchanged
+		{"S-1-1-0", "", "S-1-1-0"},                                            // no domain configured
+		{"S-1-1-0", "corp.com", "CORP.COM-S-1-1-0"},                           // domain is upper-cased
+		{TrustedInstallerSID, "CORP.COM", "CORP.COM-" + TrustedInstallerSID},  // service SID
+	}
+
+	for _, tt := range tests {
+		if got := SIDToObjectID(tt.sid, tt.domain); got != tt.want {
+			t.Errorf("SIDToObjectID(%q, %q) = %q, want %q", tt.sid, tt.domain, got, tt.want)
+		}
+	}
+}
+
+func TestSIDDomainPart(t *testing.T) {
+	tests := []struct {
+		sid  string
+		want string
+	}{
+		{"S-1-5-21-111-222-333-1104", "S-1-5-21-111-222-333"},
+		{"S-1-5-21-111-222-333-500", "S-1-5-21-111-222-333"},
+		{"S-1-5-32-544", ""}, // BUILTIN, not domain-relative
+		{"S-1-1-0", ""},
+		{"not-a-sid", ""},
+	}
+
+	for _, tt := range tests {
+		if got := SIDDomainPart(tt.sid); got != tt.want {
+			t.Errorf("SIDDomainPart(%q) = %q, want %q", tt.sid, got, tt.want)
+		}
+	}
+}
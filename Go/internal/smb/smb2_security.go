@@ -1,36 +1,52 @@
-// Package smb provides SMB session management and security descriptor parsing.
-package smb
-
-import (
-	"github.com/medianexapp/go-smb2"
-)
-
-// Security information request flags
-const (
-	SecurityInfoOwner smb2.SecurityInformationRequestFlags = 0x00000001
-	SecurityInfoGroup smb2.SecurityInformationRequestFlags = 0x00000002
-	SecurityInfoDACL  smb2.SecurityInformationRequestFlags = 0x00000004
-	SecurityInfoSACL  smb2.SecurityInformationRequestFlags = 0x00000008
-)
-
-// QuerySecurityDescriptorLinked queries the security descriptor for a file or directory
-// using the medianexapp/go-smb2 fork which has native security descriptor support.
-// Returns nil, nil if the security descriptor cannot be retrieved (e.g., access denied).
-func QuerySecurityDescriptorLinked(share *smb2.Share, path string) ([]byte, error) {
-	if share == nil {
-		return nil, nil
-	}
-
-	// Use the native SecurityInfoRaw method from the medianexapp fork
-	// This queries OWNER | GROUP | DACL information
-	flags := SecurityInfoOwner | SecurityInfoGroup | SecurityInfoDACL
-
-	sdBytes, err := share.SecurityInfoRaw(path, flags)
-	if err != nil {
-		// Return nil for access denied or other errors - this is expected for some files
-		// The caller can still list and traverse directories even without READ_CONTROL permission
-		return nil, nil
-	}
-
-	return sdBytes, nil
-}
+// Package smb provides SMB session management and security descriptor parsing.
+package smb
+
+import (
+	"github.com/medianexapp/go-smb2"
+)
+
+// Security information request flags, mirroring the MS-SMB2 wire values.
+const (
+	SecurityInfoOwner smb2.SecurityInformationRequestFlags = 0x00000001
+	SecurityInfoGroup smb2.SecurityInformationRequestFlags = 0x00000002
+	SecurityInfoDACL  smb2.SecurityInformationRequestFlags = 0x00000004
+	SecurityInfoSACL  smb2.SecurityInformationRequestFlags = 0x00000008
+	SecurityInfoLabel smb2.SecurityInformationRequestFlags = 0x00000010
+)
+
+// securityQuerier is the minimal surface this package needs from the SMB
+// library to issue QUERY_INFO (SecInfo class) requests. Pinning the library
+// access behind this interface keeps it in one place: an upgrade of the
+// go-smb2 fork that changes or removes the method breaks compilation here
+// and in the version-skew tests, not at scattered call sites.
+type securityQuerier interface {
+	SecurityInfoRaw(path string, info smb2.SecurityInformationRequestFlags) ([]byte, error)
+}
+
+// Version-skew guard: the go-smb2 fork must keep satisfying the query
+// surface this package is written against.
+var _ securityQuerier = (*smb2.Share)(nil)
+
+// QuerySecurityDescriptor queries the security descriptor for a file or
+// directory on a mounted share via SMB2 QUERY_INFO (SecInfo class).
+// Returns nil, nil if the security descriptor cannot be retrieved (e.g.,
+// access denied).
+func QuerySecurityDescriptor(share securityQuerier, path string) ([]byte, error) {
+	if share == nil {
+		return nil, nil
+	}
+
+	// Query OWNER | GROUP | DACL | LABEL information — LABEL returns the
+	// mandatory integrity label ACE in the SACL field and, unlike full
+	// SACL access, only requires READ_CONTROL on the object.
+	flags := SecurityInfoOwner | SecurityInfoGroup | SecurityInfoDACL | SecurityInfoLabel
+
+	sdBytes, err := share.SecurityInfoRaw(path, flags)
+	if err != nil {
+		// Return nil for access denied or other errors - this is expected for some files
+		// The caller can still list and traverse directories even without READ_CONTROL permission
+		return nil, nil
+	}
+
+	return sdBytes, nil
+}
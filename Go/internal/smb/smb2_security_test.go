@@ -0,0 +1,88 @@
+package smb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/medianexapp/go-smb2"
+)
+
+type fakeSecurityQuerier struct {
+	data     []byte
+	err      error
+	gotPath  string
+	gotFlags smb2.SecurityInformationRequestFlags
+}
+
+func (f *fakeSecurityQuerier) SecurityInfoRaw(path string, info smb2.SecurityInformationRequestFlags) ([]byte, error) {
+	f.gotPath = path
+	f.gotFlags = info
+	return f.data, f.err
+}
+
+func TestQuerySecurityDescriptor(t *testing.T) {
+	sd := []byte{0x01, 0x00, 0x04, 0x80}
+	querier := &fakeSecurityQuerier{data: sd}
+
+	got, err := QuerySecurityDescriptor(querier, "Reports\\q3.xlsx")
+	if err != nil {
+		t.Fatalf("QuerySecurityDescriptor: %v", err)
+	}
+	if !bytes.Equal(got, sd) {
+		t.Errorf("sd = % x, want % x", got, sd)
+	}
+	if querier.gotPath != "Reports\\q3.xlsx" {
+		t.Errorf("path = %q, want Reports\\q3.xlsx", querier.gotPath)
+	}
+
+	want := SecurityInfoOwner | SecurityInfoGroup | SecurityInfoDACL | SecurityInfoLabel
+	if querier.gotFlags != want {
+		t.Errorf("flags = 0x%08x, want 0x%08x", querier.gotFlags, want)
+	}
+}
+
+func TestQuerySecurityDescriptorSwallowsErrors(t *testing.T) {
+	querier := &fakeSecurityQuerier{err: errors.New("access denied")}
+
+	got, err := QuerySecurityDescriptor(querier, "secret.txt")
+	if got != nil || err != nil {
+		t.Errorf("got (% x, %v), want (nil, nil) — traversal must continue without READ_CONTROL", got, err)
+	}
+}
+
+func TestQuerySecurityDescriptorNilQuerier(t *testing.T) {
+	if got, err := QuerySecurityDescriptor(nil, "x"); got != nil || err != nil {
+		t.Errorf("got (% x, %v), want (nil, nil)", got, err)
+	}
+}
+
+// Version-skew test: the flag values this package hardcodes must match both
+// the MS-SMB2 wire values and whatever the go-smb2 fork currently exports,
+// so a library upgrade that redefines them is caught here.
+func TestSecurityInfoFlagsMatchLibrary(t *testing.T) {
+	cases := []struct {
+		name string
+		ours smb2.SecurityInformationRequestFlags
+		lib  smb2.SecurityInformationRequestFlags
+		wire uint32
+	}{
+		{"owner", SecurityInfoOwner, smb2.OwnerSecurityInformation, 0x00000001},
+		{"group", SecurityInfoGroup, smb2.GroupSecurityInformation, 0x00000002},
+		{"dacl", SecurityInfoDACL, smb2.DACLSecurityInformation, 0x00000004},
+		{"sacl", SecurityInfoSACL, smb2.SACLSecurityInformation, 0x00000008},
+	}
+	for _, tc := range cases {
+		if tc.ours != tc.lib {
+			t.Errorf("%s: our flag 0x%08x != library flag 0x%08x", tc.name, tc.ours, tc.lib)
+		}
+		if uint32(tc.ours) != tc.wire {
+			t.Errorf("%s: our flag 0x%08x != MS-SMB2 wire value 0x%08x", tc.name, tc.ours, tc.wire)
+		}
+	}
+
+	// The fork exports no label constant; pin the wire value directly.
+	if uint32(SecurityInfoLabel) != 0x00000010 {
+		t.Errorf("label: our flag 0x%08x != MS-SMB2 wire value 0x00000010", SecurityInfoLabel)
+	}
+}
@@ -0,0 +1,122 @@
+// Package smbtest provides an in-process transport harness for exercising
+// SMB client code offline. It does not implement the SMB protocol: servers
+// operate at the TCP transport layer (black hole or replay of recorded
+// frames), which is enough to drive connection, timeout, and shutdown logic
+// in CI without SMB_TEST_* environment variables or a live Windows host.
+// Wire-accurate behavior remains covered by the integration tests in
+// internal/smb.
+package smbtest
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Server is a local TCP endpoint that stands in for an SMB host.
+type Server struct {
+	ln      net.Listener
+	handler func(net.Conn)
+
+	mu     sync.Mutex
+	conns  []net.Conn
+	closed bool
+}
+
+func newServer(handler func(net.Conn)) (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open test listener: %w", err)
+	}
+
+	s := &Server{ln: ln, handler: handler}
+	go s.acceptLoop()
+	return s, nil
+}
+
+// NewBlackhole starts a server that accepts connections and reads forever
+// without ever responding, emulating a host that is reachable but hung.
+func NewBlackhole() (*Server, error) {
+	return newServer(func(conn net.Conn) {
+		io.Copy(io.Discard, conn) //nolint:errcheck
+	})
+}
+
+// NewReplay starts a server that answers each received transport frame with
+// the next recorded response frame, in order. Frames are raw bytes as they
+// appear on the wire, including the 4-byte NetBIOS session framing expected
+// by direct-TCP SMB. Once the script is exhausted the connection degrades to
+// a black hole.
+func NewReplay(script [][]byte) (*Server, error) {
+	return newServer(func(conn net.Conn) {
+		for _, frame := range script {
+			if _, err := readFrame(conn); err != nil {
+				return
+			}
+			if _, err := conn.Write(frame); err != nil {
+				return
+			}
+		}
+		io.Copy(io.Discard, conn) //nolint:errcheck
+	})
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+
+		go s.handler(conn)
+	}
+}
+
+// Host returns the address clients should dial, always a loopback IP.
+func (s *Server) Host() string {
+	return s.ln.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the listening TCP port.
+func (s *Server) Port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+// Close stops accepting and tears down every live connection, unblocking any
+// client stuck in a read against the server.
+func (s *Server) Close() {
+	s.ln.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+// readFrame reads one NetBIOS-framed message (4-byte header carrying a
+// 24-bit length, then the payload).
+func readFrame(conn net.Conn) ([]byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(conn, hdr[:]); err != nil {
+		return nil, err
+	}
+	size := int(hdr[1])<<16 | int(hdr[2])<<8 | int(hdr[3])
+	body := make([]byte, size)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
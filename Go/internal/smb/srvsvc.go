@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
+	"strings"
 	"unicode/utf16"
 
 	"github.com/medianexapp/go-smb2"
@@ -22,15 +24,37 @@ const (
 	// RPC packet types
 	rpcRequest  = 0
 	rpcResponse = 2
+	rpcFault    = 3
 	rpcBind     = 11
 	rpcBindAck  = 12
 
+	// Fragment flags
+	rpcFlagFirstFrag = 0x01
+	rpcFlagLastFrag  = 0x02
+
+	// Sizes of the common PDU header and the request/response header
+	// (common header + alloc hint + context ID + opnum/cancel count).
+	rpcCommonHeaderSize   = 16
+	rpcResponseHeaderSize = 24
+
+	// maxStubSize caps the reassembled response stub so a malformed or
+	// hostile server cannot make us allocate unbounded memory.
+	maxStubSize = 16 << 20
+
 	// SRVSVC UUID: 4b324fc8-1670-01d3-1278-5a47bf6ee188
-	// NetrShareGetInfo operation number
-	opNetrShareGetInfo = 16
+	// Operation numbers
+	opNetrSessionEnum   = 12
+	opNetrShareEnum     = 15
+	opNetrShareGetInfo  = 16
+	opNetrServerGetInfo = 21
 
 	// Share info levels
+	shareInfoLevel1   = 1
 	shareInfoLevel502 = 502
+
+	// Server and session info levels
+	serverInfoLevel101 = 101
+	sessionInfoLevel10 = 10
 )
 
 // SRVSVCClient provides access to SRVSVC RPC for share information.
@@ -91,18 +115,29 @@ func (c *SRVSVCClient) bind() error {
 		0x12, 0x78, 0x5a, 0x47, 0xbf, 0x6e, 0xe1, 0x88,
 	}
 
+	if err := bindPipe(c.pipe, c.callID, uuid, 3, 0); err != nil {
+		return err
+	}
+
+	c.callID++
+	return nil
+}
+
+// bindPipe sends an RPC bind for the given abstract interface over pipe and
+// verifies the bind ack.
+func bindPipe(pipe *smb2.File, callID uint32, abstractUUID []byte, verMajor, verMinor uint16) error {
 	// Build bind request
 	var buf bytes.Buffer
 
 	// RPC header
-	buf.WriteByte(rpcVersionMajor)          // Version major
-	buf.WriteByte(rpcVersionMinor)          // Version minor
-	buf.WriteByte(rpcBind)                  // Packet type
-	buf.WriteByte(0x03)                     // Packet flags (first + last frag)
+	buf.WriteByte(rpcVersionMajor)                    // Version major
+	buf.WriteByte(rpcVersionMinor)                    // Version minor
+	buf.WriteByte(rpcBind)                            // Packet type
+	buf.WriteByte(rpcFlagFirstFrag | rpcFlagLastFrag) // Packet flags
 	binary.Write(&buf, binary.LittleEndian, uint32(0x10000000)) // Data representation
 	binary.Write(&buf, binary.LittleEndian, uint16(72))         // Frag length
 	binary.Write(&buf, binary.LittleEndian, uint16(0))          // Auth length
-	binary.Write(&buf, binary.LittleEndian, c.callID)           // Call ID
+	binary.Write(&buf, binary.LittleEndian, callID)             // Call ID
 
 	// Bind specific fields
 	binary.Write(&buf, binary.LittleEndian, uint16(4280)) // Max xmit frag
@@ -114,10 +149,10 @@ func (c *SRVSVCClient) bind() error {
 	binary.Write(&buf, binary.LittleEndian, uint16(0)) // Context ID
 	binary.Write(&buf, binary.LittleEndian, uint16(1)) // Num trans items
 
-	// Abstract syntax (SRVSVC UUID)
-	buf.Write(uuid)
-	binary.Write(&buf, binary.LittleEndian, uint16(3)) // Version major
-	binary.Write(&buf, binary.LittleEndian, uint16(0)) // Version minor
+	// Abstract syntax
+	buf.Write(abstractUUID)
+	binary.Write(&buf, binary.LittleEndian, verMajor) // Version major
+	binary.Write(&buf, binary.LittleEndian, verMinor) // Version minor
 
 	// Transfer syntax (NDR)
 	ndrUUID := []byte{
@@ -128,30 +163,283 @@ func (c *SRVSVCClient) bind() error {
 	binary.Write(&buf, binary.LittleEndian, uint32(2)) // NDR version
 
 	// Send bind request
-	if _, err := c.pipe.Write(buf.Bytes()); err != nil {
+	if _, err := pipe.Write(buf.Bytes()); err != nil {
 		return fmt.Errorf("failed to send bind request: %w", err)
 	}
 
 	// Read bind response
-	response := make([]byte, 4280)
-	n, err := c.pipe.Read(response)
+	response, err := readPDU(pipe)
 	if err != nil {
 		return fmt.Errorf("failed to read bind response: %w", err)
 	}
 
-	if n < 24 {
-		return fmt.Errorf("bind response too short")
-	}
-
 	// Check packet type
 	if response[2] != rpcBindAck {
 		return fmt.Errorf("unexpected response type: %d", response[2])
 	}
 
-	c.callID++
 	return nil
 }
 
+// readPDU reads exactly one RPC PDU from r: the 16-byte common header first,
+// then the rest of the fragment as announced by the header's frag length.
+// Reading by fragment length rather than a single fixed-size Read keeps PDUs
+// intact when the pipe returns them in arbitrary chunks.
+func readPDU(r io.Reader) ([]byte, error) {
+	header := make([]byte, rpcCommonHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read PDU header: %w", err)
+	}
+
+	if header[0] != rpcVersionMajor {
+		return nil, fmt.Errorf("unexpected RPC version: %d.%d", header[0], header[1])
+	}
+
+	fragLen := binary.LittleEndian.Uint16(header[8:10])
+	if int(fragLen) < rpcCommonHeaderSize {
+		return nil, fmt.Errorf("invalid fragment length: %d", fragLen)
+	}
+
+	pdu := make([]byte, fragLen)
+	copy(pdu, header)
+	if _, err := io.ReadFull(r, pdu[rpcCommonHeaderSize:]); err != nil {
+		return nil, fmt.Errorf("failed to read PDU body: %w", err)
+	}
+	return pdu, nil
+}
+
+// readResponseStub reads response PDUs from r and reassembles their stub data
+// until the last-fragment flag is seen. Responses larger than the server's
+// max fragment size (large SDs, long remarks) arrive as multiple fragments;
+// the first fragment's alloc hint is used to pre-size the stub buffer.
+func readResponseStub(r io.Reader) ([]byte, error) {
+	var stub bytes.Buffer
+
+	for first := true; ; first = false {
+		pdu, err := readPDU(r)
+		if err != nil {
+			return nil, err
+		}
+
+		switch pdu[2] {
+		case rpcResponse:
+		case rpcFault:
+			if len(pdu) >= rpcResponseHeaderSize+4 {
+				return nil, fmt.Errorf("RPC fault: 0x%08x", binary.LittleEndian.Uint32(pdu[rpcResponseHeaderSize:]))
+			}
+			return nil, fmt.Errorf("RPC fault")
+		default:
+			return nil, fmt.Errorf("unexpected response type: %d", pdu[2])
+		}
+
+		if len(pdu) < rpcResponseHeaderSize {
+			return nil, fmt.Errorf("response fragment too short")
+		}
+		if first && pdu[3]&rpcFlagFirstFrag == 0 {
+			return nil, fmt.Errorf("first response fragment missing first-frag flag")
+		}
+
+		body := pdu[rpcResponseHeaderSize:]
+
+		// Strip the auth trailer (8-byte sec_trailer header + auth value)
+		// if present; the bind is performed without RPC-level auth, so this
+		// is normally zero.
+		if authLen := int(binary.LittleEndian.Uint16(pdu[10:12])); authLen > 0 {
+			if authLen+8 > len(body) {
+				return nil, fmt.Errorf("invalid auth trailer length: %d", authLen)
+			}
+			body = body[:len(body)-authLen-8]
+		}
+
+		if first {
+			if hint := binary.LittleEndian.Uint32(pdu[16:20]); hint > 0 && hint <= maxStubSize {
+				stub.Grow(int(hint))
+			}
+		}
+
+		if stub.Len()+len(body) > maxStubSize {
+			return nil, fmt.Errorf("response stub exceeds %d bytes", maxStubSize)
+		}
+		stub.Write(body)
+
+		if pdu[3]&rpcFlagLastFrag != 0 {
+			return stub.Bytes(), nil
+		}
+	}
+}
+
+// EnumShareInfo holds one SHARE_INFO_1 entry from NetrShareEnum.
+type EnumShareInfo struct {
+	Name    string
+	Type    uint32
+	Comment string
+}
+
+// EnumShares enumerates all shares on the server via NetrShareEnum at info
+// level 1, returning names along with their raw STYPE flags and remarks.
+// Unlike go-smb2's ListSharenames (which uses level 0), this exposes the
+// share type so special/temporary/cluster shares can be identified.
+func (c *SRVSVCClient) EnumShares(serverName string) ([]EnumShareInfo, error) {
+	var buf bytes.Buffer
+
+	// ServerName: unique pointer to wide string
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020000)) // Referent ID
+	writeNDRWString(&buf, serverName)
+
+	// InfoStruct: SHARE_ENUM_STRUCT { Level, union tag, pointer to container }
+	binary.Write(&buf, binary.LittleEndian, uint32(shareInfoLevel1)) // Level
+	binary.Write(&buf, binary.LittleEndian, uint32(shareInfoLevel1)) // Union tag
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020004))      // Referent ID (container)
+	binary.Write(&buf, binary.LittleEndian, uint32(0))               // EntriesRead
+	binary.Write(&buf, binary.LittleEndian, uint32(0))               // Null buffer pointer
+
+	// PreferedMaximumLength: no limit
+	binary.Write(&buf, binary.LittleEndian, uint32(0xFFFFFFFF))
+
+	// ResumeHandle: unique pointer to zero
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020008)) // Referent ID
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+
+	request := c.buildRPCRequest(opNetrShareEnum, buf.Bytes())
+
+	if _, err := c.pipe.Write(request); err != nil {
+		return nil, fmt.Errorf("failed to send NetrShareEnum request: %w", err)
+	}
+
+	stub, err := readResponseStub(c.pipe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NetrShareEnum response: %w", err)
+	}
+
+	return parseShareEnumResponse(stub)
+}
+
+// writeNDRWString writes a conformant varying wide string (with null
+// terminator) followed by 4-byte alignment padding.
+func writeNDRWString(buf *bytes.Buffer, s string) {
+	encoded := utf16.Encode([]rune(s + "\x00"))
+	binary.Write(buf, binary.LittleEndian, uint32(len(encoded))) // Max count
+	binary.Write(buf, binary.LittleEndian, uint32(0))            // Offset
+	binary.Write(buf, binary.LittleEndian, uint32(len(encoded))) // Actual count
+	for _, c := range encoded {
+		binary.Write(buf, binary.LittleEndian, c)
+	}
+	for buf.Len()%4 != 0 {
+		buf.WriteByte(0)
+	}
+}
+
+// parseShareEnumResponse parses a reassembled NetrShareEnum response stub
+// into SHARE_INFO_1 entries.
+func parseShareEnumResponse(payload []byte) ([]EnumShareInfo, error) {
+	if len(payload) < 28 {
+		return nil, fmt.Errorf("payload too short")
+	}
+
+	// Check the NetrShareEnum return code at the end of the stub.
+	// ERROR_MORE_DATA (234) still carries a valid partial buffer.
+	returnCode := binary.LittleEndian.Uint32(payload[len(payload)-4:])
+	if returnCode != 0 && returnCode != 234 {
+		return nil, fmt.Errorf("NetrShareEnum failed with code: 0x%08x", returnCode)
+	}
+
+	// Stub layout: Level, union tag, container pointer, EntriesRead,
+	// array pointer, array max count, then EntriesRead * SHARE_INFO_1
+	// fixed parts followed by the deferred string data.
+	offset := 12
+	entriesRead := binary.LittleEndian.Uint32(payload[offset : offset+4])
+	offset += 4
+
+	arrayPtr := binary.LittleEndian.Uint32(payload[offset : offset+4])
+	offset += 4
+	if arrayPtr == 0 || entriesRead == 0 {
+		return nil, nil
+	}
+	offset += 4 // array max count
+
+	// Sanity cap: each fixed part is 12 bytes
+	if entriesRead > uint32(len(payload))/12 {
+		return nil, fmt.Errorf("implausible entry count: %d", entriesRead)
+	}
+
+	type fixedPart struct {
+		namePtr    uint32
+		shareType  uint32
+		commentPtr uint32
+	}
+
+	fixed := make([]fixedPart, 0, entriesRead)
+	for i := uint32(0); i < entriesRead; i++ {
+		if offset+12 > len(payload) {
+			return nil, fmt.Errorf("truncated SHARE_INFO_1 array")
+		}
+		fixed = append(fixed, fixedPart{
+			namePtr:    binary.LittleEndian.Uint32(payload[offset : offset+4]),
+			shareType:  binary.LittleEndian.Uint32(payload[offset+4 : offset+8]),
+			commentPtr: binary.LittleEndian.Uint32(payload[offset+8 : offset+12]),
+		})
+		offset += 12
+	}
+
+	// Deferred string data follows, in pointer order
+	shares := make([]EnumShareInfo, 0, entriesRead)
+	for _, fp := range fixed {
+		info := EnumShareInfo{Type: fp.shareType}
+
+		if fp.namePtr != 0 {
+			s, next, err := readNDRWString(payload, offset)
+			if err != nil {
+				return nil, err
+			}
+			info.Name = s
+			offset = next
+		}
+		if fp.commentPtr != 0 {
+			s, next, err := readNDRWString(payload, offset)
+			if err != nil {
+				return nil, err
+			}
+			info.Comment = s
+			offset = next
+		}
+
+		shares = append(shares, info)
+	}
+
+	return shares, nil
+}
+
+// readNDRWString reads a conformant varying wide string at offset and
+// returns the decoded string (without null terminator) and the offset of
+// the next 4-byte aligned element.
+func readNDRWString(data []byte, offset int) (string, int, error) {
+	if offset+12 > len(data) {
+		return "", 0, fmt.Errorf("truncated NDR string header")
+	}
+	actualCount := binary.LittleEndian.Uint32(data[offset+8 : offset+12])
+	offset += 12
+
+	byteLen := int(actualCount) * 2
+	if byteLen < 0 || offset+byteLen > len(data) {
+		return "", 0, fmt.Errorf("truncated NDR string data")
+	}
+
+	chars := make([]uint16, actualCount)
+	for i := range chars {
+		chars[i] = binary.LittleEndian.Uint16(data[offset+i*2 : offset+i*2+2])
+	}
+	offset += byteLen
+
+	// Align to 4 bytes
+	if offset%4 != 0 {
+		offset += 4 - offset%4
+	}
+
+	s := string(utf16.Decode(chars))
+	s = strings.TrimRight(s, "\x00")
+	return s, offset, nil
+}
+
 // GetShareSecurityDescriptor retrieves the security descriptor for a share.
 func (c *SRVSVCClient) GetShareSecurityDescriptor(serverName, shareName string) ([]byte, error) {
 	// Build NetrShareGetInfo request
@@ -194,59 +482,53 @@ func (c *SRVSVCClient) GetShareSecurityDescriptor(serverName, shareName string)
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
-	// Read response
-	response := make([]byte, 65536)
-	n, err := c.pipe.Read(response)
+	// Read response, reassembling fragments (large SDs routinely exceed the
+	// negotiated fragment size)
+	stub, err := readResponseStub(c.pipe)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse response
-	return c.parseShareInfoResponse(response[:n])
+	return c.parseShareInfoResponse(stub)
 }
 
 // buildRPCRequest builds an RPC request packet.
 func (c *SRVSVCClient) buildRPCRequest(opNum uint16, data []byte) []byte {
+	request := buildRequestPDU(c.callID, c.contextID, opNum, data)
+	c.callID++
+	return request
+}
+
+// buildRequestPDU builds an RPC request packet for any bound pipe client.
+func buildRequestPDU(callID uint32, contextID uint16, opNum uint16, data []byte) []byte {
 	var buf bytes.Buffer
 
-	fragLen := uint16(24 + len(data))
+	fragLen := uint16(rpcResponseHeaderSize + len(data))
 
 	// RPC header
-	buf.WriteByte(rpcVersionMajor)          // Version major
-	buf.WriteByte(rpcVersionMinor)          // Version minor
-	buf.WriteByte(rpcRequest)               // Packet type
-	buf.WriteByte(0x03)                     // Packet flags (first + last frag)
+	buf.WriteByte(rpcVersionMajor)                    // Version major
+	buf.WriteByte(rpcVersionMinor)                    // Version minor
+	buf.WriteByte(rpcRequest)                         // Packet type
+	buf.WriteByte(rpcFlagFirstFrag | rpcFlagLastFrag) // Packet flags
 	binary.Write(&buf, binary.LittleEndian, uint32(0x10000000)) // Data representation
 	binary.Write(&buf, binary.LittleEndian, fragLen)            // Frag length
 	binary.Write(&buf, binary.LittleEndian, uint16(0))          // Auth length
-	binary.Write(&buf, binary.LittleEndian, c.callID)           // Call ID
+	binary.Write(&buf, binary.LittleEndian, callID)             // Call ID
 
 	// Request specific fields
 	binary.Write(&buf, binary.LittleEndian, uint32(len(data))) // Alloc hint
-	binary.Write(&buf, binary.LittleEndian, c.contextID)       // Context ID
+	binary.Write(&buf, binary.LittleEndian, contextID)         // Context ID
 	binary.Write(&buf, binary.LittleEndian, opNum)             // Op num
 
 	// Data
 	buf.Write(data)
 
-	c.callID++
 	return buf.Bytes()
 }
 
-// parseShareInfoResponse parses the NetrShareGetInfo response.
-func (c *SRVSVCClient) parseShareInfoResponse(data []byte) ([]byte, error) {
-	if len(data) < 24 {
-		return nil, fmt.Errorf("response too short")
-	}
-
-	// Check packet type
-	if data[2] != rpcResponse {
-		return nil, fmt.Errorf("unexpected response type: %d", data[2])
-	}
-
-	// Skip RPC header (24 bytes)
-	payload := data[24:]
-
+// parseShareInfoResponse parses a reassembled NetrShareGetInfo response stub.
+func (c *SRVSVCClient) parseShareInfoResponse(payload []byte) ([]byte, error) {
 	if len(payload) < 8 {
 		return nil, fmt.Errorf("payload too short")
 	}
@@ -365,3 +647,260 @@ func (c *SRVSVCClient) calculateSDLength(data []byte) int {
 
 	return int(maxOffset) + 64 // Default estimate
 }
+
+// ServerInfo101 holds a SERVER_INFO_101 structure from NetrServerGetInfo.
+type ServerInfo101 struct {
+	PlatformID   uint32
+	Name         string
+	VersionMajor uint32
+	VersionMinor uint32
+	Type         uint32
+	Comment      string
+}
+
+// GetServerInfo retrieves server platform, version and role information via
+// NetrServerGetInfo at info level 101.
+func (c *SRVSVCClient) GetServerInfo(serverName string) (*ServerInfo101, error) {
+	var buf bytes.Buffer
+
+	// ServerName: unique pointer to wide string
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020000)) // Referent ID
+	writeNDRWString(&buf, serverName)
+
+	// Level
+	binary.Write(&buf, binary.LittleEndian, uint32(serverInfoLevel101))
+
+	request := c.buildRPCRequest(opNetrServerGetInfo, buf.Bytes())
+
+	if _, err := c.pipe.Write(request); err != nil {
+		return nil, fmt.Errorf("failed to send NetrServerGetInfo request: %w", err)
+	}
+
+	stub, err := readResponseStub(c.pipe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NetrServerGetInfo response: %w", err)
+	}
+
+	return parseServerInfoResponse(stub)
+}
+
+// parseServerInfoResponse parses a reassembled NetrServerGetInfo response
+// stub into a SERVER_INFO_101 structure.
+func parseServerInfoResponse(payload []byte) (*ServerInfo101, error) {
+	if len(payload) < 36 {
+		return nil, fmt.Errorf("payload too short")
+	}
+
+	returnCode := binary.LittleEndian.Uint32(payload[len(payload)-4:])
+	if returnCode != 0 {
+		return nil, fmt.Errorf("NetrServerGetInfo failed with code: 0x%08x", returnCode)
+	}
+
+	// Stub layout: union tag, pointer to SERVER_INFO_101, then the fixed
+	// part (platform ID, name pointer, version major/minor, type, comment
+	// pointer) followed by the deferred string data.
+	infoPtr := binary.LittleEndian.Uint32(payload[4:8])
+	if infoPtr == 0 {
+		return nil, fmt.Errorf("NetrServerGetInfo returned a null info pointer")
+	}
+
+	info := &ServerInfo101{
+		PlatformID:   binary.LittleEndian.Uint32(payload[8:12]),
+		VersionMajor: binary.LittleEndian.Uint32(payload[16:20]),
+		VersionMinor: binary.LittleEndian.Uint32(payload[20:24]),
+		Type:         binary.LittleEndian.Uint32(payload[24:28]),
+	}
+	namePtr := binary.LittleEndian.Uint32(payload[12:16])
+	commentPtr := binary.LittleEndian.Uint32(payload[28:32])
+
+	offset := 32
+	if namePtr != 0 {
+		s, next, err := readNDRWString(payload, offset)
+		if err != nil {
+			return nil, err
+		}
+		info.Name = s
+		offset = next
+	}
+	if commentPtr != 0 {
+		s, _, err := readNDRWString(payload, offset)
+		if err != nil {
+			return nil, err
+		}
+		info.Comment = s
+	}
+
+	return info, nil
+}
+
+// SV_TYPE flags of interest from the SERVER_INFO_101 type field.
+var serverTypeRoleNames = []struct {
+	flag uint32
+	name string
+}{
+	{0x00000004, "SQL Server"},
+	{0x00000008, "Domain Controller"},
+	{0x00000010, "Backup Domain Controller"},
+	{0x00000020, "Time Source"},
+	{0x00000200, "Print Server"},
+	{0x00000400, "Dial-in Server"},
+	{0x02000000, "Terminal Server"},
+	{svTypeClusterVSNT, "Cluster Virtual Server"},
+	{0x10000000, "Cluster Node"},
+}
+
+// svTypeClusterVSNT (SV_TYPE_CLUSTER_VS_NT) marks a failover cluster virtual
+// server: the answering name is a cluster resource that can move between
+// nodes, not a physical machine.
+const svTypeClusterVSNT = 0x04000000
+
+// IsClusterVirtualServer reports whether a SERVER_INFO_101 type value
+// identifies the answering server as a failover cluster virtual server.
+func IsClusterVirtualServer(serverType uint32) bool {
+	return serverType&svTypeClusterVSNT != 0
+}
+
+// ServerTypeRoles decodes the interesting role flags from a SERVER_INFO_101
+// type value into human-readable names.
+func ServerTypeRoles(serverType uint32) []string {
+	var roles []string
+	for _, r := range serverTypeRoleNames {
+		if serverType&r.flag != 0 {
+			roles = append(roles, r.name)
+		}
+	}
+	return roles
+}
+
+// SessionInfo10 holds one SESSION_INFO_10 entry from NetrSessionEnum.
+type SessionInfo10 struct {
+	ClientName string
+	UserName   string
+	Time       uint32
+	IdleTime   uint32
+}
+
+// EnumSessions enumerates active sessions on the server via NetrSessionEnum
+// at info level 10, surfacing which accounts are connected from where. Most
+// servers restrict this to administrators, so callers should treat failures
+// as expected.
+func (c *SRVSVCClient) EnumSessions(serverName string) ([]SessionInfo10, error) {
+	var buf bytes.Buffer
+
+	// ServerName: unique pointer to wide string
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020000)) // Referent ID
+	writeNDRWString(&buf, serverName)
+
+	// ClientName and UserName: null pointers (no filtering)
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+
+	// InfoStruct: SESSION_ENUM_STRUCT { Level, union tag, pointer to container }
+	binary.Write(&buf, binary.LittleEndian, uint32(sessionInfoLevel10)) // Level
+	binary.Write(&buf, binary.LittleEndian, uint32(sessionInfoLevel10)) // Union tag
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020004))         // Referent ID (container)
+	binary.Write(&buf, binary.LittleEndian, uint32(0))                  // EntriesRead
+	binary.Write(&buf, binary.LittleEndian, uint32(0))                  // Null buffer pointer
+
+	// PreferedMaximumLength: no limit
+	binary.Write(&buf, binary.LittleEndian, uint32(0xFFFFFFFF))
+
+	// ResumeHandle: unique pointer to zero
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020008)) // Referent ID
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+
+	request := c.buildRPCRequest(opNetrSessionEnum, buf.Bytes())
+
+	if _, err := c.pipe.Write(request); err != nil {
+		return nil, fmt.Errorf("failed to send NetrSessionEnum request: %w", err)
+	}
+
+	stub, err := readResponseStub(c.pipe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NetrSessionEnum response: %w", err)
+	}
+
+	return parseSessionEnumResponse(stub)
+}
+
+// parseSessionEnumResponse parses a reassembled NetrSessionEnum response
+// stub into SESSION_INFO_10 entries.
+func parseSessionEnumResponse(payload []byte) ([]SessionInfo10, error) {
+	if len(payload) < 28 {
+		return nil, fmt.Errorf("payload too short")
+	}
+
+	// ERROR_MORE_DATA (234) still carries a valid partial buffer.
+	returnCode := binary.LittleEndian.Uint32(payload[len(payload)-4:])
+	if returnCode != 0 && returnCode != 234 {
+		return nil, fmt.Errorf("NetrSessionEnum failed with code: 0x%08x", returnCode)
+	}
+
+	// Stub layout mirrors NetrShareEnum: Level, union tag, container
+	// pointer, EntriesRead, array pointer, array max count, then
+	// EntriesRead * SESSION_INFO_10 fixed parts followed by the deferred
+	// string data.
+	offset := 12
+	entriesRead := binary.LittleEndian.Uint32(payload[offset : offset+4])
+	offset += 4
+
+	arrayPtr := binary.LittleEndian.Uint32(payload[offset : offset+4])
+	offset += 4
+	if arrayPtr == 0 || entriesRead == 0 {
+		return nil, nil
+	}
+	offset += 4 // array max count
+
+	// Sanity cap: each fixed part is 16 bytes
+	if entriesRead > uint32(len(payload))/16 {
+		return nil, fmt.Errorf("implausible entry count: %d", entriesRead)
+	}
+
+	type fixedPart struct {
+		clientPtr uint32
+		userPtr   uint32
+		time      uint32
+		idleTime  uint32
+	}
+
+	fixed := make([]fixedPart, 0, entriesRead)
+	for i := uint32(0); i < entriesRead; i++ {
+		if offset+16 > len(payload) {
+			return nil, fmt.Errorf("truncated SESSION_INFO_10 array")
+		}
+		fixed = append(fixed, fixedPart{
+			clientPtr: binary.LittleEndian.Uint32(payload[offset : offset+4]),
+			userPtr:   binary.LittleEndian.Uint32(payload[offset+4 : offset+8]),
+			time:      binary.LittleEndian.Uint32(payload[offset+8 : offset+12]),
+			idleTime:  binary.LittleEndian.Uint32(payload[offset+12 : offset+16]),
+		})
+		offset += 16
+	}
+
+	// Deferred string data follows, in pointer order
+	sessions := make([]SessionInfo10, 0, entriesRead)
+	for _, fp := range fixed {
+		info := SessionInfo10{Time: fp.time, IdleTime: fp.idleTime}
+
+		if fp.clientPtr != 0 {
+			s, next, err := readNDRWString(payload, offset)
+			if err != nil {
+				return nil, err
+			}
+			info.ClientName = s
+			offset = next
+		}
+		if fp.userPtr != 0 {
+			s, next, err := readNDRWString(payload, offset)
+			if err != nil {
+				return nil, err
+			}
+			info.UserName = s
+			offset = next
+		}
+
+		sessions = append(sessions, info)
+	}
+
+	return sessions, nil
+}
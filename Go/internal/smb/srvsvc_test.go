@@ -0,0 +1,220 @@
+package smb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// buildResponseFragment assembles one RPC response PDU around the given stub
+// data, mirroring the wire layout produced by Windows servers.
+func buildResponseFragment(t *testing.T, packetType byte, flags byte, allocHint uint32, stub []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.WriteByte(rpcVersionMajor)
+	buf.WriteByte(rpcVersionMinor)
+	buf.WriteByte(packetType)
+	buf.WriteByte(flags)
+	binary.Write(&buf, binary.LittleEndian, uint32(0x10000000))                      // Data representation
+	binary.Write(&buf, binary.LittleEndian, uint16(rpcResponseHeaderSize+len(stub))) // Frag length
+	binary.Write(&buf, binary.LittleEndian, uint16(0))                               // Auth length
+	binary.Write(&buf, binary.LittleEndian, uint32(1))                               // Call ID
+	binary.Write(&buf, binary.LittleEndian, allocHint)                               // Alloc hint
+	binary.Write(&buf, binary.LittleEndian, uint16(0))                               // Context ID
+	buf.WriteByte(0)                                                                 // Cancel count
+	buf.WriteByte(0)                                                                 // Reserved
+	buf.Write(stub)
+	return buf.Bytes()
+}
+
+func TestReadResponseStubSingleFragment(t *testing.T) {
+	stub := []byte{0xde, 0xad, 0xbe, 0xef, 0x01, 0x02, 0x03, 0x04}
+	pdu := buildResponseFragment(t, rpcResponse, rpcFlagFirstFrag|rpcFlagLastFrag, uint32(len(stub)), stub)
+
+	got, err := readResponseStub(bytes.NewReader(pdu))
+	if err != nil {
+		t.Fatalf("readResponseStub: %v", err)
+	}
+	if !bytes.Equal(got, stub) {
+		t.Errorf("stub = % x, want % x", got, stub)
+	}
+}
+
+func TestReadResponseStubMultiFragment(t *testing.T) {
+	// A large response split over three fragments, as a server does when the
+	// stub exceeds the negotiated fragment size.
+	part1 := bytes.Repeat([]byte{0xaa}, 100)
+	part2 := bytes.Repeat([]byte{0xbb}, 100)
+	part3 := bytes.Repeat([]byte{0xcc}, 50)
+	total := uint32(len(part1) + len(part2) + len(part3))
+
+	var wire bytes.Buffer
+	wire.Write(buildResponseFragment(t, rpcResponse, rpcFlagFirstFrag, total, part1))
+	wire.Write(buildResponseFragment(t, rpcResponse, 0, total, part2))
+	wire.Write(buildResponseFragment(t, rpcResponse, rpcFlagLastFrag, total, part3))
+
+	got, err := readResponseStub(&wire)
+	if err != nil {
+		t.Fatalf("readResponseStub: %v", err)
+	}
+
+	want := append(append(append([]byte{}, part1...), part2...), part3...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("reassembled stub has length %d, want %d", len(got), len(want))
+	}
+}
+
+func TestReadResponseStubFault(t *testing.T) {
+	// Fault PDU carrying nca_s_fault_access_denied (0x00000005)
+	fault := buildResponseFragment(t, rpcFault, rpcFlagFirstFrag|rpcFlagLastFrag, 4, []byte{0x05, 0x00, 0x00, 0x00})
+
+	_, err := readResponseStub(bytes.NewReader(fault))
+	if err == nil {
+		t.Fatal("readResponseStub accepted a fault PDU")
+	}
+	if !strings.Contains(err.Error(), "0x00000005") {
+		t.Errorf("fault error = %q, want it to carry the fault code", err)
+	}
+}
+
+func TestReadResponseStubTruncated(t *testing.T) {
+	stub := bytes.Repeat([]byte{0x11}, 64)
+	pdu := buildResponseFragment(t, rpcResponse, rpcFlagFirstFrag|rpcFlagLastFrag, uint32(len(stub)), stub)
+
+	// Cut the PDU short of its announced fragment length
+	if _, err := readResponseStub(bytes.NewReader(pdu[:len(pdu)-10])); err == nil {
+		t.Error("readResponseStub accepted a truncated fragment")
+	}
+}
+
+func TestReadResponseStubRejectsMissingFirstFrag(t *testing.T) {
+	pdu := buildResponseFragment(t, rpcResponse, rpcFlagLastFrag, 0, []byte{0x00})
+
+	if _, err := readResponseStub(bytes.NewReader(pdu)); err == nil {
+		t.Error("readResponseStub accepted a response starting mid-stream")
+	}
+}
+
+func TestParseShareEnumResponseStub(t *testing.T) {
+	// NetrShareEnum level-1 stub containing one share, built field by field
+	// to match the NDR layout the parser expects.
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(shareInfoLevel1)) // Level
+	binary.Write(&stub, binary.LittleEndian, uint32(shareInfoLevel1)) // Union tag
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020000))      // Container pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(1))               // EntriesRead
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020004))      // Array pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(1))               // Array max count
+
+	// SHARE_INFO_1 fixed part
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020008)) // Name pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // STYPE_DISKTREE
+	binary.Write(&stub, binary.LittleEndian, uint32(0x0002000c)) // Comment pointer
+
+	// Deferred string data
+	writeNDRWString(&stub, "Finance")
+	writeNDRWString(&stub, "Quarterly reports")
+
+	binary.Write(&stub, binary.LittleEndian, uint32(1))          // TotalEntries
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020010)) // ResumeHandle pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // ResumeHandle
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // Return code
+
+	shares, err := parseShareEnumResponse(stub.Bytes())
+	if err != nil {
+		t.Fatalf("parseShareEnumResponse: %v", err)
+	}
+	if len(shares) != 1 {
+		t.Fatalf("got %d shares, want 1", len(shares))
+	}
+	if shares[0].Name != "Finance" || shares[0].Comment != "Quarterly reports" || shares[0].Type != 0 {
+		t.Errorf("share = %+v, want Finance/Quarterly reports/type 0", shares[0])
+	}
+}
+
+func TestParseServerInfoResponseStub(t *testing.T) {
+	// NetrServerGetInfo level-101 stub for a Windows Server acting as a
+	// domain controller (SV_TYPE_DOMAIN_CTRL | SV_TYPE_NT)
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(serverInfoLevel101)) // Union tag
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020000))         // Info pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(500))                // PlatformID (NT)
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020004))         // Name pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(10))                 // Version major
+	binary.Write(&stub, binary.LittleEndian, uint32(0))                  // Version minor
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00001008))         // Type
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020008))         // Comment pointer
+	writeNDRWString(&stub, "DC01")
+	writeNDRWString(&stub, "Primary DC")
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // Return code
+
+	info, err := parseServerInfoResponse(stub.Bytes())
+	if err != nil {
+		t.Fatalf("parseServerInfoResponse: %v", err)
+	}
+	if info.Name != "DC01" || info.Comment != "Primary DC" {
+		t.Errorf("info = %+v, want name DC01 and comment Primary DC", info)
+	}
+	if info.VersionMajor != 10 || info.VersionMinor != 0 {
+		t.Errorf("version = %d.%d, want 10.0", info.VersionMajor, info.VersionMinor)
+	}
+
+	roles := ServerTypeRoles(info.Type)
+	if len(roles) != 1 || roles[0] != "Domain Controller" {
+		t.Errorf("roles = %v, want [Domain Controller]", roles)
+	}
+}
+
+func TestParseSessionEnumResponseStub(t *testing.T) {
+	// NetrSessionEnum level-10 stub containing one session
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(sessionInfoLevel10)) // Level
+	binary.Write(&stub, binary.LittleEndian, uint32(sessionInfoLevel10)) // Union tag
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020000))         // Container pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(1))                  // EntriesRead
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020004))         // Array pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(1))                  // Array max count
+
+	// SESSION_INFO_10 fixed part
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020008)) // Client name pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(0x0002000c)) // User name pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(3600))       // Session time
+	binary.Write(&stub, binary.LittleEndian, uint32(120))        // Idle time
+
+	// Deferred string data
+	writeNDRWString(&stub, "\\\\10.0.0.7")
+	writeNDRWString(&stub, "jdoe")
+
+	binary.Write(&stub, binary.LittleEndian, uint32(1))          // TotalEntries
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020010)) // ResumeHandle pointer
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // ResumeHandle
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // Return code
+
+	sessions, err := parseSessionEnumResponse(stub.Bytes())
+	if err != nil {
+		t.Fatalf("parseSessionEnumResponse: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(sessions))
+	}
+	s := sessions[0]
+	if s.ClientName != "\\\\10.0.0.7" || s.UserName != "jdoe" || s.Time != 3600 || s.IdleTime != 120 {
+		t.Errorf("session = %+v, want \\\\10.0.0.7/jdoe/3600/120", s)
+	}
+}
+
+func TestIsClusterVirtualServer(t *testing.T) {
+	if !IsClusterVirtualServer(0x04000000) {
+		t.Error("SV_TYPE_CLUSTER_VS_NT should be recognized")
+	}
+	if IsClusterVirtualServer(0x10000000) {
+		t.Error("A plain cluster node is not a virtual server")
+	}
+
+	roles := ServerTypeRoles(0x04000000 | 0x10000000)
+	if len(roles) != 2 || roles[0] != "Cluster Virtual Server" || roles[1] != "Cluster Node" {
+		t.Errorf("roles = %v, want [Cluster Virtual Server Cluster Node]", roles)
+	}
+}
@@ -0,0 +1,130 @@
+package smb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/credentials"
+	"github.com/specterops/sharehound/internal/smb/smbtest"
+)
+
+func newOfflineSession(host string, port int, timeout time.Duration) *SMBSession {
+	creds := &credentials.Credentials{
+		Username: "tester",
+		Password: "tester",
+	}
+	return NewSMBSession(host, port, timeout, creds, "localhost", "", &config.Config{}, newTestLogger(nil))
+}
+
+// TestConnectTimeoutAgainstHungServer verifies that Connect gives up within
+// its timeout when the host accepts TCP but never answers SMB negotiation,
+// instead of hanging a worker forever.
+func TestConnectTimeoutAgainstHungServer(t *testing.T) {
+	srv, err := smbtest.NewBlackhole()
+	if err != nil {
+		t.Fatalf("smbtest.NewBlackhole: %v", err)
+	}
+	defer srv.Close()
+
+	sess := newOfflineSession(srv.Host(), srv.Port(), 500*time.Millisecond)
+
+	start := time.Now()
+	err = sess.Connect()
+	elapsed := time.Since(start)
+
+	if err == nil {
+		sess.Close()
+		t.Fatal("Connect succeeded against a server that never responds")
+	}
+	if elapsed > 10*time.Second {
+		t.Errorf("Connect took %v against a hung server; timeout not honored", elapsed)
+	}
+	if sess.IsConnected() {
+		t.Error("session reports connected after a failed Connect")
+	}
+}
+
+// TestConnectClosedPort verifies the fast-fail path when nothing is
+// listening on the target port.
+func TestConnectClosedPort(t *testing.T) {
+	// Grab a port that is free by opening and immediately closing a listener
+	srv, err := smbtest.NewBlackhole()
+	if err != nil {
+		t.Fatalf("smbtest.NewBlackhole: %v", err)
+	}
+	host, port := srv.Host(), srv.Port()
+	srv.Close()
+
+	sess := newOfflineSession(host, port, 2*time.Second)
+	if err := sess.Connect(); err != ErrConnectionFailed {
+		t.Errorf("Connect = %v, want ErrConnectionFailed", err)
+	}
+}
+
+// TestConnectServerDisconnect verifies that an abrupt server disconnect
+// during negotiation surfaces as a failed Connect rather than a hang.
+func TestConnectServerDisconnect(t *testing.T) {
+	srv, err := smbtest.NewBlackhole()
+	if err != nil {
+		t.Fatalf("smbtest.NewBlackhole: %v", err)
+	}
+
+	sess := newOfflineSession(srv.Host(), srv.Port(), 30*time.Second)
+
+	done := make(chan error, 1)
+	go func() { done <- sess.Connect() }()
+
+	// Tear the server down mid-negotiation
+	time.Sleep(50 * time.Millisecond)
+	srv.Close()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			sess.Close()
+			t.Fatal("Connect succeeded against a server that disconnected mid-negotiation")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Connect still blocked after the server disconnected")
+	}
+}
+
+// TestReplayServesRecordedFrames exercises the replay harness itself with a
+// raw client: each framed request must be answered with the next recorded
+// response, byte for byte.
+func TestReplayServesRecordedFrames(t *testing.T) {
+	script := [][]byte{
+		{0x00, 0x00, 0x00, 0x02, 0xAA, 0xBB},
+		{0x00, 0x00, 0x00, 0x01, 0xCC},
+	}
+	srv, err := smbtest.NewReplay(script)
+	if err != nil {
+		t.Fatalf("smbtest.NewReplay: %v", err)
+	}
+	defer srv.Close()
+
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", srv.Host(), srv.Port()), time.Second)
+	if err != nil {
+		t.Fatalf("dial replay server: %v", err)
+	}
+	defer conn.Close()
+
+	for i, want := range script {
+		// Any framed request advances the script
+		if _, err := conn.Write([]byte{0x00, 0x00, 0x00, 0x01, 0x42}); err != nil {
+			t.Fatalf("frame %d: write: %v", i, err)
+		}
+		got := make([]byte, len(want))
+		if _, err := io.ReadFull(conn, got); err != nil {
+			t.Fatalf("frame %d: read: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("frame %d = % x, want % x", i, got, want)
+		}
+	}
+}
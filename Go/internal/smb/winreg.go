@@ -0,0 +1,283 @@
+package smb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+
+	"github.com/medianexapp/go-smb2"
+)
+
+// MS-RRP (Remote Registry) constants
+const (
+	// Named pipe for the Remote Registry service
+	winregPipeName = "winreg"
+
+	// Operation numbers
+	opOpenLocalMachine  = 2
+	opBaseRegCloseKey   = 5
+	opBaseRegOpenKey    = 15
+	opBaseRegQueryValue = 17
+
+	// Registry access rights
+	regKeyRead = 0x00020019 // KEY_READ
+
+	// RPC_HKEY handles are 20-byte opaque context handles
+	regHandleSize = 20
+
+	// Share security descriptors live as REG_BINARY values named after the
+	// share under this key.
+	lanmanSharesSecurityKey = `SYSTEM\CurrentControlSet\Services\LanmanServer\Shares\Security`
+)
+
+// WinRegClient provides access to the Remote Registry service (MS-RRP) for
+// reading share security descriptors when SRVSVC denies NetrShareGetInfo.
+type WinRegClient struct {
+	share     *smb2.Share
+	pipe      *smb2.File
+	callID    uint32
+	contextID uint16
+}
+
+// NewWinRegClient creates a new Remote Registry client. It fails when the
+// Remote Registry service is stopped or the winreg pipe is not exposed.
+func NewWinRegClient(session *smb2.Session) (*WinRegClient, error) {
+	// Connect to IPC$ share
+	share, err := session.Mount("IPC$")
+	if err != nil {
+		return nil, fmt.Errorf("failed to mount IPC$: %w", err)
+	}
+
+	// Open the winreg named pipe
+	pipe, err := share.OpenFile(winregPipeName, 0x12019f, 0)
+	if err != nil {
+		share.Umount()
+		return nil, fmt.Errorf("failed to open winreg pipe: %w", err)
+	}
+
+	client := &WinRegClient{
+		share:  share,
+		pipe:   pipe,
+		callID: 1,
+	}
+
+	// Bind to WINREG interface
+	if err := client.bind(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to bind to WINREG: %w", err)
+	}
+
+	return client, nil
+}
+
+// Close closes the Remote Registry client.
+func (c *WinRegClient) Close() {
+	if c.pipe != nil {
+		c.pipe.Close()
+	}
+	if c.share != nil {
+		c.share.Umount()
+	}
+}
+
+// bind performs RPC bind to the WINREG interface.
+func (c *WinRegClient) bind() error {
+	// WINREG UUID: 338cd001-2244-31f1-aaaa-900038001003
+	uuid := []byte{
+		0x01, 0xd0, 0x8c, 0x33, 0x44, 0x22, 0xf1, 0x31,
+		0xaa, 0xaa, 0x90, 0x00, 0x38, 0x00, 0x10, 0x03,
+	}
+
+	if err := bindPipe(c.pipe, c.callID, uuid, 1, 0); err != nil {
+		return err
+	}
+
+	c.callID++
+	return nil
+}
+
+// call sends one request PDU and returns the reassembled response stub.
+func (c *WinRegClient) call(opNum uint16, stub []byte) ([]byte, error) {
+	request := buildRequestPDU(c.callID, c.contextID, opNum, stub)
+	c.callID++
+
+	if _, err := c.pipe.Write(request); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return readResponseStub(c.pipe)
+}
+
+// writeRRPUnicodeString writes an RRP_UNICODE_STRING (counted string with a
+// unique buffer pointer) followed by 4-byte alignment padding.
+func writeRRPUnicodeString(buf *bytes.Buffer, s string) {
+	encoded := utf16.Encode([]rune(s + "\x00"))
+	byteLen := uint16(len(encoded) * 2)
+
+	binary.Write(buf, binary.LittleEndian, byteLen)              // Length (bytes, incl. null)
+	binary.Write(buf, binary.LittleEndian, byteLen)              // MaximumLength
+	binary.Write(buf, binary.LittleEndian, uint32(0x00020000))   // Referent ID
+	binary.Write(buf, binary.LittleEndian, uint32(len(encoded))) // Max count
+	binary.Write(buf, binary.LittleEndian, uint32(0))            // Offset
+	binary.Write(buf, binary.LittleEndian, uint32(len(encoded))) // Actual count
+	for _, ch := range encoded {
+		binary.Write(buf, binary.LittleEndian, ch)
+	}
+	for buf.Len()%4 != 0 {
+		buf.WriteByte(0)
+	}
+}
+
+// handleAndReturnCode splits a response stub consisting of an RPC_HKEY
+// followed by the operation's return code.
+func handleAndReturnCode(stub []byte) ([]byte, uint32, error) {
+	if len(stub) < regHandleSize+4 {
+		return nil, 0, fmt.Errorf("response stub too short")
+	}
+	return stub[:regHandleSize], binary.LittleEndian.Uint32(stub[regHandleSize : regHandleSize+4]), nil
+}
+
+// openLocalMachine opens a handle to HKEY_LOCAL_MACHINE.
+func (c *WinRegClient) openLocalMachine() ([]byte, error) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(0))          // ServerName: null pointer
+	binary.Write(&buf, binary.LittleEndian, uint32(regKeyRead)) // samDesired
+
+	stub, err := c.call(opOpenLocalMachine, buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("OpenLocalMachine failed: %w", err)
+	}
+
+	handle, code, err := handleAndReturnCode(stub)
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, fmt.Errorf("OpenLocalMachine failed with code: 0x%08x", code)
+	}
+	return handle, nil
+}
+
+// openKey opens a subkey relative to the given handle.
+func (c *WinRegClient) openKey(parent []byte, subKey string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(parent)
+	writeRRPUnicodeString(&buf, subKey)
+	binary.Write(&buf, binary.LittleEndian, uint32(0))          // dwOptions
+	binary.Write(&buf, binary.LittleEndian, uint32(regKeyRead)) // samDesired
+
+	stub, err := c.call(opBaseRegOpenKey, buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("BaseRegOpenKey failed: %w", err)
+	}
+
+	handle, code, err := handleAndReturnCode(stub)
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, fmt.Errorf("BaseRegOpenKey(%s) failed with code: 0x%08x", subKey, code)
+	}
+	return handle, nil
+}
+
+// closeKey releases a registry handle; errors are ignored since the handles
+// die with the connection anyway.
+func (c *WinRegClient) closeKey(handle []byte) {
+	c.call(opBaseRegCloseKey, handle) //nolint:errcheck
+}
+
+// queryValue reads a value's raw data from the key behind handle.
+func (c *WinRegClient) queryValue(handle []byte, valueName string) ([]byte, error) {
+	const bufSize = 65536
+
+	var buf bytes.Buffer
+	buf.Write(handle)
+	writeRRPUnicodeString(&buf, valueName)
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020004)) // lpType referent ID
+	binary.Write(&buf, binary.LittleEndian, uint32(0))          // Type
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020008)) // lpData referent ID
+	binary.Write(&buf, binary.LittleEndian, uint32(bufSize))    // Max count
+	binary.Write(&buf, binary.LittleEndian, uint32(0))          // Offset
+	binary.Write(&buf, binary.LittleEndian, uint32(0))          // Actual count (empty on input)
+	binary.Write(&buf, binary.LittleEndian, uint32(0x0002000c)) // lpcbData referent ID
+	binary.Write(&buf, binary.LittleEndian, uint32(bufSize))    // Buffer size
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00020010)) // lpcbLen referent ID
+	binary.Write(&buf, binary.LittleEndian, uint32(0))          // Length
+
+	stub, err := c.call(opBaseRegQueryValue, buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("BaseRegQueryValue failed: %w", err)
+	}
+
+	return parseRegQueryValueResponse(stub, valueName)
+}
+
+// parseRegQueryValueResponse parses a BaseRegQueryValue response stub and
+// returns the value's raw data.
+func parseRegQueryValueResponse(payload []byte, valueName string) ([]byte, error) {
+	if len(payload) < 4 {
+		return nil, fmt.Errorf("payload too short")
+	}
+
+	// Error responses carry null pointers and little else, so check the
+	// return code before expecting the full success layout.
+	returnCode := binary.LittleEndian.Uint32(payload[len(payload)-4:])
+	if returnCode != 0 {
+		return nil, fmt.Errorf("BaseRegQueryValue(%s) failed with code: 0x%08x", valueName, returnCode)
+	}
+
+	if len(payload) < 24 {
+		return nil, fmt.Errorf("payload too short")
+	}
+
+	// Stub layout: lpType pointer + value, lpData pointer with conformant
+	// varying byte array, then lpcbData and lpcbLen pointers + values and
+	// the return code.
+	offset := 0
+	if binary.LittleEndian.Uint32(payload[offset:offset+4]) != 0 {
+		offset += 4 // referent ID
+		offset += 4 // type value
+	} else {
+		offset += 4
+	}
+
+	if offset+4 > len(payload) {
+		return nil, fmt.Errorf("truncated data pointer")
+	}
+	dataPtr := binary.LittleEndian.Uint32(payload[offset : offset+4])
+	offset += 4
+	if dataPtr == 0 {
+		return nil, fmt.Errorf("BaseRegQueryValue(%s) returned no data", valueName)
+	}
+
+	if offset+12 > len(payload) {
+		return nil, fmt.Errorf("truncated data array header")
+	}
+	actualCount := binary.LittleEndian.Uint32(payload[offset+8 : offset+12])
+	offset += 12
+
+	if actualCount > uint32(len(payload)-offset) {
+		return nil, fmt.Errorf("truncated data array")
+	}
+	return payload[offset : offset+int(actualCount)], nil
+}
+
+// GetShareSecurity reads a share's security descriptor blob from the
+// LanmanServer Shares\Security registry key. The blob is a self-relative
+// security descriptor suitable for ParseSecurityDescriptor.
+func (c *WinRegClient) GetShareSecurity(shareName string) ([]byte, error) {
+	hklm, err := c.openLocalMachine()
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeKey(hklm)
+
+	key, err := c.openKey(hklm, lanmanSharesSecurityKey)
+	if err != nil {
+		return nil, err
+	}
+	defer c.closeKey(key)
+
+	return c.queryValue(key, shareName)
+}
@@ -0,0 +1,54 @@
+package smb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestParseRegQueryValueResponse(t *testing.T) {
+	blob := []byte{0x01, 0x00, 0x04, 0x80, 0xde, 0xad, 0xbe, 0xef}
+
+	// BaseRegQueryValue response stub: lpType, lpData with a conformant
+	// varying byte array, lpcbData, lpcbLen, return code.
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020000)) // lpType referent ID
+	binary.Write(&stub, binary.LittleEndian, uint32(3))          // REG_BINARY
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020004)) // lpData referent ID
+	binary.Write(&stub, binary.LittleEndian, uint32(65536))      // Max count
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // Offset
+	binary.Write(&stub, binary.LittleEndian, uint32(len(blob)))  // Actual count
+	stub.Write(blob)
+	binary.Write(&stub, binary.LittleEndian, uint32(0x00020008)) // lpcbData referent ID
+	binary.Write(&stub, binary.LittleEndian, uint32(len(blob)))  // cbData
+	binary.Write(&stub, binary.LittleEndian, uint32(0x0002000c)) // lpcbLen referent ID
+	binary.Write(&stub, binary.LittleEndian, uint32(len(blob)))  // cbLen
+	binary.Write(&stub, binary.LittleEndian, uint32(0))          // Return code
+
+	data, err := parseRegQueryValueResponse(stub.Bytes(), "Finance")
+	if err != nil {
+		t.Fatalf("parseRegQueryValueResponse: %v", err)
+	}
+	if !bytes.Equal(data, blob) {
+		t.Errorf("data = % x, want % x", data, blob)
+	}
+}
+
+func TestParseRegQueryValueResponseError(t *testing.T) {
+	// Stub carrying only null pointers and ERROR_FILE_NOT_FOUND (2)
+	var stub bytes.Buffer
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // lpType: null
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // lpData: null
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // lpcbData: null
+	binary.Write(&stub, binary.LittleEndian, uint32(0)) // lpcbLen: null
+	binary.Write(&stub, binary.LittleEndian, uint32(2)) // Return code
+
+	_, err := parseRegQueryValueResponse(stub.Bytes(), "Missing")
+	if err == nil {
+		t.Fatal("parseRegQueryValueResponse accepted an error response")
+	}
+	if !strings.Contains(err.Error(), "0x00000002") {
+		t.Errorf("error = %q, want it to carry the return code", err)
+	}
+}
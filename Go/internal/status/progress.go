@@ -3,32 +3,66 @@ package status
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
-	"sync"
 	"time"
 
+	"golang.org/x/term"
+
 	"github.com/specterops/sharehound/internal/collector"
+	"github.com/specterops/sharehound/internal/utils"
 )
 
+// rateSmoothing is the EWMA factor applied to the displayed rates. A weight
+// of 0.2 on the newest sample follows genuine speed changes within a few
+// ticks while damping the per-second jitter that made the ETA jump wildly
+// when it was derived from the instantaneous rate alone.
+const rateSmoothing = 0.2
+
+// maxPanelHosts is the number of active hosts shown in the live panel,
+// longest-running first.
+const maxPanelHosts = 5
+
+// statusLogEvery is how often (in ticks) a status line is logged when
+// stdout is not a terminal.
+const statusLogEvery = 10
+
 // ProgressTracker tracks and displays progress.
+//
+// When stdout is a terminal it renders a live multi-line panel (overall bar
+// plus the longest-running active hosts with their current share and path)
+// using ANSI cursor control. When piped it falls back to a periodic plain
+// status line so logs stay readable.
 type ProgressTracker struct {
-	results     *collector.WorkerResults
-	resultsLock *sync.Mutex
-	totalHosts  int
-	startTime   time.Time
-	done        chan bool
-	lastUpdate  time.Time
-	lastHosts   int64
+	results    *collector.WorkerResults
+	totalHosts int
+	startTime  time.Time
+	done       chan bool
+	lastUpdate time.Time
+	lastHosts  int64
+
+	isTTY      bool
+	ticks      int
+	panelLines int // height of the previously rendered panel
+
+	// EWMA rate state, updated once per tick
+	lastTick  time.Time
+	lastFiles int64
+	lastBytes int64
+	hostRate  float64 // hosts per minute
+	fileRate  float64 // files per second
+	byteRate  float64 // bytes per second
 }
 
 // NewProgressTracker creates a new ProgressTracker.
-func NewProgressTracker(results *collector.WorkerResults, lock *sync.Mutex, totalHosts int) *ProgressTracker {
+func NewProgressTracker(results *collector.WorkerResults, totalHosts int) *ProgressTracker {
 	return &ProgressTracker{
-		results:     results,
-		resultsLock: lock,
-		totalHosts:  totalHosts,
-		startTime:   time.Now(),
-		done:        make(chan bool),
+		results:    results,
+		totalHosts: totalHosts,
+		startTime:  time.Now(),
+		done:       make(chan bool),
+		isTTY:      term.IsTerminal(int(os.Stdout.Fd())),
 	}
 }
 
@@ -51,34 +85,41 @@ func (p *ProgressTracker) Start() {
 
 // printStatus prints a single-line status update.
 func (p *ProgressTracker) printStatus() {
-	p.resultsLock.Lock()
-	hostsDone := p.results.Success + p.results.Errors
-	hostsActive := p.results.ActiveHosts
-	sharesDone := p.results.SharesProcessed
-	sharesTotal := p.results.SharesTotal
-	filesDone := p.results.FilesProcessed
-	dirsDone := p.results.DirectoriesProcessed
-	errors := p.results.Errors
-	// Copy active host names for display
-	var activeHostNames []string
-	for name := range p.results.ActiveHostNames {
-		activeHostNames = append(activeHostNames, name)
-	}
-	p.resultsLock.Unlock()
-
-	elapsed := time.Since(p.startTime)
-
-	// Calculate rate
-	rate := float64(0)
-	if elapsed.Seconds() > 0 {
-		rate = float64(hostsDone) / elapsed.Minutes()
-	}
-
-	// Calculate ETA
+	hostsDone := p.results.Success.Load() + p.results.Errors.Load()
+	hostsActive := p.results.ActiveHosts.Load()
+	sharesDone := p.results.SharesProcessed.Load()
+	sharesTotal := p.results.SharesTotal.Load()
+	filesDone := p.results.FilesProcessed.Load()
+	dirsDone := p.results.DirectoriesProcessed.Load()
+	errors := p.results.Errors.Load()
+	bytesDone := p.results.BytesProcessed.Load()
+	activeHostNames := p.results.ActiveHostNames()
+
+	// Update the smoothed rates from this tick's deltas
+	now := time.Now()
+	if !p.lastTick.IsZero() {
+		if dt := now.Sub(p.lastTick).Seconds(); dt > 0 {
+			p.hostRate = smooth(p.hostRate, float64(hostsDone-p.lastHosts)/dt*60)
+			p.fileRate = smooth(p.fileRate, float64(filesDone-p.lastFiles)/dt)
+			p.byteRate = smooth(p.byteRate, float64(bytesDone-p.lastBytes)/dt)
+		}
+	}
+	p.lastTick = now
+	p.lastFiles = filesDone
+	p.lastBytes = bytesDone
+
+	// Phase: hosts are in discovery until traversal starts producing content;
+	// the export phase after the scan reports its own progress separately.
+	phase := "discovery"
+	if filesDone > 0 || dirsDone > 0 {
+		phase = "traversal"
+	}
+
+	// Calculate ETA from the smoothed host rate
 	eta := "calculating..."
-	if rate > 0 && hostsDone > 0 {
+	if p.hostRate > 0 && hostsDone > 0 {
 		remaining := p.totalHosts - int(hostsDone)
-		etaMinutes := float64(remaining) / rate
+		etaMinutes := float64(remaining) / p.hostRate
 		if etaMinutes < 60 {
 			eta = fmt.Sprintf("%.0fm", etaMinutes)
 		} else {
@@ -95,10 +136,11 @@ func (p *ProgressTracker) printStatus() {
 	filled := int(pct / 100 * float64(barWidth))
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
 
-	// Clear line and print status
-	fmt.Printf("\r\033[K[%s] %5.1f%% │ Hosts: %d/%d (active: %d) │ Shares: %d │ Files: %d │ Dirs: %d │ Errors: %d │ Rate: %.1f/min │ ETA: %s",
+	// Build the status line
+	line := fmt.Sprintf("[%s] %5.1f%% │ %s │ Hosts: %d/%d (active: %d) │ Shares: %d │ Files: %d │ Dirs: %d │ Errors: %d │ Rate: %.1f/min, %.0f files/s, %s/s │ ETA: %s",
 		bar,
 		pct,
+		phase,
 		hostsDone,
 		p.totalHosts,
 		hostsActive,
@@ -106,7 +148,9 @@ func (p *ProgressTracker) printStatus() {
 		filesDone,
 		dirsDone,
 		errors,
-		rate,
+		p.hostRate,
+		p.fileRate,
+		utils.FormatFileSize(int64(p.byteRate)),
 		eta,
 	)
 
@@ -115,12 +159,12 @@ func (p *ProgressTracker) printStatus() {
 		// Show which hosts are slow
 		if len(activeHostNames) > 0 {
 			if len(activeHostNames) <= 3 {
-				fmt.Printf(" [!SLOW: %s]", strings.Join(activeHostNames, ", "))
+				line += fmt.Sprintf(" [!SLOW: %s]", strings.Join(activeHostNames, ", "))
 			} else {
-				fmt.Printf(" [!SLOW: %s +%d more]", strings.Join(activeHostNames[:3], ", "), len(activeHostNames)-3)
+				line += fmt.Sprintf(" [!SLOW: %s +%d more]", strings.Join(activeHostNames[:3], ", "), len(activeHostNames)-3)
 			}
 		} else {
-			fmt.Printf(" [!SLOW]")
+			line += " [!SLOW]"
 		}
 	}
 
@@ -131,51 +175,142 @@ func (p *ProgressTracker) printStatus() {
 
 	// Show warning if active hosts is 0 but not done
 	if hostsActive == 0 && int(hostsDone) < p.totalHosts && sharesTotal > 0 {
-		fmt.Printf(" [IDLE?]")
+		line += " [IDLE?]"
+	}
+
+	if p.isTTY {
+		p.renderPanel(line)
+		return
+	}
+
+	// Piped output: a periodic plain line instead of cursor control
+	p.ticks++
+	if p.ticks%statusLogEvery == 0 {
+		fmt.Println(line)
+	}
+}
+
+// renderPanel redraws the live panel in place: the status line followed by
+// the longest-running active hosts with their current share, path and
+// elapsed time. The cursor is left at the panel's first line so the next
+// tick overwrites it.
+func (p *ProgressTracker) renderPanel(statusLine string) {
+	lines := []string{statusLine}
+	for _, activity := range p.results.ActiveHostActivity(maxPanelHosts) {
+		location := activity.Share
+		if activity.Path != "" {
+			location += "\\" + activity.Path
+		}
+		if location == "" {
+			location = "connecting..."
+		}
+		lines = append(lines, fmt.Sprintf("  %-28s %-48s %s",
+			truncate(activity.Host, 28),
+			truncate(location, 48),
+			formatDuration(time.Since(activity.StartedAt))))
+	}
+
+	var panel strings.Builder
+	for _, line := range lines {
+		panel.WriteString("\r\033[K")
+		panel.WriteString(line)
+		panel.WriteString("\n")
+	}
+	// Blank out leftover lines when the previous panel was taller
+	height := len(lines)
+	for i := len(lines); i < p.panelLines; i++ {
+		panel.WriteString("\r\033[K\n")
+		height++
+	}
+	fmt.Printf("%s\033[%dA", panel.String(), height)
+	p.panelLines = len(lines)
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
 	}
+	return string(runes[:n-1]) + "…"
 }
 
 // Stop stops the progress display.
 func (p *ProgressTracker) Stop() {
 	close(p.done)
+	if p.panelLines > 0 {
+		// Step past the rendered panel so later output doesn't overwrite it
+		fmt.Printf("\033[%dB", p.panelLines)
+	}
 	fmt.Println() // New line after progress
 }
 
 // PrintFinalSummary prints the final summary.
-func PrintFinalSummary(results *collector.WorkerResults, lock *sync.Mutex) {
-	lock.Lock()
-	defer lock.Unlock()
-
+func PrintFinalSummary(results *collector.WorkerResults) {
 	fmt.Println("\n" + strings.Repeat("─", 60))
 	fmt.Println("                      SCAN COMPLETE")
 	fmt.Println(strings.Repeat("─", 60))
 	fmt.Printf("  Hosts:       %d successful, %d errors\n",
-		results.Success, results.Errors)
+		results.Success.Load(), results.Errors.Load())
+	if errorsByCategory := results.ErrorCategoryCounts(); len(errorsByCategory) > 0 {
+		categories := make([]string, 0, len(errorsByCategory))
+		for category := range errorsByCategory {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		parts := make([]string, 0, len(categories))
+		for _, category := range categories {
+			parts = append(parts, fmt.Sprintf("%s: %d", category, errorsByCategory[category]))
+		}
+		fmt.Printf("  Error types: %s\n", strings.Join(parts, ", "))
+	}
 	fmt.Printf("  Shares:      %d processed, %d skipped (total: %d)\n",
-		results.SharesProcessed, results.SharesSkipped, results.SharesTotal)
+		results.SharesProcessed.Load(), results.SharesSkipped.Load(), results.SharesTotal.Load())
 	fmt.Printf("  Files:       %d processed, %d skipped (total: %d)\n",
-		results.FilesProcessed, results.FilesSkipped, results.FilesTotal)
+		results.FilesProcessed.Load(), results.FilesSkipped.Load(), results.FilesTotal.Load())
 	fmt.Printf("  Directories: %d processed, %d skipped (total: %d)\n",
-		results.DirectoriesProcessed, results.DirectoriesSkipped, results.DirectoriesTotal)
+		results.DirectoriesProcessed.Load(), results.DirectoriesSkipped.Load(), results.DirectoriesTotal.Load())
+	if abandoned := results.SharesAbandoned.Load(); abandoned > 0 {
+		fmt.Printf("  Abandoned:   %d share(s) dropped by the host timeout budget\n",
+			abandoned)
+	}
+	if worldWritable := results.WorldWritableShares.Load(); worldWritable > 0 {
+		fmt.Printf("  Alerts:      %d world-writable share(s)\n",
+			worldWritable)
+	}
+	if suppressed := results.EdgesSuppressed.Load(); suppressed > 0 {
+		fmt.Printf("  Edges:       %d suppressed (ignored principals)\n",
+			suppressed)
+	}
+	if sdFailures := results.SDQueryFailures.Load(); sdFailures > 0 {
+		fmt.Printf("  ACLs:        %d security descriptor queries failed\n",
+			sdFailures)
+	}
 	fmt.Println(strings.Repeat("─", 60))
 }
 
 // PrintStatus prints the current status to stdout (for logging).
-func PrintStatus(results *collector.WorkerResults, lock *sync.Mutex, elapsed time.Duration) {
-	lock.Lock()
-	defer lock.Unlock()
-
+func PrintStatus(results *collector.WorkerResults, elapsed time.Duration) {
 	fmt.Printf("[status] Hosts: %d (active: %d) | Shares: %d | Files: %d | Dirs: %d | Errors: %d | Time: %s\n",
-		results.Success+results.Errors,
-		results.ActiveHosts,
-		results.SharesProcessed,
-		results.FilesProcessed,
-		results.DirectoriesProcessed,
-		results.Errors,
+		results.Success.Load()+results.Errors.Load(),
+		results.ActiveHosts.Load(),
+		results.SharesProcessed.Load(),
+		results.FilesProcessed.Load(),
+		results.DirectoriesProcessed.Load(),
+		results.Errors.Load(),
 		formatDuration(elapsed),
 	)
 }
 
+// smooth folds a new rate sample into an exponentially weighted moving
+// average. The first sample seeds the average directly.
+func smooth(current, sample float64) float64 {
+	if current == 0 {
+		return sample
+	}
+	return rateSmoothing*sample + (1-rateSmoothing)*current
+}
+
 func formatDuration(d time.Duration) string {
 	d = d.Round(time.Second)
 	h := d / time.Hour
@@ -0,0 +1,212 @@
+// Package stream exposes scan output as a live event feed: every node and
+// edge accepted into the graph plus periodic progress updates are pushed to
+// connected subscribers as newline-delimited JSON, so integrators can
+// consume results while the scan runs instead of polling for the finished
+// file. Subscribers that cannot keep up are disconnected rather than allowed
+// to stall the scan.
+//
+// The wire format is newline-delimited JSON over TCP (optionally mutual-TLS)
+// rather than gRPC, a deliberate trade: no proto toolchain or generated
+// stubs to version, and the feed is consumable from anything that can read a
+// socket. The contract is the Event type below plus one JSON object per
+// line; it is documented for integrators in the README under "Live Event
+// Stream".
+package stream
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// Event types on the subscriber stream.
+const (
+	EventNode     = "node"
+	EventEdge     = "edge"
+	EventProgress = "progress"
+	EventDone     = "done"
+)
+
+// Event is one unit of the live feed.
+type Event struct {
+	Type     string      `json:"type"`
+	Node     *graph.Node `json:"node,omitempty"`
+	Edge     *graph.Edge `json:"edge,omitempty"`
+	Progress *Progress   `json:"progress,omitempty"`
+}
+
+// Progress summarizes how far the scan has come.
+type Progress struct {
+	HostsProcessed  int64 `json:"hosts_processed"`
+	HostsTotal      int   `json:"hosts_total"`
+	SharesProcessed int64 `json:"shares_processed"`
+	FilesProcessed  int64 `json:"files_processed"`
+	Errors          int64 `json:"errors"`
+}
+
+// subscriberBuffer is the number of events a subscriber may fall behind
+// before it is disconnected.
+const subscriberBuffer = 1024
+
+type subscriber struct {
+	conn net.Conn
+	ch   chan Event
+}
+
+// Server fans scan events out to connected subscribers.
+type Server struct {
+	log      logger.LoggerInterface
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	subs   map[*subscriber]struct{}
+	closed bool
+}
+
+// NewServer creates a results stream server.
+func NewServer(log logger.LoggerInterface) *Server {
+	return &Server{log: log, subs: make(map[*subscriber]struct{})}
+}
+
+// Start begins accepting subscribers on addr. tlsConfig may be nil for a
+// plaintext listener (localhost integrations); pass a server configuration
+// requiring client certificates (see distrib.ServerTLSConfig) to expose the
+// feed across the network.
+func (s *Server) Start(addr string, tlsConfig *tls.Config) error {
+	var (
+		listener net.Listener
+		err      error
+	)
+	if tlsConfig != nil {
+		listener, err = tls.Listen("tcp", addr, tlsConfig)
+	} else {
+		listener, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return // listener closed
+		}
+
+		sub := &subscriber{conn: conn, ch: make(chan Event, subscriberBuffer)}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.subs[sub] = struct{}{}
+		s.mu.Unlock()
+		s.log.Info(fmt.Sprintf("Results stream subscriber connected from %s", conn.RemoteAddr()))
+
+		s.wg.Add(1)
+		go s.serve(sub)
+	}
+}
+
+func (s *Server) serve(sub *subscriber) {
+	defer s.wg.Done()
+	defer s.drop(sub)
+
+	encoder := json.NewEncoder(sub.conn)
+	for ev := range sub.ch {
+		if err := encoder.Encode(ev); err != nil {
+			return
+		}
+	}
+}
+
+// drop disconnects a subscriber. The channel is only closed while it is
+// still registered, so Publish never sends on a closed channel.
+func (s *Server) drop(sub *subscriber) {
+	sub.conn.Close() //nolint:errcheck
+	s.mu.Lock()
+	if _, ok := s.subs[sub]; ok {
+		delete(s.subs, sub)
+		close(sub.ch)
+	}
+	s.mu.Unlock()
+}
+
+// Publish delivers an event to every subscriber. A subscriber whose buffer
+// is full is disconnected so the scan never blocks on a slow consumer.
+func (s *Server) Publish(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		select {
+		case sub.ch <- ev:
+		default:
+			s.log.Warning(fmt.Sprintf("Results stream subscriber %s cannot keep up, disconnecting", sub.conn.RemoteAddr()))
+			delete(s.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// PublishNode matches the graph observer signature (see
+// OpenGraph.SetObserver); it must not block.
+func (s *Server) PublishNode(node *graph.Node) {
+	s.Publish(Event{Type: EventNode, Node: node})
+}
+
+// PublishEdge matches the graph observer signature; it must not block.
+func (s *Server) PublishEdge(edge *graph.Edge) {
+	s.Publish(Event{Type: EventEdge, Edge: edge})
+}
+
+// PublishProgress pushes a progress summary to all subscribers.
+func (s *Server) PublishProgress(p Progress) {
+	s.Publish(Event{Type: EventProgress, Progress: &p})
+}
+
+// Close sends a final done event, disconnects all subscribers and stops the
+// listener.
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	for sub := range s.subs {
+		select {
+		case sub.ch <- Event{Type: EventDone}:
+		default:
+		}
+		delete(s.subs, sub)
+		close(sub.ch)
+	}
+	s.mu.Unlock()
+
+	if s.listener != nil {
+		s.listener.Close() //nolint:errcheck
+	}
+	s.wg.Wait()
+}
@@ -0,0 +1,130 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+func startServer(t *testing.T) *Server {
+	t.Helper()
+	s := NewServer(logger.NewLogger(config.NewConfig(false, nil), ""))
+	if err := s.Start("127.0.0.1:0", nil); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return s
+}
+
+// subscribe connects and waits until the server has registered the
+// subscriber, so subsequently published events are not missed.
+func subscribe(t *testing.T, s *Server) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		n := len(s.subs)
+		s.mu.Unlock()
+		if n > 0 {
+			return conn
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("subscriber was not registered")
+	return nil
+}
+
+func readEvent(t *testing.T, scanner *bufio.Scanner) Event {
+	t.Helper()
+	if !scanner.Scan() {
+		t.Fatalf("stream ended early: %v", scanner.Err())
+	}
+	var ev Event
+	if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+		t.Fatalf("invalid event %q: %v", scanner.Text(), err)
+	}
+	return ev
+}
+
+func TestSubscriberReceivesEvents(t *testing.T) {
+	s := startServer(t)
+	defer s.Close()
+
+	conn := subscribe(t, s)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second)) //nolint:errcheck
+	scanner := bufio.NewScanner(conn)
+
+	s.PublishNode(graph.NewNode("NSHOST:fs01", "TestKind"))
+	s.PublishEdge(graph.NewEdge("NSHOST:fs01", "NSHOST:fs02", "TestEdge"))
+	s.PublishProgress(Progress{HostsProcessed: 1, HostsTotal: 10})
+
+	ev := readEvent(t, scanner)
+	if ev.Type != EventNode || ev.Node == nil || ev.Node.ID != "NSHOST:fs01" {
+		t.Errorf("first event = %+v, want node NSHOST:fs01", ev)
+	}
+	ev = readEvent(t, scanner)
+	if ev.Type != EventEdge || ev.Edge == nil || ev.Edge.Kind != "TestEdge" {
+		t.Errorf("second event = %+v, want TestEdge edge", ev)
+	}
+	ev = readEvent(t, scanner)
+	if ev.Type != EventProgress || ev.Progress == nil || ev.Progress.HostsTotal != 10 {
+		t.Errorf("third event = %+v, want progress", ev)
+	}
+}
+
+func TestCloseSendsDone(t *testing.T) {
+	s := startServer(t)
+	conn := subscribe(t, s)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second)) //nolint:errcheck
+	scanner := bufio.NewScanner(conn)
+
+	s.Close()
+
+	ev := readEvent(t, scanner)
+	if ev.Type != EventDone {
+		t.Errorf("event after Close = %+v, want done", ev)
+	}
+	if scanner.Scan() {
+		t.Error("stream should end after the done event")
+	}
+}
+
+func TestGraphObserverFeedsStream(t *testing.T) {
+	s := startServer(t)
+	defer s.Close()
+
+	og, err := graph.NewOpenGraph("test")
+	if err != nil {
+		t.Fatalf("NewOpenGraph: %v", err)
+	}
+	defer og.Close()
+	og.SetObserver(s.PublishNode, s.PublishEdge)
+
+	conn := subscribe(t, s)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second)) //nolint:errcheck
+	scanner := bufio.NewScanner(conn)
+
+	og.AddNode(graph.NewNode("NSHOST:fs01", "TestKind"))
+	og.AddNode(graph.NewNode("NSHOST:fs01", "TestKind")) // duplicate, not re-emitted
+	og.AddEdge(graph.NewEdge("NSHOST:fs01", "NSHOST:fs02", "TestEdge"))
+
+	ev := readEvent(t, scanner)
+	if ev.Type != EventNode {
+		t.Errorf("first event = %+v, want node", ev)
+	}
+	ev = readEvent(t, scanner)
+	if ev.Type != EventEdge {
+		t.Errorf("second event = %+v, want edge (duplicate node must be suppressed)", ev)
+	}
+}
@@ -0,0 +1,76 @@
+package targets
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/specterops/sharehound/internal/logger"
+	"github.com/specterops/sharehound/internal/utils"
+)
+
+// DedupeByAddress collapses targets that are aliases of the same server.
+// When an IP address, a short name and an FQDN in the target list all
+// resolve to the same address, only one of them is scanned, preventing
+// duplicated host, share and file nodes in the graph. FQDN aliases are
+// preferred as the surviving identity because node IDs derived from them
+// carry the domain suffix; among names the longest wins, so an FQDN beats
+// the short name it expands. Targets that fail to resolve keep their own
+// identity — the scan surfaces the DNS failure later.
+func DedupeByAddress(input []Target, nameserver, dcIP string, timeout time.Duration, log logger.LoggerInterface) []Target {
+	type alias struct {
+		target Target
+		rank   int
+	}
+
+	// FQDNs make the best node IDs, bare addresses the worst.
+	rank := func(t Target) int {
+		if t.Type == "fqdn" {
+			return 1
+		}
+		return 0
+	}
+
+	best := make(map[string]*alias)
+	order := make([]string, 0, len(input))
+	dropped := 0
+
+	for _, t := range input {
+		addr := t.Value
+		if t.Type == "fqdn" {
+			resolved, err := utils.DNSResolve(t.Value, nameserver, dcIP, timeout)
+			if err != nil || resolved == "" {
+				addr = "unresolved:" + t.Value
+			} else {
+				addr = resolved
+			}
+		}
+
+		cur, ok := best[addr]
+		if !ok {
+			best[addr] = &alias{target: t, rank: rank(t)}
+			order = append(order, addr)
+			continue
+		}
+
+		dropped++
+		if rank(t) > cur.rank || (rank(t) == cur.rank && len(t.Value) > len(cur.target.Value)) {
+			log.Debug(fmt.Sprintf("Target %s is an alias of %s (%s); scanning as %s", cur.target.Value, t.Value, addr, t.Value))
+			cur.target = t
+			cur.rank = rank(t)
+		} else {
+			log.Debug(fmt.Sprintf("Target %s is an alias of %s (%s); scanning as %s", t.Value, cur.target.Value, addr, cur.target.Value))
+		}
+	}
+
+	if dropped == 0 {
+		return input
+	}
+
+	log.Info(fmt.Sprintf("Deduplicated %d alias target(s) pointing at already-listed servers", dropped))
+
+	result := make([]Target, 0, len(order))
+	for _, addr := range order {
+		result = append(result, best[addr].target)
+	}
+	return result
+}
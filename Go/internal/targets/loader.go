@@ -4,6 +4,7 @@ package targets
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -19,6 +20,7 @@ import (
 type Target struct {
 	Type  string // "ipv4", "ipv6", "fqdn"
 	Value string
+	Tags  map[string]string // business-context tags from an extended targets CSV
 }
 
 // Options holds target loading options.
@@ -36,12 +38,19 @@ type Options struct {
 	KDCHost      string
 	UseLDAPS     bool
 	Subnets      bool
+	Forest       bool
+	MaxTargets   int // cap on CIDR-derived targets, 0 = no cap; expansion stops at the cap instead of materializing
 	Timeout      time.Duration
 }
 
+// cidrWarnSize is the expansion size above which a CIDR draws a warning:
+// anything wider than a /16 is usually a typo rather than a real scan scope.
+const cidrWarnSize = 1 << 16
+
 // LoadTargets loads and parses targets from various sources.
 func LoadTargets(opts *Options, cfg *config.Config, log logger.LoggerInterface) ([]Target, error) {
 	var rawTargets []string
+	var targetTags map[string]map[string]string
 
 	// Check if DC is reachable for LDAP queries
 	if opts.AuthDCIP != "" && opts.hasLDAPAuth() {
@@ -58,12 +67,17 @@ func LoadTargets(opts *Options, cfg *config.Config, log logger.LoggerInterface)
 
 	// Load from file
 	if opts.TargetsFile != "" {
-		log.Debug("Loading targets from file: " + opts.TargetsFile)
-		fileTargets, err := loadFromFile(opts.TargetsFile)
+		if opts.TargetsFile == "-" {
+			log.Debug("Loading targets from standard input")
+		} else {
+			log.Debug("Loading targets from file: " + opts.TargetsFile)
+		}
+		fileTargets, fileTags, err := loadFromFile(opts.TargetsFile)
 		if err != nil {
 			log.Error("Error loading targets file: " + err.Error())
 		} else {
 			rawTargets = append(rawTargets, fileTargets...)
+			targetTags = fileTags
 		}
 	}
 
@@ -75,13 +89,24 @@ func LoadTargets(opts *Options, cfg *config.Config, log logger.LoggerInterface)
 
 	// Load from AD if no explicit targets
 	if len(rawTargets) == 0 && opts.AuthDCIP != "" && opts.hasLDAPAuth() {
-		log.Info(fmt.Sprintf("No target list specified, fetching all computers from Active Directory domain '%s'", opts.AuthDomain))
+		if opts.Forest {
+			log.Info("No target list specified, fetching all computers from every domain in the forest")
 
-		adTargets, err := loadFromActiveDirectory(opts, log)
-		if err != nil {
-			log.Error("Error loading targets from Active Directory: " + err.Error())
+			forestTargets, err := loadFromForest(opts, log)
+			if err != nil {
+				log.Error("Error loading targets from forest: " + err.Error())
+			} else {
+				rawTargets = append(rawTargets, forestTargets...)
+			}
 		} else {
-			rawTargets = append(rawTargets, adTargets...)
+			log.Info(fmt.Sprintf("No target list specified, fetching all computers from Active Directory domain '%s'", opts.AuthDomain))
+
+			adTargets, err := loadFromActiveDirectory(opts, log)
+			if err != nil {
+				log.Error("Error loading targets from Active Directory: " + err.Error())
+			} else {
+				rawTargets = append(rawTargets, adTargets...)
+			}
 		}
 	}
 
@@ -99,8 +124,13 @@ func LoadTargets(opts *Options, cfg *config.Config, log logger.LoggerInterface)
 	// Deduplicate and sort
 	rawTargets = uniqueStrings(rawTargets)
 
-	// Parse and classify targets
+	// Parse and classify targets. The cap counts only CIDR-derived
+	// addresses: explicit entries and AD enumeration are the operator's
+	// deliberate scope, while a mistyped CIDR is where an unbounded
+	// expansion can materialize millions of targets.
 	var finalTargets []Target
+	capped := false
+	expanded := 0
 	for _, t := range rawTargets {
 		t = strings.TrimSpace(t)
 		if t == "" {
@@ -108,26 +138,41 @@ func LoadTargets(opts *Options, cfg *config.Config, log logger.LoggerInterface)
 		}
 
 		if utils.IsIPv4CIDR(t) {
-			// Expand CIDR
-			ips, err := utils.ExpandCIDR(t)
+			if count, err := utils.CIDRHostCount(t); err == nil && count > cidrWarnSize {
+				log.Warning(fmt.Sprintf("CIDR %s expands to %d addresses (wider than a /16); double-check it before scanning", t, count))
+			}
+
+			// Expand the CIDR one address at a time so a fat-fingered
+			// /8 is stopped at the cap instead of being materialized
+			tags := targetTags[t]
+			err := utils.ExpandCIDREach(t, func(ip string) bool {
+				if opts.MaxTargets > 0 && expanded >= opts.MaxTargets {
+					capped = true
+					return false
+				}
+				expanded++
+				finalTargets = append(finalTargets, Target{Type: "ipv4", Value: ip, Tags: tags})
+				return true
+			})
 			if err != nil {
 				log.Debug("Error expanding CIDR " + t + ": " + err.Error())
 				continue
 			}
-			for _, ip := range ips {
-				finalTargets = append(finalTargets, Target{Type: "ipv4", Value: ip})
-			}
 		} else if utils.IsIPv4Addr(t) {
-			finalTargets = append(finalTargets, Target{Type: "ipv4", Value: t})
+			finalTargets = append(finalTargets, Target{Type: "ipv4", Value: t, Tags: targetTags[t]})
 		} else if utils.IsIPv6Addr(t) {
-			finalTargets = append(finalTargets, Target{Type: "ipv6", Value: t})
+			finalTargets = append(finalTargets, Target{Type: "ipv6", Value: t, Tags: targetTags[t]})
 		} else if utils.IsFQDN(t) {
-			finalTargets = append(finalTargets, Target{Type: "fqdn", Value: t})
+			finalTargets = append(finalTargets, Target{Type: "fqdn", Value: t, Tags: targetTags[t]})
 		} else {
 			log.Debug("Target '" + t + "' was not added (unknown type)")
 		}
 	}
 
+	if capped {
+		return nil, fmt.Errorf("CIDR expansion exceeds the --max-targets cap of %d; narrow the ranges or raise the cap", opts.MaxTargets)
+	}
+
 	// Deduplicate final targets
 	finalTargets = uniqueTargets(finalTargets)
 
@@ -194,6 +239,58 @@ func loadFromActiveDirectory(opts *Options, log logger.LoggerInterface) ([]strin
 	return targets, nil
 }
 
+// loadFromForest loads computers from every domain in the forest by walking
+// the crossRef objects in the configuration partition over a Global Catalog
+// connection. There is no per-domain credential mapping: every discovered
+// domain is enumerated and later scanned with the single bound credential,
+// which forest-wide trust normally makes valid everywhere. Environments with
+// selective authentication need separate runs with per-domain credentials.
+func loadFromForest(opts *Options, log logger.LoggerInterface) ([]string, error) {
+	ldapOpts := &ldap.ClientOptions{
+		Domain:      opts.AuthDomain,
+		DCIP:        opts.AuthDCIP,
+		Username:    opts.AuthUser,
+		Password:    opts.AuthPassword,
+		Hashes:      opts.AuthHashes,
+		AuthKey:     opts.AuthKey,
+		UseLDAPS:    opts.UseLDAPS,
+		UseGC:       true,
+		UseKerberos: opts.UseKerberos,
+		WindowsAuth: opts.WindowsAuth,
+		KDCHost:     opts.KDCHost,
+	}
+
+	client, err := ldap.NewClient(ldapOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LDAP client: %w", err)
+	}
+
+	if err := client.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Global Catalog: %w", err)
+	}
+	defer client.Close()
+
+	domains, err := client.GetForestDomains()
+	if err != nil {
+		return nil, fmt.Errorf("failed to enumerate forest domains: %w", err)
+	}
+
+	log.Info(fmt.Sprintf("Found %d domains in the forest", len(domains)))
+
+	var targets []string
+	for _, domain := range domains {
+		computers, err := client.GetComputersInDomain(domain)
+		if err != nil {
+			log.Warning(fmt.Sprintf("Error fetching computers from domain '%s': %v", domain, err))
+			continue
+		}
+		log.Info(fmt.Sprintf("Found %d computers in domain '%s'", len(computers), domain))
+		targets = append(targets, computers...)
+	}
+
+	return targets, nil
+}
+
 // loadSubnetsFromAD loads subnet CIDRs from AD Sites and Services.
 func loadSubnetsFromAD(opts *Options, log logger.LoggerInterface) ([]string, error) {
 	ldapOpts := &ldap.ClientOptions{
@@ -228,24 +325,78 @@ func loadSubnetsFromAD(opts *Options, log logger.LoggerInterface) ([]string, err
 	return subnets, nil
 }
 
-// loadFromFile loads targets from a file, one per line.
-func loadFromFile(path string) ([]string, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
+// loadFromFile loads targets from a file, one per line. The path "-" reads
+// from standard input so other tools can pipe targets in directly.
+//
+// A first line of "host,site,tier,..." switches the file to extended CSV
+// mode: the first column is the target and the remaining columns, named by
+// the header, become tags that end up as host node properties and in
+// per-host reporting, so results can be filtered by business context.
+func loadFromFile(path string) ([]string, map[string]map[string]string, error) {
+	var reader io.Reader
+	if path == "-" {
+		reader = os.Stdin
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+		reader = file
 	}
-	defer file.Close()
 
 	var targets []string
-	scanner := bufio.NewScanner(file)
+	var header []string
+	tags := make(map[string]map[string]string)
+	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-		if line != "" && !strings.HasPrefix(line, "#") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if header == nil && len(targets) == 0 && strings.Contains(line, ",") {
+			fields := splitCSVLine(line)
+			if strings.EqualFold(fields[0], "host") {
+				header = fields
+				continue
+			}
+		}
+
+		if header == nil {
 			targets = append(targets, line)
+			continue
+		}
+
+		fields := splitCSVLine(line)
+		target := fields[0]
+		if target == "" {
+			continue
+		}
+		targets = append(targets, target)
+
+		targetTag := make(map[string]string)
+		for i := 1; i < len(fields) && i < len(header); i++ {
+			if header[i] != "" && fields[i] != "" {
+				targetTag[header[i]] = fields[i]
+			}
+		}
+		if len(targetTag) > 0 {
+			tags[target] = targetTag
 		}
 	}
 
-	return targets, scanner.Err()
+	return targets, tags, scanner.Err()
+}
+
+// splitCSVLine splits a line of the extended targets CSV on commas and trims
+// each field. Target names never contain commas, so no quoting is supported.
+func splitCSVLine(line string) []string {
+	fields := strings.Split(line, ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
 }
 
 // uniqueStrings returns unique strings sorted.
@@ -0,0 +1,56 @@
+package targets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTargetsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "targets.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write targets file: %v", err)
+	}
+	return path
+}
+
+func TestLoadFromFilePlain(t *testing.T) {
+	path := writeTargetsFile(t, "# comment\n192.168.1.10\n\nfs01.corp.example.com\n")
+
+	targets, tags, err := loadFromFile(path)
+	if err != nil {
+		t.Fatalf("loadFromFile: %v", err)
+	}
+	if len(targets) != 2 || targets[0] != "192.168.1.10" || targets[1] != "fs01.corp.example.com" {
+		t.Errorf("targets = %v, want [192.168.1.10 fs01.corp.example.com]", targets)
+	}
+	if len(tags) != 0 {
+		t.Errorf("plain file produced tags: %v", tags)
+	}
+}
+
+func TestLoadFromFileExtendedCSV(t *testing.T) {
+	path := writeTargetsFile(t, "host,site,tier,owner\nfs01.corp.example.com,HQ,0,finance\n192.168.1.0/30,DC2,1,\n")
+
+	targets, tags, err := loadFromFile(path)
+	if err != nil {
+		t.Fatalf("loadFromFile: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("targets = %v, want 2 entries", targets)
+	}
+
+	fs01 := tags["fs01.corp.example.com"]
+	if fs01["site"] != "HQ" || fs01["tier"] != "0" || fs01["owner"] != "finance" {
+		t.Errorf("fs01 tags = %v, want site=HQ tier=0 owner=finance", fs01)
+	}
+
+	cidr := tags["192.168.1.0/30"]
+	if cidr["site"] != "DC2" || cidr["tier"] != "1" {
+		t.Errorf("cidr tags = %v, want site=DC2 tier=1", cidr)
+	}
+	if _, ok := cidr["owner"]; ok {
+		t.Errorf("empty owner column must not produce a tag: %v", cidr)
+	}
+}
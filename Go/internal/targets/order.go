@@ -0,0 +1,119 @@
+package targets
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Ordering modes accepted by OrderTargets (--order-by).
+const (
+	OrderRandom            = "random"
+	OrderSubnetInterleaved = "subnet-interleaved"
+	OrderADSite            = "ad-site"
+)
+
+// Shuffle randomizes the target order in place.
+func Shuffle(targets []Target) {
+	rand.Shuffle(len(targets), func(i, j int) {
+		targets[i], targets[j] = targets[j], targets[i]
+	})
+}
+
+// OrderTargets reorders targets according to mode. "random" shuffles,
+// "subnet-interleaved" round-robins across /24 subnets, and "ad-site"
+// round-robins across AD sites using the subnet-to-site map from
+// GetSubnetSites. Both interleaved modes avoid hammering one subnet (or
+// site) with a sequential sweep.
+func OrderTargets(targets []Target, mode string, siteBySubnet map[string]string) ([]Target, error) {
+	switch mode {
+	case OrderRandom:
+		Shuffle(targets)
+		return targets, nil
+	case OrderSubnetInterleaved:
+		return interleaveBy(targets, SubnetKey), nil
+	case OrderADSite:
+		if len(siteBySubnet) == 0 {
+			return nil, fmt.Errorf("AD-site ordering requires subnet-to-site information from Active Directory")
+		}
+		return interleaveBy(targets, siteKeyFunc(siteBySubnet)), nil
+	default:
+		return nil, fmt.Errorf("unknown ordering mode %q (supported: %s, %s, %s)",
+			mode, OrderRandom, OrderSubnetInterleaved, OrderADSite)
+	}
+}
+
+// interleaveBy groups targets by key and emits them round-robin, one target
+// per group per round, preserving order within each group.
+func interleaveBy(targets []Target, key func(Target) string) []Target {
+	groups := make(map[string][]Target)
+	var keys []string
+	for _, t := range targets {
+		k := key(t)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], t)
+	}
+	sort.Strings(keys)
+
+	result := make([]Target, 0, len(targets))
+	for len(result) < len(targets) {
+		for _, k := range keys {
+			if len(groups[k]) > 0 {
+				result = append(result, groups[k][0])
+				groups[k] = groups[k][1:]
+			}
+		}
+	}
+	return result
+}
+
+// SubnetKey maps an IPv4 target to its /24 network; other target types each
+// form their own group so hostnames stay evenly spread. It is also the
+// grouping key for the per-subnet concurrency cap.
+func SubnetKey(t Target) string {
+	if t.Type == "ipv4" {
+		if idx := strings.LastIndex(t.Value, "."); idx > 0 {
+			return t.Value[:idx] + ".0/24"
+		}
+	}
+	return t.Type + ":" + t.Value
+}
+
+// siteKeyFunc maps IPv4 targets to AD site names by longest-prefix match
+// against the subnet CIDRs from AD Sites and Services. Targets that match no
+// subnet (and non-IPv4 targets) fall into a shared catch-all group.
+func siteKeyFunc(siteBySubnet map[string]string) func(Target) string {
+	type subnetSite struct {
+		net  *net.IPNet
+		site string
+	}
+	var subnets []subnetSite
+	for cidr, site := range siteBySubnet {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			subnets = append(subnets, subnetSite{net: ipNet, site: site})
+		}
+	}
+	// Longest prefix first so more specific subnets win
+	sort.Slice(subnets, func(i, j int) bool {
+		iOnes, _ := subnets[i].net.Mask.Size()
+		jOnes, _ := subnets[j].net.Mask.Size()
+		return iOnes > jOnes
+	})
+
+	return func(t Target) string {
+		if t.Type == "ipv4" {
+			if ip := net.ParseIP(t.Value); ip != nil {
+				for _, s := range subnets {
+					if s.net.Contains(ip) {
+						return s.site
+					}
+				}
+			}
+		}
+		return "(no site)"
+	}
+}
@@ -4,6 +4,7 @@ package utils
 import (
 	"context"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/miekg/dns"
@@ -97,3 +98,85 @@ func systemResolve(hostname string, timeout time.Duration) (string, error) {
 
 	return "", nil
 }
+
+// DNSReverse resolves an IP address to its PTR name using DNS. Like
+// DNSResolve it prefers the explicit nameserver, then the DC, then the
+// system resolver, and tries UDP before TCP. The returned name has no
+// trailing dot; an empty string means the address has no PTR record.
+func DNSReverse(ip string, nameserver string, dcIP string, timeout time.Duration) (string, error) {
+	var server string
+	if nameserver != "" {
+		server = nameserver
+	} else if dcIP != "" {
+		server = dcIP
+	} else {
+		return systemReverse(ip, timeout)
+	}
+
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	name, err := dnsReverseQuery(ip, server, false, timeout)
+	if err == nil && name != "" {
+		return name, nil
+	}
+
+	name, err = dnsReverseQuery(ip, server, true, timeout)
+	if err == nil && name != "" {
+		return name, nil
+	}
+
+	return "", err
+}
+
+// dnsReverseQuery performs a DNS PTR record query.
+func dnsReverseQuery(ip, server string, useTCP bool, timeout time.Duration) (string, error) {
+	arpa, err := dns.ReverseAddr(ip)
+	if err != nil {
+		return "", err
+	}
+
+	c := new(dns.Client)
+	c.Timeout = timeout
+	if useTCP {
+		c.Net = "tcp"
+	}
+
+	m := new(dns.Msg)
+	m.SetQuestion(arpa, dns.TypePTR)
+	m.RecursionDesired = true
+
+	r, _, err := c.Exchange(m, server)
+	if err != nil {
+		return "", err
+	}
+
+	if r.Rcode != dns.RcodeSuccess {
+		return "", nil
+	}
+
+	for _, ans := range r.Answer {
+		if ptr, ok := ans.(*dns.PTR); ok {
+			return strings.TrimSuffix(ptr.Ptr, "."), nil
+		}
+	}
+
+	return "", nil
+}
+
+// systemReverse uses the system resolver for the PTR lookup.
+func systemReverse(ip string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resolver := &net.Resolver{}
+	names, err := resolver.LookupAddr(ctx, ip)
+	if err != nil {
+		return "", err
+	}
+	if len(names) > 0 {
+		return strings.TrimSuffix(names[0], "."), nil
+	}
+	return "", nil
+}
@@ -55,21 +55,63 @@ func IsIPv4CIDR(s string) bool {
 
 // ExpandCIDR expands a CIDR notation to a list of IP addresses.
 func ExpandCIDR(cidr string) ([]string, error) {
-	ip, ipnet, err := net.ParseCIDR(cidr)
+	var ips []string
+	err := ExpandCIDREach(cidr, func(ip string) bool {
+		ips = append(ips, ip)
+		return true
+	})
 	if err != nil {
 		return nil, err
 	}
+	return ips, nil
+}
 
-	var ips []string
+// ExpandCIDREach streams the addresses of a CIDR to fn in order, stopping
+// early when fn returns false. Network and broadcast addresses are skipped
+// for /31 and larger networks, matching ExpandCIDR, but addresses are
+// produced one at a time so a wide prefix is never materialized in memory.
+func ExpandCIDREach(cidr string, fn func(ip string) bool) error {
+	ip, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	ones, bits := ipnet.Mask.Size()
+	hostBits := uint(bits - ones)
+	last := ^uint64(0)
+	if hostBits < 64 {
+		last = uint64(1)<<hostBits - 1
+	}
+
+	index := uint64(0)
 	for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); incIP(ip) {
-		ips = append(ips, ip.String())
+		// Skip network and broadcast addresses for /31 and larger networks
+		skip := hostBits >= 2 && (index == 0 || index == last)
+		index++
+		if skip {
+			continue
+		}
+		if !fn(ip.String()) {
+			return nil
+		}
 	}
+	return nil
+}
 
-	// Remove network and broadcast addresses for /31 and larger networks
-	if len(ips) > 2 {
-		return ips[1 : len(ips)-1], nil
+// CIDRHostCount returns the number of addresses ExpandCIDR would produce for
+// an IPv4 CIDR, computed from the prefix length without expanding it.
+func CIDRHostCount(cidr string) (int, error) {
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return 0, err
 	}
-	return ips, nil
+
+	ones, bits := ipnet.Mask.Size()
+	count := 1 << uint(bits-ones)
+	if count > 2 {
+		count -= 2
+	}
+	return count, nil
 }
 
 // incIP increments an IP address by one.
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestExpandCIDR(t *testing.T) {
+	tests := []struct {
+		cidr  string
+		count int
+		first string
+		last  string
+	}{
+		{"192.168.1.0/30", 2, "192.168.1.1", "192.168.1.2"},
+		{"192.168.1.0/24", 254, "192.168.1.1", "192.168.1.254"},
+		{"10.0.0.4/31", 2, "10.0.0.4", "10.0.0.5"},
+		{"10.0.0.4/32", 1, "10.0.0.4", "10.0.0.4"},
+	}
+	for _, tt := range tests {
+		ips, err := ExpandCIDR(tt.cidr)
+		if err != nil {
+			t.Errorf("ExpandCIDR(%q) error: %v", tt.cidr, err)
+			continue
+		}
+		if len(ips) != tt.count {
+			t.Errorf("ExpandCIDR(%q) returned %d addresses, want %d", tt.cidr, len(ips), tt.count)
+			continue
+		}
+		if ips[0] != tt.first || ips[len(ips)-1] != tt.last {
+			t.Errorf("ExpandCIDR(%q) = %s..%s, want %s..%s", tt.cidr, ips[0], ips[len(ips)-1], tt.first, tt.last)
+		}
+	}
+
+	if _, err := ExpandCIDR("not-a-cidr"); err == nil {
+		t.Error("ExpandCIDR(not-a-cidr) expected error, got nil")
+	}
+}
+
+func TestExpandCIDREachStopsEarly(t *testing.T) {
+	var seen []string
+	err := ExpandCIDREach("10.0.0.0/24", func(ip string) bool {
+		seen = append(seen, ip)
+		return len(seen) < 3
+	})
+	if err != nil {
+		t.Fatalf("ExpandCIDREach error: %v", err)
+	}
+	if len(seen) != 3 {
+		t.Errorf("callback ran %d times after returning false at 3", len(seen))
+	}
+	if seen[0] != "10.0.0.1" {
+		t.Errorf("first address = %s, want 10.0.0.1 (network address must be skipped)", seen[0])
+	}
+}
+
+func TestCIDRHostCount(t *testing.T) {
+	tests := []struct {
+		cidr  string
+		count int
+	}{
+		{"10.0.0.0/8", 16777214},
+		{"172.16.0.0/16", 65534},
+		{"192.168.1.0/24", 254},
+		{"10.0.0.4/31", 2},
+		{"10.0.0.4/32", 1},
+	}
+	for _, tt := range tests {
+		count, err := CIDRHostCount(tt.cidr)
+		if err != nil {
+			t.Errorf("CIDRHostCount(%q) error: %v", tt.cidr, err)
+			continue
+		}
+		if count != tt.count {
+			t.Errorf("CIDRHostCount(%q) = %d, want %d", tt.cidr, count, tt.count)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import "strings"
+
+// PathBuilder builds the per-entry relative and UNC paths of a share
+// traversal. The UNC prefix (\\host\share\) is computed once and a scratch
+// buffer is reused across entries, so building paths for millions of files
+// does not allocate beyond the result strings themselves. A builder belongs
+// to one traversal goroutine and must not be shared.
+type PathBuilder struct {
+	prefix string
+	buf    []byte
+}
+
+// NewPathBuilder creates a builder for paths under \\host\share\.
+func NewPathBuilder(host, share string) *PathBuilder {
+	return &PathBuilder{prefix: "\\\\" + host + "\\" + share + "\\"}
+}
+
+// Join appends name to dir with the SMB separator. Unlike filepath.Join it
+// is OS-independent (always backslash, matching the separator the rest of
+// the traversal uses) and performs no cleaning — entry names come straight
+// from directory listings and never contain separators or dot components.
+func (b *PathBuilder) Join(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	b.buf = append(b.buf[:0], dir...)
+	b.buf = append(b.buf, '\\')
+	b.buf = append(b.buf, name...)
+	return string(b.buf)
+}
+
+// UNC builds the UNC path for relPath with the same normalization and
+// extended-length escaping as UNCPath, using the precomputed prefix.
+func (b *PathBuilder) UNC(relPath string) string {
+	if strings.ContainsRune(relPath, '/') {
+		relPath = strings.ReplaceAll(relPath, "/", "\\")
+	}
+	b.buf = append(b.buf[:0], b.prefix...)
+	b.buf = append(b.buf, relPath...)
+	p := NormalizeName(string(b.buf))
+
+	if len(p) >= maxPath || strings.ContainsAny(relPath, uncReservedChars) {
+		return "\\\\?\\UNC\\" + strings.TrimPrefix(p, "\\\\")
+	}
+	return p
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPathBuilderJoin(t *testing.T) {
+	pb := NewPathBuilder("srv", "data")
+
+	tests := []struct {
+		dir, name, want string
+	}{
+		{"", "file.txt", "file.txt"},
+		{"docs", "file.txt", "docs\\file.txt"},
+		{"docs\\2024", "report.pdf", "docs\\2024\\report.pdf"},
+	}
+	for _, tt := range tests {
+		if got := pb.Join(tt.dir, tt.name); got != tt.want {
+			t.Errorf("Join(%q, %q) = %q, want %q", tt.dir, tt.name, got, tt.want)
+		}
+	}
+
+	// The scratch buffer must not alias previously returned strings
+	first := pb.Join("a", "b")
+	pb.Join("ccc", "ddd")
+	if first != "a\\b" {
+		t.Errorf("earlier result was clobbered by buffer reuse: %q", first)
+	}
+}
+
+// TestPathBuilderUNCMatchesUNCPath pins the builder to the reference
+// implementation across the interesting cases: plain paths, normalization,
+// reserved characters and the MAX_PATH escape.
+func TestPathBuilderUNCMatchesUNCPath(t *testing.T) {
+	pb := NewPathBuilder("fileserver01", "share")
+
+	paths := []string{
+		"file.txt",
+		"docs\\2024\\report.pdf",
+		"docs/legacy/readme.md",
+		"mixed\\sub/dir\\file",
+		"backup|2024<old>.bak",
+		"cafe\u0301\\re\u0301sume\u0301.doc", // NFD input, must compose
+		"deep\\" + strings.Repeat("x", 300) + "\\f.txt",
+	}
+	for _, relPath := range paths {
+		want := UNCPath("fileserver01", "share", relPath)
+		if got := pb.UNC(relPath); got != want {
+			t.Errorf("UNC(%q) = %q, want %q", relPath, got, want)
+		}
+	}
+}
+
+func BenchmarkPathBuilder(b *testing.B) {
+	pb := NewPathBuilder("fileserver01", "share")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fullPath := pb.Join("docs\\projects\\2024", "quarterly-report.xlsx")
+		_ = pb.UNC(fullPath)
+	}
+}
+
+// BenchmarkPathBuilderBaseline measures the construction the builder
+// replaced, for comparison.
+func BenchmarkPathBuilderBaseline(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fullPath := filepath.Join("docs\\projects\\2024", "quarterly-report.xlsx")
+		_ = UNCPath("fileserver01", "share", fullPath)
+	}
+}
@@ -9,6 +9,11 @@ const (
 	STYPE_IPC       = 0x3        // Interprocess communication (IPC)
 	STYPE_SPECIAL   = 0x80000000 // Administrative share (IPC$, ADMIN$, C$, etc.)
 	STYPE_TEMPORARY = 0x40000000 // Temporary share
+
+	// Failover cluster share flags
+	STYPE_CLUSTER_FS   = 0x02000000 // Cluster share
+	STYPE_CLUSTER_SOFS = 0x04000000 // Scale-out cluster share
+	STYPE_CLUSTER_DFS  = 0x08000000 // DFS share in a cluster
 )
 
 // Share type names
@@ -17,8 +22,11 @@ const (
 	STYPE_NAME_PRINTQ    = "STYPE_PRINTQ"
 	STYPE_NAME_DEVICE    = "STYPE_DEVICE"
 	STYPE_NAME_IPC       = "STYPE_IPC"
-	STYPE_NAME_SPECIAL   = "STYPE_SPECIAL"
-	STYPE_NAME_TEMPORARY = "STYPE_TEMPORARY"
+	STYPE_NAME_SPECIAL      = "STYPE_SPECIAL"
+	STYPE_NAME_TEMPORARY    = "STYPE_TEMPORARY"
+	STYPE_NAME_CLUSTER_FS   = "STYPE_CLUSTER_FS"
+	STYPE_NAME_CLUSTER_SOFS = "STYPE_CLUSTER_SOFS"
+	STYPE_NAME_CLUSTER_DFS  = "STYPE_CLUSTER_DFS"
 )
 
 // STYPEMask extracts share type flags from a share type value.
@@ -46,6 +54,15 @@ func STYPEMask(stypeValue uint32) []string {
 	if stypeValue&STYPE_TEMPORARY == STYPE_TEMPORARY {
 		flags = append(flags, STYPE_NAME_TEMPORARY)
 	}
+	if stypeValue&STYPE_CLUSTER_FS == STYPE_CLUSTER_FS {
+		flags = append(flags, STYPE_NAME_CLUSTER_FS)
+	}
+	if stypeValue&STYPE_CLUSTER_SOFS == STYPE_CLUSTER_SOFS {
+		flags = append(flags, STYPE_NAME_CLUSTER_SOFS)
+	}
+	if stypeValue&STYPE_CLUSTER_DFS == STYPE_CLUSTER_DFS {
+		flags = append(flags, STYPE_NAME_CLUSTER_DFS)
+	}
 
 	return flags
 }
@@ -64,3 +81,13 @@ func IsSpecialShare(stypeValue uint32) bool {
 func IsIPCShare(stypeValue uint32) bool {
 	return (stypeValue & 0b11) == STYPE_IPC
 }
+
+// IsTemporaryShare returns true if the share is temporary.
+func IsTemporaryShare(stypeValue uint32) bool {
+	return stypeValue&STYPE_TEMPORARY == STYPE_TEMPORARY
+}
+
+// IsClusterShare returns true if the share is served by a failover cluster.
+func IsClusterShare(stypeValue uint32) bool {
+	return stypeValue&(STYPE_CLUSTER_FS|STYPE_CLUSTER_SOFS|STYPE_CLUSTER_DFS) != 0
+}
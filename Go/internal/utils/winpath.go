@@ -0,0 +1,67 @@
+// Package utils provides utility functions for ShareHound.
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxPath is the classic Windows MAX_PATH limit. Paths at or beyond this
+// length break most GUI tools and Win32 APIs unless they are written in
+// extended-length (\\?\) form.
+const maxPath = 260
+
+// uncReservedChars are characters that are valid in SMB wire names but
+// reserved in Win32 path parsing; paths containing them are only usable
+// through the extended-length prefix, which disables that parsing.
+const uncReservedChars = "<>\"|?*"
+
+// UNCPath builds the UNC path for an element of a share, normalizing
+// forward slashes and cleaning up invalid UTF-8 (for example unpaired
+// UTF-16 surrogates decoded from the wire). When the result would exceed
+// MAX_PATH or contains Win32-reserved characters, it is returned in
+// extended-length form (\\?\UNC\server\share\...) so the recorded path
+// stays usable by Windows tooling.
+func UNCPath(host, share, relPath string) string {
+	relPath = strings.ReplaceAll(relPath, "/", "\\")
+	p := NormalizeName("\\\\" + host + "\\" + share + "\\" + relPath)
+
+	if len(p) >= maxPath || strings.ContainsAny(relPath, uncReservedChars) {
+		return "\\\\?\\UNC\\" + strings.TrimPrefix(p, "\\\\")
+	}
+	return p
+}
+
+// SanitizeName replaces invalid UTF-8 sequences in a name received from a
+// remote server with the Unicode replacement character. SMB transfers names
+// as UTF-16, and servers can legally send unpaired surrogates that decode
+// into byte sequences JSON encoders and graph consumers disagree on;
+// normalizing them here keeps node IDs stable and exports valid.
+func SanitizeName(name string) string {
+	if utf8.ValidString(name) {
+		return name
+	}
+	return strings.ToValidUTF8(name, string(utf8.RuneError))
+}
+
+// NormalizeName prepares a server-supplied name for use in node IDs and
+// exported properties: invalid UTF-8 is replaced, control characters are
+// dropped, and the result is composed to NFC so visually identical names
+// (for example NFD names written by macOS clients) dedup to the same key.
+// The wire-exact name must still be used for SMB operations — servers
+// resolve paths byte-for-byte, not by canonical equivalence.
+func NormalizeName(name string) string {
+	name = SanitizeName(name)
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		name = strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, name)
+	}
+	return norm.NFC.String(name)
+}
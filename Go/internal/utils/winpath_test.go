@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUNCPathShort(t *testing.T) {
+	got := UNCPath("fileserver01", "data", "finance\\q3\\report.xlsx")
+	want := "\\\\fileserver01\\data\\finance\\q3\\report.xlsx"
+	if got != want {
+		t.Errorf("UNCPath = %q, want %q", got, want)
+	}
+}
+
+func TestUNCPathNormalizesForwardSlashes(t *testing.T) {
+	got := UNCPath("srv", "share", "a/b/c.txt")
+	want := "\\\\srv\\share\\a\\b\\c.txt"
+	if got != want {
+		t.Errorf("UNCPath = %q, want %q", got, want)
+	}
+}
+
+func TestUNCPathLongPathUsesExtendedForm(t *testing.T) {
+	deep := strings.Repeat("subdirectory\\", 25) + "file.txt"
+	got := UNCPath("srv", "share", deep)
+
+	if !strings.HasPrefix(got, "\\\\?\\UNC\\srv\\share\\") {
+		t.Errorf("Expected extended-length form for a %d-char path, got %q", len(deep), got)
+	}
+	if strings.Count(got, "\\\\") != 1 {
+		t.Errorf("Extended form must not contain the plain \\\\ prefix twice: %q", got)
+	}
+}
+
+func TestUNCPathBoundary(t *testing.T) {
+	// A path one character below MAX_PATH stays in plain form
+	prefix := "\\\\srv\\share\\"
+	rel := strings.Repeat("a", maxPath-len(prefix)-1)
+	if got := UNCPath("srv", "share", rel); strings.HasPrefix(got, "\\\\?\\") {
+		t.Errorf("Path of length %d should stay in plain form: %q", len(prefix)+len(rel), got)
+	}
+	rel += "a"
+	if got := UNCPath("srv", "share", rel); !strings.HasPrefix(got, "\\\\?\\UNC\\") {
+		t.Errorf("Path of length %d should use extended form: %q", len(prefix)+len(rel), got)
+	}
+}
+
+func TestUNCPathReservedCharacters(t *testing.T) {
+	got := UNCPath("srv", "share", "what?.txt")
+	if !strings.HasPrefix(got, "\\\\?\\UNC\\") {
+		t.Errorf("Expected extended form for a reserved character, got %q", got)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Plain ASCII", "report.txt", "report.txt"},
+		{"Valid multibyte", "レポート.txt", "レポート.txt"},
+		{"Invalid UTF-8 byte", "bad\xff.txt", "bad�.txt"},
+		// ToValidUTF8 coalesces a run of invalid bytes into one replacement
+		{"Unpaired surrogate encoding", "x\xed\xa0\x80y", "x�y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeName(tt.input); got != tt.want {
+				t.Errorf("SanitizeName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Plain ASCII untouched", "Quarterly Report.docx", "Quarterly Report.docx"},
+		{"CJK untouched", "财务报表2024.xlsx", "财务报表2024.xlsx"},
+		{"Emoji untouched", "📁 backups", "📁 backups"},
+		// NFD (e + combining acute) composes to NFC (é)
+		{"NFD composes to NFC", "re\u0301sume\u0301.pdf", "r\u00e9sum\u00e9.pdf"},
+		{"Control characters dropped", "evil\x00name\x1b[31m.txt", "evilname[31m.txt"},
+		{"Invalid UTF-8 replaced", "bad\xff.txt", "bad�.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeName(tt.input); got != tt.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeNameDedupsEquivalentForms(t *testing.T) {
+	nfc := "caf\u00e9"
+	nfd := "cafe\u0301"
+	if nfc == nfd {
+		t.Fatal("test inputs must differ byte-wise")
+	}
+	if NormalizeName(nfc) != NormalizeName(nfd) {
+		t.Errorf("NFC %q and NFD %q must normalize to the same key", nfc, nfd)
+	}
+}
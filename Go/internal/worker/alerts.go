@@ -0,0 +1,63 @@
+// Package worker provides worker pool functionality for processing targets.
+package worker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+// webhookTimeout bounds the alert POST so a slow endpoint cannot stall a worker.
+const webhookTimeout = 10 * time.Second
+
+// worldWritableSIDs are the principals that make a writable share "world
+// writable": Everyone and Authenticated Users.
+var worldWritableSIDs = []string{"S-1-1-0", "S-1-5-11"}
+
+// isWorldWritable reports whether the share-level rights grant write access
+// to Everyone or Authenticated Users.
+func isWorldWritable(shareRights graph.ShareRights) bool {
+	for _, sid := range worldWritableSIDs {
+		for _, kind := range shareRights[sid] {
+			switch kind {
+			case kinds.EdgeKindCanGenericWrite, kinds.EdgeKindCanGenericAll, kinds.EdgeKindCanShareWrite:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// notifyWebhook fires a JSON POST to the configured alert webhook. Failures
+// are logged at debug level only — alerting must never break a scan.
+func notifyWebhook(webhookURL, remoteName, shareName string, log logger.LoggerInterface) {
+	payload, err := json.Marshal(map[string]string{
+		"event":     "world_writable_share",
+		"host":      remoteName,
+		"share":     shareName,
+		"unc":       fmt.Sprintf("\\\\%s\\%s", remoteName, shareName),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to build webhook payload: %v", err))
+		return
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		log.Debug(fmt.Sprintf("Webhook notification failed: %v", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Debug(fmt.Sprintf("Webhook endpoint returned status %d", resp.StatusCode))
+	}
+}
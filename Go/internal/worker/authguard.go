@@ -0,0 +1,62 @@
+package worker
+
+import "sync"
+
+// AuthGuard is a circuit breaker against account lockout. It counts
+// consecutive authentication failures across hosts and trips once the
+// threshold is reached; after that, connection attempts are refused without
+// touching the network so the audit account is not locked out by hundreds
+// of further logon attempts. A successful authentication resets the count,
+// matching how domain lockout policies track consecutive failures. A scan
+// runs with one credential, so one guard covers it.
+type AuthGuard struct {
+	mu        sync.Mutex
+	threshold int
+	failures  int
+	tripped   bool
+}
+
+// NewAuthGuard creates a guard that trips after threshold consecutive
+// authentication failures.
+func NewAuthGuard(threshold int) *AuthGuard {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &AuthGuard{threshold: threshold}
+}
+
+// Threshold returns the configured failure threshold.
+func (g *AuthGuard) Threshold() int {
+	return g.threshold
+}
+
+// Tripped reports whether the credential has been suspended.
+func (g *AuthGuard) Tripped() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.tripped
+}
+
+// RecordSuccess resets the consecutive failure count.
+func (g *AuthGuard) RecordSuccess() {
+	g.mu.Lock()
+	g.failures = 0
+	g.mu.Unlock()
+}
+
+// RecordFailure counts one authentication failure and returns true when
+// this failure is the one that tripped the guard, so the caller can warn
+// exactly once.
+func (g *AuthGuard) RecordFailure() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.tripped {
+		return false
+	}
+	g.failures++
+	if g.failures >= g.threshold {
+		g.tripped = true
+		return true
+	}
+	return false
+}
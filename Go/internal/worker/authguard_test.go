@@ -0,0 +1,37 @@
+package worker
+
+import "testing"
+
+func TestAuthGuardTripsAtThreshold(t *testing.T) {
+	guard := NewAuthGuard(3)
+
+	if guard.RecordFailure() || guard.RecordFailure() {
+		t.Fatal("guard tripped before the threshold")
+	}
+	if guard.Tripped() {
+		t.Fatal("Tripped() = true before the threshold")
+	}
+	if !guard.RecordFailure() {
+		t.Fatal("third failure did not trip the guard")
+	}
+	if !guard.Tripped() {
+		t.Fatal("Tripped() = false after tripping")
+	}
+	// Only the tripping failure reports true, so the warning fires once
+	if guard.RecordFailure() {
+		t.Error("RecordFailure returned true after the guard already tripped")
+	}
+}
+
+func TestAuthGuardSuccessResetsStreak(t *testing.T) {
+	guard := NewAuthGuard(2)
+
+	guard.RecordFailure()
+	guard.RecordSuccess()
+	if guard.RecordFailure() {
+		t.Fatal("guard tripped although a success reset the streak")
+	}
+	if !guard.RecordFailure() {
+		t.Fatal("guard did not trip after two consecutive failures")
+	}
+}
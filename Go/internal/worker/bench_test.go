@@ -0,0 +1,24 @@
+package worker
+
+import (
+	"runtime"
+	"testing"
+)
+
+// BenchmarkLimiter measures the acquire/release cost of the global
+// concurrency limiter under contention. Every host a worker picks up goes
+// through one acquire/release pair, so this bounds pool dispatch overhead
+// (hosts/minute is never limited by it in practice — network dominates —
+// but a regression here shows up on large target lists). Compare against
+// test/bench/baseline.txt.
+func BenchmarkLimiter(b *testing.B) {
+	limiter := NewLimiter(runtime.GOMAXPROCS(0) * 2)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			limiter.Acquire()
+			limiter.Release()
+		}
+	})
+}
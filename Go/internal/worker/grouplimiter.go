@@ -0,0 +1,43 @@
+package worker
+
+import "sync"
+
+// GroupLimiter caps concurrency independently per group key. It complements
+// the global Limiter: the global limit bounds total in-flight hosts while a
+// GroupLimiter keyed by subnet keeps any single network segment from
+// absorbing too many of them at once. Limiters are created lazily per key
+// and share one capacity.
+type GroupLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	groups map[string]*Limiter
+}
+
+// NewGroupLimiter creates a GroupLimiter with the given per-group capacity.
+func NewGroupLimiter(limit int) *GroupLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+	return &GroupLimiter{limit: limit, groups: make(map[string]*Limiter)}
+}
+
+// Acquire blocks until a slot is available in the group's limiter.
+func (g *GroupLimiter) Acquire(key string) {
+	g.group(key).Acquire()
+}
+
+// Release frees a slot in the group's limiter.
+func (g *GroupLimiter) Release(key string) {
+	g.group(key).Release()
+}
+
+func (g *GroupLimiter) group(key string) *Limiter {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	l, ok := g.groups[key]
+	if !ok {
+		l = NewLimiter(g.limit)
+		g.groups[key] = l
+	}
+	return l
+}
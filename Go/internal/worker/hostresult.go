@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/specterops/sharehound/internal/logger"
+)
+
+// HostResult is the per-host JSON sidecar written with --per-host-results.
+// It keeps partial data available even if the final graph export fails and
+// makes per-host troubleshooting possible without parsing the full graph.
+type HostResult struct {
+	Host       string            `json:"host"`
+	Address    string            `json:"address,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt time.Time         `json:"finishedAt"`
+	Duration   string            `json:"duration"`
+
+	Shares            []string `json:"shares,omitempty"`
+	SharesProcessed   int64    `json:"sharesProcessed"`
+	SharesAbandoned   []string `json:"sharesAbandoned,omitempty"`
+	Files             int64    `json:"files"`
+	Directories       int64    `json:"directories"`
+	AvgSDQueryLatency string   `json:"avgSDQueryLatency,omitempty"`
+
+	Errors []string `json:"errors,omitempty"`
+}
+
+// addError records a failed host operation in the sidecar. Safe to call on a
+// nil receiver so call sites don't have to guard on the feature being enabled.
+func (r *HostResult) addError(stage string, err error) {
+	if r == nil {
+		return
+	}
+	r.Errors = append(r.Errors, stage+": "+err.Error())
+}
+
+// writeHostResult writes the sidecar as <dir>/<host>.json. Failures are
+// logged at debug level only — sidecars must never break a scan.
+func writeHostResult(dir string, result *HostResult, log logger.LoggerInterface) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Debug(fmt.Sprintf("Failed to create per-host results directory: %v", err))
+		return
+	}
+
+	// IPv6 addresses contain colons, which are invalid in filenames on Windows
+	name := strings.ReplaceAll(result.Host, ":", "_") + ".json"
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Debug(fmt.Sprintf("Failed to marshal host result for %s: %v", result.Host, err))
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), append(data, '\n'), 0o644); err != nil {
+		log.Debug(fmt.Sprintf("Failed to write host result for %s: %v", result.Host, err))
+	}
+}
@@ -0,0 +1,60 @@
+package worker
+
+import "sync"
+
+// Limiter is a resizable counting semaphore used to cap global worker
+// concurrency. Unlike a buffered channel, its capacity can be changed while
+// slots are held: raising the limit unblocks waiters immediately, and
+// lowering it takes effect as held slots are released.
+type Limiter struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	limit int
+	inUse int
+}
+
+// NewLimiter creates a Limiter with the given initial capacity.
+func NewLimiter(limit int) *Limiter {
+	if limit < 1 {
+		limit = 1
+	}
+	l := &Limiter{limit: limit}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// Acquire blocks until a slot is available.
+func (l *Limiter) Acquire() {
+	l.mu.Lock()
+	for l.inUse >= l.limit {
+		l.cond.Wait()
+	}
+	l.inUse++
+	l.mu.Unlock()
+}
+
+// Release frees a slot.
+func (l *Limiter) Release() {
+	l.mu.Lock()
+	l.inUse--
+	l.cond.Broadcast()
+	l.mu.Unlock()
+}
+
+// SetLimit changes the capacity at runtime.
+func (l *Limiter) SetLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+	l.mu.Lock()
+	l.limit = limit
+	l.cond.Broadcast()
+	l.mu.Unlock()
+}
+
+// Limit returns the current capacity.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/specterops/sharehound/internal/graph"
+	"github.com/specterops/sharehound/internal/logger"
+	"github.com/specterops/sharehound/internal/smb"
+	"github.com/specterops/sharehound/pkg/kinds"
+)
+
+// localGroupEdgeKinds maps the sampled BUILTIN alias RIDs to the standard
+// BloodHound edge kind their membership implies.
+var localGroupEdgeKinds = map[uint32]string{
+	smb.AliasAdministrators:     kinds.EdgeKindAdminTo,
+	smb.AliasRemoteDesktopUsers: kinds.EdgeKindCanRDP,
+}
+
+// collectLocalGroups samples local Administrators and Remote Desktop Users
+// membership via SAMR and emits AdminTo / CanRDP edges from each member to
+// the host's BloodHound Computer node, so local-admin context and share
+// exposure come from a single run. Best effort: querying SAMR remotely
+// usually needs admin rights on hardened hosts, so failures only get a
+// debug line and fewer edges.
+func collectLocalGroups(og *graph.OpenGraph, conn *smb.SMBSession, remoteName, credDomain string, log logger.LoggerInterface) {
+	members, err := conn.GetLocalGroupMembers(smb.AliasAdministrators, smb.AliasRemoteDesktopUsers)
+	if err != nil {
+		log.Debug(fmt.Sprintf("Local group sampling on %s unavailable: %v", remoteName, err))
+		return
+	}
+
+	// Same domain suffix derivation as the rights edges: the host's DNS
+	// domain when it has one, the credential domain otherwise.
+	domainSuffix := credDomain
+	if parts := strings.SplitN(remoteName, ".", 2); len(parts) == 2 {
+		domainSuffix = parts[1]
+	}
+
+	computer := strings.ToUpper(remoteName)
+	for rid, sids := range members {
+		edgeKind := localGroupEdgeKinds[rid]
+		for _, sid := range sids {
+			memberID := smb.SIDToObjectID(sid, domainSuffix)
+
+			// Mirror ensurePrincipalNode so the edge start does not dangle
+			// when AD collection has not ingested the principal.
+			nodeKinds := []string{kinds.NodeKindPrincipal}
+			if kind := smb.SIDPrincipalKind(sid); kind != "" {
+				nodeKinds = append(nodeKinds, kind)
+			}
+			memberNode := graph.NewNode(memberID, nodeKinds...)
+			memberNode.SetProperty("objectid", memberID)
+			if name := smb.GetWellKnownName(sid); name != "" {
+				memberNode.SetProperty("name", name)
+			}
+			og.AddNode(memberNode)
+
+			// Match the Computer end by name like the HostsNetworkShare
+			// edge, so the edge lands on the standard BloodHound node.
+			edge := graph.NewEdge(memberID, computer, edgeKind)
+			edge.SetEndMatchBy("name")
+			edge.SetEndKind("Computer")
+			if desc, ok := kinds.EdgeDescriptions[edgeKind]; ok {
+				edge.SetProperty("description", desc)
+			}
+			og.AddEdge(edge)
+		}
+		log.Debug(fmt.Sprintf("Local group sampling on %s: %d %s member(s)", remoteName, len(sids), edgeKind))
+	}
+}
@@ -16,8 +16,13 @@ import (
 // ConnectionPool manages SMB session connections per host with connection reuse.
 type ConnectionPool struct {
 	maxConnectionsPerHost int
+	port                  int           // TCP port new sessions dial (--smb-port)
+	minConnectInterval    time.Duration // minimum spacing between new connections per host
 	connections           map[string][]*smb.SMBSession
-	activeConnections     map[*smb.SMBSession]bool // Track connections currently in use
+	activeConnections     map[*smb.SMBSession]bool   // Track connections currently in use
+	primaries             map[string]*smb.SMBSession // session owning the one TCP transport per host
+	lastConnect           map[string]time.Time       // last time a new connection was opened per host
+	sdStats               *smb.SecurityQueryStats    // shared SD query timing, stamped on new sessions
 	mu                    sync.Mutex
 }
 
@@ -25,11 +30,42 @@ type ConnectionPool struct {
 func NewConnectionPool(maxConnectionsPerHost int) *ConnectionPool {
 	return &ConnectionPool{
 		maxConnectionsPerHost: maxConnectionsPerHost,
+		port:                  smb.PortSMBDirect,
 		connections:           make(map[string][]*smb.SMBSession),
 		activeConnections:     make(map[*smb.SMBSession]bool),
+		primaries:             make(map[string]*smb.SMBSession),
+		lastConnect:           make(map[string]time.Time),
 	}
 }
 
+// SetPort changes the TCP port new sessions dial, for environments where
+// SMB is not served on 445.
+func (p *ConnectionPool) SetPort(port int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if port > 0 {
+		p.port = port
+	}
+}
+
+// SetMinConnectInterval caps the rate of new connections per host: GetConnection
+// waits until at least d has passed since the last connection attempt to the
+// same host. Reused pooled connections are not delayed.
+func (p *ConnectionPool) SetMinConnectInterval(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.minConnectInterval = d
+}
+
+// SetSecurityQueryStats attaches a security descriptor query accumulator
+// that is stamped on every session the pool creates, so per-host timing can
+// be aggregated across all of the host's connections.
+func (p *ConnectionPool) SetSecurityQueryStats(stats *smb.SecurityQueryStats) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sdStats = stats
+}
+
 // GetConnection gets an available connection for the host, creating one if needed.
 func (p *ConnectionPool) GetConnection(
 	host, remoteName string,
@@ -54,16 +90,60 @@ func (p *ConnectionPool) GetConnection(
 		// Connection is dead, close it and remove from active
 		p.mu.Lock()
 		delete(p.activeConnections, conn)
+		if p.primaries[host] == conn {
+			delete(p.primaries, host)
+		}
 		p.mu.Unlock()
 		conn.Close()
 	} else {
 		p.mu.Unlock()
 	}
 
+	// Multiplex over the host's existing authenticated session when one is
+	// open: a new tree connect on the same transport costs no TCP handshake
+	// or authentication, so the connect rate cap does not apply.
+	p.mu.Lock()
+	primary := p.primaries[host]
+	p.mu.Unlock()
+	p.mu.Lock()
+	port := p.port
+	sdStats := p.sdStats
+	p.mu.Unlock()
+
+	if primary != nil {
+		session := smb.NewSMBSession(host, port, timeout, creds, remoteName, advertisedName, cfg, log)
+		session.SetSecurityQueryStats(sdStats)
+
+		p.mu.Lock()
+		p.activeConnections[session] = true
+		p.mu.Unlock()
+
+		if err := session.ConnectMultiplexed(primary); err == nil {
+			return session, nil
+		}
+		// Primary went away between the lookup and the attach; fall through
+		// and dial a fresh connection.
+		p.mu.Lock()
+		delete(p.activeConnections, session)
+		p.mu.Unlock()
+	}
+
+	// Enforce the per-host connection rate cap before opening a new session
+	p.mu.Lock()
+	if p.minConnectInterval > 0 {
+		if wait := p.minConnectInterval - time.Since(p.lastConnect[host]); wait > 0 {
+			p.mu.Unlock()
+			time.Sleep(wait)
+			p.mu.Lock()
+		}
+		p.lastConnect[host] = time.Now()
+	}
+	p.mu.Unlock()
+
 	// Create new connection
 	session := smb.NewSMBSession(
 		host,
-		445,
+		port,
 		timeout,
 		creds,
 		remoteName,
@@ -71,6 +151,7 @@ func (p *ConnectionPool) GetConnection(
 		cfg,
 		log,
 	)
+	session.SetSecurityQueryStats(sdStats)
 
 	// Track as active BEFORE Connect() so timeout can close it if stuck
 	p.mu.Lock()
@@ -85,6 +166,13 @@ func (p *ConnectionPool) GetConnection(
 		return nil, err
 	}
 
+	// First live connection to the host becomes the multiplexing primary
+	p.mu.Lock()
+	if _, ok := p.primaries[host]; !ok {
+		p.primaries[host] = session
+	}
+	p.mu.Unlock()
+
 	return session, nil
 }
 
@@ -96,7 +184,9 @@ func (p *ConnectionPool) ReturnConnection(host string, conn *smb.SMBSession) {
 	// Remove from active connections
 	delete(p.activeConnections, conn)
 
-	if len(p.connections[host]) < p.maxConnectionsPerHost {
+	// The primary owns the host's only TCP transport; closing it would break
+	// every multiplexed session attached to it, so it is always pooled.
+	if len(p.connections[host]) < p.maxConnectionsPerHost || p.primaries[host] == conn {
 		p.connections[host] = append(p.connections[host], conn)
 	} else {
 		// Pool is full, close the connection
@@ -115,6 +205,7 @@ func (p *ConnectionPool) CloseAll() {
 		}
 	}
 	p.connections = make(map[string][]*smb.SMBSession)
+	p.primaries = make(map[string]*smb.SMBSession)
 }
 
 // ForceCloseAll forcefully closes all connections, interrupting any blocking operations.
@@ -146,4 +237,5 @@ func (p *ConnectionPool) ForceCloseAll() {
 		}
 	}
 	p.connections = make(map[string][]*smb.SMBSession)
+	p.primaries = make(map[string]*smb.SMBSession)
 }
@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/credentials"
+	"github.com/specterops/sharehound/internal/logger"
+	"github.com/specterops/sharehound/internal/smb"
+)
+
+// The dial tests target SMB on loopback, where nothing listens in CI, so
+// GetConnection fails fast through the port probe while still exercising the
+// pool's bookkeeping around a dial attempt.
+const unreachableHost = "127.0.0.1"
+
+func testPoolDeps() (*credentials.Credentials, *config.Config, *logger.Logger) {
+	creds := &credentials.Credentials{
+		Username: "tester",
+		Password: "tester",
+	}
+	cfg := &config.Config{}
+	return creds, cfg, logger.NewLogger(cfg, "")
+}
+
+// TestGetConnectionFailureLeavesNoActiveEntry verifies that a failed dial is
+// fully untracked, so ForceCloseAll never touches half-built sessions.
+func TestGetConnectionFailureLeavesNoActiveEntry(t *testing.T) {
+	creds, cfg, log := testPoolDeps()
+	pool := NewConnectionPool(2)
+
+	if _, err := pool.GetConnection(unreachableHost, unreachableHost, creds, 500*time.Millisecond, "", cfg, log); err == nil {
+		t.Skip("something is answering SMB on loopback; skipping dial-failure test")
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if n := len(pool.activeConnections); n != 0 {
+		t.Errorf("activeConnections has %d entries after a failed dial, want 0", n)
+	}
+	if len(pool.primaries) != 0 {
+		t.Error("a failed dial was recorded as the host primary")
+	}
+}
+
+// TestGetConnectionHonorsConnectInterval verifies the per-host rate cap:
+// a second dial to the same host must not start before the configured
+// interval has elapsed since the first.
+func TestGetConnectionHonorsConnectInterval(t *testing.T) {
+	creds, cfg, log := testPoolDeps()
+	pool := NewConnectionPool(2)
+	const interval = 600 * time.Millisecond
+	pool.SetMinConnectInterval(interval)
+
+	start := time.Now()
+	// Both dials fail fast, but each one still counts against the rate cap.
+	pool.GetConnection(unreachableHost, unreachableHost, creds, 500*time.Millisecond, "", cfg, log) //nolint:errcheck
+	pool.GetConnection(unreachableHost, unreachableHost, creds, 500*time.Millisecond, "", cfg, log) //nolint:errcheck
+
+	if elapsed := time.Since(start); elapsed < interval {
+		t.Errorf("second dial started after %v, want at least %v between dials", elapsed, interval)
+	}
+}
+
+// TestReturnConnectionKeepsPrimaryBeyondCap verifies that the session owning
+// the host's TCP transport is pooled even when the pool is full, since
+// multiplexed siblings depend on it staying open.
+func TestReturnConnectionKeepsPrimaryBeyondCap(t *testing.T) {
+	creds, cfg, log := testPoolDeps()
+	pool := NewConnectionPool(1)
+
+	const host = "host.example"
+	primary := smb.NewSMBSession(host, 445, time.Second, creds, host, "", cfg, log)
+	extra := smb.NewSMBSession(host, 445, time.Second, creds, host, "", cfg, log)
+
+	pool.mu.Lock()
+	pool.primaries[host] = primary
+	pool.activeConnections[primary] = true
+	pool.activeConnections[extra] = true
+	pool.mu.Unlock()
+
+	pool.ReturnConnection(host, extra)   // fills the pool to its cap of 1
+	pool.ReturnConnection(host, primary) // over cap, but must still be kept
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	found := false
+	for _, conn := range pool.connections[host] {
+		if conn == primary {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("primary was dropped from the pool when over cap")
+	}
+}
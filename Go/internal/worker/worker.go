@@ -4,7 +4,10 @@ package worker
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
+	"net"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,6 +15,7 @@ import (
 
 	"github.com/specterops/sharehound/internal/collector"
 	"github.com/specterops/sharehound/internal/config"
+	"github.com/specterops/sharehound/internal/control"
 	"github.com/specterops/sharehound/internal/credentials"
 	"github.com/specterops/sharehound/internal/graph"
 	"github.com/specterops/sharehound/internal/logger"
@@ -28,15 +32,92 @@ type Options struct {
 	Creds               *credentials.Credentials
 	Timeout             time.Duration
 	HostTimeout         time.Duration
+	HostTimeoutPerShare time.Duration // extra host time budget per discovered share
+	HostTimeoutMax      time.Duration // upper bound on the adaptive host budget
 	AdvertisedName      string
 	MaxWorkersPerHost   int
 	GlobalMaxWorkers    int
 	Depth               int
 	Nameserver          string
+	Shares              []string
+	Port                int
 	Logfile             string
 	EffectiveAccessOnly bool
+	AccessCheckOnly     bool
+	Impersonated        map[string][]string   // test account SID -> token SIDs, from --impersonate
+	IgnoredSIDs         []string              // SID patterns whose rights edges are suppressed
+	DomainSID           string                // SID of the authenticated domain; classifies S-1-5-21-* principals as domain or machine-local
+	SkipLocalPrincipals bool                  // drop edges granted to machine-local SAM accounts instead of tagging them
+	RollupEdges         bool                  // emit aggregate HasNTFSAccess edges instead of granular CanNTFS* edges
+	IncludeEdgeKinds    []string              // when non-empty, only these permission edge kinds are collected
+	ExcludeEdgeKinds    []string              // permission edge kinds that are never collected
+	OmittedProperties   []string              // node properties stripped before emission (--minimal-properties)
+	AlertWebhook        string                // URL notified when a world-writable share is discovered
+	OpsecStrict         bool                  // strict OPSEC profile: jitter connections and cap per-host rate
+	SkipReport          *collector.SkipReport // machine-readable record of skipped shares and subtrees (nil = disabled)
+	PerHostResultsDir   string                // directory for per-host result sidecars (empty = disabled)
+	Control             *control.Server       // runtime control socket (nil = disabled)
+	AuthGuard           *AuthGuard            // lockout circuit breaker (nil = disabled)
+	LocalGroups         bool                  // sample local group membership via SAMR (--local-groups)
+	CanonicalIDs        bool                  // node IDs use the reverse-resolved, case-folded FQDN (--canonical-ids)
+	RawSDDL             bool                  // stamp the raw SDDL of each collected security descriptor on its node (--raw-sddl)
+
+	// Runtime overrides, adjustable over the control socket. Hosts pick up
+	// the current values when their processing starts.
+	workersPerHost  atomic.Int64 // overrides MaxWorkersPerHost when > 0
+	connectInterval atomic.Int64 // nanoseconds between new connections per host, when > 0
 }
 
+// shareRequested reports whether a share is on the --share list
+// (case-insensitive); an empty list requests every share.
+func (o *Options) shareRequested(name string) bool {
+	if len(o.Shares) == 0 {
+		return true
+	}
+	for _, s := range o.Shares {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetWorkersPerHost overrides the per-host worker cap at runtime.
+func (o *Options) SetWorkersPerHost(n int) {
+	o.workersPerHost.Store(int64(n))
+}
+
+// SetConnectInterval overrides the minimum spacing between new SMB
+// connections to the same host at runtime.
+func (o *Options) SetConnectInterval(d time.Duration) {
+	o.connectInterval.Store(int64(d))
+}
+
+func (o *Options) effectiveWorkersPerHost() int {
+	if n := o.workersPerHost.Load(); n > 0 {
+		return int(n)
+	}
+	return o.MaxWorkersPerHost
+}
+
+func (o *Options) effectiveConnectInterval() time.Duration {
+	if d := o.connectInterval.Load(); d > 0 {
+		return time.Duration(d)
+	}
+	if o.OpsecStrict {
+		return opsecMinConnectInterval
+	}
+	return 0
+}
+
+// Strict OPSEC profile tuning: each host connection is preceded by a random
+// delay of up to opsecConnectJitter, and new connections to the same host are
+// spaced at least opsecMinConnectInterval apart.
+const (
+	opsecConnectJitter      = 5 * time.Second
+	opsecMinConnectInterval = 2 * time.Second
+)
+
 // ProcessTarget processes a single target host.
 func ProcessTarget(
 	target targets.Target,
@@ -45,7 +126,6 @@ func ProcessTarget(
 	og *graph.OpenGraph,
 	parsedRules []rules.Rule,
 	results *collector.WorkerResults,
-	resultsLock *sync.Mutex,
 ) {
 	log := logger.NewLogger(cfg, opts.Logfile)
 
@@ -54,30 +134,59 @@ func ProcessTarget(
 	remoteName := target.Value
 
 	// Track active host (using target.Value as the display name)
-	resultsLock.Lock()
-	results.ActiveHosts++
-	if results.ActiveHostNames == nil {
-		results.ActiveHostNames = make(map[string]bool)
+	results.AddActiveHost(target.Value)
+	defer results.RemoveActiveHost(target.Value)
+
+	// Per-host result sidecar, written when the host finishes (success or
+	// failure) so partial data survives a failed final export
+	var hostResult *HostResult
+	if opts.PerHostResultsDir != "" {
+		hostResult = &HostResult{Host: target.Value, Tags: target.Tags, StartedAt: time.Now()}
+		defer func() {
+			if host != target.Value {
+				hostResult.Address = host
+			}
+			hostResult.FinishedAt = time.Now()
+			hostResult.Duration = hostResult.FinishedAt.Sub(hostResult.StartedAt).Round(time.Millisecond).String()
+			writeHostResult(opts.PerHostResultsDir, hostResult, log)
+		}()
 	}
-	results.ActiveHostNames[target.Value] = true
-	resultsLock.Unlock()
-	defer func() {
-		resultsLock.Lock()
-		results.ActiveHosts--
-		delete(results.ActiveHostNames, target.Value)
-		resultsLock.Unlock()
-	}()
 
 	// Set up host timeout FIRST - before any network operations
+	hostStart := time.Now()
 	var timeoutFlag atomic.Bool
 	timeoutFlag.Store(false)
 	var hostTimeoutTimer *time.Timer
 	var timeoutTicker *time.Ticker
 
 	// Create connection pool early so timeout can close connections
-	pool := NewConnectionPool(opts.MaxWorkersPerHost)
+	workersPerHost := opts.effectiveWorkersPerHost()
+	pool := NewConnectionPool(workersPerHost)
+	pool.SetPort(opts.Port)
 	defer pool.CloseAll()
 
+	// Aggregate security descriptor query timing across this host's sessions
+	sdStats := &smb.SecurityQueryStats{}
+	pool.SetSecurityQueryStats(sdStats)
+
+	// Let the control socket abort this host (skip-host) by reusing the
+	// host-timeout machinery: set the flag and tear down connections
+	if opts.Control != nil {
+		opts.Control.RegisterHost(target.Value, func() {
+			timeoutFlag.Store(true)
+			pool.ForceCloseAll()
+		})
+		defer opts.Control.UnregisterHost(target.Value)
+	}
+
+	if interval := opts.effectiveConnectInterval(); interval > 0 {
+		pool.SetMinConnectInterval(interval)
+	}
+	if opts.OpsecStrict {
+		// Stagger host connections so scan traffic doesn't arrive in a burst
+		time.Sleep(time.Duration(rand.Int64N(int64(opsecConnectJitter))))
+	}
+
 	if opts.HostTimeout > 0 {
 		hostTimeoutTimer = time.AfterFunc(opts.HostTimeout, func() {
 			timeoutFlag.Store(true)
@@ -108,31 +217,60 @@ func ProcessTarget(
 			resolved, err := utils.DNSResolve(target.Value, opts.Nameserver, "", opts.Timeout)
 			if err != nil || resolved == "" {
 				log.Debug("Failed to resolve domain name: " + target.Value)
-				resultsLock.Lock()
-				results.Errors++
-				results.TasksTotal++
-				results.TasksFinished++
-				resultsLock.Unlock()
+				if err == nil {
+					err = fmt.Errorf("DNS resolution returned no address")
+				}
+				recordHostError(results, target.Value, "dns", err)
+				hostResult.addError("dns", err)
+				results.Errors.Add(1)
+				results.TasksTotal.Add(1)
+				results.TasksFinished.Add(1)
 				return
 			}
 			host = resolved
 		}
 	}
 
+	// Canonical ID scheme: emit every node under the host's normalized,
+	// case-folded FQDN so scans addressing the same machine by IP and by
+	// name update one set of nodes instead of duplicating them. Share and
+	// path components keep the server-reported case, which is stable.
+	if opts.CanonicalIDs {
+		if target.Type == "ipv4" || target.Type == "ipv6" {
+			if ptr, err := utils.DNSReverse(target.Value, opts.Nameserver, "", opts.Timeout); err == nil && ptr != "" {
+				log.Debug(fmt.Sprintf("Canonical IDs: %s resolves to %s", target.Value, ptr))
+				remoteName = ptr
+			} else {
+				log.Debug(fmt.Sprintf("Canonical IDs: no PTR record for %s, keeping the address", target.Value))
+			}
+		}
+		remoteName = strings.ToLower(remoteName)
+	}
+
 	// Check timeout before port check
 	if timeoutFlag.Load() {
 		return
 	}
 
-	// Check if port 445 is open
-	ok, err := utils.IsPortOpen(host, 445, opts.Timeout)
+	// Check if the configured SMB port is open. When that port is 445, an
+	// open 139 also counts, so the session's NetBIOS fallback (which only
+	// engages once 445 turns out to be filtered) stays reachable.
+	ok, err := utils.IsPortOpen(host, opts.Port, opts.Timeout)
+	if !ok && opts.Port == smb.PortSMBDirect {
+		if ok139, _ := utils.IsPortOpen(host, smb.PortNetBIOS, opts.Timeout); ok139 {
+			ok = true
+		}
+	}
 	if !ok {
-		log.Debug(fmt.Sprintf("Port 445 is not open on %s: %v", host, err))
-		resultsLock.Lock()
-		results.Errors++
-		results.TasksTotal++
-		results.TasksFinished++
-		resultsLock.Unlock()
+		log.Debug(fmt.Sprintf("Port %d is not open on %s: %v", opts.Port, host, err))
+		if err == nil {
+			err = fmt.Errorf("port %d unreachable", opts.Port)
+		}
+		recordHostError(results, host, "port-check", err)
+		hostResult.addError("port-check", err)
+		results.Errors.Add(1)
+		results.TasksTotal.Add(1)
+		results.TasksFinished.Add(1)
 		return
 	}
 
@@ -141,17 +279,39 @@ func ProcessTarget(
 		return
 	}
 
+	// Refuse to present the credential again once the lockout circuit
+	// breaker has tripped
+	if opts.AuthGuard != nil && opts.AuthGuard.Tripped() {
+		err := fmt.Errorf("credential suspended after %d consecutive authentication failures", opts.AuthGuard.Threshold())
+		recordHostError(results, host, "auth-guard", err)
+		hostResult.addError("auth-guard", err)
+		results.Errors.Add(1)
+		results.TasksTotal.Add(1)
+		results.TasksFinished.Add(1)
+		return
+	}
+
 	// Get initial connection to discover shares
 	conn, err := pool.GetConnection(host, remoteName, opts.Creds, opts.Timeout, opts.AdvertisedName, cfg, log)
 	if err != nil {
 		log.Debug("Failed to initialize SMB session: " + err.Error())
-		resultsLock.Lock()
-		results.Errors++
-		results.TasksTotal++
-		results.TasksFinished++
-		resultsLock.Unlock()
+		if opts.AuthGuard != nil && smb.ClassifyError(err).Category == smb.ErrorCategoryAuth {
+			if opts.AuthGuard.RecordFailure() {
+				// Use stderr directly to ensure visibility (progress bar may overwrite log output)
+				fmt.Fprintf(os.Stderr, "\n\n=== CREDENTIAL SUSPENDED === %d consecutive logon failures, refusing further authentication to protect the account from lockout ===\n\n", opts.AuthGuard.Threshold())
+				log.Error(fmt.Sprintf("Suspending credential after %d consecutive authentication failures; remaining hosts will be skipped (tune with --max-auth-failures)", opts.AuthGuard.Threshold()))
+			}
+		}
+		recordHostError(results, host, "connect", err)
+		hostResult.addError("connect", err)
+		results.Errors.Add(1)
+		results.TasksTotal.Add(1)
+		results.TasksFinished.Add(1)
 		return
 	}
+	if opts.AuthGuard != nil {
+		opts.AuthGuard.RecordSuccess()
+	}
 
 	// Check timeout before listing shares
 	if timeoutFlag.Load() {
@@ -163,38 +323,87 @@ func ProcessTarget(
 	shares, err := conn.ListShares()
 	if err != nil {
 		log.Debug("Failed to list shares: " + err.Error())
+		recordHostError(results, host, "list-shares", err)
+		hostResult.addError("list-shares", err)
 		pool.ReturnConnection(host, conn)
-		resultsLock.Lock()
-		results.Errors++
-		results.TasksTotal++
-		results.TasksFinished++
-		resultsLock.Unlock()
+		results.Errors.Add(1)
+		results.TasksTotal.Add(1)
+		results.TasksFinished.Add(1)
 		return
 	}
 
+	// Keep only the shares requested with --share
+	if len(opts.Shares) > 0 {
+		for name := range shares {
+			if !opts.shareRequested(name) {
+				delete(shares, name)
+			}
+		}
+	}
+
 	log.Debug(fmt.Sprintf("Found %d shares on %s", len(shares), host))
 
+	if hostResult != nil {
+		for name := range shares {
+			hostResult.Shares = append(hostResult.Shares, name)
+		}
+		sort.Strings(hostResult.Shares)
+	}
+
+	// Grow the host timeout budget with the share count so file servers with
+	// hundreds of shares aren't cut off by a flat --host-timeout
+	if hostTimeoutTimer != nil && opts.HostTimeoutPerShare > 0 && !timeoutFlag.Load() {
+		budget := opts.HostTimeout + time.Duration(len(shares))*opts.HostTimeoutPerShare
+		if opts.HostTimeoutMax > 0 && budget > opts.HostTimeoutMax {
+			budget = opts.HostTimeoutMax
+		}
+		if remaining := budget - time.Since(hostStart); remaining > 0 {
+			hostTimeoutTimer.Reset(remaining)
+			log.Debug(fmt.Sprintf("Host timeout budget for %s extended to %v (%d shares)", host, budget, len(shares)))
+		}
+	}
+
 	// Update task counters
-	resultsLock.Lock()
-	results.TasksTotal += int64(len(shares))
-	results.TasksPending += int64(len(shares))
-	results.SharesPending += int64(len(shares))
-	resultsLock.Unlock()
+	results.TasksTotal.Add(int64(len(shares)))
+	results.TasksPending.Add(int64(len(shares)))
+	results.SharesPending.Add(int64(len(shares)))
 
 	if len(shares) == 0 {
 		pool.ReturnConnection(host, conn)
-		resultsLock.Lock()
-		results.Success++
-		results.TasksTotal++
-		results.TasksFinished++
-		resultsLock.Unlock()
+		results.Success.Add(1)
+		results.TasksTotal.Add(1)
+		results.TasksFinished.Add(1)
 		return
 	}
 
+	// Enrich the host node with server role info and, when permitted, who
+	// else is connected. Both are best effort: they require SRVSVC (disabled
+	// under strict OPSEC) and session enumeration usually needs admin rights.
+	hostProps, clusterName := collectHostInfo(conn, log)
+	if clusterName != "" {
+		// Failover cluster awareness: the target answered as a cluster
+		// virtual server, so emit shares under the cluster's advertised name
+		// rather than the address we happened to reach it by.
+		if canonical := canonicalHostName(remoteName, clusterName); !strings.EqualFold(canonical, remoteName) {
+			log.Info(fmt.Sprintf("Host %s answers as cluster virtual server %s; emitting shares under %s", remoteName, clusterName, canonical))
+			remoteName = canonical
+		}
+	}
+
+	// Business-context tags from the extended targets CSV become host node
+	// properties so results can be filtered by site/tier/owner downstream.
+	for key, value := range target.Tags {
+		hostProps[key] = value
+	}
+
+	if opts.LocalGroups {
+		collectLocalGroups(og, conn, remoteName, opts.Creds.Domain, log)
+	}
+
 	pool.ReturnConnection(host, conn)
 
 	// Create semaphore for per-host concurrency
-	hostSem := semaphore.NewWeighted(int64(opts.MaxWorkersPerHost))
+	hostSem := semaphore.NewWeighted(int64(workersPerHost))
 
 	// Create a cancellable context for semaphore acquisition.
 	// When the host timeout fires, cancel this context so goroutines
@@ -231,6 +440,10 @@ func ProcessTarget(
 	var totalFileCount, skippedFilesCount, processedFilesCount int64
 	var totalDirCount, skippedDirsCount, processedDirsCount int64
 
+	// Shares never processed because the host timeout budget ran out
+	var abandonedMu sync.Mutex
+	var abandonedShares []string
+
 	for shareName, shareInfo := range shares {
 		wg.Add(1)
 		go func(name string, info smb.ShareInfo) {
@@ -240,21 +453,23 @@ func ProcessTarget(
 			// unblocks all waiting goroutines immediately
 			if err := hostSem.Acquire(semCtx, 1); err != nil {
 				// Context cancelled (host timeout) or other error
-				resultsLock.Lock()
-				results.TasksPending--
-				results.TasksFinished++
-				resultsLock.Unlock()
+				results.TasksPending.Add(-1)
+				results.TasksFinished.Add(1)
+				abandonedMu.Lock()
+				abandonedShares = append(abandonedShares, name)
+				abandonedMu.Unlock()
 				return
 			}
 			defer hostSem.Release(1)
 
 			// Check timeout
 			if timeoutFlag.Load() {
-				resultsLock.Lock()
-				results.TasksPending--
-				results.TasksFinished++
-				resultsLock.Unlock()
+				results.TasksPending.Add(-1)
+				results.TasksFinished.Add(1)
 				atomic.AddInt64(&skippedSharesCount, 1)
+				abandonedMu.Lock()
+				abandonedShares = append(abandonedShares, name)
+				abandonedMu.Unlock()
 				return
 			}
 
@@ -262,7 +477,8 @@ func ProcessTarget(
 			counts := processShare(
 				name, info, host, remoteName,
 				opts, cfg, og, parsedRules,
-				pool, results, resultsLock, log, &timeoutFlag,
+				pool, results, log, &timeoutFlag,
+				hostProps,
 			)
 
 			atomic.AddInt64(&totalShareCount, 1)
@@ -273,37 +489,183 @@ func ProcessTarget(
 			atomic.AddInt64(&skippedDirsCount, counts.SkippedDirectories)
 			atomic.AddInt64(&processedDirsCount, counts.ProcessedDirectories)
 
-			resultsLock.Lock()
-			results.TasksPending--
-			results.TasksFinished++
-			resultsLock.Unlock()
+			results.TasksPending.Add(-1)
+			results.TasksFinished.Add(1)
 		}(shareName, shareInfo)
 	}
 
 	wg.Wait()
 
+	if len(abandonedShares) > 0 {
+		sort.Strings(abandonedShares)
+		log.Warning(fmt.Sprintf("Host timeout budget exhausted on %s, abandoned %d share(s): %s",
+			host, len(abandonedShares), strings.Join(abandonedShares, ", ")))
+		recordAbandonedShares(og, remoteName, abandonedShares)
+		for _, name := range abandonedShares {
+			opts.SkipReport.Add(collector.SkipEntry{
+				Host: remoteName, Share: name,
+				Kind: "share", Reason: collector.SkipReasonHostTimeout,
+			})
+		}
+		results.SharesAbandoned.Add(int64(len(abandonedShares)))
+	}
+
+	if hostResult != nil {
+		hostResult.SharesProcessed = totalShareCount
+		hostResult.SharesAbandoned = abandonedShares
+		hostResult.Files = processedFilesCount
+		hostResult.Directories = processedDirsCount
+		if avg := sdStats.AverageLatency(); avg > 0 {
+			hostResult.AvgSDQueryLatency = avg.Round(time.Microsecond).String()
+		}
+	}
+
 	elapsed := time.Since(startTime)
 
+	// Stamp per-host timing and throughput on the host node so slow or
+	// error-prone servers are queryable in the graph rather than buried in
+	// logs. The node was first emitted during share processing; UpdateNode
+	// appends a property-only entry that merges on ingest.
+	errorCount := results.HostErrorCountFor(host)
+	if host != target.Value {
+		errorCount += results.HostErrorCountFor(target.Value)
+	}
+	metricsNode := graph.NewNode("NSHOST:"+strings.ToLower(remoteName), kinds.NodeKindNetworkShareHost).
+		SetProperty("name", remoteName+" (Network Share Host)").
+		SetProperty("fqdn", remoteName).
+		SetProperty("scanDurationSeconds", elapsed.Round(time.Millisecond).Seconds()).
+		SetProperty("sharesProcessed", totalShareCount).
+		SetProperty("filesEnumerated", processedFilesCount).
+		SetProperty("directoriesEnumerated", processedDirsCount).
+		SetProperty("errorCount", errorCount)
+	if queries := sdStats.Queries(); queries > 0 {
+		metricsNode.SetProperty("sdQueries", queries)
+		metricsNode.SetProperty("avgSDQueryMs", float64(sdStats.AverageLatency().Microseconds())/1000.0)
+	}
+	if og.LimitReached() {
+		// The graph size cap degraded this host to directory-level
+		// collection for at least part of the scan; make that visible on
+		// the node rather than only in the skip report.
+		metricsNode.SetProperty("collectionDegraded", true).
+			SetProperty("degradedReason", collector.SkipReasonGraphLimit)
+	}
+	og.UpdateNode(metricsNode)
+
 	// Update results
-	resultsLock.Lock()
-	results.SharesTotal += totalShareCount + skippedSharesCount
-	results.SharesProcessed += totalShareCount
-	results.SharesSkipped += skippedSharesCount
-	results.SharesPending -= totalShareCount + skippedSharesCount
-	results.FilesTotal += totalFileCount + skippedFilesCount
-	results.FilesProcessed += processedFilesCount
-	results.FilesSkipped += skippedFilesCount
-	results.DirectoriesTotal += totalDirCount + skippedDirsCount
-	results.DirectoriesProcessed += processedDirsCount
-	results.DirectoriesSkipped += skippedDirsCount
-	results.Success++
-	results.TasksFinished++
-	resultsLock.Unlock()
+	results.SharesTotal.Add(totalShareCount + skippedSharesCount)
+	results.SharesProcessed.Add(totalShareCount)
+	results.SharesSkipped.Add(skippedSharesCount)
+	results.SharesPending.Add(-(totalShareCount + skippedSharesCount))
+	results.FilesTotal.Add(totalFileCount + skippedFilesCount)
+	results.FilesProcessed.Add(processedFilesCount)
+	results.FilesSkipped.Add(skippedFilesCount)
+	results.DirectoriesTotal.Add(totalDirCount + skippedDirsCount)
+	results.DirectoriesProcessed.Add(processedDirsCount)
+	results.DirectoriesSkipped.Add(skippedDirsCount)
+	results.Success.Add(1)
+	results.TasksFinished.Add(1)
 
 	log.Info(fmt.Sprintf("Target %s completed: %d shares, %d files, %d directories in %s",
 		host, totalShareCount, totalFileCount, totalDirCount, utils.DeltaTime(elapsed)))
 }
 
+// recordHostError classifies a failed host operation with smb.ClassifyError
+// and records it in the shared results for the summary and errors sidecar.
+func recordHostError(results *collector.WorkerResults, host, stage string, err error) {
+	classification := smb.ClassifyError(err)
+	results.AddHostError(host, stage, classification.Category, err.Error())
+}
+
+// recordAbandonedShares marks shares that were never processed because the
+// host timeout budget ran out. The share nodes are emitted with an
+// "abandoned" marker so the gap is visible in the graph, not just in logs.
+func recordAbandonedShares(og *graph.OpenGraph, remoteName string, shareNames []string) {
+	hostNode := graph.NewNode("NSHOST:"+strings.ToLower(remoteName), kinds.NodeKindNetworkShareHost).
+		SetProperty("name", remoteName+" (Network Share Host)").
+		SetProperty("fqdn", remoteName).
+		SetProperty("abandonedShares", len(shareNames))
+	og.AddNode(hostNode)
+
+	for _, shareName := range shareNames {
+		shareID := fmt.Sprintf("\\\\%s\\%s\\", remoteName, utils.NormalizeName(shareName))
+		shareNode := graph.NewNode(shareID, kinds.NodeKindNetworkShareSMB).
+			SetProperty("displayName", shareName).
+			SetProperty("abandoned", true).
+			SetProperty("abandonedReason", "host timeout budget exhausted")
+		og.AddNode(shareNode)
+
+		shareEdge := graph.NewEdge(hostNode.ID, shareID, kinds.EdgeKindHasNetworkShare)
+		shareEdge.SetStartMatchBy("id")
+		shareEdge.SetStartKind(kinds.NodeKindNetworkShareHost)
+		shareEdge.SetEndKind(kinds.NodeKindNetworkShareSMB)
+		og.AddEdge(shareEdge)
+	}
+}
+
+// collectHostInfo queries SRVSVC for server version/role information and
+// active sessions, returning properties to stamp on the host node. When the
+// target answers as a failover cluster virtual server, the cluster's
+// advertised name is returned as well so the caller can canonicalize the
+// host identity. Failures are expected on hardened hosts and simply yield
+// fewer properties.
+func collectHostInfo(conn *smb.SMBSession, log logger.LoggerInterface) (map[string]any, string) {
+	props := make(map[string]any)
+	clusterName := ""
+
+	if info, err := conn.GetServerInfo(); err == nil && info != nil {
+		props["osVersion"] = fmt.Sprintf("%d.%d", info.VersionMajor, info.VersionMinor)
+		props["serverType"] = info.Type
+		if roles := smb.ServerTypeRoles(info.Type); len(roles) > 0 {
+			props["serverRoles"] = roles
+		}
+		if info.Comment != "" {
+			props["serverComment"] = info.Comment
+		}
+		if smb.IsClusterVirtualServer(info.Type) && info.Name != "" {
+			clusterName = info.Name
+			props["clusterVirtualServer"] = true
+		}
+	}
+
+	if sessions, err := conn.EnumSessions(); err == nil && len(sessions) > 0 {
+		users := make([]string, 0, len(sessions))
+		for _, sess := range sessions {
+			client := strings.TrimPrefix(sess.ClientName, "\\\\")
+			switch {
+			case sess.UserName != "" && client != "":
+				users = append(users, sess.UserName+"@"+client)
+			case sess.UserName != "":
+				users = append(users, sess.UserName)
+			case client != "":
+				users = append(users, client)
+			}
+		}
+		if len(users) > 0 {
+			sort.Strings(users)
+			props["activeSessions"] = users
+			log.Debug(fmt.Sprintf("Active sessions on %s: %s", conn.GetRemoteName(), strings.Join(users, ", ")))
+		}
+	}
+
+	return props, clusterName
+}
+
+// canonicalHostName substitutes a cluster virtual server name into the name
+// shares are emitted under. The cluster name is NetBIOS-style; when the
+// original target was an FQDN its domain part is kept so node IDs stay
+// domain-qualified (an IP target gets the bare cluster name). Shares of the
+// same cluster reached via different node addresses then collapse into a
+// single host instead of duplicating per IP.
+func canonicalHostName(remoteName, clusterName string) string {
+	clusterName = strings.ToLower(clusterName)
+	if net.ParseIP(remoteName) == nil {
+		if idx := strings.Index(remoteName, "."); idx >= 0 {
+			return clusterName + remoteName[idx:]
+		}
+	}
+	return clusterName
+}
+
 // processShare processes a single share.
 func processShare(
 	shareName string,
@@ -315,9 +677,9 @@ func processShare(
 	parsedRules []rules.Rule,
 	pool *ConnectionPool,
 	results *collector.WorkerResults,
-	resultsLock *sync.Mutex,
 	log logger.LoggerInterface,
 	timeoutFlag *atomic.Bool,
+	hostProps map[string]any,
 ) collector.TraversalCounts {
 	counts := collector.TraversalCounts{}
 
@@ -328,18 +690,25 @@ func processShare(
 
 	taskLog := logger.NewTaskLogger(log.(*logger.Logger), fmt.Sprintf("%s:%s", remoteName, shareName))
 
+	results.SetHostActivity(remoteName, shareName, "")
+
 	// Create rules evaluator
 	rulesEval := rules.NewEvaluator(parsedRules)
 
 	// Check if share should be explored
 	ruleShare := &rules.RuleObjectShare{
-		Name:   shareName,
-		Hidden: len(shareName) > 0 && shareName[len(shareName)-1] == '$',
+		Name: shareName,
+		Hidden: (len(shareName) > 0 && shareName[len(shareName)-1] == '$') ||
+			utils.IsSpecialShare(shareInfo.RawType),
 	}
 	rulesEval.SetShare(ruleShare)
 
 	if !rulesEval.CanExplore(ruleShare) {
 		taskLog.Debug("Skipping share: " + shareName)
+		opts.SkipReport.Add(collector.SkipEntry{
+			Host: remoteName, Share: shareName,
+			Kind: "share", Reason: collector.SkipReasonRule,
+		})
 		return counts
 	}
 
@@ -364,6 +733,19 @@ func processShare(
 	// Create OpenGraph context
 	ogc := graph.NewOpenGraphContext(og, taskLog)
 	ogc.SetEffectiveAccessOnly(opts.EffectiveAccessOnly)
+	ogc.SetImpersonatedPrincipals(opts.Impersonated)
+	ogc.SetIgnoredSIDs(opts.IgnoredSIDs)
+	ogc.SetDomainSID(opts.DomainSID)
+	ogc.SetSkipLocalPrincipals(opts.SkipLocalPrincipals)
+	ogc.SetRollupEdges(opts.RollupEdges)
+	ogc.SetEdgeKindFilter(opts.IncludeEdgeKinds, opts.ExcludeEdgeKinds)
+	ogc.SetOmittedProperties(opts.OmittedProperties)
+	ogc.SetRecordSDDL(opts.RawSDDL)
+	defer func() {
+		if suppressed := ogc.GetSuppressedEdges(); suppressed > 0 {
+			results.EdgesSuppressed.Add(int64(suppressed))
+		}
+	}()
 
 	// Extract domain from FQDN (e.g. "server.corp.com" -> "CORP.COM")
 	// and set it on the context so well-known SIDs get domain-prefixed.
@@ -383,14 +765,27 @@ func processShare(
 	hostNode := graph.NewNode("NSHOST:"+strings.ToLower(remoteName), kinds.NodeKindNetworkShareHost).
 		SetProperty("name", remoteName+" (Network Share Host)").
 		SetProperty("fqdn", remoteName)
+	if suffix := ogc.GetDomainSuffix(); suffix != "" {
+		hostNode.SetProperty("domain", suffix)
+	}
+	// Server role and session enrichment collected once per host; the graph
+	// dedupes nodes by ID, so every share task must stamp the same properties.
+	for key, value := range hostProps {
+		hostNode.SetProperty(key, value)
+	}
 	ogc.SetHost(hostNode)
 
-	// Create share node — use remoteName for UNC path consistency
-	shareID := fmt.Sprintf("\\\\%s\\%s\\", remoteName, shareName)
+	// Create share node — use remoteName for UNC path consistency. The ID
+	// and description are normalized for export; displayName stays
+	// wire-exact because it is what gets mounted.
+	shareID := fmt.Sprintf("\\\\%s\\%s\\", remoteName, utils.NormalizeName(shareName))
 	shareNode := graph.NewNode(shareID, kinds.NodeKindNetworkShareSMB).
 		SetProperty("displayName", shareName).
-		SetProperty("description", shareInfo.Comment).
-		SetProperty("hidden", ruleShare.Hidden)
+		SetProperty("description", utils.NormalizeName(shareInfo.Comment)).
+		SetProperty("hidden", ruleShare.Hidden).
+		SetProperty("special", utils.IsSpecialShare(shareInfo.RawType)).
+		SetProperty("temporary", utils.IsTemporaryShare(shareInfo.RawType)).
+		SetProperty("clusterShare", utils.IsClusterShare(shareInfo.RawType))
 	ogc.SetShare(shareNode)
 
 	// Set share in SMB session
@@ -399,18 +794,64 @@ func processShare(
 		return counts
 	}
 
-	// Collect share rights
-	shareRights, err := collector.CollectShareRights(conn, shareName, taskLog)
+	// Collect share rights, recording which method of the fallback chain
+	// (SRVSVC SD, root NTFS SD, access probe) produced them.
+	shareRights, aclSource, shareSDDL, err := collector.CollectShareRights(conn, shareName, taskLog)
 	if err != nil {
 		taskLog.Debug("Error collecting share rights: " + err.Error())
 	}
 	ogc.SetShareRights(shareRights)
+	ogc.SetShareACLSource(aclSource)
+	if aclSource != "" {
+		shareNode.SetProperty("aclSource", aclSource)
+	}
+	if aclSource == collector.ACLSourceAccessProbe {
+		shareNode.SetProperty("rootListable", true)
+	}
+	if opts.RawSDDL && shareSDDL != "" {
+		shareNode.SetProperty("sddl", shareSDDL)
+	}
+	// Every ACL source failed: mark the coverage gap on the share node so
+	// "no rights edges" and "could not read the ACL" stay distinguishable.
+	if aclSource == "" {
+		shareNode.SetProperty("aclCollectionFailed", true)
+		if err != nil {
+			shareNode.SetProperty("aclFailureCategory", smb.ClassifyError(err).Category)
+		}
+	}
+
+	// Alert on world-writable shares as soon as they are discovered, rather
+	// than leaving them to be found in the graph after the scan.
+	if isWorldWritable(shareRights) {
+		taskLog.Warning(fmt.Sprintf("ALERT: \\\\%s\\%s grants write access to Everyone/Authenticated Users", remoteName, shareName))
+		results.WorldWritableShares.Add(1)
+		if opts.AlertWebhook != "" {
+			notifyWebhook(opts.AlertWebhook, remoteName, shareName, taskLog)
+		}
+	}
+
+	// Access-check-only mode: record whether the share root can be listed,
+	// emit the share path and rights edges, and skip content traversal.
+	if opts.AccessCheckOnly {
+		_, listErr := conn.ListContents("")
+		shareNode.SetProperty("rootListable", listErr == nil)
+		if listErr != nil {
+			taskLog.Debug("Access check: could not list share root: " + listErr.Error())
+		}
+		if rulesEval.CanProcess(ruleShare) {
+			ogc.AddPathToGraph()
+		}
+		return counts
+	}
 
 	// Collect NTFS rights for the share root directory.
 	// These serve as a fallback for first-level files whose individual
 	// security descriptors cannot be retrieved over SMB.
-	shareRootNTFS, _ := collector.CollectNTFSRights(conn, ogc, "", taskLog)
+	shareRootNTFS, rootLabel, _, _ := collector.CollectNTFSRights(conn, ogc, "", true, taskLog)
 	ogc.SetShareRootNTFSRights(shareRootNTFS)
+	if rootLabel != nil {
+		shareNode.SetProperty("integrityLevel", rootLabel.Level)
+	}
 
 	// Check if share should be processed
 	if rulesEval.CanProcess(ruleShare) {
@@ -423,11 +864,20 @@ func processShare(
 		ogc,
 		rulesEval,
 		results,
-		resultsLock,
+		opts.SkipReport,
 		taskLog,
 		opts.Depth,
 		timeoutFlag,
 	)
 
+	// Per-object security descriptor failures degrade rights coverage for
+	// the whole share; the share node was already emitted, so restamp it
+	// with the count (UpdateNode entries merge by ID on ingest).
+	if counts.SDQueryFailures > 0 {
+		taskLog.Debug(fmt.Sprintf("%d security descriptor queries failed on \\\\%s\\%s", counts.SDQueryFailures, remoteName, shareName))
+		shareNode.SetProperty("sdQueryFailures", counts.SDQueryFailures)
+		og.UpdateNode(shareNode)
+	}
+
 	return counts
 }
@@ -0,0 +1,22 @@
+package worker
+
+import "testing"
+
+func TestCanonicalHostName(t *testing.T) {
+	tests := []struct {
+		remoteName  string
+		clusterName string
+		want        string
+	}{
+		{"node1.corp.com", "SOFS1", "sofs1.corp.com"}, // FQDN target keeps its domain part
+		{"node1", "SOFS1", "sofs1"},                   // short name target
+		{"10.0.0.5", "SOFS1", "sofs1"},                // IP target must not inherit octets
+		{"sofs1.corp.com", "SOFS1", "sofs1.corp.com"}, // already canonical
+	}
+
+	for _, tt := range tests {
+		if got := canonicalHostName(tt.remoteName, tt.clusterName); got != tt.want {
+			t.Errorf("canonicalHostName(%q, %q) = %q, want %q", tt.remoteName, tt.clusterName, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package yara
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/specterops/sharehound/internal/collector"
+)
+
+// Handler scans the contents of files selected by the ShareQL rules against
+// a compiled YARA rule set and records matches as node properties. It is
+// registered through collector.RegisterFileHandler when --yara-rules is set.
+type Handler struct {
+	scanner *Scanner
+	maxSize int64
+}
+
+// NewHandler creates a file handler around a compiled scanner. Files larger
+// than maxSize bytes are skipped; files are read up to maxSize bytes.
+func NewHandler(scanner *Scanner, maxSize int64) *Handler {
+	return &Handler{scanner: scanner, maxSize: maxSize}
+}
+
+// Name implements collector.FileHandler.
+func (h *Handler) Name() string {
+	return "yara"
+}
+
+// Matches implements collector.FileHandler. The decision is size-only: any
+// file the ShareQL rules selected for processing is a scan candidate.
+func (h *Handler) Matches(name string, size int64) bool {
+	return size > 0 && size <= h.maxSize
+}
+
+// Handle implements collector.FileHandler. It streams the file's contents
+// and attaches yaraMatches/yaraMatchCount properties when rules fire.
+func (h *Handler) Handle(hctx *collector.FileHandlerContext) error {
+	reader, err := hctx.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, h.maxSize))
+	if err != nil {
+		return err
+	}
+
+	matches := h.scanner.Scan(data)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(matches))
+	tags := make(map[string]bool)
+	for i, m := range matches {
+		names[i] = m.Rule
+		for _, tag := range m.Tags {
+			tags[tag] = true
+		}
+	}
+	hctx.Node.SetProperty("yaraMatches", names)
+	hctx.Node.SetProperty("yaraMatchCount", len(matches))
+	if len(tags) > 0 {
+		tagList := make([]string, 0, len(tags))
+		for tag := range tags {
+			tagList = append(tagList, tag)
+		}
+		sort.Strings(tagList)
+		hctx.Node.SetProperty("yaraTags", tagList)
+	}
+	hctx.Log.Info(fmt.Sprintf("YARA match on %s: %s", hctx.UNCPath, strings.Join(names, ", ")))
+	return nil
+}
@@ -0,0 +1,496 @@
+package yara
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Parser parses YARA rule files into the subset this package supports.
+// The parser is line oriented: rule headers, section labels and each
+// meta/string entry must sit on their own line, which is how YARA rules
+// are conventionally written anyway.
+type Parser struct{}
+
+// NewParser creates a new YARA parser.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// ParseFile reads and parses a YARA rule file.
+func (p *Parser) ParseFile(path string) ([]Rule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse(path, string(content))
+}
+
+var ruleNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+var stringIDPattern = regexp.MustCompile(`^\$[A-Za-z0-9_]*$`)
+
+// Parse parses YARA rules from content; source names the input in errors.
+func (p *Parser) Parse(source, content string) ([]Rule, error) {
+	var (
+		parsed        []Rule
+		current       *Rule
+		section       string
+		conditionText []string
+		bodyOpen      bool
+	)
+
+	fail := func(line int, format string, args ...interface{}) ([]Rule, error) {
+		return nil, fmt.Errorf("%s:%d: %s", source, line, fmt.Sprintf(format, args...))
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, raw := range lines {
+		lineNo := i + 1
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if current == nil {
+			name, tags, open, err := parseRuleHeader(line)
+			if err != nil {
+				return fail(lineNo, "%v", err)
+			}
+			current = &Rule{Name: name, Tags: tags, Meta: map[string]string{}}
+			section = ""
+			conditionText = nil
+			bodyOpen = open
+			continue
+		}
+
+		if !bodyOpen {
+			if line != "{" {
+				return fail(lineNo, "expected '{' to open rule %s", current.Name)
+			}
+			bodyOpen = true
+			continue
+		}
+
+		if line == "}" {
+			if len(conditionText) == 0 {
+				return fail(lineNo, "rule %s has no condition", current.Name)
+			}
+			cond, err := parseCondition(strings.Join(conditionText, " "), current.Strings)
+			if err != nil {
+				return fail(lineNo, "rule %s: %v", current.Name, err)
+			}
+			current.condition = cond
+			parsed = append(parsed, *current)
+			current = nil
+			continue
+		}
+
+		switch {
+		case line == "meta:":
+			section = "meta"
+		case line == "strings:":
+			section = "strings"
+		case strings.HasPrefix(line, "condition:"):
+			section = "condition"
+			if rest := strings.TrimSpace(strings.TrimPrefix(line, "condition:")); rest != "" {
+				conditionText = append(conditionText, rest)
+			}
+		default:
+			switch section {
+			case "meta":
+				key, value, err := parseMetaEntry(line)
+				if err != nil {
+					return fail(lineNo, "rule %s: %v", current.Name, err)
+				}
+				current.Meta[key] = value
+			case "strings":
+				str, err := parseStringEntry(line)
+				if err != nil {
+					return fail(lineNo, "rule %s: %v", current.Name, err)
+				}
+				current.Strings = append(current.Strings, str)
+			case "condition":
+				conditionText = append(conditionText, line)
+			default:
+				return fail(lineNo, "rule %s: statement outside a section: %s", current.Name, line)
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%s: unexpected end of input in rule %s", source, current.Name)
+	}
+	return parsed, nil
+}
+
+// parseRuleHeader parses "rule Name", "rule Name : tag1 tag2" and the same
+// forms with a trailing "{".
+func parseRuleHeader(line string) (name string, tags []string, open bool, err error) {
+	if strings.HasSuffix(line, "{") {
+		open = true
+		line = strings.TrimSpace(strings.TrimSuffix(line, "{"))
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "rule" {
+		return "", nil, false, fmt.Errorf("expected a rule declaration, got: %s", line)
+	}
+	name = fields[1]
+	rest := fields[2:]
+	if strings.Contains(name, ":") {
+		// "rule Name: tag" without spaces around the colon
+		parts := strings.SplitN(name, ":", 2)
+		name = parts[0]
+		if parts[1] != "" {
+			rest = append([]string{parts[1]}, rest...)
+		}
+	} else if len(rest) > 0 && rest[0] == ":" {
+		rest = rest[1:]
+	} else if len(rest) > 0 && strings.HasPrefix(rest[0], ":") {
+		rest[0] = strings.TrimPrefix(rest[0], ":")
+	} else if len(rest) > 0 {
+		return "", nil, false, fmt.Errorf("unexpected tokens after rule name: %s", strings.Join(rest, " "))
+	}
+	if !ruleNamePattern.MatchString(name) {
+		return "", nil, false, fmt.Errorf("invalid rule name: %s", name)
+	}
+	for _, tag := range rest {
+		if tag == "" {
+			continue
+		}
+		if !ruleNamePattern.MatchString(tag) {
+			return "", nil, false, fmt.Errorf("invalid tag: %s", tag)
+		}
+		tags = append(tags, tag)
+	}
+	return name, tags, open, nil
+}
+
+// parseMetaEntry parses one `key = value` line; quoted values are unquoted,
+// everything else is kept verbatim.
+func parseMetaEntry(line string) (string, string, error) {
+	eq := strings.Index(line, "=")
+	if eq < 0 {
+		return "", "", fmt.Errorf("invalid meta entry: %s", line)
+	}
+	key := strings.TrimSpace(line[:eq])
+	value := strings.TrimSpace(line[eq+1:])
+	if key == "" {
+		return "", "", fmt.Errorf("invalid meta entry: %s", line)
+	}
+	if len(value) >= 2 && strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+		unquoted, err := unescapeText(value[1 : len(value)-1])
+		if err != nil {
+			return "", "", err
+		}
+		value = string(unquoted)
+	}
+	return key, value, nil
+}
+
+// parseStringEntry parses one `$id = ...` line from a strings section.
+func parseStringEntry(line string) (String, error) {
+	eq := strings.Index(line, "=")
+	if eq < 0 {
+		return String{}, fmt.Errorf("invalid string entry: %s", line)
+	}
+	id := strings.TrimSpace(line[:eq])
+	if !stringIDPattern.MatchString(id) {
+		return String{}, fmt.Errorf("invalid string identifier: %s", id)
+	}
+	value := strings.TrimSpace(line[eq+1:])
+	str := String{ID: id}
+
+	switch {
+	case strings.HasPrefix(value, "\""):
+		end := closingQuote(value)
+		if end < 0 {
+			return String{}, fmt.Errorf("unterminated string literal: %s", value)
+		}
+		text, err := unescapeText(value[1:end])
+		if err != nil {
+			return String{}, err
+		}
+		str.Text = text
+		if err := parseModifiers(&str, value[end+1:]); err != nil {
+			return String{}, err
+		}
+	case strings.HasPrefix(value, "{"):
+		end := strings.Index(value, "}")
+		if end < 0 {
+			return String{}, fmt.Errorf("unterminated hex string: %s", value)
+		}
+		hex, err := parseHexTokens(value[1:end])
+		if err != nil {
+			return String{}, err
+		}
+		str.Hex = hex
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" {
+			return String{}, fmt.Errorf("hex strings take no modifiers: %s", rest)
+		}
+	case strings.HasPrefix(value, "/"):
+		end := closingSlash(value)
+		if end < 0 {
+			return String{}, fmt.Errorf("unterminated regex: %s", value)
+		}
+		expr := value[1:end]
+		if err := parseModifiers(&str, value[end+1:]); err != nil {
+			return String{}, err
+		}
+		if str.Wide || str.Ascii {
+			return String{}, fmt.Errorf("regex strings support only the nocase modifier")
+		}
+		if str.Nocase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return String{}, fmt.Errorf("invalid regex %s: %v", str.ID, err)
+		}
+		str.Regex = re
+	default:
+		return String{}, fmt.Errorf("invalid string value: %s", value)
+	}
+	return str, nil
+}
+
+// closingQuote returns the index of the closing unescaped double quote of a
+// literal starting at index 0, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// closingSlash returns the index of the closing unescaped slash of a regex
+// starting at index 0, or -1.
+func closingSlash(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '/':
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeText expands the escape sequences YARA allows in text strings.
+func unescapeText(s string) ([]byte, error) {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			out = append(out, s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return nil, fmt.Errorf("trailing backslash in string literal")
+		}
+		switch s[i] {
+		case '"', '\\':
+			out = append(out, s[i])
+		case 'n':
+			out = append(out, '\n')
+		case 't':
+			out = append(out, '\t')
+		case 'r':
+			out = append(out, '\r')
+		case 'x':
+			if i+2 >= len(s) {
+				return nil, fmt.Errorf("truncated \\x escape")
+			}
+			b, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid \\x escape: %s", s[i+1:i+3])
+			}
+			out = append(out, byte(b))
+			i += 2
+		default:
+			return nil, fmt.Errorf("unsupported escape sequence \\%c", s[i])
+		}
+	}
+	return out, nil
+}
+
+// parseModifiers applies the keyword modifiers following a string value.
+func parseModifiers(str *String, rest string) error {
+	for _, mod := range strings.Fields(rest) {
+		switch mod {
+		case "nocase":
+			str.Nocase = true
+		case "ascii":
+			str.Ascii = true
+		case "wide":
+			str.Wide = true
+		default:
+			return fmt.Errorf("unsupported string modifier: %s", mod)
+		}
+	}
+	return nil
+}
+
+// parseHexTokens parses the body of a hex string into byte patterns.
+func parseHexTokens(body string) ([]HexByte, error) {
+	fields := strings.Fields(body)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty hex string")
+	}
+	hex := make([]HexByte, 0, len(fields))
+	for _, tok := range fields {
+		if tok == "??" {
+			hex = append(hex, HexByte{Any: true})
+			continue
+		}
+		if len(tok) != 2 {
+			return nil, fmt.Errorf("unsupported hex token: %s", tok)
+		}
+		b, err := strconv.ParseUint(tok, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported hex token: %s", tok)
+		}
+		hex = append(hex, HexByte{Value: byte(b)})
+	}
+	return hex, nil
+}
+
+// conditionParser is a recursive-descent parser over condition tokens.
+type conditionParser struct {
+	tokens []string
+	pos    int
+	ids    map[string]bool
+}
+
+// parseCondition parses a condition expression, checking that every string
+// reference resolves to a declared string.
+func parseCondition(expr string, strs []String) (condition, error) {
+	ids := make(map[string]bool, len(strs))
+	for _, s := range strs {
+		ids[s.ID] = true
+	}
+	cp := &conditionParser{tokens: tokenizeCondition(expr), ids: ids}
+	cond, err := cp.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if cp.pos != len(cp.tokens) {
+		return nil, fmt.Errorf("unexpected token in condition: %s", cp.tokens[cp.pos])
+	}
+	return cond, nil
+}
+
+// tokenizeCondition splits a condition into tokens, treating parentheses as
+// their own tokens.
+func tokenizeCondition(expr string) []string {
+	expr = strings.ReplaceAll(expr, "(", " ( ")
+	expr = strings.ReplaceAll(expr, ")", " ) ")
+	return strings.Fields(expr)
+}
+
+func (cp *conditionParser) peek() string {
+	if cp.pos < len(cp.tokens) {
+		return cp.tokens[cp.pos]
+	}
+	return ""
+}
+
+func (cp *conditionParser) parseOr() (condition, error) {
+	left, err := cp.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for cp.peek() == "or" {
+		cp.pos++
+		right, err := cp.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCondition{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (cp *conditionParser) parseAnd() (condition, error) {
+	left, err := cp.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for cp.peek() == "and" {
+		cp.pos++
+		right, err := cp.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = andCondition{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (cp *conditionParser) parseFactor() (condition, error) {
+	tok := cp.peek()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of condition")
+	case tok == "not":
+		cp.pos++
+		inner, err := cp.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{inner: inner}, nil
+	case tok == "(":
+		cp.pos++
+		inner, err := cp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if cp.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis in condition")
+		}
+		cp.pos++
+		return inner, nil
+	case strings.HasPrefix(tok, "$"):
+		cp.pos++
+		if !cp.ids[tok] {
+			return nil, fmt.Errorf("condition references undefined string %s", tok)
+		}
+		return stringRef{id: tok}, nil
+	default:
+		return cp.parseOfThem(tok)
+	}
+}
+
+// parseOfThem parses "any of them", "all of them" and "N of them".
+func (cp *conditionParser) parseOfThem(tok string) (condition, error) {
+	var min int
+	switch tok {
+	case "any":
+		min = 1
+	case "all":
+		min = -1
+	default:
+		n, err := strconv.Atoi(tok)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("unsupported condition term: %s", tok)
+		}
+		min = n
+	}
+	cp.pos++
+	if cp.peek() != "of" {
+		return nil, fmt.Errorf("expected 'of' after %s", tok)
+	}
+	cp.pos++
+	if cp.peek() != "them" {
+		return nil, fmt.Errorf("only 'of them' is supported, got 'of %s'", cp.peek())
+	}
+	cp.pos++
+	return ofThem{min: min}, nil
+}
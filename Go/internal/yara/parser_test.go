@@ -0,0 +1,172 @@
+package yara
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBasicRule(t *testing.T) {
+	src := `
+rule AWSAccessKey : credentials aws
+{
+    meta:
+        description = "AWS access key ID"
+        author = "sharehound"
+    strings:
+        $akia = "AKIA"
+        $asia = "ASIA"
+    condition:
+        any of them
+}
+`
+	parsed, err := NewParser().Parse("test", src)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(parsed) != 1 {
+		t.Fatalf("Expected 1 rule, got %d", len(parsed))
+	}
+	rule := parsed[0]
+	if rule.Name != "AWSAccessKey" {
+		t.Errorf("Name = %q", rule.Name)
+	}
+	if len(rule.Tags) != 2 || rule.Tags[0] != "credentials" || rule.Tags[1] != "aws" {
+		t.Errorf("Tags = %v", rule.Tags)
+	}
+	if rule.Meta["description"] != "AWS access key ID" {
+		t.Errorf("Meta description = %q", rule.Meta["description"])
+	}
+	if len(rule.Strings) != 2 {
+		t.Fatalf("Expected 2 strings, got %d", len(rule.Strings))
+	}
+	if rule.Strings[0].ID != "$akia" || string(rule.Strings[0].Text) != "AKIA" {
+		t.Errorf("First string = %+v", rule.Strings[0])
+	}
+	if rule.condition == nil {
+		t.Error("Condition was not parsed")
+	}
+}
+
+func TestParseMultipleRulesAndBraceStyles(t *testing.T) {
+	src := `
+// opening brace on its own line
+rule First
+{
+    strings:
+        $a = "one"
+    condition:
+        $a
+}
+
+rule Second {
+    strings:
+        $a = "two"
+    condition:
+        all of them
+}
+`
+	parsed, err := NewParser().Parse("test", src)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(parsed) != 2 || parsed[0].Name != "First" || parsed[1].Name != "Second" {
+		t.Errorf("Parsed rules: %+v", parsed)
+	}
+}
+
+func TestParseStringModifiers(t *testing.T) {
+	src := `
+rule Modifiers {
+    strings:
+        $a = "password" nocase
+        $b = "secret" wide ascii
+        $c = { 4D 5A ?? 00 }
+        $d = /BEGIN (RSA|EC) PRIVATE KEY/
+        $e = /api[_-]key/ nocase
+    condition:
+        any of them
+}
+`
+	parsed, err := NewParser().Parse("test", src)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	strs := parsed[0].Strings
+	if !strs[0].Nocase {
+		t.Error("$a should be nocase")
+	}
+	if !strs[1].Wide || !strs[1].Ascii {
+		t.Error("$b should be wide and ascii")
+	}
+	if len(strs[2].Hex) != 4 || !strs[2].Hex[2].Any || strs[2].Hex[0].Value != 0x4D {
+		t.Errorf("$c hex = %+v", strs[2].Hex)
+	}
+	if strs[3].Regex == nil {
+		t.Error("$d should be a regex")
+	}
+	if strs[4].Regex == nil || !strs[4].Regex.MatchString("API-KEY") {
+		t.Error("$e nocase regex should match API-KEY")
+	}
+}
+
+func TestParseTextEscapes(t *testing.T) {
+	src := `
+rule Escapes {
+    strings:
+        $a = "tab\there \"quoted\" \x41\\"
+    condition:
+        $a
+}
+`
+	parsed, err := NewParser().Parse("test", src)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := "tab\there \"quoted\" A\\"
+	if got := string(parsed[0].Strings[0].Text); got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no condition", "rule X {\n strings:\n $a = \"x\"\n}\n", "no condition"},
+		{"undefined string", "rule X {\n strings:\n $a = \"x\"\n condition:\n $b\n}\n", "undefined string"},
+		{"unsupported term", "rule X {\n condition:\n filesize > 100\n}\n", "unsupported condition term"},
+		{"unsupported modifier", "rule X {\n strings:\n $a = \"x\" fullword\n condition:\n $a\n}\n", "unsupported string modifier"},
+		{"hex jump", "rule X {\n strings:\n $a = { 4D [2-4] 5A }\n condition:\n $a\n}\n", "unsupported hex token"},
+		{"bad regex", "rule X {\n strings:\n $a = /((/\n condition:\n $a\n}\n", "invalid regex"},
+		{"bad header", "garbage here\n", "expected a rule declaration"},
+		{"truncated input", "rule X {\n condition:\n", "unexpected end of input"},
+	}
+	for _, tc := range cases {
+		_, err := NewParser().Parse("test", tc.src)
+		if err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error %q does not mention %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestParseConditionExpressions(t *testing.T) {
+	src := `
+rule Combo {
+    strings:
+        $a = "alpha"
+        $b = "beta"
+        $c = "gamma"
+    condition:
+        ($a and $b) or (not $c and 2 of them)
+}
+`
+	if _, err := NewParser().Parse("test", src); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+// Package yara implements a small, pure-Go subset of the YARA rule language
+// so file contents selected by ShareQL rules can be scanned for known
+// patterns without a cgo dependency on libyara.
+//
+// The supported subset covers the constructs that matter for hunting on file
+// shares:
+//
+//   - text strings with the nocase, ascii and wide modifiers
+//   - hex strings with ?? wildcard bytes (no jumps or alternations)
+//   - regex strings (Go regexp syntax) with the nocase modifier
+//   - conditions built from string identifiers, and/or/not, parentheses and
+//     "any of them" / "all of them" / "N of them"
+//
+// Rules using features outside this subset fail to parse with a clear error
+// rather than silently matching differently than real YARA would.
+package yara
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// Rule is a single parsed YARA rule.
+type Rule struct {
+	Name    string
+	Tags    []string
+	Meta    map[string]string
+	Strings []String
+
+	condition condition
+}
+
+// String is one entry from a rule's strings section. Exactly one of Text,
+// Hex or Regex is set.
+type String struct {
+	ID    string
+	Text  []byte
+	Hex   []HexByte
+	Regex *regexp.Regexp
+
+	Nocase bool
+	Ascii  bool
+	Wide   bool
+}
+
+// HexByte is one byte position in a hex string; Any marks a ?? wildcard.
+type HexByte struct {
+	Value byte
+	Any   bool
+}
+
+// Match records one rule that matched during a scan.
+type Match struct {
+	Rule    string
+	Tags    []string
+	Strings []string // IDs of the strings that matched
+}
+
+// Scanner holds a compiled rule set and matches it against file contents.
+// A Scanner is immutable after construction and safe for concurrent use.
+type Scanner struct {
+	rules []Rule
+}
+
+// NewScanner builds a Scanner from parsed rules.
+func NewScanner(rules []Rule) *Scanner {
+	return &Scanner{rules: rules}
+}
+
+// RuleCount returns the number of rules in the set.
+func (s *Scanner) RuleCount() int {
+	return len(s.rules)
+}
+
+// Scan evaluates every rule against data and returns the matches in rule
+// order.
+func (s *Scanner) Scan(data []byte) []Match {
+	var matches []Match
+	for i := range s.rules {
+		rule := &s.rules[i]
+		matched := make(map[string]bool, len(rule.Strings))
+		var matchedIDs []string
+		for j := range rule.Strings {
+			str := &rule.Strings[j]
+			if str.match(data) {
+				matched[str.ID] = true
+				matchedIDs = append(matchedIDs, str.ID)
+			}
+		}
+		if rule.condition.eval(matched, len(rule.Strings)) {
+			matches = append(matches, Match{
+				Rule:    rule.Name,
+				Tags:    rule.Tags,
+				Strings: matchedIDs,
+			})
+		}
+	}
+	return matches
+}
+
+// match reports whether the string occurs anywhere in data.
+func (s *String) match(data []byte) bool {
+	switch {
+	case s.Regex != nil:
+		return s.Regex.Match(data)
+	case s.Hex != nil:
+		return matchHex(data, s.Hex)
+	default:
+		// Text. Like YARA, an explicit wide modifier without ascii
+		// searches only the UTF-16LE form.
+		if s.Wide && matchText(data, widen(s.Text), s.Nocase) {
+			return true
+		}
+		if !s.Wide || s.Ascii {
+			return matchText(data, s.Text, s.Nocase)
+		}
+		return false
+	}
+}
+
+// matchText reports whether pattern occurs in data, optionally ignoring
+// ASCII case (YARA's nocase is ASCII-only).
+func matchText(data, pattern []byte, nocase bool) bool {
+	if !nocase {
+		return bytes.Contains(data, pattern)
+	}
+	if len(pattern) == 0 {
+		return true
+	}
+	for i := 0; i+len(pattern) <= len(data); i++ {
+		if bytes.EqualFold(data[i:i+len(pattern)], pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHex scans data for the hex pattern, honoring ?? wildcards.
+func matchHex(data []byte, pattern []HexByte) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	for i := 0; i+len(pattern) <= len(data); i++ {
+		ok := true
+		for j, hb := range pattern {
+			if !hb.Any && data[i+j] != hb.Value {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// widen converts an ASCII pattern to its UTF-16LE form, as YARA's wide
+// modifier does.
+func widen(pattern []byte) []byte {
+	wide := make([]byte, 0, len(pattern)*2)
+	for _, b := range pattern {
+		wide = append(wide, b, 0)
+	}
+	return wide
+}
+
+// condition is the parsed condition of a rule, evaluated against the set of
+// string IDs that matched and the total number of strings in the rule.
+type condition interface {
+	eval(matched map[string]bool, total int) bool
+}
+
+// stringRef is a bare $identifier in a condition.
+type stringRef struct {
+	id string
+}
+
+func (c stringRef) eval(matched map[string]bool, total int) bool {
+	return matched[c.id]
+}
+
+// ofThem is "any of them", "all of them" or "N of them".
+type ofThem struct {
+	min int // -1 means all
+}
+
+func (c ofThem) eval(matched map[string]bool, total int) bool {
+	if c.min < 0 {
+		return len(matched) == total
+	}
+	return len(matched) >= c.min
+}
+
+type notCondition struct {
+	inner condition
+}
+
+func (c notCondition) eval(matched map[string]bool, total int) bool {
+	return !c.inner.eval(matched, total)
+}
+
+type andCondition struct {
+	left, right condition
+}
+
+func (c andCondition) eval(matched map[string]bool, total int) bool {
+	return c.left.eval(matched, total) && c.right.eval(matched, total)
+}
+
+type orCondition struct {
+	left, right condition
+}
+
+func (c orCondition) eval(matched map[string]bool, total int) bool {
+	return c.left.eval(matched, total) || c.right.eval(matched, total)
+}
@@ -25,6 +25,10 @@ const (
 	NodeKindGroup     = "Group"
 )
 
+// Scan metadata node kind — one node per ShareHound run, linked to every
+// host node the run produced so a run's data can be filtered or deleted.
+const NodeKindShareHoundScan = "ShareHoundScan"
+
 // Containment edge kinds
 const (
 	EdgeKindHasNetworkShare   = "HasNetworkShare"
@@ -32,6 +36,18 @@ const (
 	EdgeKindContains          = "Contains"
 )
 
+// Scan provenance edge kind — connects a ShareHoundScan node to the host
+// nodes produced during that run.
+const EdgeKindScannedHost = "ScannedHost"
+
+// Standard BloodHound edge kinds emitted by the optional SAMR local group
+// sampling. These are not ShareHound-specific: BloodHound understands them
+// natively, so local-admin context composes with ingested SharpHound data.
+const (
+	EdgeKindAdminTo = "AdminTo"
+	EdgeKindCanRDP  = "CanRDP"
+)
+
 // Share-level permission edge kinds - Generic rights
 const (
 	EdgeKindCanGenericExecute = "CanGenericExecute"
@@ -99,6 +115,23 @@ const (
 	EdgeKindCanNTFSWriteAttributes = "CanNTFSWriteAttributes" // FILE_WRITE_ATTRIBUTES
 )
 
+// NTFS-level directory-specific permission edge kinds. The low access-mask
+// bits are overloaded: on a directory 0x1 means FILE_LIST_DIRECTORY rather
+// than FILE_READ_DATA, 0x2 FILE_ADD_FILE, 0x4 FILE_ADD_SUBDIRECTORY and
+// 0x20 FILE_TRAVERSE. Directory ACEs are mapped to these kinds so queries
+// can read an edge's meaning without joining on the end node's kind.
+const (
+	EdgeKindCanNTFSListDirectory   = "CanNTFSListDirectory"   // FILE_LIST_DIRECTORY (0x00000001 on directories)
+	EdgeKindCanNTFSAddFile         = "CanNTFSAddFile"         // FILE_ADD_FILE (0x00000002 on directories)
+	EdgeKindCanNTFSAddSubdirectory = "CanNTFSAddSubdirectory" // FILE_ADD_SUBDIRECTORY (0x00000004 on directories)
+	EdgeKindCanNTFSTraverse        = "CanNTFSTraverse"        // FILE_TRAVERSE (0x00000020 on directories)
+)
+
+// Aggregate rollup edge kind — emitted instead of the granular CanNTFS* edges
+// when rollup mode is enabled. The individual rights are carried in the edge's
+// "rights" property and the coarse category in "category" (Read/Write/FullControl).
+const EdgeKindHasNTFSAccess = "HasNTFSAccess"
+
 // Effective access edge kinds — intersection of share-level and NTFS-level generic rights
 // for the same SID. Represents what a principal can actually do when accessing a file
 // over SMB (both permission layers must allow the operation).
@@ -115,6 +148,13 @@ var EdgeDescriptions = map[string]string{
 	EdgeKindHostsNetworkShare: "The host machine serves this network share.",
 	EdgeKindContains:          "The parent share or directory contains this child item.",
 
+	// Scan provenance
+	EdgeKindScannedHost: "The ShareHound scan run that produced this network share host node.",
+
+	// Local group membership (SAMR sampling)
+	EdgeKindAdminTo: "The principal is a member of the local Administrators group on the computer.",
+	EdgeKindCanRDP:  "The principal is a member of the local Remote Desktop Users group on the computer.",
+
 	// Share-level generic rights
 	EdgeKindCanGenericExecute: "Share-level DACL grants GENERIC_EXECUTE, allowing the principal to traverse directories on the share.",
 	EdgeKindCanGenericWrite:   "Share-level DACL grants GENERIC_WRITE, allowing the principal to create and modify content on the share.",
@@ -166,6 +206,15 @@ var EdgeDescriptions = map[string]string{
 	EdgeKindCanNTFSReadAttributes:  "NTFS DACL grants FILE_READ_ATTRIBUTES, allowing the principal to read basic attributes of the file or directory.",
 	EdgeKindCanNTFSWriteAttributes: "NTFS DACL grants FILE_WRITE_ATTRIBUTES, allowing the principal to modify basic attributes of the file or directory.",
 
+	// NTFS-level directory-specific permission edges
+	EdgeKindCanNTFSListDirectory:   "NTFS DACL grants FILE_LIST_DIRECTORY, allowing the principal to list the entries of the directory.",
+	EdgeKindCanNTFSAddFile:         "NTFS DACL grants FILE_ADD_FILE, allowing the principal to create files in the directory.",
+	EdgeKindCanNTFSAddSubdirectory: "NTFS DACL grants FILE_ADD_SUBDIRECTORY, allowing the principal to create subdirectories in the directory.",
+	EdgeKindCanNTFSTraverse:        "NTFS DACL grants FILE_TRAVERSE, allowing the principal to pass through the directory to reach its children.",
+
+	// Aggregate rollup edge
+	EdgeKindHasNTFSAccess: "The principal has NTFS access to this file or directory. The granular rights are listed in the \"rights\" property and summarized in \"category\" (Read, Write or FullControl).",
+
 	// Effective access edges
 	EdgeKindCanEffectiveRead:    "The principal can read this file or directory over SMB. Both the share-level and NTFS DACLs grant read access for this SID.",
 	EdgeKindCanEffectiveWrite:   "The principal can write to this file or directory over SMB. Both the share-level and NTFS DACLs grant write access for this SID.",
@@ -184,6 +233,7 @@ func AllNodeKinds() []string {
 		NodeKindPrincipal,
 		NodeKindUser,
 		NodeKindGroup,
+		NodeKindShareHoundScan,
 	}
 }
 
@@ -194,6 +244,11 @@ func AllEdgeKinds() []string {
 		EdgeKindHasNetworkShare,
 		EdgeKindHostsNetworkShare,
 		EdgeKindContains,
+		// Scan provenance
+		EdgeKindScannedHost,
+		// Local group membership
+		EdgeKindAdminTo,
+		EdgeKindCanRDP,
 		// Share-level generic
 		EdgeKindCanGenericExecute,
 		EdgeKindCanGenericWrite,
@@ -239,9 +294,70 @@ func AllEdgeKinds() []string {
 		EdgeKindCanNTFSDeleteChild,
 		EdgeKindCanNTFSReadAttributes,
 		EdgeKindCanNTFSWriteAttributes,
+		// NTFS-level directory-specific
+		EdgeKindCanNTFSListDirectory,
+		EdgeKindCanNTFSAddFile,
+		EdgeKindCanNTFSAddSubdirectory,
+		EdgeKindCanNTFSTraverse,
+		// Aggregate rollup
+		EdgeKindHasNTFSAccess,
 		// Effective access (intersection of share-level and NTFS-level)
 		EdgeKindCanEffectiveRead,
 		EdgeKindCanEffectiveWrite,
 		EdgeKindCanEffectiveExecute,
 	}
 }
+
+// Pathfinding composition hints. BloodHound's OpenGraph pathfinding
+// configuration decides which edges a path may walk from edge properties;
+// these classes bucket the rights edges the way that configuration filters
+// on (see the "traversable" and "rightClass" edge properties).
+const (
+	RightClassRead    = "read"
+	RightClassWrite   = "write"
+	RightClassExecute = "execute"
+	RightClassControl = "control"
+)
+
+// RightClass returns the coarse access class of a rights edge kind, or ""
+// for edge kinds that carry no access semantics.
+func RightClass(edgeKind string) string {
+	switch edgeKind {
+	case EdgeKindCanGenericRead, EdgeKindCanShareRead, EdgeKindCanEffectiveRead,
+		EdgeKindCanNTFSGenericRead, EdgeKindCanNTFSReadData, EdgeKindCanNTFSReadEA,
+		EdgeKindCanNTFSReadAttributes, EdgeKindCanNTFSReadControl,
+		EdgeKindCanNTFSListDirectory,
+		EdgeKindCanReadControl, EdgeKindCanDsReadProperty,
+		EdgeKindCanDsListContents, EdgeKindCanDsListObject:
+		return RightClassRead
+	case EdgeKindCanGenericWrite, EdgeKindCanShareWrite, EdgeKindCanEffectiveWrite,
+		EdgeKindCanNTFSGenericWrite, EdgeKindCanNTFSWriteData, EdgeKindCanNTFSAppendData,
+		EdgeKindCanNTFSWriteEA, EdgeKindCanNTFSWriteAttributes,
+		EdgeKindCanNTFSDelete, EdgeKindCanNTFSDeleteChild, EdgeKindCanDelete,
+		EdgeKindCanNTFSAddFile, EdgeKindCanNTFSAddSubdirectory,
+		EdgeKindCanDsCreateChild, EdgeKindCanDsDeleteChild, EdgeKindCanDsDeleteTree,
+		EdgeKindCanDsWriteProperty, EdgeKindCanDsWriteExtendedProperties:
+		return RightClassWrite
+	case EdgeKindCanGenericExecute, EdgeKindCanShareExecute, EdgeKindCanEffectiveExecute,
+		EdgeKindCanNTFSGenericExecute, EdgeKindCanNTFSExecute, EdgeKindCanNTFSTraverse:
+		return RightClassExecute
+	case EdgeKindCanGenericAll, EdgeKindCanNTFSGenericAll,
+		EdgeKindCanWriteDacl, EdgeKindCanWriteOwner,
+		EdgeKindCanNTFSWriteDacl, EdgeKindCanNTFSWriteOwner,
+		EdgeKindCanDsControlAccess:
+		return RightClassControl
+	}
+	return ""
+}
+
+// Traversable reports whether pathfinding may walk an edge of this kind:
+// the containment edges that connect hosts, shares and their contents, the
+// rights edges (including the HasNTFSAccess rollup), but not provenance
+// edges like ScannedHost.
+func Traversable(edgeKind string) bool {
+	switch edgeKind {
+	case EdgeKindContains, EdgeKindHasNetworkShare, EdgeKindHostsNetworkShare, EdgeKindHasNTFSAccess:
+		return true
+	}
+	return RightClass(edgeKind) != ""
+}